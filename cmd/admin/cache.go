@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// cacheNamespaces are the key prefixes of cached reads. Other Redis keys, such
+// as sessions and rate limit buckets, are state rather than cache and cannot
+// be flushed from here.
+var cacheNamespaces = []string{
+	"dashboard_stats",
+	"money_flow",
+	"network",
+	"network_rankings",
+	"network_stats",
+	"networks",
+	"pairwise_transactions",
+	"risk_score",
+	"transaction",
+	"transaction_data",
+	"wallet",
+	"wallet_network",
+	"wallet_rankings",
+	"wallet_stats",
+	"wallet_transactions",
+}
+
+// cacheResult is the number of keys in each namespace, flushed or counted
+type cacheResult struct {
+	Action     string         `json:"action"`
+	DryRun     bool           `json:"dry_run"`
+	Namespaces map[string]int `json:"namespaces"`
+	Total      int            `json:"total"`
+}
+
+func (r *cacheResult) printText(w io.Writer) {
+	if r.DryRun {
+		fmt.Fprintf(w, "Dry run: %s (nothing was changed)\n", r.Action)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tKEYS")
+	for _, namespace := range sortedKeys(r.Namespaces) {
+		fmt.Fprintf(tw, "%s\t%d\n", namespace, r.Namespaces[namespace])
+	}
+	fmt.Fprintf(tw, "total\t%d\n", r.Total)
+	tw.Flush()
+}
+
+var cacheFlushCommand = command{
+	group:   "cache",
+	name:    "flush",
+	summary: "drop cached reads of namespaces on every replica",
+	setup: func(fs *flag.FlagSet) action {
+		namespaces := fs.String("namespace", "", "comma separated namespaces, or all (required): "+strings.Join(cacheNamespaces, ", "))
+
+		var selected []string
+		return action{
+			check: func() error {
+				var err error
+				selected, err = parseNamespaces(*namespaces, false)
+				return err
+			},
+			run: func(ctx context.Context, env *env) (interface{}, error) {
+				if env.dryRun {
+					result, err := env.countKeys(ctx, selected)
+					if err != nil {
+						return nil, err
+					}
+					result.Action = "flush"
+					result.DryRun = true
+					return result, nil
+				}
+
+				result := &cacheResult{Action: "flush", Namespaces: make(map[string]int, len(selected))}
+				for _, namespace := range selected {
+					flushed, err := env.Cache.InvalidateNamespace(ctx, namespace)
+					if err != nil {
+						return nil, fmt.Errorf("failed to flush %s after %d keys: %w", namespace, flushed, err)
+					}
+					result.Namespaces[namespace] = flushed
+					result.Total += flushed
+				}
+				return result, nil
+			},
+		}
+	},
+}
+
+var cacheCountCommand = command{
+	group:   "cache",
+	name:    "count",
+	summary: "count cached keys per namespace",
+	setup: func(fs *flag.FlagSet) action {
+		namespaces := fs.String("namespace", "", "comma separated namespaces, all when empty")
+
+		var selected []string
+		return action{
+			check: func() error {
+				var err error
+				selected, err = parseNamespaces(*namespaces, true)
+				return err
+			},
+			run: func(ctx context.Context, env *env) (interface{}, error) {
+				result, err := env.countKeys(ctx, selected)
+				if err != nil {
+					return nil, err
+				}
+				result.Action = "count"
+				return result, nil
+			},
+		}
+	},
+}
+
+// countKeys counts the keys of each namespace
+func (e *env) countKeys(ctx context.Context, namespaces []string) (*cacheResult, error) {
+	result := &cacheResult{Namespaces: make(map[string]int, len(namespaces))}
+	for _, namespace := range namespaces {
+		keys, err := e.Redis.ScanKeys(ctx, namespace+":*")
+		if err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", namespace, err)
+		}
+		result.Namespaces[namespace] = len(keys)
+		result.Total += len(keys)
+	}
+	return result, nil
+}
+
+// parseNamespaces parses a comma separated list of cache namespaces. "all" and,
+// if emptyIsAll, an empty list select every namespace.
+func parseNamespaces(value string, emptyIsAll bool) ([]string, error) {
+	names := splitList(value)
+	if len(names) == 0 {
+		if !emptyIsAll {
+			return nil, errors.New("-namespace is required")
+		}
+		return cacheNamespaces, nil
+	}
+
+	known := make(map[string]bool, len(cacheNamespaces))
+	for _, namespace := range cacheNamespaces {
+		known[namespace] = true
+	}
+
+	seen := make(map[string]bool, len(names))
+	var selected []string
+	for _, name := range names {
+		if name == "all" {
+			return cacheNamespaces, nil
+		}
+		if !known[name] {
+			return nil, fmt.Errorf("unknown cache namespace %q, expected one of: %s", name, strings.Join(cacheNamespaces, ", "))
+		}
+		if !seen[name] {
+			seen[name] = true
+			selected = append(selected, name)
+		}
+	}
+	sort.Strings(selected)
+	return selected, nil
+}
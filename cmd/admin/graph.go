@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+)
+
+// addressItem is the outcome of a command for one address
+type addressItem struct {
+	Address string      `json:"address"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   string      `json:"error,omitempty"`
+	// summary is the result in a line of text
+	summary string
+}
+
+// addressResult is the outcome of a command run over a list of addresses.
+// Failures are reported per address rather than stopping the run.
+type addressResult struct {
+	Action    string        `json:"action"`
+	DryRun    bool          `json:"dry_run"`
+	Addresses []addressItem `json:"addresses"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+}
+
+func (r *addressResult) printText(w io.Writer) {
+	if r.DryRun {
+		fmt.Fprintf(w, "Dry run: %s (nothing was changed)\n", r.Action)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ADDRESS\tRESULT")
+	for _, item := range r.Addresses {
+		if item.Error != "" {
+			fmt.Fprintf(tw, "%s\terror: %s\n", item.Address, item.Error)
+		} else {
+			fmt.Fprintf(tw, "%s\t%s\n", item.Address, item.summary)
+		}
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "%d succeeded, %d failed\n", r.Succeeded, r.Failed)
+}
+
+// forEachAddress runs fn for each address, recording its result or error
+func forEachAddress(ctx context.Context, action string, dryRun bool, addresses []string, fn func(ctx context.Context, address string) (interface{}, string, error)) (*addressResult, error) {
+	result := &addressResult{Action: action, DryRun: dryRun, Addresses: make([]addressItem, 0, len(addresses))}
+	for _, address := range addresses {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		item := addressItem{Address: address}
+		value, summary, err := fn(ctx, address)
+		if err != nil {
+			item.Error = err.Error()
+			result.Failed++
+		} else {
+			item.Result = value
+			item.summary = summary
+			result.Succeeded++
+		}
+		result.Addresses = append(result.Addresses, item)
+	}
+	return result, nil
+}
+
+var graphRiskScoresCommand = command{
+	group:   "graph",
+	name:    "risk-scores",
+	summary: "recompute risk scores of addresses",
+	setup: func(fs *flag.FlagSet) action {
+		list, file := addressFlags(fs)
+
+		var addresses []string
+		return action{
+			check: func() error {
+				var err error
+				addresses, err = readAddresses(*list, *file)
+				return err
+			},
+			run: func(ctx context.Context, env *env) (interface{}, error) {
+				return forEachAddress(ctx, "risk-scores", env.dryRun, addresses, func(ctx context.Context, address string) (interface{}, string, error) {
+					var (
+						score *entity.RiskScore
+						err   error
+					)
+					if env.dryRun {
+						score, err = env.Wallets.GetRiskScore(ctx, address)
+					} else {
+						// Scores are computed on read, so dropping the wallet's
+						// cached reads and reading the score recomputes it
+						score, err = env.Wallets.UpdateRiskScore(ctx, address, nil, nil)
+					}
+					if err != nil {
+						return nil, "", err
+					}
+					return score, fmt.Sprintf("%d (%s)", score.TotalScore, score.RiskLevel), nil
+				})
+			},
+		}
+	},
+}
+
+// rankingCategories are the wallet ranking categories
+var rankingCategories = []entity.RankingCategory{
+	entity.RankingCategoryQuality,
+	entity.RankingCategoryReputation,
+	entity.RankingCategoryVolume,
+	entity.RankingCategoryActivity,
+	entity.RankingCategoryAge,
+	entity.RankingCategoryNetwork,
+	entity.RankingCategorySafety,
+}
+
+// rankingsResult is the outcome of a rankings rebuild
+type rankingsResult struct {
+	DryRun bool `json:"dry_run"`
+	// Flushed is the number of cached rankings dropped, or that would be
+	Flushed int `json:"flushed"`
+	// Rebuilt is the number of wallets ranked in each category
+	Rebuilt map[string]int `json:"rebuilt"`
+}
+
+func (r *rankingsResult) printText(w io.Writer) {
+	if r.DryRun {
+		fmt.Fprintf(w, "Dry run: would flush %d cached rankings (nothing was changed)\n", r.Flushed)
+		return
+	}
+	fmt.Fprintf(w, "Flushed %d cached rankings\n", r.Flushed)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CATEGORY\tWALLETS")
+	for _, category := range sortedKeys(r.Rebuilt) {
+		fmt.Fprintf(tw, "%s\t%d\n", category, r.Rebuilt[category])
+	}
+	tw.Flush()
+}
+
+var graphRankingsCommand = command{
+	group:   "graph",
+	name:    "rankings",
+	summary: "flush and rebuild wallet and network rankings",
+	setup: func(fs *flag.FlagSet) action {
+		limit := fs.Int("limit", 100, "wallets ranked per category when rebuilding")
+
+		return action{
+			check: func() error {
+				if *limit <= 0 {
+					return errors.New("-limit must be positive")
+				}
+				return nil
+			},
+			run: func(ctx context.Context, env *env) (interface{}, error) {
+				namespaces := []string{"network_rankings", "wallet_rankings"}
+				counts, err := env.countKeys(ctx, namespaces)
+				if err != nil {
+					return nil, err
+				}
+				result := &rankingsResult{DryRun: env.dryRun, Flushed: counts.Total}
+				if env.dryRun {
+					return result, nil
+				}
+
+				result.Flushed = 0
+				for _, namespace := range namespaces {
+					flushed, err := env.Cache.InvalidateNamespace(ctx, namespace)
+					if err != nil {
+						return nil, fmt.Errorf("failed to flush %s: %w", namespace, err)
+					}
+					result.Flushed += flushed
+				}
+
+				// Network rankings are rebuilt on their next read; wallet rankings
+				// are the expensive ones, so they are warmed here
+				result.Rebuilt = make(map[string]int, len(rankingCategories))
+				for _, category := range rankingCategories {
+					rankings, err := env.Wallets.GetWalletRankings(ctx, category, nil, *limit, 0)
+					if err != nil {
+						return nil, fmt.Errorf("failed to rebuild %s rankings: %w", category, err)
+					}
+					result.Rebuilt[string(category)] = len(rankings.Rankings)
+				}
+				return result, nil
+			},
+		}
+	},
+}
+
+var graphAttributionsCommand = command{
+	group:   "graph",
+	name:    "attributions",
+	summary: "re-resolve address labels and store them on the wallets",
+	setup: func(fs *flag.FlagSet) action {
+		list, file := addressFlags(fs)
+
+		var addresses []string
+		return action{
+			check: func() error {
+				var err error
+				addresses, err = readAddresses(*list, *file)
+				return err
+			},
+			run: func(ctx context.Context, env *env) (interface{}, error) {
+				return forEachAddress(ctx, "attributions", env.dryRun, addresses, func(ctx context.Context, address string) (interface{}, string, error) {
+					attribution, err := env.Labels.Attribution(ctx, address)
+					if err != nil {
+						return nil, "", err
+					}
+					if !env.dryRun && env.Labels.Propagate(ctx, []string{address}) == 0 {
+						return nil, "", errors.New("failed to store attribution, see the logs")
+					}
+
+					summary := "no label"
+					if attribution.Label != nil {
+						summary = fmt.Sprintf("%s (%s, %d conflicts)", attribution.Label.Name, attribution.Label.Source, len(attribution.Conflicts))
+					}
+					return attribution, summary, nil
+				})
+			},
+		}
+	},
+}
+
+var graphClassifyCommand = command{
+	group:   "graph",
+	name:    "classify",
+	summary: "reclassify wallets",
+	setup: func(fs *flag.FlagSet) action {
+		list, file := addressFlags(fs)
+
+		var addresses []string
+		return action{
+			check: func() error {
+				var err error
+				addresses, err = readAddresses(*list, *file)
+				return err
+			},
+			run: func(ctx context.Context, env *env) (interface{}, error) {
+				return forEachAddress(ctx, "classify", env.dryRun, addresses, func(ctx context.Context, address string) (interface{}, string, error) {
+					var (
+						classification *entity.WalletClassification
+						err            error
+					)
+					if env.dryRun {
+						classification, err = env.Classifier.Classification(ctx, address)
+					} else {
+						classification, err = env.Classifier.Classify(ctx, address)
+					}
+					if err != nil {
+						return nil, "", err
+					}
+					if classification == nil {
+						return nil, "not classified", nil
+					}
+
+					summary := fmt.Sprintf("%s (%.2f)", classification.WalletType, classification.Confidence)
+					if env.dryRun {
+						summary = "currently " + summary
+					} else if classification.PreviousType != nil && *classification.PreviousType != classification.WalletType {
+						summary += fmt.Sprintf(", was %s", *classification.PreviousType)
+					}
+					return classification, summary, nil
+				})
+			},
+		}
+	},
+}
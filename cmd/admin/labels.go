@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/infrastructure/labels"
+)
+
+// labelImportResult is the outcome of a label pack import. A dry run parses
+// the pack only, so it reports rows and rejections but not created or
+// updated labels.
+type labelImportResult struct {
+	DryRun bool                `json:"dry_run"`
+	Import *entity.LabelImport `json:"import"`
+}
+
+func (r *labelImportResult) printText(w io.Writer) {
+	if r.DryRun {
+		fmt.Fprintf(w, "Dry run: parsed %s (nothing was changed)\n", r.Import.FileName)
+		fmt.Fprintf(w, "Rows: %d, valid: %d, rejected: %d\n", r.Import.TotalRows, r.Import.TotalRows-r.Import.Rejected, r.Import.Rejected)
+	} else {
+		fmt.Fprintf(w, "Imported %s as %s\n", r.Import.FileName, r.Import.ID)
+		fmt.Fprintf(w, "Rows: %d, created: %d, updated: %d, rejected: %d, addresses: %d\n",
+			r.Import.TotalRows, r.Import.Created, r.Import.Updated, r.Import.Rejected, r.Import.Addresses)
+	}
+	for _, rowErr := range r.Import.Errors {
+		fmt.Fprintf(w, "  %s\n", rowErr)
+	}
+}
+
+var labelsImportCommand = command{
+	group:   "labels",
+	name:    "import",
+	summary: "import a CSV or JSON label pack",
+	setup: func(fs *flag.FlagSet) action {
+		file := fs.String("file", "", "label pack to import (required)")
+		format := fs.String("format", "", "CSV or JSON, from the file extension when empty")
+		source := fs.String("source", "", "source the labels are attributed to (required)")
+		as := actorFlag(fs)
+
+		var (
+			packFormat entity.LabelPackFormat
+			data       []byte
+		)
+		return action{
+			check: func() error {
+				err := errors.Join(required("file", *file), required("source", *source), required("as", *as))
+				if err != nil {
+					return err
+				}
+				*source = strings.TrimSpace(*source)
+
+				if packFormat, err = parseLabelPackFormat(*format, *file); err != nil {
+					return err
+				}
+				if data, err = os.ReadFile(*file); err != nil {
+					return fmt.Errorf("failed to read label pack: %w", err)
+				}
+				return nil
+			},
+			run: func(ctx context.Context, env *env) (interface{}, error) {
+				user, err := env.actor(ctx, *as)
+				if err != nil {
+					return nil, err
+				}
+				fileName := filepath.Base(*file)
+
+				if env.dryRun {
+					cfg := env.Config.Labels
+					pack, err := labels.Parse(packFormat, bytes.NewReader(data), *source, cfg.DefaultConfidence, cfg.MaxImportRows)
+					if err != nil {
+						return nil, err
+					}
+					return &labelImportResult{DryRun: true, Import: &entity.LabelImport{
+						Source:     *source,
+						Format:     packFormat,
+						FileName:   fileName,
+						TotalRows:  pack.TotalRows,
+						Rejected:   pack.Rejected,
+						Errors:     pack.Errors,
+						ImportedBy: user.Email,
+					}}, nil
+				}
+
+				labelImport, err := env.Labels.Import(ctx, packFormat, fileName, *source, data, user)
+				if err != nil {
+					return nil, err
+				}
+				return &labelImportResult{Import: labelImport}, nil
+			},
+		}
+	},
+}
+
+// parseLabelPackFormat parses format, or infers it from the extension of file
+func parseLabelPackFormat(format, file string) (entity.LabelPackFormat, error) {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(file), ".")
+	}
+	switch packFormat := entity.LabelPackFormat(strings.ToUpper(format)); packFormat {
+	case entity.LabelPackFormatCSV, entity.LabelPackFormatJSON:
+		return packFormat, nil
+	default:
+		return "", fmt.Errorf("invalid label pack format %q, expected CSV or JSON", format)
+	}
+}
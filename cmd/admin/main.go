@@ -0,0 +1,328 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/infrastructure/config"
+	"crypto-bubble-map-be/internal/infrastructure/container"
+	"crypto-bubble-map-be/internal/infrastructure/logger"
+
+	"go.uber.org/fx"
+)
+
+// options are the flags every command accepts
+type options struct {
+	dryRun  bool
+	json    bool
+	verbose bool
+}
+
+// register adds the common flags to fs
+func (o *options) register(fs *flag.FlagSet) {
+	fs.BoolVar(&o.dryRun, "dry-run", o.dryRun, "report what would change without changing anything")
+	fs.BoolVar(&o.json, "json", o.json, "print the result as JSON")
+	fs.BoolVar(&o.verbose, "verbose", o.verbose, "log at the configured level instead of warnings only")
+}
+
+// env is what a running command works with
+type env struct {
+	*container.Container
+	dryRun bool
+}
+
+// runFunc runs a command and returns its result
+type runFunc func(ctx context.Context, env *env) (interface{}, error)
+
+// action is what a command does once its flags are parsed
+type action struct {
+	// check validates the flags before anything is connected to
+	check func() error
+	run   runFunc
+}
+
+// command is a subcommand such as "users create"
+type command struct {
+	group   string
+	name    string
+	summary string
+	// setup registers the command's flags and returns its action
+	setup func(fs *flag.FlagSet) action
+}
+
+// textResult is a result with a human readable form. Results without one are
+// printed as JSON.
+type textResult interface {
+	printText(w io.Writer)
+}
+
+// commands are the admin commands in usage order
+var commands = []command{
+	usersCreateCommand,
+	usersGetCommand,
+	usersSetRoleCommand,
+	usersSetActiveCommand,
+	cacheFlushCommand,
+	cacheCountCommand,
+	graphRiskScoresCommand,
+	graphRankingsCommand,
+	graphAttributionsCommand,
+	graphClassifyCommand,
+	labelsImportCommand,
+	reportsRegenerateCommand,
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `Usage: admin [flags] <group> <command> [command flags]
+
+Commands:
+`)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-26s %s\n", cmd.group+" "+cmd.name, cmd.summary)
+	}
+	fmt.Fprint(w, `
+Flags, accepted before the group or after the command:
+  -dry-run    report what would change without changing anything
+  -json       print the result as JSON
+  -verbose    log at the configured level instead of warnings only
+
+Run "admin <group> <command> -h" for the flags of a command.
+`)
+}
+
+func main() {
+	var opts options
+	root := flag.NewFlagSet("admin", flag.ContinueOnError)
+	root.Usage = func() { usage(root.Output()) }
+	opts.register(root)
+	if err := root.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+
+	args := root.Args()
+	if len(args) < 2 {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+	cmd, ok := findCommand(args[0], args[1])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", strings.Join(args[:2], " "))
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("admin "+cmd.group+" "+cmd.name, flag.ContinueOnError)
+	act := cmd.setup(fs)
+	opts.register(fs)
+	if err := fs.Parse(args[2:]); err != nil {
+		os.Exit(2)
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		os.Exit(2)
+	}
+	if act.check != nil {
+		if err := act.check(); err != nil {
+			fail(err, opts, 2)
+		}
+	}
+
+	result, err := execute(act.run, opts)
+	if err != nil {
+		fail(err, opts, 1)
+	}
+
+	if opts.json {
+		printJSON(os.Stdout, result)
+	} else if text, ok := result.(textResult); ok {
+		text.printText(os.Stdout)
+	} else {
+		printJSON(os.Stdout, result)
+	}
+}
+
+// findCommand returns the command named by group and name
+func findCommand(group, name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.group == group && cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// execute builds the application container and runs a command with it. The
+// container is built but not started, so no background workers, schedulers or
+// migrations run; the datastore clients connect on first use.
+func execute(run runFunc, opts options) (interface{}, error) {
+	var c *container.Container
+	app := container.NewContainer(
+		fx.NopLogger,
+		fx.Decorate(func(cfg *config.Config) (*logger.Logger, error) {
+			return newLogger(cfg, opts.verbose)
+		}),
+		fx.Populate(&c),
+	)
+	if err := app.Err(); err != nil {
+		return nil, fmt.Errorf("failed to initialize application: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	defer c.Close(context.Background())
+
+	return run(ctx, &env{Container: c, dryRun: opts.dryRun})
+}
+
+// newLogger creates the logger of the container. Logs go to stderr so that
+// they never mix with the result on stdout, and only warnings and errors are
+// logged unless verbose.
+func newLogger(cfg *config.Config, verbose bool) (*logger.Logger, error) {
+	level := cfg.App.LogLevel
+	if !verbose {
+		level = "warn"
+	}
+	return logger.NewLogger(&logger.Config{
+		Level:       level,
+		Environment: cfg.App.Environment,
+		Debug:       cfg.App.Debug && verbose,
+		Output:      "stderr",
+	})
+}
+
+// fail reports err, as JSON on stdout in JSON mode, and exits with code
+func fail(err error, opts options, code int) {
+	if opts.json {
+		printJSON(os.Stdout, map[string]string{"error": err.Error()})
+	} else {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	os.Exit(code)
+}
+
+// printJSON prints v as indented JSON
+func printJSON(w io.Writer, v interface{}) {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode result: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// actorFlag registers the -as flag naming the user a command acts as
+func actorFlag(fs *flag.FlagSet) *string {
+	return fs.String("as", "", "email of the user the change is recorded as (required)")
+}
+
+// actor returns the active user with email, whom changes are attributed to
+func (e *env) actor(ctx context.Context, email string) (*entity.User, error) {
+	user, err := e.user(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, fmt.Errorf("user %s is not active", email)
+	}
+	return user, nil
+}
+
+// addressFlags registers the flags selecting the addresses a command works on
+func addressFlags(fs *flag.FlagSet) (list, file *string) {
+	list = fs.String("addresses", "", "comma separated addresses")
+	file = fs.String("file", "", `file with one address per line, "-" for stdin; blank lines and lines starting with # are skipped`)
+	return list, file
+}
+
+// readAddresses returns the normalized, deduplicated addresses of the
+// -addresses and -file flags, in the order given
+func readAddresses(list, file string) ([]string, error) {
+	raw := strings.Split(list, ",")
+
+	if file != "" {
+		var r io.Reader = os.Stdin
+		if file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open address file: %w", err)
+			}
+			defer f.Close()
+			r = f
+		}
+
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			raw = append(raw, line)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read address file: %w", err)
+		}
+	}
+
+	seen := make(map[string]bool, len(raw))
+	var addresses, invalid []string
+	for _, address := range raw {
+		address = entity.NormalizeAddress(address)
+		if address == "" || seen[address] {
+			continue
+		}
+		seen[address] = true
+		if !entity.IsValidAddress(address) {
+			invalid = append(invalid, address)
+			continue
+		}
+		addresses = append(addresses, address)
+	}
+
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("invalid addresses: %s", strings.Join(invalid, ", "))
+	}
+	if len(addresses) == 0 {
+		return nil, errors.New("no addresses given, use -addresses or -file")
+	}
+	return addresses, nil
+}
+
+// required returns an error if the value of the flag name is blank
+func required(name, value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("-%s is required", name)
+	}
+	return nil
+}
+
+// splitList splits a comma separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+)
+
+// reportResult is the outcome of a report regeneration. Report is the new
+// report, or nil on a dry run.
+type reportResult struct {
+	DryRun   bool                     `json:"dry_run"`
+	Original *entity.ComplianceReport `json:"original"`
+	Report   *entity.ComplianceReport `json:"report,omitempty"`
+}
+
+func (r *reportResult) printText(w io.Writer) {
+	if r.DryRun {
+		fmt.Fprintln(w, "Dry run: would generate a new draft of this report (nothing was changed)")
+		printReport(w, r.Original)
+		return
+	}
+	fmt.Fprintf(w, "Regenerated %s\n", r.Original.ID)
+	printReport(w, r.Report)
+}
+
+// printReport prints the identifying fields of a report
+func printReport(w io.Writer, report *entity.ComplianceReport) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID:\t%s\n", report.ID)
+	fmt.Fprintf(tw, "Wallet:\t%s\n", report.WalletAddress)
+	fmt.Fprintf(tw, "Type:\t%s\n", report.ReportType)
+	fmt.Fprintf(tw, "Status:\t%s\n", report.Status)
+	fmt.Fprintf(tw, "Time range:\t%s to %s\n", report.TimeRange.Start.Format(time.RFC3339), report.TimeRange.End.Format(time.RFC3339))
+	fmt.Fprintf(tw, "Generated:\t%s by %s\n", report.GeneratedAt.Format(time.RFC3339), report.GeneratedBy)
+	tw.Flush()
+}
+
+var reportsRegenerateCommand = command{
+	group:   "reports",
+	name:    "regenerate",
+	summary: "generate a new draft of a compliance report",
+	setup: func(fs *flag.FlagSet) action {
+		id := fs.String("id", "", "ID of the report to regenerate (required)")
+		as := actorFlag(fs)
+
+		return action{
+			check: func() error {
+				*id = strings.TrimSpace(*id)
+				return errors.Join(required("id", *id), required("as", *as))
+			},
+			run: func(ctx context.Context, env *env) (interface{}, error) {
+				user, err := env.actor(ctx, *as)
+				if err != nil {
+					return nil, err
+				}
+
+				original, err := env.Compliance.Report(ctx, *id)
+				if err != nil {
+					return nil, err
+				}
+				if env.dryRun {
+					return &reportResult{DryRun: true, Original: original}, nil
+				}
+
+				report, err := env.Compliance.RegenerateReport(ctx, *id, user)
+				if err != nil {
+					return nil, err
+				}
+				return &reportResult{Original: original, Report: report}, nil
+			},
+		}
+	},
+}
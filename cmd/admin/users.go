@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// minPasswordLength is the shortest password accepted for a new user
+const minPasswordLength = 12
+
+// userResult is the outcome of a user command
+type userResult struct {
+	Action string       `json:"action"`
+	DryRun bool         `json:"dry_run"`
+	User   *entity.User `json:"user"`
+	// Password is set when the password was generated, as it is shown only once
+	Password string `json:"password,omitempty"`
+	// Changed reports whether the user was changed, or would be on a dry run
+	Changed bool `json:"changed"`
+}
+
+func (r *userResult) printText(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if r.DryRun {
+		fmt.Fprintf(tw, "Dry run:\t%s (nothing was changed)\n", r.Action)
+	} else if r.Action != "get" && !r.Changed {
+		fmt.Fprintf(tw, "Action:\t%s (already up to date)\n", r.Action)
+	} else if r.Action != "get" {
+		fmt.Fprintf(tw, "Action:\t%s\n", r.Action)
+	}
+	fmt.Fprintf(tw, "ID:\t%d\n", r.User.ID)
+	fmt.Fprintf(tw, "Email:\t%s\n", r.User.Email)
+	fmt.Fprintf(tw, "Role:\t%s\n", r.User.Role)
+	fmt.Fprintf(tw, "Active:\t%t\n", r.User.IsActive)
+	fmt.Fprintf(tw, "Email verified:\t%t\n", r.User.EmailVerified)
+	if r.User.LastLoginAt != nil {
+		fmt.Fprintf(tw, "Last login:\t%s\n", r.User.LastLoginAt.Format(time.RFC3339))
+	}
+	if !r.User.CreatedAt.IsZero() {
+		fmt.Fprintf(tw, "Created:\t%s\n", r.User.CreatedAt.Format(time.RFC3339))
+	}
+	if r.Password != "" {
+		fmt.Fprintf(tw, "Password:\t%s (generated, store it now)\n", r.Password)
+	}
+	tw.Flush()
+}
+
+var usersCreateCommand = command{
+	group:   "users",
+	name:    "create",
+	summary: "create a user, e.g. the first admin",
+	setup: func(fs *flag.FlagSet) action {
+		email := fs.String("email", "", "email of the user (required)")
+		role := fs.String("role", string(entity.UserRoleAdmin), "role: USER, ADMIN, MODERATOR or ANALYST")
+		firstName := fs.String("first-name", "", "first name")
+		lastName := fs.String("last-name", "", "last name")
+		passwordStdin := fs.Bool("password-stdin", false, "read the password from the first line of stdin instead of generating one")
+
+		var userRole entity.UserRole
+		return action{
+			check: func() error {
+				*email = strings.TrimSpace(*email)
+				if !strings.Contains(*email, "@") {
+					return errors.New("-email must be an email address")
+				}
+				var err error
+				userRole, err = parseRole(*role)
+				return err
+			},
+			run: func(ctx context.Context, env *env) (interface{}, error) {
+				existing, err := env.Users.GetUserByEmail(ctx, *email)
+				if err != nil {
+					return nil, err
+				}
+				if existing != nil {
+					return nil, fmt.Errorf("user %s already exists", *email)
+				}
+
+				user := &entity.User{
+					Email:         *email,
+					Role:          userRole,
+					IsActive:      true,
+					EmailVerified: true,
+				}
+				if *firstName != "" {
+					user.FirstName = firstName
+				}
+				if *lastName != "" {
+					user.LastName = lastName
+				}
+
+				result := &userResult{Action: "create", DryRun: env.dryRun, User: user, Changed: true}
+				if env.dryRun {
+					return result, nil
+				}
+
+				var password string
+				if *passwordStdin {
+					password, err = readPassword(os.Stdin)
+				} else {
+					password, err = generatePassword()
+					result.Password = password
+				}
+				if err != nil {
+					return nil, err
+				}
+
+				hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+				if err != nil {
+					return nil, fmt.Errorf("failed to hash password: %w", err)
+				}
+				now := time.Now()
+				user.PasswordHash = string(hash)
+				user.EmailVerifiedAt = &now
+
+				if err := env.Users.CreateUser(ctx, user); err != nil {
+					return nil, err
+				}
+				return result, nil
+			},
+		}
+	},
+}
+
+var usersGetCommand = command{
+	group:   "users",
+	name:    "get",
+	summary: "show a user",
+	setup: func(fs *flag.FlagSet) action {
+		email := fs.String("email", "", "email of the user (required)")
+
+		return action{
+			check: func() error {
+				return required("email", *email)
+			},
+			run: func(ctx context.Context, env *env) (interface{}, error) {
+				user, err := env.user(ctx, *email)
+				if err != nil {
+					return nil, err
+				}
+				return &userResult{Action: "get", User: user}, nil
+			},
+		}
+	},
+}
+
+var usersSetRoleCommand = command{
+	group:   "users",
+	name:    "set-role",
+	summary: "change the role of a user",
+	setup: func(fs *flag.FlagSet) action {
+		email := fs.String("email", "", "email of the user (required)")
+		role := fs.String("role", "", "new role: USER, ADMIN, MODERATOR or ANALYST (required)")
+
+		var userRole entity.UserRole
+		return action{
+			check: func() error {
+				if err := required("email", *email); err != nil {
+					return err
+				}
+				var err error
+				userRole, err = parseRole(*role)
+				return err
+			},
+			run: func(ctx context.Context, env *env) (interface{}, error) {
+				return env.updateUser(ctx, *email, "set-role", func(user *entity.User) bool {
+					if user.Role == userRole {
+						return false
+					}
+					user.Role = userRole
+					return true
+				})
+			},
+		}
+	},
+}
+
+var usersSetActiveCommand = command{
+	group:   "users",
+	name:    "set-active",
+	summary: "activate or deactivate a user",
+	setup: func(fs *flag.FlagSet) action {
+		email := fs.String("email", "", "email of the user (required)")
+		active := fs.Bool("active", true, "whether the user may sign in")
+
+		return action{
+			check: func() error {
+				return required("email", *email)
+			},
+			run: func(ctx context.Context, env *env) (interface{}, error) {
+				return env.updateUser(ctx, *email, "set-active", func(user *entity.User) bool {
+					if user.IsActive == *active {
+						return false
+					}
+					user.IsActive = *active
+					return true
+				})
+			},
+		}
+	},
+}
+
+// user returns the user with email
+func (e *env) user(ctx context.Context, email string) (*entity.User, error) {
+	email = strings.TrimSpace(email)
+	user, err := e.Users.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user %s not found", email)
+	}
+	return user, nil
+}
+
+// updateUser applies change to the user with email and saves the user if
+// change reports a difference
+func (e *env) updateUser(ctx context.Context, email, action string, change func(user *entity.User) bool) (*userResult, error) {
+	user, err := e.user(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &userResult{Action: action, DryRun: e.dryRun, User: user, Changed: change(user)}
+	if !result.Changed || e.dryRun {
+		return result, nil
+	}
+
+	if err := e.Users.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// parseRole parses a user role
+func parseRole(role string) (entity.UserRole, error) {
+	switch userRole := entity.UserRole(strings.ToUpper(strings.TrimSpace(role))); userRole {
+	case entity.UserRoleUser, entity.UserRoleAdmin, entity.UserRoleModerator, entity.UserRoleAnalyst:
+		return userRole, nil
+	default:
+		return "", fmt.Errorf("invalid role %q, expected USER, ADMIN, MODERATOR or ANALYST", role)
+	}
+}
+
+// readPassword reads a password from the first line of r
+func readPassword(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if len(password) < minPasswordLength {
+		return "", fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	return password, nil
+}
+
+// generatePassword returns a random password
+func generatePassword() (string, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate password: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"crypto-bubble-map-be/internal/infrastructure/config"
+	"crypto-bubble-map-be/internal/infrastructure/database"
+	"crypto-bubble-map-be/internal/infrastructure/logger"
+	"crypto-bubble-map-be/internal/infrastructure/migrations"
+)
+
+const usage = `Usage: migrate [flags] <command>
+
+Commands:
+  up       apply pending migrations
+  down     revert the last applied migrations (-steps, default 1)
+  status   show applied and pending migrations
+
+Flags:
+`
+
+func main() {
+	store := flag.String("store", "", "only migrate this store: postgresql, mongodb or neo4j")
+	steps := flag.Int("steps", 1, "number of migrations to revert with down")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	switch flag.Arg(0) {
+	case "up", "down", "status":
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", flag.Arg(0))
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Arg(0), *store, *steps); err != nil {
+		fmt.Fprintf(os.Stderr, "Migration failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run connects to the datastores and runs command
+func run(command, store string, steps int) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	log, err := logger.NewLogger(&logger.Config{
+		Level:       cfg.App.LogLevel,
+		Environment: cfg.App.Environment,
+		Debug:       cfg.App.Debug,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer log.Sync()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// The clients connect lazily, so stores that are not migrated are not
+	// contacted
+	neo4jClient, err := database.NewNeo4jClient(&cfg.Database.Neo4j, nil, nil, log.Logger)
+	if err != nil {
+		return fmt.Errorf("failed to initialize Neo4j: %w", err)
+	}
+	defer neo4jClient.Close(context.Background())
+
+	mongoClient, err := database.NewMongoClient(&cfg.Database.MongoDB, nil, nil, log.Logger)
+	if err != nil {
+		return fmt.Errorf("failed to initialize MongoDB: %w", err)
+	}
+	defer mongoClient.Close(context.Background())
+
+	postgresClient, err := database.NewPostgreSQLClient(&cfg.Database.PostgreSQL, nil, nil, log.Logger)
+	if err != nil {
+		return fmt.Errorf("failed to initialize PostgreSQL: %w", err)
+	}
+	defer postgresClient.Close()
+
+	runner := migrations.NewDatastoreRunner(&cfg.Database.Migrations, postgresClient, mongoClient, neo4jClient, log.Logger)
+
+	switch command {
+	case "up":
+		return runner.Up(ctx, store)
+	case "down":
+		return runner.Down(ctx, store, steps)
+	case "status":
+		statuses, err := runner.Status(ctx, store)
+		if err != nil {
+			return err
+		}
+		printStatus(statuses)
+	}
+	return nil
+}
+
+// printStatus prints the migrations of each store as a table
+func printStatus(statuses []migrations.StoreStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STORE\tVERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, status := range statuses {
+		for _, migration := range status.Migrations {
+			appliedAt := "-"
+			if migration.AppliedAt != nil {
+				appliedAt = migration.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", status.Store, migration.Version, migration.Name, migration.State, appliedAt)
+		}
+	}
+	w.Flush()
+}
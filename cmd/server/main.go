@@ -10,18 +10,33 @@ import (
 	"time"
 
 	"crypto-bubble-map-be/graph"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/assistant"
 	"crypto-bubble-map-be/internal/infrastructure/cache"
+	"crypto-bubble-map-be/internal/infrastructure/cases"
+	"crypto-bubble-map-be/internal/infrastructure/classification"
+	"crypto-bubble-map-be/internal/infrastructure/compliance"
 	"crypto-bubble-map-be/internal/infrastructure/config"
 	"crypto-bubble-map-be/internal/infrastructure/database"
+	"crypto-bubble-map-be/internal/infrastructure/detection"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
 	"crypto-bubble-map-be/internal/infrastructure/external"
 	"crypto-bubble-map-be/internal/infrastructure/health"
+	"crypto-bubble-map-be/internal/infrastructure/labels"
 	"crypto-bubble-map-be/internal/infrastructure/logger"
 	"crypto-bubble-map-be/internal/infrastructure/middleware"
+	"crypto-bubble-map-be/internal/infrastructure/migrations"
 	"crypto-bubble-map-be/internal/infrastructure/monitoring"
 	repoImpl "crypto-bubble-map-be/internal/infrastructure/repository"
+	"crypto-bubble-map-be/internal/infrastructure/resilience"
+	"crypto-bubble-map-be/internal/infrastructure/sanctions"
+	"crypto-bubble-map-be/internal/infrastructure/screening"
+	"crypto-bubble-map-be/internal/infrastructure/search"
 	"crypto-bubble-map-be/internal/interfaces/graphql"
+	"crypto-bubble-map-be/internal/interfaces/rest"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
 )
 
@@ -34,11 +49,20 @@ type Server struct {
 	postgresql         *database.PostgreSQLClient
 	redis              *cache.RedisClient
 	httpServer         *http.Server
+	metricsServer      *http.Server
 	resolver           *graph.Resolver
+	screeningService   *screening.Service
+	userRepo           repository.UserRepository
 	performanceMonitor *monitoring.PerformanceMonitor
+	cacheAside         *cache.Aside
+	tracing            *monitoring.Tracing
 	systemMetrics      *monitoring.SystemMetrics
 	healthManager      *health.HealthManager
 	metricsCollector   *monitoring.MetricsCollector
+	errorCollector     *apperrors.ErrorCollector
+	detectionRunner    *detection.Runner
+	classifierService  *classification.Service
+	labelService       *labels.Service
 }
 
 // NewServer creates a new server instance
@@ -72,60 +96,97 @@ func NewServer() (*Server, error) {
 		zap.String("log_level", cfg.App.LogLevel),
 	)
 
+	// Initialize tracing before the database clients so their first spans are exported
+	tracing, err := monitoring.NewTracing(&cfg.Monitoring, cfg.App.Environment, log.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+
+	// Initialize monitoring before the database clients, which report query latency
+	metricsCollector := monitoring.NewMetricsCollector(log.Logger)
+	performanceMonitor := monitoring.NewPerformanceMonitor(metricsCollector, log.Logger)
+	systemMetrics := monitoring.NewSystemMetrics(metricsCollector, log.Logger)
+	errorCollector := apperrors.NewErrorCollector()
+
+	// One circuit breaker per dependency, created by the clients that use it
+	breakers := resilience.NewRegistry(&cfg.Resilience, performanceMonitor, log.Logger)
+
 	// Initialize databases
-	neo4jClient, err := database.NewNeo4jClient(&cfg.Database.Neo4j, log.Logger)
+	neo4jClient, err := database.NewNeo4jClient(&cfg.Database.Neo4j, performanceMonitor, breakers, log.Logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize Neo4j: %w", err)
 	}
 
-	mongoClient, err := database.NewMongoClient(&cfg.Database.MongoDB, log.Logger)
+	mongoClient, err := database.NewMongoClient(&cfg.Database.MongoDB, performanceMonitor, breakers, log.Logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize MongoDB: %w", err)
 	}
 
-	postgresClient, err := database.NewPostgreSQLClient(&cfg.Database.PostgreSQL, log.Logger)
+	postgresClient, err := database.NewPostgreSQLClient(&cfg.Database.PostgreSQL, performanceMonitor, breakers, log.Logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize PostgreSQL: %w", err)
 	}
 
-	// Run database migrations
-	if err := postgresClient.AutoMigrate(); err != nil {
-		return nil, fmt.Errorf("failed to run database migrations: %w", err)
-	}
-
 	// Initialize Redis cache
-	redisClient, err := cache.NewRedisClient(&cfg.Cache.Redis, &cfg.Cache.TTL, log.Logger)
+	redisClient, err := cache.NewRedisClient(&cfg.Cache.Redis, &cfg.Cache.TTL, breakers, log.Logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize Redis: %w", err)
 	}
 
-	// Create MongoDB indexes
-	if err := mongoClient.CreateIndexes(context.Background()); err != nil {
-		log.Warn("Failed to create MongoDB indexes", zap.Error(err))
+	// Check the datastores and migrate those that are reachable; the others
+	// are migrated by the health manager once they recover, and the server
+	// starts degraded unless every datastore is required
+	healthManager := health.NewHealthManager(cfg, log.Logger)
+	health.SetupHealthCheckers(healthManager, postgresClient, mongoClient, neo4jClient, redisClient, breakers, cfg, log.Logger)
+
+	var initTasks []health.InitTask
+	if cfg.Database.Migrations.OnStartup {
+		migrationRunner := migrations.NewDatastoreRunner(&cfg.Database.Migrations, postgresClient, mongoClient, neo4jClient, log.Logger)
+		initTasks = health.DatastoreInitTasks(migrationRunner)
+	}
+	if err := healthManager.Initialize(context.Background(), cfg.App.RequireDatastores, initTasks...); err != nil {
+		return nil, fmt.Errorf("failed to initialize datastores: %w", err)
 	}
 
-	// Initialize repositories with real implementations
-	walletRepo := repoImpl.NewNeo4jWalletRepository(neo4jClient, log.Logger)
-	transactionRepo := repoImpl.NewMongoTransactionRepository(mongoClient, log.Logger)
+	// Initialize repositories with real implementations, reading through the cache
+	cacheAside := cache.NewAside(redisClient, &cfg.Cache, performanceMonitor, log.Logger)
+	walletRepo := repoImpl.NewCachedWalletRepository(
+		repoImpl.NewNeo4jWalletRepository(neo4jClient, log.Logger), cacheAside, &cfg.Cache.TTL, log.Logger)
+	transactionRepo := repoImpl.NewCachedTransactionRepository(
+		repoImpl.NewMongoTransactionRepository(mongoClient, log.Logger), cacheAside, &cfg.Cache.TTL, log.Logger)
 
 	// Create blockchain API client for NetworkRepository
-	apiClient := external.NewBlockchainAPIClient(&cfg.External, log.Logger)
-	networkRepo := repoImpl.NewNetworkRepository(neo4jClient, mongoClient, apiClient, log.Logger)
+	apiClient := external.NewBlockchainAPIClient(&cfg.External, breakers, log.Logger)
+	networkRepo := repoImpl.NewCachedNetworkRepository(
+		repoImpl.NewNetworkRepository(neo4jClient, mongoClient, apiClient, log.Logger), cacheAside, &cfg.Cache.TTL, log.Logger)
 
-	watchListRepo := repoImpl.NewPostgreSQLWatchListRepository(postgresClient, log.Logger)
+	watchListRepo := repoImpl.NewGuardedWatchListRepository(
+		repoImpl.NewPostgreSQLWatchListRepository(postgresClient, log.Logger),
+		func() bool { return healthManager.CapabilityAvailable(health.CapabilityWatchLists) }, log.Logger)
 	securityRepo := repoImpl.NewMongoSecurityRepository(mongoClient, log.Logger)
 	userRepo := repoImpl.NewPostgreSQLUserRepository(postgresClient, log.Logger)
 	cacheRepo := repoImpl.NewRedisCacheRepository(redisClient, log.Logger)
-	aiRepo := repoImpl.NewOpenAIRepository(&cfg.External, log.Logger)
-
-	// Initialize monitoring and health systems
-	metricsCollector := monitoring.NewMetricsCollector(log.Logger)
-	performanceMonitor := monitoring.NewPerformanceMonitor(metricsCollector, log.Logger)
-	systemMetrics := monitoring.NewSystemMetrics(metricsCollector, log.Logger)
-
-	// Initialize health manager
-	healthManager := health.NewHealthManager(cfg, log.Logger)
-	health.SetupHealthCheckers(healthManager, postgresClient, mongoClient, neo4jClient, redisClient, cfg, log.Logger)
+	aiRepo := repoImpl.NewOpenAIRepository(&cfg.External, walletRepo, transactionRepo, securityRepo, breakers, log.Logger)
+	sanctionsRepo := repoImpl.NewMongoSanctionsRepository(mongoClient, neo4jClient, log.Logger)
+	screeningRepo := repoImpl.NewMongoScreeningRepository(mongoClient, log.Logger)
+	conversationRepo := repoImpl.NewPostgreSQLAIConversationRepository(postgresClient, log.Logger)
+	aiUsageRepo := repoImpl.NewPostgreSQLAIUsageRepository(postgresClient, log.Logger)
+	caseRepo := repoImpl.NewMongoCaseRepository(mongoClient, log.Logger)
+	mevRepo := repoImpl.NewMongoMEVRepository(mongoClient, log.Logger)
+	classificationRepo := repoImpl.NewMongoClassificationRepository(mongoClient, log.Logger)
+	labelRepo := repoImpl.NewMongoLabelRepository(mongoClient, log.Logger)
+	labelProposalRepo := repoImpl.NewMongoLabelProposalRepository(mongoClient, log.Logger)
+
+	// Initialize services
+	sanctionsService := sanctions.NewService(sanctionsRepo, securityRepo, watchListRepo, &cfg.Compliance, log.Logger)
+	screeningService := screening.NewService(walletRepo, screeningRepo, sanctionsService, &cfg.Compliance, log.Logger)
+	assistantService := assistant.NewService(conversationRepo, aiUsageRepo, userRepo, aiRepo, cacheRepo, &cfg.External, metricsCollector, log.Logger)
+	complianceService := compliance.NewService(transactionRepo, walletRepo, securityRepo, sanctionsRepo, aiRepo, assistantService, &cfg.Compliance, log.Logger)
+	casesService := cases.NewService(caseRepo, securityRepo, userRepo, &cfg.Compliance, log.Logger)
+	detectionRunner := detection.NewRunner(transactionRepo, walletRepo, sanctionsRepo, securityRepo, mevRepo, &cfg.Detection, metricsCollector, log.Logger)
+	classifierService := classification.NewService(walletRepo, transactionRepo, classificationRepo, mevRepo, sanctionsRepo, securityRepo, &cfg.Classifier, metricsCollector, log.Logger)
+	labelService := labels.NewService(labelRepo, labelProposalRepo, walletRepo, &cfg.Labels, log.Logger)
+	searchService := search.NewService(walletRepo, transactionRepo, labelRepo, log.Logger)
 
 	// Create GraphQL resolver with real repositories
 	resolver := graph.NewResolver(
@@ -137,6 +198,15 @@ func NewServer() (*Server, error) {
 		userRepo,
 		cacheRepo,
 		aiRepo,
+		mevRepo,
+		sanctionsService,
+		screeningService,
+		complianceService,
+		assistantService,
+		casesService,
+		classifierService,
+		labelService,
+		searchService,
 		redisClient,
 		log,
 	)
@@ -149,10 +219,18 @@ func NewServer() (*Server, error) {
 		postgresql:         postgresClient,
 		redis:              redisClient,
 		resolver:           resolver,
+		screeningService:   screeningService,
+		userRepo:           userRepo,
 		performanceMonitor: performanceMonitor,
+		cacheAside:         cacheAside,
+		tracing:            tracing,
 		systemMetrics:      systemMetrics,
 		healthManager:      healthManager,
 		metricsCollector:   metricsCollector,
+		errorCollector:     errorCollector,
+		detectionRunner:    detectionRunner,
+		classifierService:  classifierService,
+		labelService:       labelService,
 	}
 
 	// Setup HTTP server
@@ -171,7 +249,9 @@ func (s *Server) setupHTTPServer() error {
 	// Create Gin router
 	router := gin.New()
 
-	// Add comprehensive middleware stack
+	// Add comprehensive middleware stack; the request span continues any
+	// incoming W3C trace context
+	router.Use(otelgin.Middleware(s.config.Monitoring.TracingServiceName))
 	router.Use(middleware.CorrelationIDMiddleware())
 	router.Use(middleware.DetailedLoggingMiddleware(s.logger.Logger, s.performanceMonitor))
 	router.Use(middleware.ErrorHandlerMiddleware(s.logger.Logger, s.performanceMonitor))
@@ -180,9 +260,12 @@ func (s *Server) setupHTTPServer() error {
 	router.Use(middleware.PerformanceLoggingMiddleware(s.logger.Logger, 5*time.Second))
 	router.Use(middleware.TimeoutMiddleware(30*time.Second, s.logger.Logger))
 	router.Use(s.corsMiddleware())
+	router.Use(middleware.AuthenticationMiddleware(s.userRepo, s.logger.Logger))
 
 	if s.config.Security.EnableRateLimiting {
-		router.Use(s.rateLimitMiddleware())
+		// GraphQL requests are charged by operation cost once the query is parsed
+		limiter := middleware.NewRateLimiter(s.redis, &s.config.Security, s.logger.Logger)
+		router.Use(middleware.RateLimitMiddleware(limiter, s.logger.Logger, "/graphql"))
 	}
 
 	// Health check endpoints
@@ -193,13 +276,33 @@ func (s *Server) setupHTTPServer() error {
 	router.GET("/metrics/prometheus", s.prometheusMetricsHandler)
 
 	// GraphQL endpoint
-	graphqlHandler := graphql.NewHandler(s.resolver, s.logger)
-	router.POST("/graphql", graphqlHandler.GraphQLHandler())
+	graphqlHandler := graphql.NewHandler(s.resolver, s.userRepo, &s.config.GraphQL, &s.config.Server, &s.config.App,
+		s.performanceMonitor, s.errorCollector, s.logger)
+	graphqlServer := graphqlHandler.GraphQLHandler()
+	router.POST("/graphql", graphqlServer)
+	// Websocket upgrades for subscriptions
+	router.GET("/graphql", graphqlServer)
 
 	if s.config.GraphQL.PlaygroundEnabled {
 		router.GET("/playground", graphqlHandler.PlaygroundHandler())
 	}
 
+	// Bulk screening file upload and result downloads
+	screeningHandler := rest.NewScreeningHandler(s.screeningService, s.logger.Logger)
+	screeningHandler.RegisterRoutes(router.Group(screening.RoutePrefix))
+
+	// Prometheus scrapes a separate listener so that metrics stay off the public port
+	if s.config.Monitoring.EnableMetrics && s.config.Monitoring.MetricsPort != s.config.Server.Port {
+		metricsMux := http.NewServeMux()
+		metricsMux.HandleFunc("/metrics", s.prometheusHandler)
+		s.metricsServer = &http.Server{
+			Addr:         fmt.Sprintf("%s:%d", s.config.Server.Host, s.config.Monitoring.MetricsPort),
+			Handler:      metricsMux,
+			ReadTimeout:  5 * time.Second,
+			WriteTimeout: 10 * time.Second,
+		}
+	}
+
 	// Create HTTP server
 	s.httpServer = &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", s.config.Server.Host, s.config.Server.Port),
@@ -226,6 +329,28 @@ func (s *Server) Start() error {
 		}
 	}()
 
+	if s.metricsServer != nil {
+		go func() {
+			if err := s.metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				s.logger.Error("Metrics server failed", zap.Error(err))
+			}
+		}()
+		s.logger.Info("Serving Prometheus metrics", zap.String("addr", s.metricsServer.Addr))
+	}
+
+	s.healthManager.Start(s.config.Monitoring.HealthCheckInterval)
+	s.cacheAside.Start()
+	s.screeningService.Start()
+	if s.config.App.EnableBackgroundJobs && s.config.Detection.Enabled {
+		s.detectionRunner.Start()
+	}
+	if s.config.App.EnableBackgroundJobs && s.config.Classifier.Enabled {
+		s.classifierService.Start()
+	}
+	if s.config.App.EnableBackgroundJobs {
+		s.labelService.Start()
+	}
+
 	s.logger.Info("Server started successfully", zap.String("addr", s.httpServer.Addr))
 	return nil
 }
@@ -239,6 +364,19 @@ func (s *Server) Stop(ctx context.Context) error {
 		s.logger.Error("Failed to shutdown HTTP server", zap.Error(err))
 		return err
 	}
+	if s.metricsServer != nil {
+		if err := s.metricsServer.Shutdown(ctx); err != nil {
+			s.logger.Error("Failed to shutdown metrics server", zap.Error(err))
+		}
+	}
+
+	// Let in-flight background runs finish before closing their databases
+	s.screeningService.Stop()
+	s.detectionRunner.Stop()
+	s.classifierService.Stop()
+	s.labelService.Stop()
+	s.cacheAside.Stop()
+	s.healthManager.Stop()
 
 	// Close database connections
 	if err := s.neo4j.Close(ctx); err != nil {
@@ -257,6 +395,11 @@ func (s *Server) Stop(ctx context.Context) error {
 		s.logger.Error("Failed to close Redis connection", zap.Error(err))
 	}
 
+	// Flush the spans of the shutdown itself
+	if err := s.tracing.Shutdown(ctx); err != nil {
+		s.logger.Error("Failed to shutdown tracing", zap.Error(err))
+	}
+
 	// Close logger
 	if err := s.logger.Close(); err != nil {
 		fmt.Printf("Failed to close logger: %v\n", err)
@@ -277,57 +420,49 @@ func (s *Server) healthHandler(c *gin.Context) {
 	})
 }
 
-// readinessHandler handles readiness check requests
+// readinessHandler handles readiness check requests. The server stays ready
+// while datastores are down, as long as any capability can be served; the
+// capabilities currently degraded or unavailable are listed.
 func (s *Server) readinessHandler(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	checks := map[string]string{
-		"neo4j":      "healthy",
-		"mongodb":    "healthy",
-		"postgresql": "healthy",
-		"redis":      "healthy",
-	}
+	systemHealth := s.healthManager.CheckHealth(ctx)
 
-	// Check Neo4j
-	if err := s.neo4j.Health(ctx); err != nil {
-		checks["neo4j"] = "unhealthy"
-		s.logger.Error("Neo4j health check failed", zap.Error(err))
+	checks := make(map[string]string, len(systemHealth.Components))
+	for name, component := range systemHealth.Components {
+		checks[name] = string(component.Status)
 	}
 
-	// Check MongoDB
-	if err := s.mongodb.Health(ctx); err != nil {
-		checks["mongodb"] = "unhealthy"
-		s.logger.Error("MongoDB health check failed", zap.Error(err))
-	}
-
-	// Check PostgreSQL
-	if err := s.postgresql.Health(ctx); err != nil {
-		checks["postgresql"] = "unhealthy"
-		s.logger.Error("PostgreSQL health check failed", zap.Error(err))
-	}
-
-	// Check Redis
-	if err := s.redis.Health(ctx); err != nil {
-		checks["redis"] = "unhealthy"
-		s.logger.Error("Redis health check failed", zap.Error(err))
+	capabilities := s.healthManager.Capabilities()
+	degraded := make([]string, 0)
+	available := 0
+	for _, capability := range capabilities {
+		if capability.Status != health.StatusUnhealthy {
+			available++
+		}
+		if capability.Status != health.StatusHealthy {
+			degraded = append(degraded, capability.Name)
+		}
 	}
 
 	// Determine overall status
 	status := "ready"
 	statusCode := http.StatusOK
-	for _, check := range checks {
-		if check == "unhealthy" {
-			status = "not ready"
-			statusCode = http.StatusServiceUnavailable
-			break
-		}
+	switch {
+	case available == 0:
+		status = "not ready"
+		statusCode = http.StatusServiceUnavailable
+	case len(degraded) > 0:
+		status = "degraded"
 	}
 
 	c.JSON(statusCode, gin.H{
-		"status":    status,
-		"checks":    checks,
-		"timestamp": time.Now().UTC(),
+		"status":       status,
+		"checks":       checks,
+		"capabilities": capabilities,
+		"degraded":     degraded,
+		"timestamp":    time.Now().UTC(),
 	})
 }
 
@@ -363,6 +498,7 @@ func (s *Server) metricsHandler(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"metrics":   metrics,
+		"errors":    s.errorCollector.GetMetrics(),
 		"timestamp": time.Now(),
 	})
 }
@@ -428,38 +564,6 @@ func (s *Server) recoveryMiddleware() gin.HandlerFunc {
 	})
 }
 
-// rateLimitMiddleware implements rate limiting
-func (s *Server) rateLimitMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		clientIP := c.ClientIP()
-		key := fmt.Sprintf("rate_limit:%s", clientIP)
-
-		allowed, err := s.redis.CheckRateLimit(
-			c.Request.Context(),
-			key,
-			int64(s.config.Security.RateLimitRequestsPerMin),
-			time.Minute,
-		)
-
-		if err != nil {
-			s.logger.Error("Rate limit check failed", zap.Error(err))
-			c.Next()
-			return
-		}
-
-		if !allowed {
-			s.logger.Warn("Rate limit exceeded", zap.String("client_ip", clientIP))
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error": "Rate limit exceeded",
-			})
-			c.Abort()
-			return
-		}
-
-		c.Next()
-	}
-}
-
 // detailedHealthHandler provides detailed health information
 func (s *Server) detailedHealthHandler(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
@@ -477,8 +581,13 @@ func (s *Server) detailedHealthHandler(c *gin.Context) {
 
 // prometheusMetricsHandler provides metrics in Prometheus format
 func (s *Server) prometheusMetricsHandler(c *gin.Context) {
+	s.prometheusHandler(c.Writer, c.Request)
+}
+
+// prometheusHandler writes metrics in the Prometheus text exposition format
+func (s *Server) prometheusHandler(w http.ResponseWriter, r *http.Request) {
 	// Update system metrics before returning
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
 	s.systemMetrics.UpdateSystemMetrics(ctx)
@@ -486,8 +595,9 @@ func (s *Server) prometheusMetricsHandler(c *gin.Context) {
 	exporter := monitoring.NewMetricsExporter(s.metricsCollector, s.logger.Logger)
 	prometheusMetrics := exporter.ExportPrometheus()
 
-	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
-	c.String(http.StatusOK, prometheusMetrics)
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(prometheusMetrics))
 }
 
 // main function
@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"context"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+	"crypto-bubble-map-be/internal/infrastructure/middleware"
+)
+
+// currentUser returns the authenticated user or an authentication error
+func currentUser(ctx context.Context) (*entity.User, error) {
+	user, ok := middleware.UserFromContext(ctx)
+	if !ok {
+		return nil, apperrors.NewAuthError(apperrors.ErrCodeAuthTokenInvalid, "Authentication required")
+	}
+	return user, nil
+}
+
+// requireAdmin returns the authenticated user if they are an administrator
+func requireAdmin(ctx context.Context) (*entity.User, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsAdmin() {
+		return nil, apperrors.NewAuthError(apperrors.ErrCodeAuthPermissionDenied, "Administrator role required")
+	}
+	return user, nil
+}
+
+// requireAnalyst returns the authenticated user if they hold at least the analyst role
+func requireAnalyst(ctx context.Context) (*entity.User, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsAnalyst() {
+		return nil, apperrors.NewAuthError(apperrors.ErrCodeAuthPermissionDenied, "Analyst role required")
+	}
+	return user, nil
+}
+
+// requireModerator returns the authenticated user if they hold at least the moderator role
+func requireModerator(ctx context.Context) (*entity.User, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsModerator() {
+		return nil, apperrors.NewAuthError(apperrors.ErrCodeAuthPermissionDenied, "Moderator role required")
+	}
+	return user, nil
+}
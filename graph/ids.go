@@ -0,0 +1,28 @@
+package graph
+
+import (
+	"strconv"
+
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+)
+
+// parseUintID converts a GraphQL ID argument into a database ID
+func parseUintID(field, id string) (uint, error) {
+	value, err := strconv.ParseUint(id, 10, 0)
+	if err != nil || value == 0 {
+		return 0, apperrors.NewValidationError(field, "must be a numeric ID")
+	}
+	return uint(value), nil
+}
+
+// parseOptionalUintID converts an optional GraphQL ID argument into a database ID
+func parseOptionalUintID(field string, id *string) (*uint, error) {
+	if id == nil {
+		return nil, nil
+	}
+	value, err := parseUintID(field, *id)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"time"
+
+	"crypto-bubble-map-be/graph/model"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+	"crypto-bubble-map-be/internal/infrastructure/labels"
+)
+
+// toLabelInput converts a GraphQL address label input for the label registry
+func toLabelInput(input model.AddressLabelInput) (labels.LabelInput, error) {
+	validFrom, err := parseOptionalTime("input.validFrom", input.ValidFrom)
+	if err != nil {
+		return labels.LabelInput{}, err
+	}
+	validUntil, err := parseOptionalTime("input.validUntil", input.ValidUntil)
+	if err != nil {
+		return labels.LabelInput{}, err
+	}
+
+	labelInput := labels.LabelInput{
+		Address:    input.Address,
+		Name:       input.Name,
+		Entity:     input.Entity,
+		Tags:       input.Tags,
+		Source:     input.Source,
+		Confidence: input.Confidence,
+		ValidFrom:  validFrom,
+		ValidUntil: validUntil,
+		Reference:  input.Reference,
+		Notes:      input.Notes,
+	}
+	if input.Category != nil {
+		labelInput.Category = *input.Category
+	}
+	return labelInput, nil
+}
+
+// toProposalInput converts a GraphQL label proposal input for the label registry
+func toProposalInput(input model.LabelProposalInput) labels.ProposalInput {
+	return labels.ProposalInput{
+		Kind:          input.Kind,
+		Address:       input.Address,
+		Name:          input.Name,
+		Entity:        input.Entity,
+		Category:      input.Category,
+		Tags:          input.Tags,
+		Description:   input.Description,
+		EvidenceLinks: input.EvidenceLinks,
+	}
+}
+
+// parseOptionalTime parses an optional RFC3339 DateTime argument
+func parseOptionalTime(field string, value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return nil, apperrors.NewValidationError(field, "must be an RFC3339 timestamp")
+	}
+	return &t, nil
+}
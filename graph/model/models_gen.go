@@ -2,8 +2,65 @@
 
 package model
 
+import (
+	"crypto-bubble-map-be/internal/domain/entity"
+)
+
+type AddressLabelInput struct {
+	Address    string                `json:"address"`
+	Name       string                `json:"name"`
+	Entity     *string               `json:"entity,omitempty"`
+	Category   *entity.LabelCategory `json:"category,omitempty"`
+	Tags       []string              `json:"tags,omitempty"`
+	Source     *string               `json:"source,omitempty"`
+	Confidence *float64              `json:"confidence,omitempty"`
+	ValidFrom  *string               `json:"validFrom,omitempty"`
+	ValidUntil *string               `json:"validUntil,omitempty"`
+	Reference  *string               `json:"reference,omitempty"`
+	Notes      *string               `json:"notes,omitempty"`
+}
+
+type CaseAttachmentInput struct {
+	Type      entity.CaseAttachmentType `json:"type"`
+	Reference string                    `json:"reference"`
+	Name      *string                   `json:"name,omitempty"`
+}
+
+type CreateSecurityCaseInput struct {
+	Title       string   `json:"title"`
+	Description *string  `json:"description,omitempty"`
+	AlertIds    []string `json:"alertIds"`
+	AssigneeID  *string  `json:"assigneeId,omitempty"`
+}
+
+type LabelProposalInput struct {
+	Kind          entity.LabelProposalKind `json:"kind"`
+	Address       string                   `json:"address"`
+	Name          *string                  `json:"name,omitempty"`
+	Entity        *string                  `json:"entity,omitempty"`
+	Category      *entity.LabelCategory    `json:"category,omitempty"`
+	Tags          []string                 `json:"tags,omitempty"`
+	Description   *string                  `json:"description,omitempty"`
+	EvidenceLinks []string                 `json:"evidenceLinks"`
+}
+
 type Mutation struct {
 }
 
 type Query struct {
 }
+
+type ScreeningOptionsInput struct {
+	Hops            *int  `json:"hops,omitempty"`
+	IncludeExposure *bool `json:"includeExposure,omitempty"`
+	IncludeLabels   *bool `json:"includeLabels,omitempty"`
+	RaiseAlerts     *bool `json:"raiseAlerts,omitempty"`
+}
+
+type Subscription struct {
+}
+
+type TimeRangeInput struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
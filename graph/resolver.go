@@ -2,8 +2,16 @@ package graph
 
 import (
 	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/assistant"
 	"crypto-bubble-map-be/internal/infrastructure/cache"
+	"crypto-bubble-map-be/internal/infrastructure/cases"
+	"crypto-bubble-map-be/internal/infrastructure/classification"
+	"crypto-bubble-map-be/internal/infrastructure/compliance"
+	"crypto-bubble-map-be/internal/infrastructure/labels"
 	"crypto-bubble-map-be/internal/infrastructure/logger"
+	"crypto-bubble-map-be/internal/infrastructure/sanctions"
+	"crypto-bubble-map-be/internal/infrastructure/screening"
+	"crypto-bubble-map-be/internal/infrastructure/search"
 )
 
 // Resolver is the root GraphQL resolver
@@ -17,6 +25,17 @@ type Resolver struct {
 	userRepo        repository.UserRepository
 	cacheRepo       repository.CacheRepository
 	aiRepo          repository.AIRepository
+	mevRepo         repository.MEVRepository
+
+	// Services
+	sanctionsService  *sanctions.Service
+	screeningService  *screening.Service
+	complianceService *compliance.Service
+	assistantService  *assistant.Service
+	casesService      *cases.Service
+	classifierService *classification.Service
+	labelService      *labels.Service
+	searchService     *search.Service
 
 	// Infrastructure
 	cache  *cache.RedisClient
@@ -33,19 +52,37 @@ func NewResolver(
 	userRepo repository.UserRepository,
 	cacheRepo repository.CacheRepository,
 	aiRepo repository.AIRepository,
+	mevRepo repository.MEVRepository,
+	sanctionsService *sanctions.Service,
+	screeningService *screening.Service,
+	complianceService *compliance.Service,
+	assistantService *assistant.Service,
+	casesService *cases.Service,
+	classifierService *classification.Service,
+	labelService *labels.Service,
+	searchService *search.Service,
 	cache *cache.RedisClient,
 	logger *logger.Logger,
 ) *Resolver {
 	return &Resolver{
-		walletRepo:      walletRepo,
-		transactionRepo: transactionRepo,
-		networkRepo:     networkRepo,
-		watchListRepo:   watchListRepo,
-		securityRepo:    securityRepo,
-		userRepo:        userRepo,
-		cacheRepo:       cacheRepo,
-		aiRepo:          aiRepo,
-		cache:           cache,
-		logger:          logger,
+		walletRepo:        walletRepo,
+		transactionRepo:   transactionRepo,
+		networkRepo:       networkRepo,
+		watchListRepo:     watchListRepo,
+		securityRepo:      securityRepo,
+		userRepo:          userRepo,
+		cacheRepo:         cacheRepo,
+		aiRepo:            aiRepo,
+		mevRepo:           mevRepo,
+		sanctionsService:  sanctionsService,
+		screeningService:  screeningService,
+		complianceService: complianceService,
+		assistantService:  assistantService,
+		casesService:      casesService,
+		classifierService: classifierService,
+		labelService:      labelService,
+		searchService:     searchService,
+		cache:             cache,
+		logger:            logger,
 	}
 }
@@ -7,103 +7,1092 @@ package graph
 import (
 	"context"
 	"crypto-bubble-map-be/graph/generated"
+	"crypto-bubble-map-be/graph/model"
 	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/infrastructure/assistant"
+	"crypto-bubble-map-be/internal/infrastructure/cases"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+	"crypto-bubble-map-be/internal/infrastructure/screening"
+	"crypto-bubble-map-be/internal/infrastructure/search"
 	"fmt"
 	"time"
+
+	"go.uber.org/zap"
 )
 
+// CreatedAt is the resolver for the createdAt field.
+func (r *aIConversationResolver) CreatedAt(ctx context.Context, obj *entity.AIConversation) (string, error) {
+	return obj.CreatedAt.Format(time.RFC3339), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *aIConversationResolver) UpdatedAt(ctx context.Context, obj *entity.AIConversation) (string, error) {
+	return obj.UpdatedAt.Format(time.RFC3339), nil
+}
+
+// Messages is the resolver for the messages field.
+func (r *aIConversationResolver) Messages(ctx context.Context, obj *entity.AIConversation, limit *int, offset *int) ([]*entity.AIMessage, error) {
+	l, o := 50, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+
+	messages, err := r.assistantService.Messages(ctx, obj.ID, l, o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation messages: %w", err)
+	}
+
+	result := make([]*entity.AIMessage, len(messages))
+	for i := range messages {
+		result[i] = &messages[i]
+	}
+	return result, nil
+}
+
+// Date is the resolver for the date field.
+func (r *aIDailyUsageResolver) Date(ctx context.Context, obj *entity.AIDailyUsage) (string, error) {
+	return obj.Date.Format(time.RFC3339), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *aIMessageResolver) CreatedAt(ctx context.Context, obj *entity.AIMessage) (string, error) {
+	return obj.CreatedAt.Format(time.RFC3339), nil
+}
+
+// GeneratedAt is the resolver for the generatedAt field.
+func (r *aIResponseResolver) GeneratedAt(ctx context.Context, obj *entity.AIResponse) (string, error) {
+	return obj.GeneratedAt.Format(time.RFC3339), nil
+}
+
+// Role is the resolver for the role field.
+func (r *aIUsageReportResolver) Role(ctx context.Context, obj *entity.AIUsageReport) (string, error) {
+	return string(obj.Role), nil
+}
+
+// ResetsAt is the resolver for the resetsAt field.
+func (r *aIUsageReportResolver) ResetsAt(ctx context.Context, obj *entity.AIUsageReport) (string, error) {
+	return obj.ResetsAt.Format(time.RFC3339), nil
+}
+
+// ValidFrom is the resolver for the validFrom field.
+func (r *addressLabelResolver) ValidFrom(ctx context.Context, obj *entity.AddressLabel) (*string, error) {
+	if obj.ValidFrom == nil {
+		return nil, nil
+	}
+	validFrom := obj.ValidFrom.Format(time.RFC3339)
+	return &validFrom, nil
+}
+
+// ValidUntil is the resolver for the validUntil field.
+func (r *addressLabelResolver) ValidUntil(ctx context.Context, obj *entity.AddressLabel) (*string, error) {
+	if obj.ValidUntil == nil {
+		return nil, nil
+	}
+	validUntil := obj.ValidUntil.Format(time.RFC3339)
+	return &validUntil, nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *addressLabelResolver) CreatedAt(ctx context.Context, obj *entity.AddressLabel) (string, error) {
+	return obj.CreatedAt.Format(time.RFC3339), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *addressLabelResolver) UpdatedAt(ctx context.Context, obj *entity.AddressLabel) (string, error) {
+	return obj.UpdatedAt.Format(time.RFC3339), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *caseCommentResolver) CreatedAt(ctx context.Context, obj *entity.CaseComment) (string, error) {
+	return obj.CreatedAt.Format(time.RFC3339), nil
+}
+
+// Edited is the resolver for the edited field.
+func (r *complianceNarrativeResolver) Edited(ctx context.Context, obj *entity.ComplianceNarrative) (bool, error) {
+	return obj.IsEdited(), nil
+}
+
+// DraftedAt is the resolver for the draftedAt field.
+func (r *complianceNarrativeDraftResolver) DraftedAt(ctx context.Context, obj *entity.ComplianceNarrativeDraft) (string, error) {
+	return obj.DraftedAt.Format(time.RFC3339), nil
+}
+
+// EditedAt is the resolver for the editedAt field.
+func (r *complianceNarrativeEditResolver) EditedAt(ctx context.Context, obj *entity.ComplianceNarrativeEdit) (string, error) {
+	return obj.EditedAt.Format(time.RFC3339), nil
+}
+
+// GeneratedAt is the resolver for the generatedAt field.
+func (r *complianceReportResolver) GeneratedAt(ctx context.Context, obj *entity.ComplianceReport) (string, error) {
+	return obj.GeneratedAt.Format(time.RFC3339), nil
+}
+
+// History is the resolver for the history field.
+func (r *complianceReportResolver) History(ctx context.Context, obj *entity.ComplianceReport) ([]*entity.ComplianceReportEvent, error) {
+	history, err := r.complianceService.ReportHistory(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get compliance report history: %w", err)
+	}
+
+	result := make([]*entity.ComplianceReportEvent, len(history))
+	for i := range history {
+		result[i] = &history[i]
+	}
+	return result, nil
+}
+
+// ActorRole is the resolver for the actorRole field.
+func (r *complianceReportEventResolver) ActorRole(ctx context.Context, obj *entity.ComplianceReportEvent) (string, error) {
+	return string(obj.ActorRole), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *complianceReportEventResolver) CreatedAt(ctx context.Context, obj *entity.ComplianceReportEvent) (string, error) {
+	return obj.CreatedAt.Format(time.RFC3339), nil
+}
+
+// VerifiedAt is the resolver for the verifiedAt field.
+func (r *complianceReportVerificationResolver) VerifiedAt(ctx context.Context, obj *entity.ComplianceReportVerification) (string, error) {
+	return obj.VerifiedAt.Format(time.RFC3339), nil
+}
+
+// NextReviewDate is the resolver for the nextReviewDate field.
+func (r *complianceRiskAssessmentResolver) NextReviewDate(ctx context.Context, obj *entity.ComplianceRiskAssessment) (string, error) {
+	return obj.NextReviewDate.Format(time.RFC3339), nil
+}
+
 // LastUpdate is the resolver for the lastUpdate field.
 func (r *dashboardStatsResolver) LastUpdate(ctx context.Context, obj *entity.DashboardStats) (string, error) {
 	return obj.LastUpdate.Format(time.RFC3339), nil
 }
 
-// Ping is the resolver for the ping field.
-func (r *mutationResolver) Ping(ctx context.Context) (string, error) {
-	return "pong", nil
+// ImportedAt is the resolver for the importedAt field.
+func (r *labelImportResolver) ImportedAt(ctx context.Context, obj *entity.LabelImport) (string, error) {
+	return obj.ImportedAt.Format(time.RFC3339), nil
+}
+
+// SubmitterReputation is the resolver for the submitterReputation field.
+func (r *labelProposalResolver) SubmitterReputation(ctx context.Context, obj *entity.LabelProposal) (*entity.SubmitterReputation, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if obj.SubmitterID != user.ID && !user.IsModerator() {
+		return nil, apperrors.NewAuthError(apperrors.ErrCodeAuthPermissionDenied, "Moderator role required")
+	}
+	return r.labelService.Reputation(ctx, obj.SubmitterID)
+}
+
+// ReviewedAt is the resolver for the reviewedAt field.
+func (r *labelProposalResolver) ReviewedAt(ctx context.Context, obj *entity.LabelProposal) (*string, error) {
+	if obj.ReviewedAt == nil {
+		return nil, nil
+	}
+	formatted := obj.ReviewedAt.Format(time.RFC3339)
+	return &formatted, nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *labelProposalResolver) CreatedAt(ctx context.Context, obj *entity.LabelProposal) (string, error) {
+	return obj.CreatedAt.Format(time.RFC3339), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *labelProposalResolver) UpdatedAt(ctx context.Context, obj *entity.LabelProposal) (string, error) {
+	return obj.UpdatedAt.Format(time.RFC3339), nil
+}
+
+// Timestamp is the resolver for the timestamp field.
+func (r *mEVActivityResolver) Timestamp(ctx context.Context, obj *entity.MEVActivity) (string, error) {
+	return obj.Timestamp.Format(time.RFC3339), nil
+}
+
+// DetectedAt is the resolver for the detectedAt field.
+func (r *mEVActivityResolver) DetectedAt(ctx context.Context, obj *entity.MEVActivity) (string, error) {
+	return obj.DetectedAt.Format(time.RFC3339), nil
+}
+
+// Ping is the resolver for the ping field.
+func (r *mutationResolver) Ping(ctx context.Context) (string, error) {
+	return "pong", nil
+}
+
+// ImportSanctionsList is the resolver for the importSanctionsList field.
+func (r *mutationResolver) ImportSanctionsList(ctx context.Context, format entity.SanctionsListFormat, fileName string) (*entity.SanctionsImportResult, error) {
+	user, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.sanctionsService.ImportFile(ctx, format, fileName, user.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import sanctions list: %w", err)
+	}
+	return result, nil
+}
+
+// GenerateComplianceReport is the resolver for the generateComplianceReport field.
+func (r *mutationResolver) GenerateComplianceReport(ctx context.Context, walletAddress string, reportType entity.ComplianceReportType, timeRange model.TimeRangeInput) (*entity.ComplianceReport, error) {
+	user, err := requireAnalyst(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := time.Parse(time.RFC3339, timeRange.Start)
+	if err != nil {
+		return nil, apperrors.NewValidationError("timeRange.start", "must be an RFC3339 timestamp")
+	}
+	end, err := time.Parse(time.RFC3339, timeRange.End)
+	if err != nil {
+		return nil, apperrors.NewValidationError("timeRange.end", "must be an RFC3339 timestamp")
+	}
+
+	report, err := r.complianceService.GenerateReport(ctx, walletAddress, reportType, entity.TimeRange{Start: start, End: end}, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate compliance report: %w", err)
+	}
+	return report, nil
+}
+
+// SubmitComplianceReport is the resolver for the submitComplianceReport field.
+func (r *mutationResolver) SubmitComplianceReport(ctx context.Context, id string, comment *string) (*entity.ComplianceReport, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.complianceService.SubmitReport(ctx, id, user, comment)
+}
+
+// ApproveComplianceReport is the resolver for the approveComplianceReport field.
+func (r *mutationResolver) ApproveComplianceReport(ctx context.Context, id string, comment *string) (*entity.ComplianceReport, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.complianceService.ApproveReport(ctx, id, user, comment)
+}
+
+// RejectComplianceReport is the resolver for the rejectComplianceReport field.
+func (r *mutationResolver) RejectComplianceReport(ctx context.Context, id string, reason string) (*entity.ComplianceReport, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.complianceService.RejectReport(ctx, id, user, reason)
+}
+
+// MarkComplianceReportFiled is the resolver for the markComplianceReportFiled field.
+func (r *mutationResolver) MarkComplianceReportFiled(ctx context.Context, id string, filingReference string) (*entity.ComplianceReport, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.complianceService.MarkReportFiled(ctx, id, user, filingReference)
+}
+
+// DraftComplianceNarrative is the resolver for the draftComplianceNarrative field.
+func (r *mutationResolver) DraftComplianceNarrative(ctx context.Context, id string) (*entity.ComplianceReport, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.complianceService.DraftNarrative(ctx, id, user)
+}
+
+// EditComplianceNarrative is the resolver for the editComplianceNarrative field.
+func (r *mutationResolver) EditComplianceNarrative(ctx context.Context, id string, text string, comment *string) (*entity.ComplianceReport, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.complianceService.EditNarrative(ctx, id, user, text, comment)
+}
+
+// SendAIMessage is the resolver for the sendAIMessage field.
+func (r *mutationResolver) SendAIMessage(ctx context.Context, conversationID *string, question string, context *entity.AIContext, walletAddress *string) (*entity.AIConversationTurn, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseOptionalUintID("conversationId", conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.assistantService.Ask(ctx, user, assistant.Question{
+		ConversationID: id,
+		Question:       question,
+		Context:        context,
+		WalletAddress:  walletAddress,
+	})
+}
+
+// DeleteAIConversation is the resolver for the deleteAIConversation field.
+func (r *mutationResolver) DeleteAIConversation(ctx context.Context, id string) (bool, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return false, err
+	}
+	conversationID, err := parseUintID("id", id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.assistantService.DeleteConversation(ctx, user, conversationID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetAIDailyTokenLimit is the resolver for the setAIDailyTokenLimit field.
+func (r *mutationResolver) SetAIDailyTokenLimit(ctx context.Context, userID string, limit *int) (*entity.AIUsageReport, error) {
+	if _, err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	id, err := parseUintID("userId", userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := r.assistantService.SetDailyTokenLimit(ctx, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	return r.assistantService.Usage(ctx, user, 0)
+}
+
+// CreateSecurityCase is the resolver for the createSecurityCase field.
+func (r *mutationResolver) CreateSecurityCase(ctx context.Context, input model.CreateSecurityCaseInput) (*entity.SecurityCase, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assigneeID, err := parseOptionalUintID("assigneeId", input.AssigneeID)
+	if err != nil {
+		return nil, err
+	}
+
+	caseInput := cases.CreateCaseInput{
+		Title:      input.Title,
+		AlertIDs:   input.AlertIds,
+		AssigneeID: assigneeID,
+	}
+	if input.Description != nil {
+		caseInput.Description = *input.Description
+	}
+	return r.casesService.CreateCase(ctx, caseInput, user)
+}
+
+// AddAlertsToCase is the resolver for the addAlertsToCase field.
+func (r *mutationResolver) AddAlertsToCase(ctx context.Context, id string, alertIds []string) (*entity.SecurityCase, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.casesService.AddAlerts(ctx, id, alertIds, user)
+}
+
+// AssignSecurityCase is the resolver for the assignSecurityCase field.
+func (r *mutationResolver) AssignSecurityCase(ctx context.Context, id string, assigneeID string) (*entity.SecurityCase, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assignee, err := parseUintID("assigneeId", assigneeID)
+	if err != nil {
+		return nil, err
+	}
+	return r.casesService.AssignCase(ctx, id, assignee, user)
+}
+
+// StartCaseInvestigation is the resolver for the startCaseInvestigation field.
+func (r *mutationResolver) StartCaseInvestigation(ctx context.Context, id string, notes *string) (*entity.SecurityCase, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.casesService.StartInvestigation(ctx, id, user, notes)
+}
+
+// ResolveSecurityCase is the resolver for the resolveSecurityCase field.
+func (r *mutationResolver) ResolveSecurityCase(ctx context.Context, id string, resolution string) (*entity.SecurityCase, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.casesService.ResolveCase(ctx, id, user, resolution)
+}
+
+// MarkCaseFalsePositive is the resolver for the markCaseFalsePositive field.
+func (r *mutationResolver) MarkCaseFalsePositive(ctx context.Context, id string, reason string) (*entity.SecurityCase, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.casesService.MarkFalsePositive(ctx, id, user, reason)
+}
+
+// AddCaseComment is the resolver for the addCaseComment field.
+func (r *mutationResolver) AddCaseComment(ctx context.Context, caseID string, body string, parentID *string, attachments []*model.CaseAttachmentInput) (*entity.CaseComment, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	caseAttachments := make([]entity.CaseAttachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		caseAttachments = append(caseAttachments, entity.CaseAttachment{
+			Type:      attachment.Type,
+			Reference: attachment.Reference,
+			Name:      attachment.Name,
+		})
+	}
+	return r.casesService.AddComment(ctx, caseID, user, body, parentID, caseAttachments)
+}
+
+// ClassifyWallet is the resolver for the classifyWallet field.
+func (r *mutationResolver) ClassifyWallet(ctx context.Context, address string) (*entity.WalletClassification, error) {
+	if _, err := requireAnalyst(ctx); err != nil {
+		return nil, err
+	}
+	return r.classifierService.Classify(ctx, address)
+}
+
+// CreateAddressLabel is the resolver for the createAddressLabel field.
+func (r *mutationResolver) CreateAddressLabel(ctx context.Context, input model.AddressLabelInput) (*entity.AddressLabel, error) {
+	user, err := requireAnalyst(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	labelInput, err := toLabelInput(input)
+	if err != nil {
+		return nil, err
+	}
+	return r.labelService.CreateLabel(ctx, labelInput, user)
+}
+
+// UpdateAddressLabel is the resolver for the updateAddressLabel field.
+func (r *mutationResolver) UpdateAddressLabel(ctx context.Context, id string, input model.AddressLabelInput) (*entity.AddressLabel, error) {
+	user, err := requireAnalyst(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	labelInput, err := toLabelInput(input)
+	if err != nil {
+		return nil, err
+	}
+	return r.labelService.UpdateLabel(ctx, id, labelInput, user)
+}
+
+// DeleteAddressLabel is the resolver for the deleteAddressLabel field.
+func (r *mutationResolver) DeleteAddressLabel(ctx context.Context, id string) (bool, error) {
+	user, err := requireAnalyst(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.labelService.DeleteLabel(ctx, id, user); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ImportLabelPack is the resolver for the importLabelPack field.
+func (r *mutationResolver) ImportLabelPack(ctx context.Context, format entity.LabelPackFormat, fileName string, source string) (*entity.LabelImport, error) {
+	user, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	labelImport, err := r.labelService.ImportFile(ctx, format, fileName, source, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import label pack: %w", err)
+	}
+	return labelImport, nil
+}
+
+// ProposeLabel is the resolver for the proposeLabel field.
+func (r *mutationResolver) ProposeLabel(ctx context.Context, input model.LabelProposalInput) (*entity.LabelProposal, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.labelService.SubmitProposal(ctx, toProposalInput(input), user)
+}
+
+// ApproveLabelProposal is the resolver for the approveLabelProposal field.
+func (r *mutationResolver) ApproveLabelProposal(ctx context.Context, id string, note *string) (*entity.LabelProposal, error) {
+	user, err := requireModerator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.labelService.ApproveProposal(ctx, id, note, user)
+}
+
+// RejectLabelProposal is the resolver for the rejectLabelProposal field.
+func (r *mutationResolver) RejectLabelProposal(ctx context.Context, id string, reason string) (*entity.LabelProposal, error) {
+	user, err := requireModerator(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.labelService.RejectProposal(ctx, id, reason, user)
+}
+
+// Wallet is the resolver for the wallet field.
+func (r *queryResolver) Wallet(ctx context.Context, address string) (*entity.Wallet, error) {
+	// Use the wallet repository to get real data
+	wallet, err := r.walletRepo.GetWallet(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet: %w", err)
+	}
+	return wallet, nil
+}
+
+// WalletNetwork is the resolver for the walletNetwork field.
+func (r *queryResolver) WalletNetwork(ctx context.Context, input entity.WalletNetworkInput) (*entity.WalletNetwork, error) {
+	// Use the wallet repository to get real network data
+	network, err := r.walletRepo.GetWalletNetwork(ctx, &input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet network: %w", err)
+	}
+	return network, nil
+}
+
+// WalletRiskScore is the resolver for the walletRiskScore field.
+func (r *queryResolver) WalletRiskScore(ctx context.Context, address string) (*entity.RiskScore, error) {
+	// Use the wallet repository to get risk score
+	riskScore, err := r.walletRepo.GetRiskScore(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet risk score: %w", err)
+	}
+	return riskScore, nil
+}
+
+// DashboardStats is the resolver for the dashboardStats field.
+func (r *queryResolver) DashboardStats(ctx context.Context) (*entity.DashboardStats, error) {
+	// Use the network repository to get real dashboard stats
+	// Pass nil for networkID to get overall stats
+	stats, err := r.networkRepo.GetDashboardStats(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dashboard stats: %w", err)
+	}
+	return stats, nil
+}
+
+// SearchWallets is the resolver for the searchWallets field.
+func (r *queryResolver) SearchWallets(ctx context.Context, query string, limit *int) ([]*entity.Wallet, error) {
+	// Set default limit
+	searchLimit := 20
+	if limit != nil && *limit > 0 {
+		searchLimit = *limit
+	}
+
+	// Use the wallet repository to search wallets
+	searchResults, err := r.walletRepo.SearchWallets(ctx, query, searchLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search wallets: %w", err)
+	}
+
+	// Convert search results to wallets
+	var wallets []*entity.Wallet
+	for _, result := range searchResults {
+		wallet := &entity.Wallet{
+			Address:          result.Address,
+			Label:            result.Label,
+			Tags:             result.Tags,
+			TransactionCount: result.TransactionCount,
+			Balance:          result.Balance,
+			WalletType:       result.WalletType,
+			RiskLevel:        entity.RiskLevelLow, // Default risk level
+			IsContract:       false,               // Default value
+		}
+		if wallet.WalletType == "" {
+			wallet.WalletType = entity.WalletTypeRegular
+		}
+
+		// Prefer the stored risk level, otherwise derive it from the risk score
+		if result.RiskLevel != "" {
+			wallet.RiskLevel = result.RiskLevel
+		} else if result.RiskScore != nil {
+			if *result.RiskScore > 0.7 {
+				wallet.RiskLevel = entity.RiskLevelHigh
+			} else if *result.RiskScore > 0.4 {
+				wallet.RiskLevel = entity.RiskLevelMedium
+			}
+		}
+
+		wallets = append(wallets, wallet)
+	}
+
+	return wallets, nil
+}
+
+// Search is the resolver for the search field.
+func (r *queryResolver) Search(ctx context.Context, query string, types []entity.SearchResultType, limit *int) (*entity.SearchResponse, error) {
+	l := search.DefaultLimit
+	if limit != nil {
+		l = *limit
+	}
+
+	response, err := r.searchService.Search(ctx, query, searchableTypes(ctx, types), l)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// ScreenAddress is the resolver for the screenAddress field.
+func (r *queryResolver) ScreenAddress(ctx context.Context, address string, hops *int) (*entity.SanctionsScreeningResult, error) {
+	if _, err := requireAnalyst(ctx); err != nil {
+		return nil, err
+	}
+
+	// A negative depth selects the configured default
+	maxHops := -1
+	if hops != nil {
+		maxHops = *hops
+	}
+
+	result, err := r.sanctionsService.Screen(ctx, address, maxHops)
+	if err != nil {
+		return nil, fmt.Errorf("failed to screen address: %w", err)
+	}
+	return result, nil
+}
+
+// SanctionsListVersions is the resolver for the sanctionsListVersions field.
+func (r *queryResolver) SanctionsListVersions(ctx context.Context, source *entity.SanctionsSource, limit *int) ([]*entity.SanctionsListVersion, error) {
+	if _, err := requireAnalyst(ctx); err != nil {
+		return nil, err
+	}
+
+	listLimit := 20
+	if limit != nil && *limit > 0 {
+		listLimit = *limit
+	}
+
+	versions, err := r.sanctionsService.GetListVersions(ctx, source, listLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sanctions list versions: %w", err)
+	}
+
+	result := make([]*entity.SanctionsListVersion, len(versions))
+	for i := range versions {
+		result[i] = &versions[i]
+	}
+	return result, nil
+}
+
+// ScreenAddresses is the resolver for the screenAddresses field.
+func (r *queryResolver) ScreenAddresses(ctx context.Context, addresses []string, options *model.ScreeningOptionsInput) (*entity.ScreeningJob, error) {
+	user, err := requireAnalyst(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := entity.ScreeningOptions{IncludeExposure: true, IncludeLabels: true}
+	if options != nil {
+		if options.Hops != nil {
+			opts.Hops = *options.Hops
+		}
+		if options.IncludeExposure != nil {
+			opts.IncludeExposure = *options.IncludeExposure
+		}
+		if options.IncludeLabels != nil {
+			opts.IncludeLabels = *options.IncludeLabels
+		}
+		if options.RaiseAlerts != nil {
+			opts.RaiseAlerts = *options.RaiseAlerts
+		}
+	}
+
+	job, err := r.screeningService.Submit(ctx, addresses, opts, user.ID, "API")
+	if err != nil {
+		return nil, fmt.Errorf("failed to screen addresses: %w", err)
+	}
+	return job, nil
+}
+
+// ScreeningJob is the resolver for the screeningJob field.
+func (r *queryResolver) ScreeningJob(ctx context.Context, id string) (*entity.ScreeningJob, error) {
+	user, err := requireAnalyst(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := r.screeningService.GetJob(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get screening job: %w", err)
+	}
+
+	// Jobs are only visible to the analyst who submitted them and to administrators
+	if job == nil || (job.RequestedBy != user.ID && !user.IsAdmin()) {
+		return nil, nil
+	}
+	return job, nil
+}
+
+// ComplianceReport is the resolver for the complianceReport field.
+func (r *queryResolver) ComplianceReport(ctx context.Context, id string) (*entity.ComplianceReport, error) {
+	if _, err := requireAnalyst(ctx); err != nil {
+		return nil, err
+	}
+
+	report, err := r.securityRepo.GetComplianceReport(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get compliance report: %w", err)
+	}
+	return report, nil
+}
+
+// ComplianceReports is the resolver for the complianceReports field.
+func (r *queryResolver) ComplianceReports(ctx context.Context, walletAddress *string, reportType *entity.ComplianceReportType, status *entity.ComplianceReportStatus) ([]*entity.ComplianceReport, error) {
+	if _, err := requireAnalyst(ctx); err != nil {
+		return nil, err
+	}
+
+	filters := make(map[string]interface{})
+	if walletAddress != nil {
+		filters["wallet_address"] = entity.NormalizeAddress(*walletAddress)
+	}
+	if reportType != nil {
+		filters["report_type"] = string(*reportType)
+	}
+	if status != nil {
+		filters["status"] = string(*status)
+	}
+
+	reports, err := r.securityRepo.GetComplianceReports(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get compliance reports: %w", err)
+	}
+
+	result := make([]*entity.ComplianceReport, len(reports))
+	for i := range reports {
+		result[i] = &reports[i]
+	}
+	return result, nil
+}
+
+// VerifyComplianceReport is the resolver for the verifyComplianceReport field.
+func (r *queryResolver) VerifyComplianceReport(ctx context.Context, id string) (*entity.ComplianceReportVerification, error) {
+	if _, err := requireAnalyst(ctx); err != nil {
+		return nil, err
+	}
+	return r.complianceService.VerifyReport(ctx, id)
+}
+
+// AskAi is the resolver for the askAI field.
+func (r *queryResolver) AskAi(ctx context.Context, question string, context *entity.AIContext, walletAddress *string) (*entity.AIResponse, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.assistantService.AskOnce(ctx, user, question, context, walletAddress)
+}
+
+// AiConversations is the resolver for the aiConversations field.
+func (r *queryResolver) AiConversations(ctx context.Context, limit *int, offset *int) ([]*entity.AIConversation, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l, o := 20, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+
+	conversations, err := r.assistantService.Conversations(ctx, user, l, o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversations: %w", err)
+	}
+
+	result := make([]*entity.AIConversation, len(conversations))
+	for i := range conversations {
+		result[i] = &conversations[i]
+	}
+	return result, nil
+}
+
+// AiConversation is the resolver for the aiConversation field.
+func (r *queryResolver) AiConversation(ctx context.Context, id string) (*entity.AIConversation, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conversationID, err := parseUintID("id", id)
+	if err != nil {
+		return nil, err
+	}
+	return r.assistantService.Conversation(ctx, user, conversationID)
+}
+
+// AiUsage is the resolver for the aiUsage field.
+func (r *queryResolver) AiUsage(ctx context.Context, userID *string, days *int) (*entity.AIUsageReport, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d := 7
+	if days != nil {
+		d = *days
+	}
+
+	id, err := parseOptionalUintID("userId", userID)
+	if err != nil {
+		return nil, err
+	}
+	if id == nil || *id == user.ID {
+		return r.assistantService.Usage(ctx, user, d)
+	}
+
+	if !user.IsAdmin() {
+		return nil, apperrors.NewAuthError(apperrors.ErrCodeAuthPermissionDenied, "Administrator role required")
+	}
+	target, err := r.assistantService.User(ctx, *id)
+	if err != nil {
+		return nil, err
+	}
+	return r.assistantService.Usage(ctx, target, d)
+}
+
+// SecurityCase is the resolver for the securityCase field.
+func (r *queryResolver) SecurityCase(ctx context.Context, id string) (*entity.SecurityCase, error) {
+	if _, err := requireAnalyst(ctx); err != nil {
+		return nil, err
+	}
+
+	securityCase, err := r.casesService.Case(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get security case: %w", err)
+	}
+	return securityCase, nil
+}
+
+// SecurityCases is the resolver for the securityCases field.
+func (r *queryResolver) SecurityCases(ctx context.Context, status []entity.CaseStatus, assigneeID *string, unassigned *bool, severity *entity.AlertSeverity, walletAddress *string, limit *int, offset *int) ([]*entity.SecurityCase, error) {
+	if _, err := requireAnalyst(ctx); err != nil {
+		return nil, err
+	}
+	id, err := parseOptionalUintID("assigneeId", assigneeID)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := &entity.CaseFilters{
+		Statuses:      status,
+		AssigneeID:    id,
+		Severity:      severity,
+		WalletAddress: walletAddress,
+		Unassigned:    unassigned != nil && *unassigned,
+	}
+	l, o := 20, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+
+	securityCases, err := r.casesService.Cases(ctx, filters, l, o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get security cases: %w", err)
+	}
+
+	result := make([]*entity.SecurityCase, len(securityCases))
+	for i := range securityCases {
+		result[i] = &securityCases[i]
+	}
+	return result, nil
 }
 
-// Wallet is the resolver for the wallet field.
-func (r *queryResolver) Wallet(ctx context.Context, address string) (*entity.Wallet, error) {
-	// Use the wallet repository to get real data
-	wallet, err := r.walletRepo.GetWallet(ctx, address)
+// MyOpenCases is the resolver for the myOpenCases field.
+func (r *queryResolver) MyOpenCases(ctx context.Context) ([]*entity.SecurityCase, error) {
+	user, err := requireAnalyst(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get wallet: %w", err)
+		return nil, err
 	}
-	return wallet, nil
+
+	securityCases, err := r.casesService.MyOpenCases(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open cases: %w", err)
+	}
+
+	result := make([]*entity.SecurityCase, len(securityCases))
+	for i := range securityCases {
+		result[i] = &securityCases[i]
+	}
+	return result, nil
 }
 
-// WalletNetwork is the resolver for the walletNetwork field.
-func (r *queryResolver) WalletNetwork(ctx context.Context, input entity.WalletNetworkInput) (*entity.WalletNetwork, error) {
-	// Use the wallet repository to get real network data
-	network, err := r.walletRepo.GetWalletNetwork(ctx, &input)
+// MevActivities is the resolver for the mevActivities field.
+func (r *queryResolver) MevActivities(ctx context.Context, walletAddress *string, typeArg *entity.MEVType, limit *int, offset *int) ([]*entity.MEVActivity, error) {
+	if _, err := requireAnalyst(ctx); err != nil {
+		return nil, err
+	}
+
+	filters := &entity.MEVFilters{
+		WalletAddress: walletAddress,
+		Type:          typeArg,
+	}
+	l, o := 20, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+
+	activities, err := r.mevRepo.GetMEVActivities(ctx, filters, l, o)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get wallet network: %w", err)
+		return nil, fmt.Errorf("failed to get MEV activities: %w", err)
 	}
-	return network, nil
+
+	result := make([]*entity.MEVActivity, len(activities))
+	for i := range activities {
+		result[i] = &activities[i]
+	}
+	return result, nil
 }
 
-// WalletRiskScore is the resolver for the walletRiskScore field.
-func (r *queryResolver) WalletRiskScore(ctx context.Context, address string) (*entity.RiskScore, error) {
-	// Use the wallet repository to get risk score
-	riskScore, err := r.walletRepo.GetRiskScore(ctx, address)
+// AddressLabels is the resolver for the addressLabels field.
+func (r *queryResolver) AddressLabels(ctx context.Context, address *string, source *string, category *entity.LabelCategory, search *string, includeExpired *bool, limit *int, offset *int) ([]*entity.AddressLabel, error) {
+	if _, err := requireAnalyst(ctx); err != nil {
+		return nil, err
+	}
+
+	filters := &entity.LabelFilters{
+		Address:  address,
+		Source:   source,
+		Category: category,
+		Search:   search,
+	}
+	if includeExpired != nil {
+		filters.IncludeExpired = *includeExpired
+	}
+	l, o := 20, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+
+	addressLabels, err := r.labelService.Labels(ctx, filters, l, o)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get wallet risk score: %w", err)
+		return nil, fmt.Errorf("failed to get address labels: %w", err)
 	}
-	return riskScore, nil
+
+	result := make([]*entity.AddressLabel, len(addressLabels))
+	for i := range addressLabels {
+		result[i] = &addressLabels[i]
+	}
+	return result, nil
 }
 
-// DashboardStats is the resolver for the dashboardStats field.
-func (r *queryResolver) DashboardStats(ctx context.Context) (*entity.DashboardStats, error) {
-	// Use the network repository to get real dashboard stats
-	// Pass nil for networkID to get overall stats
-	stats, err := r.networkRepo.GetDashboardStats(ctx, nil)
+// AddressAttribution is the resolver for the addressAttribution field.
+func (r *queryResolver) AddressAttribution(ctx context.Context, address string) (*entity.AddressAttribution, error) {
+	if _, err := requireAnalyst(ctx); err != nil {
+		return nil, err
+	}
+	return r.labelService.Attribution(ctx, address)
+}
+
+// LabelImports is the resolver for the labelImports field.
+func (r *queryResolver) LabelImports(ctx context.Context, limit *int) ([]*entity.LabelImport, error) {
+	if _, err := requireAnalyst(ctx); err != nil {
+		return nil, err
+	}
+
+	l := 20
+	if limit != nil {
+		l = *limit
+	}
+
+	imports, err := r.labelService.Imports(ctx, l)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get dashboard stats: %w", err)
+		return nil, fmt.Errorf("failed to get label imports: %w", err)
 	}
-	return stats, nil
+
+	result := make([]*entity.LabelImport, len(imports))
+	for i := range imports {
+		result[i] = &imports[i]
+	}
+	return result, nil
 }
 
-// SearchWallets is the resolver for the searchWallets field.
-func (r *queryResolver) SearchWallets(ctx context.Context, query string, limit *int) ([]*entity.Wallet, error) {
-	// Set default limit
-	searchLimit := 20
-	if limit != nil && *limit > 0 {
-		searchLimit = *limit
+// LabelModerationQueue is the resolver for the labelModerationQueue field.
+func (r *queryResolver) LabelModerationQueue(ctx context.Context, status *entity.LabelProposalStatus, kind *entity.LabelProposalKind, address *string, limit *int, offset *int) ([]*entity.LabelProposal, error) {
+	if _, err := requireModerator(ctx); err != nil {
+		return nil, err
 	}
 
-	// Use the wallet repository to search wallets
-	searchResults, err := r.walletRepo.SearchWallets(ctx, query, searchLimit)
+	l, o := 20, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
+	}
+
+	filters := &entity.LabelProposalFilters{Status: status, Kind: kind, Address: address}
+	proposals, err := r.labelService.Proposals(ctx, filters, l, o)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search wallets: %w", err)
+		return nil, fmt.Errorf("failed to get label proposals: %w", err)
 	}
 
-	// Convert search results to wallets
-	var wallets []*entity.Wallet
-	for _, result := range searchResults {
-		wallet := &entity.Wallet{
-			Address:          result.Address,
-			Label:            result.Label,
-			Tags:             result.Tags,
-			TransactionCount: result.TransactionCount,
-			Balance:          result.Balance,
-			WalletType:       entity.WalletTypeRegular, // Default type
-			RiskLevel:        entity.RiskLevelLow,      // Default risk level
-			IsContract:       false,                    // Default value
-		}
+	result := make([]*entity.LabelProposal, len(proposals))
+	for i := range proposals {
+		result[i] = &proposals[i]
+	}
+	return result, nil
+}
 
-		// Set risk level based on risk score if available
-		if result.RiskScore != nil {
-			if *result.RiskScore > 0.7 {
-				wallet.RiskLevel = entity.RiskLevelHigh
-			} else if *result.RiskScore > 0.4 {
-				wallet.RiskLevel = entity.RiskLevelMedium
-			}
-		}
+// MyLabelProposals is the resolver for the myLabelProposals field.
+func (r *queryResolver) MyLabelProposals(ctx context.Context, status *entity.LabelProposalStatus, limit *int, offset *int) ([]*entity.LabelProposal, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-		wallets = append(wallets, wallet)
+	l, o := 20, 0
+	if limit != nil {
+		l = *limit
+	}
+	if offset != nil {
+		o = *offset
 	}
 
-	return wallets, nil
+	filters := &entity.LabelProposalFilters{Status: status, SubmitterID: &user.ID}
+	proposals, err := r.labelService.Proposals(ctx, filters, l, o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get label proposals: %w", err)
+	}
+
+	result := make([]*entity.LabelProposal, len(proposals))
+	for i := range proposals {
+		result[i] = &proposals[i]
+	}
+	return result, nil
+}
+
+// MyLabelReputation is the resolver for the myLabelReputation field.
+func (r *queryResolver) MyLabelReputation(ctx context.Context) (*entity.SubmitterReputation, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.labelService.Reputation(ctx, user.ID)
 }
 
 // Health is the resolver for the health field.
@@ -111,11 +1100,193 @@ func (r *queryResolver) Health(ctx context.Context) (string, error) {
 	return "GraphQL API is healthy and ready!", nil
 }
 
+// Deadline is the resolver for the deadline field.
+func (r *regulatoryFlagResolver) Deadline(ctx context.Context, obj *entity.RegulatoryFlag) (*string, error) {
+	if obj.Deadline == nil {
+		return nil, nil
+	}
+	deadline := obj.Deadline.Format(time.RFC3339)
+	return &deadline, nil
+}
+
 // LastUpdated is the resolver for the lastUpdated field.
 func (r *riskScoreResolver) LastUpdated(ctx context.Context, obj *entity.RiskScore) (string, error) {
 	return obj.LastUpdated.Format(time.RFC3339), nil
 }
 
+// PublishedAt is the resolver for the publishedAt field.
+func (r *sanctionsListVersionResolver) PublishedAt(ctx context.Context, obj *entity.SanctionsListVersion) (*string, error) {
+	if obj.PublishedAt == nil {
+		return nil, nil
+	}
+	publishedAt := obj.PublishedAt.Format(time.RFC3339)
+	return &publishedAt, nil
+}
+
+// ImportedAt is the resolver for the importedAt field.
+func (r *sanctionsListVersionResolver) ImportedAt(ctx context.Context, obj *entity.SanctionsListVersion) (string, error) {
+	return obj.ImportedAt.Format(time.RFC3339), nil
+}
+
+// ScreenedAt is the resolver for the screenedAt field.
+func (r *sanctionsScreeningResultResolver) ScreenedAt(ctx context.Context, obj *entity.SanctionsScreeningResult) (string, error) {
+	return obj.ScreenedAt.Format(time.RFC3339), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *screeningJobResolver) CreatedAt(ctx context.Context, obj *entity.ScreeningJob) (string, error) {
+	return obj.CreatedAt.Format(time.RFC3339), nil
+}
+
+// StartedAt is the resolver for the startedAt field.
+func (r *screeningJobResolver) StartedAt(ctx context.Context, obj *entity.ScreeningJob) (*string, error) {
+	if obj.StartedAt == nil {
+		return nil, nil
+	}
+	startedAt := obj.StartedAt.Format(time.RFC3339)
+	return &startedAt, nil
+}
+
+// CompletedAt is the resolver for the completedAt field.
+func (r *screeningJobResolver) CompletedAt(ctx context.Context, obj *entity.ScreeningJob) (*string, error) {
+	if obj.CompletedAt == nil {
+		return nil, nil
+	}
+	completedAt := obj.CompletedAt.Format(time.RFC3339)
+	return &completedAt, nil
+}
+
+// Results is the resolver for the results field.
+func (r *screeningJobResolver) Results(ctx context.Context, obj *entity.ScreeningJob, limit *int, offset *int) ([]*entity.AddressScreeningResult, error) {
+	resultLimit, resultOffset := 100, 0
+	if limit != nil && *limit > 0 {
+		resultLimit = *limit
+	}
+	if offset != nil && *offset > 0 {
+		resultOffset = *offset
+	}
+
+	results, err := r.screeningService.GetResults(ctx, obj.ID, resultLimit, resultOffset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get screening results: %w", err)
+	}
+
+	page := make([]*entity.AddressScreeningResult, len(results))
+	for i := range results {
+		page[i] = &results[i]
+	}
+	return page, nil
+}
+
+// CSVDownloadURL is the resolver for the csvDownloadUrl field.
+func (r *screeningJobResolver) CSVDownloadURL(ctx context.Context, obj *entity.ScreeningJob) (string, error) {
+	return screening.ResultsPath(obj.ID, "csv"), nil
+}
+
+// JSONDownloadURL is the resolver for the jsonDownloadUrl field.
+func (r *screeningJobResolver) JSONDownloadURL(ctx context.Context, obj *entity.ScreeningJob) (string, error) {
+	return screening.ResultsPath(obj.ID, "json"), nil
+}
+
+// Timestamp is the resolver for the timestamp field.
+func (r *searchResultResolver) Timestamp(ctx context.Context, obj *entity.SearchResult) (*string, error) {
+	if obj.Timestamp == nil {
+		return nil, nil
+	}
+	formatted := obj.Timestamp.Format(time.RFC3339)
+	return &formatted, nil
+}
+
+// Type is the resolver for the type field.
+func (r *securityAlertResolver) Type(ctx context.Context, obj *entity.SecurityAlert) (string, error) {
+	return string(obj.Type), nil
+}
+
+// Timestamp is the resolver for the timestamp field.
+func (r *securityAlertResolver) Timestamp(ctx context.Context, obj *entity.SecurityAlert) (string, error) {
+	return obj.Timestamp.Format(time.RFC3339), nil
+}
+
+// Alerts is the resolver for the alerts field.
+func (r *securityCaseResolver) Alerts(ctx context.Context, obj *entity.SecurityCase) ([]*entity.SecurityAlert, error) {
+	alerts, err := r.casesService.Alerts(ctx, obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get case alerts: %w", err)
+	}
+
+	result := make([]*entity.SecurityAlert, len(alerts))
+	for i := range alerts {
+		result[i] = &alerts[i]
+	}
+	return result, nil
+}
+
+// AssignedAt is the resolver for the assignedAt field.
+func (r *securityCaseResolver) AssignedAt(ctx context.Context, obj *entity.SecurityCase) (*string, error) {
+	if obj.AssignedAt == nil {
+		return nil, nil
+	}
+	assignedAt := obj.AssignedAt.Format(time.RFC3339)
+	return &assignedAt, nil
+}
+
+// ResponseDueAt is the resolver for the responseDueAt field.
+func (r *securityCaseResolver) ResponseDueAt(ctx context.Context, obj *entity.SecurityCase) (string, error) {
+	return obj.ResponseDueAt.Format(time.RFC3339), nil
+}
+
+// ResolutionDueAt is the resolver for the resolutionDueAt field.
+func (r *securityCaseResolver) ResolutionDueAt(ctx context.Context, obj *entity.SecurityCase) (string, error) {
+	return obj.ResolutionDueAt.Format(time.RFC3339), nil
+}
+
+// SLAStatus is the resolver for the slaStatus field.
+func (r *securityCaseResolver) SLAStatus(ctx context.Context, obj *entity.SecurityCase) (entity.CaseSLAStatus, error) {
+	return obj.SLAStatus(time.Now()), nil
+}
+
+// InvestigationStartedAt is the resolver for the investigationStartedAt field.
+func (r *securityCaseResolver) InvestigationStartedAt(ctx context.Context, obj *entity.SecurityCase) (*string, error) {
+	if obj.InvestigationStartedAt == nil {
+		return nil, nil
+	}
+	investigationStartedAt := obj.InvestigationStartedAt.Format(time.RFC3339)
+	return &investigationStartedAt, nil
+}
+
+// ClosedAt is the resolver for the closedAt field.
+func (r *securityCaseResolver) ClosedAt(ctx context.Context, obj *entity.SecurityCase) (*string, error) {
+	if obj.ClosedAt == nil {
+		return nil, nil
+	}
+	closedAt := obj.ClosedAt.Format(time.RFC3339)
+	return &closedAt, nil
+}
+
+// Comments is the resolver for the comments field.
+func (r *securityCaseResolver) Comments(ctx context.Context, obj *entity.SecurityCase) ([]*entity.CaseComment, error) {
+	comments, err := r.casesService.Comments(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get case comments: %w", err)
+	}
+
+	result := make([]*entity.CaseComment, len(comments))
+	for i := range comments {
+		result[i] = &comments[i]
+	}
+	return result, nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *securityCaseResolver) CreatedAt(ctx context.Context, obj *entity.SecurityCase) (string, error) {
+	return obj.CreatedAt.Format(time.RFC3339), nil
+}
+
+// UpdatedAt is the resolver for the updatedAt field.
+func (r *securityCaseResolver) UpdatedAt(ctx context.Context, obj *entity.SecurityCase) (string, error) {
+	return obj.UpdatedAt.Format(time.RFC3339), nil
+}
+
 // Medium is the resolver for the medium field.
 func (r *socialProfilesResolver) Medium(ctx context.Context, obj *entity.SocialProfiles) (*string, error) {
 	panic(fmt.Errorf("not implemented: Medium - medium"))
@@ -126,6 +1297,66 @@ func (r *socialProfilesResolver) Reddit(ctx context.Context, obj *entity.SocialP
 	panic(fmt.Errorf("not implemented: Reddit - reddit"))
 }
 
+// AiAnswer is the resolver for the aiAnswer field.
+func (r *subscriptionResolver) AiAnswer(ctx context.Context, conversationID *string, question string, context *entity.AIContext, walletAddress *string) (<-chan *entity.AIStreamEvent, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseOptionalUintID("conversationId", conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	turn, err := r.assistantService.StartTurn(ctx, user, assistant.Question{
+		ConversationID: id,
+		Question:       question,
+		Context:        context,
+		WalletAddress:  walletAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan *entity.AIStreamEvent, 32)
+	send := func(event *entity.AIStreamEvent) {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	}
+
+	go func() {
+		defer close(events)
+
+		conversationID := turn.Conversation.ID
+		result, err := r.assistantService.Answer(ctx, turn, func(delta string) {
+			send(&entity.AIStreamEvent{ConversationID: conversationID, Delta: delta})
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				r.logger.Warn("Failed to stream AI answer", zap.Uint("conversationID", conversationID), zap.Error(err))
+				message := err.Error()
+				send(&entity.AIStreamEvent{ConversationID: conversationID, Done: true, Error: &message})
+			}
+			return
+		}
+		send(&entity.AIStreamEvent{ConversationID: conversationID, Done: true, Message: result.Message})
+	}()
+
+	return events, nil
+}
+
+// Start is the resolver for the start field.
+func (r *timeRangeResolver) Start(ctx context.Context, obj *entity.TimeRange) (string, error) {
+	return obj.Start.Format(time.RFC3339), nil
+}
+
+// End is the resolver for the end field.
+func (r *timeRangeResolver) End(ctx context.Context, obj *entity.TimeRange) (string, error) {
+	return obj.End.Format(time.RFC3339), nil
+}
+
 // FirstTransactionDate is the resolver for the firstTransactionDate field.
 func (r *walletResolver) FirstTransactionDate(ctx context.Context, obj *entity.Wallet) (*string, error) {
 	panic(fmt.Errorf("not implemented: FirstTransactionDate - firstTransactionDate"))
@@ -136,6 +1367,16 @@ func (r *walletResolver) LastTransactionDate(ctx context.Context, obj *entity.Wa
 	panic(fmt.Errorf("not implemented: LastTransactionDate - lastTransactionDate"))
 }
 
+// Classification is the resolver for the classification field.
+func (r *walletResolver) Classification(ctx context.Context, obj *entity.Wallet) (*entity.WalletClassification, error) {
+	return r.classifierService.Classification(ctx, obj.Address)
+}
+
+// ClassifiedAt is the resolver for the classifiedAt field.
+func (r *walletClassificationResolver) ClassifiedAt(ctx context.Context, obj *entity.WalletClassification) (string, error) {
+	return obj.ClassifiedAt.Format(time.RFC3339), nil
+}
+
 // Timestamp is the resolver for the timestamp field.
 func (r *walletConnectionResolver) Timestamp(ctx context.Context, obj *entity.WalletConnection) (*string, error) {
 	panic(fmt.Errorf("not implemented: Timestamp - timestamp"))
@@ -156,28 +1397,133 @@ func (r *walletNetworkResolver) CenterWallet(ctx context.Context, obj *entity.Wa
 	panic(fmt.Errorf("not implemented: CenterWallet - centerWallet"))
 }
 
+// AIConversation returns generated.AIConversationResolver implementation.
+func (r *Resolver) AIConversation() generated.AIConversationResolver {
+	return &aIConversationResolver{r}
+}
+
+// AIDailyUsage returns generated.AIDailyUsageResolver implementation.
+func (r *Resolver) AIDailyUsage() generated.AIDailyUsageResolver { return &aIDailyUsageResolver{r} }
+
+// AIMessage returns generated.AIMessageResolver implementation.
+func (r *Resolver) AIMessage() generated.AIMessageResolver { return &aIMessageResolver{r} }
+
+// AIResponse returns generated.AIResponseResolver implementation.
+func (r *Resolver) AIResponse() generated.AIResponseResolver { return &aIResponseResolver{r} }
+
+// AIUsageReport returns generated.AIUsageReportResolver implementation.
+func (r *Resolver) AIUsageReport() generated.AIUsageReportResolver { return &aIUsageReportResolver{r} }
+
+// AddressLabel returns generated.AddressLabelResolver implementation.
+func (r *Resolver) AddressLabel() generated.AddressLabelResolver { return &addressLabelResolver{r} }
+
+// CaseComment returns generated.CaseCommentResolver implementation.
+func (r *Resolver) CaseComment() generated.CaseCommentResolver { return &caseCommentResolver{r} }
+
+// ComplianceNarrative returns generated.ComplianceNarrativeResolver implementation.
+func (r *Resolver) ComplianceNarrative() generated.ComplianceNarrativeResolver {
+	return &complianceNarrativeResolver{r}
+}
+
+// ComplianceNarrativeDraft returns generated.ComplianceNarrativeDraftResolver implementation.
+func (r *Resolver) ComplianceNarrativeDraft() generated.ComplianceNarrativeDraftResolver {
+	return &complianceNarrativeDraftResolver{r}
+}
+
+// ComplianceNarrativeEdit returns generated.ComplianceNarrativeEditResolver implementation.
+func (r *Resolver) ComplianceNarrativeEdit() generated.ComplianceNarrativeEditResolver {
+	return &complianceNarrativeEditResolver{r}
+}
+
+// ComplianceReport returns generated.ComplianceReportResolver implementation.
+func (r *Resolver) ComplianceReport() generated.ComplianceReportResolver {
+	return &complianceReportResolver{r}
+}
+
+// ComplianceReportEvent returns generated.ComplianceReportEventResolver implementation.
+func (r *Resolver) ComplianceReportEvent() generated.ComplianceReportEventResolver {
+	return &complianceReportEventResolver{r}
+}
+
+// ComplianceReportVerification returns generated.ComplianceReportVerificationResolver implementation.
+func (r *Resolver) ComplianceReportVerification() generated.ComplianceReportVerificationResolver {
+	return &complianceReportVerificationResolver{r}
+}
+
+// ComplianceRiskAssessment returns generated.ComplianceRiskAssessmentResolver implementation.
+func (r *Resolver) ComplianceRiskAssessment() generated.ComplianceRiskAssessmentResolver {
+	return &complianceRiskAssessmentResolver{r}
+}
+
 // DashboardStats returns generated.DashboardStatsResolver implementation.
 func (r *Resolver) DashboardStats() generated.DashboardStatsResolver {
 	return &dashboardStatsResolver{r}
 }
 
+// LabelImport returns generated.LabelImportResolver implementation.
+func (r *Resolver) LabelImport() generated.LabelImportResolver { return &labelImportResolver{r} }
+
+// LabelProposal returns generated.LabelProposalResolver implementation.
+func (r *Resolver) LabelProposal() generated.LabelProposalResolver { return &labelProposalResolver{r} }
+
+// MEVActivity returns generated.MEVActivityResolver implementation.
+func (r *Resolver) MEVActivity() generated.MEVActivityResolver { return &mEVActivityResolver{r} }
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+// RegulatoryFlag returns generated.RegulatoryFlagResolver implementation.
+func (r *Resolver) RegulatoryFlag() generated.RegulatoryFlagResolver {
+	return &regulatoryFlagResolver{r}
+}
+
 // RiskScore returns generated.RiskScoreResolver implementation.
 func (r *Resolver) RiskScore() generated.RiskScoreResolver { return &riskScoreResolver{r} }
 
+// SanctionsListVersion returns generated.SanctionsListVersionResolver implementation.
+func (r *Resolver) SanctionsListVersion() generated.SanctionsListVersionResolver {
+	return &sanctionsListVersionResolver{r}
+}
+
+// SanctionsScreeningResult returns generated.SanctionsScreeningResultResolver implementation.
+func (r *Resolver) SanctionsScreeningResult() generated.SanctionsScreeningResultResolver {
+	return &sanctionsScreeningResultResolver{r}
+}
+
+// ScreeningJob returns generated.ScreeningJobResolver implementation.
+func (r *Resolver) ScreeningJob() generated.ScreeningJobResolver { return &screeningJobResolver{r} }
+
+// SearchResult returns generated.SearchResultResolver implementation.
+func (r *Resolver) SearchResult() generated.SearchResultResolver { return &searchResultResolver{r} }
+
+// SecurityAlert returns generated.SecurityAlertResolver implementation.
+func (r *Resolver) SecurityAlert() generated.SecurityAlertResolver { return &securityAlertResolver{r} }
+
+// SecurityCase returns generated.SecurityCaseResolver implementation.
+func (r *Resolver) SecurityCase() generated.SecurityCaseResolver { return &securityCaseResolver{r} }
+
 // SocialProfiles returns generated.SocialProfilesResolver implementation.
 func (r *Resolver) SocialProfiles() generated.SocialProfilesResolver {
 	return &socialProfilesResolver{r}
 }
 
+// Subscription returns generated.SubscriptionResolver implementation.
+func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
+
+// TimeRange returns generated.TimeRangeResolver implementation.
+func (r *Resolver) TimeRange() generated.TimeRangeResolver { return &timeRangeResolver{r} }
+
 // Wallet returns generated.WalletResolver implementation.
 func (r *Resolver) Wallet() generated.WalletResolver { return &walletResolver{r} }
 
+// WalletClassification returns generated.WalletClassificationResolver implementation.
+func (r *Resolver) WalletClassification() generated.WalletClassificationResolver {
+	return &walletClassificationResolver{r}
+}
+
 // WalletConnection returns generated.WalletConnectionResolver implementation.
 func (r *Resolver) WalletConnection() generated.WalletConnectionResolver {
 	return &walletConnectionResolver{r}
@@ -186,29 +1532,38 @@ func (r *Resolver) WalletConnection() generated.WalletConnectionResolver {
 // WalletNetwork returns generated.WalletNetworkResolver implementation.
 func (r *Resolver) WalletNetwork() generated.WalletNetworkResolver { return &walletNetworkResolver{r} }
 
+type aIConversationResolver struct{ *Resolver }
+type aIDailyUsageResolver struct{ *Resolver }
+type aIMessageResolver struct{ *Resolver }
+type aIResponseResolver struct{ *Resolver }
+type aIUsageReportResolver struct{ *Resolver }
+type addressLabelResolver struct{ *Resolver }
+type caseCommentResolver struct{ *Resolver }
+type complianceNarrativeResolver struct{ *Resolver }
+type complianceNarrativeDraftResolver struct{ *Resolver }
+type complianceNarrativeEditResolver struct{ *Resolver }
+type complianceReportResolver struct{ *Resolver }
+type complianceReportEventResolver struct{ *Resolver }
+type complianceReportVerificationResolver struct{ *Resolver }
+type complianceRiskAssessmentResolver struct{ *Resolver }
 type dashboardStatsResolver struct{ *Resolver }
+type labelImportResolver struct{ *Resolver }
+type labelProposalResolver struct{ *Resolver }
+type mEVActivityResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type regulatoryFlagResolver struct{ *Resolver }
 type riskScoreResolver struct{ *Resolver }
+type sanctionsListVersionResolver struct{ *Resolver }
+type sanctionsScreeningResultResolver struct{ *Resolver }
+type screeningJobResolver struct{ *Resolver }
+type searchResultResolver struct{ *Resolver }
+type securityAlertResolver struct{ *Resolver }
+type securityCaseResolver struct{ *Resolver }
 type socialProfilesResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
+type timeRangeResolver struct{ *Resolver }
 type walletResolver struct{ *Resolver }
+type walletClassificationResolver struct{ *Resolver }
 type walletConnectionResolver struct{ *Resolver }
 type walletNetworkResolver struct{ *Resolver }
-
-// !!! WARNING !!!
-// The code below was going to be deleted when updating resolvers. It has been copied here so you have
-// one last chance to move it out of harms way if you want. There are two reasons this happens:
-//  - When renaming or deleting a resolver the old code will be put in here. You can safely delete
-//    it when you're done.
-//  - You have helper methods in this file. Move them out to keep these resolver files clean.
-/*
-	func stringPtr(s string) *string {
-	return &s
-}
-func intPtr(i int) *int {
-	return &i
-}
-func timePtr(t time.Time) *time.Time {
-	return &t
-}
-*/
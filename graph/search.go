@@ -0,0 +1,37 @@
+package graph
+
+import (
+	"context"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/infrastructure/middleware"
+)
+
+// allSearchResultTypes are the result types searched when none are requested
+var allSearchResultTypes = []entity.SearchResultType{
+	entity.SearchResultTypeWallet,
+	entity.SearchResultTypeTransaction,
+	entity.SearchResultTypeBlock,
+	entity.SearchResultTypeToken,
+	entity.SearchResultTypeLabel,
+}
+
+// searchableTypes returns the requested search result types the caller may
+// see. The label registry is restricted to analysts, so LABEL results are
+// dropped for everyone else.
+func searchableTypes(ctx context.Context, requested []entity.SearchResultType) []entity.SearchResultType {
+	if len(requested) == 0 {
+		requested = allSearchResultTypes
+	}
+	if user, ok := middleware.UserFromContext(ctx); ok && user.IsAnalyst() {
+		return requested
+	}
+
+	types := make([]entity.SearchResultType, 0, len(requested))
+	for _, t := range requested {
+		if t != entity.SearchResultTypeLabel {
+			types = append(types, t)
+		}
+	}
+	return types
+}
@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AIMessageRole identifies who wrote a conversation message
+type AIMessageRole string
+
+const (
+	AIMessageRoleUser      AIMessageRole = "USER"
+	AIMessageRoleAssistant AIMessageRole = "ASSISTANT"
+)
+
+// AIConversation is a user's thread of questions and answers with the AI assistant
+type AIConversation struct {
+	ID            uint           `json:"id" gorm:"primaryKey"`
+	UserID        uint           `json:"user_id" gorm:"not null;index"`
+	Title         string         `json:"title" gorm:"not null"`
+	WalletAddress *string        `json:"wallet_address,omitempty" gorm:"index"`
+	TokensUsed    int            `json:"tokens_used" gorm:"not null;default:0"`
+	MessageCount  int            `json:"message_count" gorm:"not null;default:0"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at" gorm:"index"`
+	DeletedAt     gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+}
+
+// AIMessage is one question or answer in a conversation
+type AIMessage struct {
+	ID               uint          `json:"id" gorm:"primaryKey"`
+	ConversationID   uint          `json:"conversation_id" gorm:"not null;index"`
+	Role             AIMessageRole `json:"role" gorm:"not null"`
+	Content          string        `json:"content" gorm:"type:text;not null"`
+	Sources          []string      `json:"sources" gorm:"serializer:json"`
+	RelatedQuestions []string      `json:"related_questions" gorm:"serializer:json"`
+	ActionItems      []string      `json:"action_items" gorm:"serializer:json"`
+	Confidence       *float64      `json:"confidence,omitempty"`
+	Model            *string       `json:"model,omitempty"`
+	TokensUsed       int           `json:"tokens_used" gorm:"not null;default:0"`
+	CreatedAt        time.Time     `json:"created_at" gorm:"index"`
+}
+
+// AIChatRequest is a question asked within a conversation
+type AIChatRequest struct {
+	Question      string
+	History       []AIMessage
+	Context       *AIContext
+	WalletAddress *string
+}
+
+// AIConversationTurn is a stored answer together with its updated conversation
+type AIConversationTurn struct {
+	Conversation *AIConversation `json:"conversation"`
+	Message      *AIMessage      `json:"message"`
+}
+
+// AIStreamEvent is pushed to subscribers while an answer is generated. Deltas
+// carry answer text as it arrives; the final event has Done set and carries the
+// stored message, which supersedes the streamed text.
+type AIStreamEvent struct {
+	ConversationID uint       `json:"conversation_id"`
+	Delta          string     `json:"delta"`
+	Done           bool       `json:"done"`
+	Message        *AIMessage `json:"message,omitempty"`
+	Error          *string    `json:"error,omitempty"`
+}
+
+// EstimateTokens approximates the model token count of text (about four
+// characters per token for English prose)
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// TableName returns the table name for AIConversation
+func (AIConversation) TableName() string {
+	return "ai_conversations"
+}
+
+// TableName returns the table name for AIMessage
+func (AIMessage) TableName() string {
+	return "ai_messages"
+}
@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+// AIUsage records the tokens consumed by one AI request
+type AIUsage struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	UserID         uint      `json:"user_id" gorm:"not null;index:idx_ai_usage_user_created"`
+	Role           UserRole  `json:"role" gorm:"not null;index"`
+	ConversationID *uint     `json:"conversation_id,omitempty" gorm:"index"`
+	Model          string    `json:"model"`
+	TokensUsed     int       `json:"tokens_used" gorm:"not null;default:0"`
+	Cached         bool      `json:"cached" gorm:"not null;default:false"`
+	CreatedAt      time.Time `json:"created_at" gorm:"index:idx_ai_usage_user_created"`
+}
+
+// AIDailyUsage is a user's AI usage on one UTC day
+type AIDailyUsage struct {
+	Date           time.Time `json:"date"`
+	TokensUsed     int       `json:"tokens_used"`
+	Requests       int       `json:"requests"`
+	CachedRequests int       `json:"cached_requests"`
+}
+
+// AIUsageReport summarizes a user's AI usage against their daily token budget.
+// DailyLimit and RemainingToday are nil when the budget is unlimited.
+type AIUsageReport struct {
+	UserID         uint           `json:"user_id"`
+	Role           UserRole       `json:"role"`
+	DailyLimit     *int           `json:"daily_limit,omitempty"`
+	UsedToday      int            `json:"used_today"`
+	RemainingToday *int           `json:"remaining_today,omitempty"`
+	ResetsAt       time.Time      `json:"resets_at"`
+	Days           []AIDailyUsage `json:"days"`
+}
+
+// TableName returns the table name for AIUsage
+func (AIUsage) TableName() string {
+	return "ai_usage"
+}
@@ -0,0 +1,63 @@
+package entity
+
+import "time"
+
+// ClassificationSource identifies the kind of signal behind a piece of evidence
+type ClassificationSource string
+
+const (
+	ClassificationSourceLabel     ClassificationSource = "LABEL"
+	ClassificationSourceContract  ClassificationSource = "CONTRACT"
+	ClassificationSourceBalance   ClassificationSource = "BALANCE"
+	ClassificationSourceFanIn     ClassificationSource = "FAN_IN"
+	ClassificationSourceBehaviour ClassificationSource = "BEHAVIOUR"
+	ClassificationSourceMEV       ClassificationSource = "MEV"
+	ClassificationSourceSanctions ClassificationSource = "SANCTIONS"
+	ClassificationSourceAlerts    ClassificationSource = "ALERTS"
+	ClassificationSourceManual    ClassificationSource = "MANUAL"
+	ClassificationSourceDefault   ClassificationSource = "DEFAULT"
+)
+
+// ClassificationEvidence is one signal in favour of a wallet type. Weights are
+// probabilities in (0, 1]; independent evidence for the same type is combined so
+// that more evidence always raises the score without exceeding 1.
+type ClassificationEvidence struct {
+	WalletType  WalletType           `bson:"wallet_type" json:"wallet_type"`
+	Source      ClassificationSource `bson:"source" json:"source"`
+	Description string               `bson:"description" json:"description"`
+	Weight      float64              `bson:"weight" json:"weight"`
+}
+
+// WalletTypeScore is the combined score of the evidence for one wallet type
+type WalletTypeScore struct {
+	WalletType WalletType `bson:"wallet_type" json:"wallet_type"`
+	Score      float64    `bson:"score" json:"score"`
+}
+
+// WalletFeatures are the behaviour features computed from a wallet's recent transactions
+type WalletFeatures struct {
+	SampledTransactions int     `bson:"sampled_transactions" json:"sampled_transactions"`
+	Incoming            int     `bson:"incoming" json:"incoming"`
+	Outgoing            int     `bson:"outgoing" json:"outgoing"`
+	DistinctSenders     int     `bson:"distinct_senders" json:"distinct_senders"`
+	DistinctRecipients  int     `bson:"distinct_recipients" json:"distinct_recipients"`
+	ContractCallRatio   float64 `bson:"contract_call_ratio" json:"contract_call_ratio"`
+	TransactionsPerDay  float64 `bson:"transactions_per_day" json:"transactions_per_day"`
+	BalanceETH          float64 `bson:"balance_eth" json:"balance_eth"`
+	ConnectionCount     int64   `bson:"connection_count" json:"connection_count"`
+}
+
+// WalletClassification is the wallet type the classifier assigned, with the
+// evidence for it. Confidence is the winning type's score (0-1).
+type WalletClassification struct {
+	Address    string     `bson:"address" json:"address"`
+	WalletType WalletType `bson:"wallet_type" json:"wallet_type"`
+	Confidence float64    `bson:"confidence" json:"confidence"`
+	// Evidence supports the winning type, strongest first
+	Evidence []ClassificationEvidence `bson:"evidence" json:"evidence"`
+	// Scores of every type with evidence, highest first
+	Scores       []WalletTypeScore `bson:"scores" json:"scores"`
+	Features     WalletFeatures    `bson:"features" json:"features"`
+	PreviousType *WalletType       `bson:"previous_type,omitempty" json:"previous_type,omitempty"`
+	ClassifiedAt time.Time         `bson:"classified_at" json:"classified_at"`
+}
@@ -0,0 +1,131 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ComplianceReportAction represents a step in the compliance report workflow
+type ComplianceReportAction string
+
+const (
+	ComplianceReportActionGenerated ComplianceReportAction = "GENERATED"
+	ComplianceReportActionSubmitted ComplianceReportAction = "SUBMITTED"
+	ComplianceReportActionApproved  ComplianceReportAction = "APPROVED"
+	ComplianceReportActionRejected  ComplianceReportAction = "REJECTED"
+	ComplianceReportActionFiled     ComplianceReportAction = "FILED"
+
+	ComplianceReportActionNarrativeDrafted ComplianceReportAction = "NARRATIVE_DRAFTED"
+	ComplianceReportActionNarrativeEdited  ComplianceReportAction = "NARRATIVE_EDITED"
+)
+
+// ComplianceReportEvent is one entry in a report's append-only, hash-chained history.
+// Each entry commits to the report content at that point and to the previous entry.
+type ComplianceReportEvent struct {
+	ID           string                 `bson:"id" json:"id"`
+	ReportID     string                 `bson:"report_id" json:"report_id"`
+	Sequence     int                    `bson:"sequence" json:"sequence"`
+	Action       ComplianceReportAction `bson:"action" json:"action"`
+	FromStatus   ComplianceReportStatus `bson:"from_status" json:"from_status"`
+	ToStatus     ComplianceReportStatus `bson:"to_status" json:"to_status"`
+	ActorID      uint                   `bson:"actor_id" json:"actor_id"`
+	Actor        string                 `bson:"actor" json:"actor"`
+	ActorRole    UserRole               `bson:"actor_role" json:"actor_role"`
+	Comment      *string                `bson:"comment,omitempty" json:"comment,omitempty"`
+	ReportHash   string                 `bson:"report_hash" json:"report_hash"`
+	PreviousHash string                 `bson:"previous_hash" json:"previous_hash"`
+	Hash         string                 `bson:"hash" json:"hash"`
+	CreatedAt    time.Time              `bson:"created_at" json:"created_at"`
+}
+
+// ComplianceReportVerification is the result of checking a report against its history
+type ComplianceReportVerification struct {
+	ReportID         string    `json:"report_id"`
+	Valid            bool      `json:"valid"`
+	ChainIntact      bool      `json:"chain_intact"`
+	ContentUnchanged bool      `json:"content_unchanged"`
+	EventCount       int       `json:"event_count"`
+	BrokenAtSequence *int      `json:"broken_at_sequence,omitempty"`
+	RecordedHash     string    `json:"recorded_hash"`
+	CurrentHash      string    `json:"current_hash"`
+	VerifiedAt       time.Time `json:"verified_at"`
+}
+
+// ComputeHash returns the SHA-256 over the event's fields and the previous entry's hash
+func (e *ComplianceReportEvent) ComputeHash() string {
+	comment := ""
+	if e.Comment != nil {
+		comment = *e.Comment
+	}
+
+	fields := []string{
+		e.PreviousHash,
+		e.ReportID,
+		fmt.Sprintf("%d", e.Sequence),
+		string(e.Action),
+		string(e.FromStatus),
+		string(e.ToStatus),
+		fmt.Sprintf("%d", e.ActorID),
+		e.Actor,
+		string(e.ActorRole),
+		comment,
+		e.ReportHash,
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// ContentHash returns the SHA-256 of the report's substantive content. Status and
+// Metadata are excluded because the workflow changes them; times are normalized to
+// the millisecond UTC precision they are stored at.
+func (cr *ComplianceReport) ContentHash() (string, error) {
+	content := *cr
+	content.Status = ""
+	content.Metadata = nil
+	content.GeneratedAt = normalizeTime(cr.GeneratedAt)
+	content.TimeRange = TimeRange{Start: normalizeTime(cr.TimeRange.Start), End: normalizeTime(cr.TimeRange.End)}
+	content.RiskAssessment.NextReviewDate = normalizeTime(cr.RiskAssessment.NextReviewDate)
+
+	content.RegulatoryFlags = make([]RegulatoryFlag, len(cr.RegulatoryFlags))
+	for i, flag := range cr.RegulatoryFlags {
+		if flag.Deadline != nil {
+			deadline := normalizeTime(*flag.Deadline)
+			flag.Deadline = &deadline
+		}
+		content.RegulatoryFlags[i] = flag
+	}
+
+	if cr.Narrative != nil {
+		narrative := *cr.Narrative
+		if narrative.AIDraft != nil {
+			draft := *narrative.AIDraft
+			draft.DraftedAt = normalizeTime(draft.DraftedAt)
+			narrative.AIDraft = &draft
+		}
+		narrative.Edits = make([]ComplianceNarrativeEdit, len(cr.Narrative.Edits))
+		for i, edit := range cr.Narrative.Edits {
+			edit.EditedAt = normalizeTime(edit.EditedAt)
+			narrative.Edits[i] = edit
+		}
+		content.Narrative = &narrative
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode report content: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// normalizeTime drops precision and location that do not survive a database round trip
+func normalizeTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Millisecond)
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"regexp"
+	"time"
+)
+
+// ComplianceNarrative is the narrative section of a compliance report. The AI
+// draft is kept as it was generated; human edits are recorded separately, and
+// Text is the latest edit or, if there is none, the draft.
+type ComplianceNarrative struct {
+	Text      string                    `bson:"text" json:"text"`
+	Citations []string                  `bson:"citations" json:"citations"`
+	AIDraft   *ComplianceNarrativeDraft `bson:"ai_draft,omitempty" json:"ai_draft,omitempty"`
+	Edits     []ComplianceNarrativeEdit `bson:"edits" json:"edits"`
+}
+
+// ComplianceNarrativeDraft is a narrative as written by the AI assistant
+type ComplianceNarrativeDraft struct {
+	Text       string    `bson:"text" json:"text"`
+	Citations  []string  `bson:"citations" json:"citations"`
+	Model      string    `bson:"model" json:"model"`
+	TokensUsed int       `bson:"tokens_used" json:"tokens_used"`
+	DraftedBy  string    `bson:"drafted_by" json:"drafted_by"`
+	DraftedAt  time.Time `bson:"drafted_at" json:"drafted_at"`
+}
+
+// ComplianceNarrativeEdit is one human revision of a narrative
+type ComplianceNarrativeEdit struct {
+	Text      string    `bson:"text" json:"text"`
+	Citations []string  `bson:"citations" json:"citations"`
+	EditedBy  string    `bson:"edited_by" json:"edited_by"`
+	EditedAt  time.Time `bson:"edited_at" json:"edited_at"`
+}
+
+// narrativeCitationPattern matches finding citations such as [F-001]
+var narrativeCitationPattern = regexp.MustCompile(`\[(F-\d+)\]`)
+
+// IsEdited reports whether a person has changed the narrative
+func (n *ComplianceNarrative) IsEdited() bool {
+	return len(n.Edits) > 0
+}
+
+// NarrativeCitations returns the IDs of the report's findings cited in text, in
+// order of first citation. Citations of findings the report does not have are ignored.
+func (cr *ComplianceReport) NarrativeCitations(text string) []string {
+	findings := make(map[string]bool, len(cr.Findings))
+	for _, finding := range cr.Findings {
+		findings[finding.ID] = true
+	}
+
+	citations := []string{}
+	seen := make(map[string]bool)
+	for _, match := range narrativeCitationPattern.FindAllStringSubmatch(text, -1) {
+		id := match[1]
+		if findings[id] && !seen[id] {
+			seen[id] = true
+			citations = append(citations, id)
+		}
+	}
+	return citations
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TransactionCursor is a position in the order transactions were crawled. Ties on
+// CrawledAt are broken by the document ID so no transaction is skipped or repeated.
+type TransactionCursor struct {
+	CrawledAt time.Time          `bson:"crawled_at" json:"crawled_at"`
+	ID        primitive.ObjectID `bson:"transaction_id" json:"transaction_id"`
+}
+
+// DetectionCheckpoint records how far the detection runner has processed the
+// transaction stream, so a restart resumes where it stopped
+type DetectionCheckpoint struct {
+	Name         string            `bson:"name" json:"name"`
+	Cursor       TransactionCursor `bson:"cursor" json:"cursor"`
+	Processed    int64             `bson:"processed" json:"processed"`
+	AlertsRaised int64             `bson:"alerts_raised" json:"alerts_raised"`
+	UpdatedAt    time.Time         `bson:"updated_at" json:"updated_at"`
+}
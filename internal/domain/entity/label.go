@@ -0,0 +1,253 @@
+package entity
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// LabelCategory classifies what an address label says the address belongs to
+type LabelCategory string
+
+const (
+	LabelCategoryExchange    LabelCategory = "EXCHANGE"
+	LabelCategoryDefi        LabelCategory = "DEFI"
+	LabelCategoryBridge      LabelCategory = "BRIDGE"
+	LabelCategoryMiner       LabelCategory = "MINER"
+	LabelCategoryMarketMaker LabelCategory = "MARKET_MAKER"
+	LabelCategoryMEVBot      LabelCategory = "MEV_BOT"
+	LabelCategoryMixer       LabelCategory = "MIXER"
+	LabelCategoryScam        LabelCategory = "SCAM"
+	LabelCategoryHacker      LabelCategory = "HACKER"
+	LabelCategorySanctioned  LabelCategory = "SANCTIONED"
+	LabelCategoryIndividual  LabelCategory = "INDIVIDUAL"
+	LabelCategoryOther       LabelCategory = "OTHER"
+)
+
+// IsValid reports whether c is a known label category
+func (c LabelCategory) IsValid() bool {
+	switch c {
+	case LabelCategoryExchange, LabelCategoryDefi, LabelCategoryBridge, LabelCategoryMiner,
+		LabelCategoryMarketMaker, LabelCategoryMEVBot, LabelCategoryMixer, LabelCategoryScam,
+		LabelCategoryHacker, LabelCategorySanctioned, LabelCategoryIndividual, LabelCategoryOther:
+		return true
+	}
+	return false
+}
+
+// LabelSourceType distinguishes how a label entered the registry
+type LabelSourceType string
+
+const (
+	// LabelSourceTypeManual labels were entered by an analyst
+	LabelSourceTypeManual LabelSourceType = "MANUAL"
+	// LabelSourceTypeImport labels came from an imported label pack
+	LabelSourceTypeImport LabelSourceType = "IMPORT"
+	// LabelSourceTypeCommunity labels are moderator-approved user proposals
+	LabelSourceTypeCommunity LabelSourceType = "COMMUNITY"
+)
+
+// sourceTypeRank orders source types when labels conflict; higher wins
+var sourceTypeRank = map[LabelSourceType]int{
+	LabelSourceTypeCommunity: 1,
+	LabelSourceTypeImport:    2,
+	LabelSourceTypeManual:    3,
+}
+
+// LabelPackFormat is the file format of a label pack
+type LabelPackFormat string
+
+const (
+	LabelPackFormatCSV  LabelPackFormat = "CSV"
+	LabelPackFormatJSON LabelPackFormat = "JSON"
+)
+
+// LabelProvenance records where a label came from
+type LabelProvenance struct {
+	ImportID  *string `bson:"import_id,omitempty" json:"import_id,omitempty"`
+	FileName  *string `bson:"file_name,omitempty" json:"file_name,omitempty"`
+	Reference *string `bson:"reference,omitempty" json:"reference,omitempty"`
+	Notes     *string `bson:"notes,omitempty" json:"notes,omitempty"`
+	// ProposalID is the community proposal the label was approved from
+	ProposalID *string `bson:"proposal_id,omitempty" json:"proposal_id,omitempty"`
+}
+
+// AddressLabel is one source's attribution of an address. A source holds at
+// most one label of a given name per address.
+type AddressLabel struct {
+	ID         string          `bson:"id" json:"id"`
+	Address    string          `bson:"address" json:"address"`
+	Name       string          `bson:"name" json:"name"`
+	Entity     *string         `bson:"entity,omitempty" json:"entity,omitempty"`
+	Category   LabelCategory   `bson:"category" json:"category"`
+	Tags       []string        `bson:"tags" json:"tags"`
+	Source     string          `bson:"source" json:"source"`
+	SourceType LabelSourceType `bson:"source_type" json:"source_type"`
+	// Confidence is the source's confidence in the label (0-1)
+	Confidence float64         `bson:"confidence" json:"confidence"`
+	ValidFrom  *time.Time      `bson:"valid_from,omitempty" json:"valid_from,omitempty"`
+	ValidUntil *time.Time      `bson:"valid_until,omitempty" json:"valid_until,omitempty"`
+	Provenance LabelProvenance `bson:"provenance" json:"provenance"`
+
+	CreatedByID uint      `bson:"created_by_id" json:"created_by_id"`
+	CreatedBy   string    `bson:"created_by" json:"created_by"`
+	CreatedAt   time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// IsValidAt reports whether the label applies at t
+func (l *AddressLabel) IsValidAt(t time.Time) bool {
+	if l.ValidFrom != nil && t.Before(*l.ValidFrom) {
+		return false
+	}
+	if l.ValidUntil != nil && !t.Before(*l.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+// tagsOnly reports whether the label carries tags without attributing the
+// address to a category or entity, as community tag proposals do
+func (l *AddressLabel) tagsOnly() bool {
+	return l.Category == LabelCategoryOther && l.Entity == nil
+}
+
+// agreesWith reports whether two labels attribute the address to the same
+// thing. A label that only carries tags agrees with any label.
+func (l *AddressLabel) agreesWith(other *AddressLabel) bool {
+	if l.tagsOnly() || other.tagsOnly() {
+		return true
+	}
+	if l.Category != other.Category {
+		return false
+	}
+	if l.Entity == nil || other.Entity == nil {
+		return true
+	}
+	return strings.EqualFold(*l.Entity, *other.Entity)
+}
+
+// LabelFilters represents filters for listing address labels
+type LabelFilters struct {
+	Address        *string        `json:"address,omitempty"`
+	Source         *string        `json:"source,omitempty"`
+	Category       *LabelCategory `json:"category,omitempty"`
+	Search         *string        `json:"search,omitempty"`
+	IncludeExpired bool           `json:"include_expired,omitempty"`
+}
+
+// LabelImport records one import of a label pack
+type LabelImport struct {
+	ID           string          `bson:"id" json:"id"`
+	Source       string          `bson:"source" json:"source"`
+	Format       LabelPackFormat `bson:"format" json:"format"`
+	FileName     string          `bson:"file_name" json:"file_name"`
+	Checksum     string          `bson:"checksum" json:"checksum"`
+	TotalRows    int             `bson:"total_rows" json:"total_rows"`
+	Created      int             `bson:"created" json:"created"`
+	Updated      int             `bson:"updated" json:"updated"`
+	Rejected     int             `bson:"rejected" json:"rejected"`
+	Errors       []string        `bson:"errors" json:"errors"`
+	Addresses    int             `bson:"addresses" json:"addresses"`
+	ImportedByID uint            `bson:"imported_by_id" json:"imported_by_id"`
+	ImportedBy   string          `bson:"imported_by" json:"imported_by"`
+	ImportedAt   time.Time       `bson:"imported_at" json:"imported_at"`
+}
+
+// AddressAttribution is the resolved view of an address's labels: the winning
+// label, the labels that agree with it and those that contradict it
+type AddressAttribution struct {
+	Address string `json:"address"`
+	// Label is the winning label, nil when no currently valid label attributes
+	// the address
+	Label *AddressLabel `json:"label,omitempty"`
+	// Labels are every currently valid label, winner first
+	Labels []AddressLabel `json:"labels"`
+	// Conflicts are valid labels that attribute the address differently than the winner
+	Conflicts           []AddressLabel `json:"conflicts"`
+	Tags                []string       `json:"tags"`
+	AssociatedExchanges []string       `json:"associated_exchanges"`
+	AssociatedProtocols []string       `json:"associated_protocols"`
+}
+
+// ResolveAttribution resolves an address's labels as of now. When sources
+// disagree, manual labels beat imported ones and imported labels beat community
+// ones, then higher confidence wins, then the most recently updated label. A
+// label that only carries tags never wins, but its tags are kept. Tags and
+// associations are taken from the labels that agree with the winner, so a
+// contradicted label cannot leak into them.
+func ResolveAttribution(address string, labels []AddressLabel, now time.Time) *AddressAttribution {
+	attribution := &AddressAttribution{
+		Address:             NormalizeAddress(address),
+		Labels:              []AddressLabel{},
+		Conflicts:           []AddressLabel{},
+		Tags:                []string{},
+		AssociatedExchanges: []string{},
+		AssociatedProtocols: []string{},
+	}
+
+	for _, label := range labels {
+		if label.IsValidAt(now) {
+			attribution.Labels = append(attribution.Labels, label)
+		}
+	}
+	if len(attribution.Labels) == 0 {
+		return attribution
+	}
+
+	sort.SliceStable(attribution.Labels, func(i, j int) bool {
+		a, b := attribution.Labels[i], attribution.Labels[j]
+		if a.tagsOnly() != b.tagsOnly() {
+			return b.tagsOnly()
+		}
+		if sourceTypeRank[a.SourceType] != sourceTypeRank[b.SourceType] {
+			return sourceTypeRank[a.SourceType] > sourceTypeRank[b.SourceType]
+		}
+		if a.Confidence != b.Confidence {
+			return a.Confidence > b.Confidence
+		}
+		return a.UpdatedAt.After(b.UpdatedAt)
+	})
+
+	winner := attribution.Labels[0]
+	if !winner.tagsOnly() {
+		attribution.Label = &winner
+	}
+
+	tags := make(map[string]bool)
+	exchanges := make(map[string]bool)
+	protocols := make(map[string]bool)
+	add := func(set map[string]bool, list *[]string, value string) {
+		key := strings.ToLower(value)
+		if value == "" || set[key] {
+			return
+		}
+		set[key] = true
+		*list = append(*list, value)
+	}
+
+	for i := range attribution.Labels {
+		label := &attribution.Labels[i]
+		if !label.agreesWith(&winner) {
+			attribution.Conflicts = append(attribution.Conflicts, *label)
+			continue
+		}
+		for _, tag := range label.Tags {
+			add(tags, &attribution.Tags, tag)
+		}
+		if label.Entity == nil {
+			continue
+		}
+		switch label.Category {
+		case LabelCategoryExchange:
+			add(exchanges, &attribution.AssociatedExchanges, *label.Entity)
+		case LabelCategoryDefi, LabelCategoryBridge:
+			add(protocols, &attribution.AssociatedProtocols, *label.Entity)
+		}
+	}
+	if attribution.Label != nil {
+		add(tags, &attribution.Tags, strings.ToLower(string(winner.Category)))
+	}
+
+	return attribution
+}
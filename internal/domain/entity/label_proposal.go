@@ -0,0 +1,105 @@
+package entity
+
+import "time"
+
+// LabelProposalKind is what a community proposal asks to record about an address
+type LabelProposalKind string
+
+const (
+	// LabelProposalKindLabel proposes a named label
+	LabelProposalKindLabel LabelProposalKind = "LABEL"
+	// LabelProposalKindTag proposes tags without attributing the address
+	LabelProposalKindTag LabelProposalKind = "TAG"
+	// LabelProposalKindScamReport reports the address as a scam
+	LabelProposalKindScamReport LabelProposalKind = "SCAM_REPORT"
+)
+
+// IsValid reports whether k is a known proposal kind
+func (k LabelProposalKind) IsValid() bool {
+	switch k {
+	case LabelProposalKindLabel, LabelProposalKindTag, LabelProposalKindScamReport:
+		return true
+	}
+	return false
+}
+
+// LabelProposalStatus is where a proposal is in moderation
+type LabelProposalStatus string
+
+const (
+	LabelProposalStatusPending  LabelProposalStatus = "PENDING"
+	LabelProposalStatusApproved LabelProposalStatus = "APPROVED"
+	LabelProposalStatusRejected LabelProposalStatus = "REJECTED"
+)
+
+// LabelProposal is a user's proposed label, tags or scam report for an address,
+// waiting for or past moderation
+type LabelProposal struct {
+	ID            string              `bson:"id" json:"id"`
+	Kind          LabelProposalKind   `bson:"kind" json:"kind"`
+	Address       string              `bson:"address" json:"address"`
+	Name          *string             `bson:"name,omitempty" json:"name,omitempty"`
+	Entity        *string             `bson:"entity,omitempty" json:"entity,omitempty"`
+	Category      *LabelCategory      `bson:"category,omitempty" json:"category,omitempty"`
+	Tags          []string            `bson:"tags" json:"tags"`
+	Description   string              `bson:"description" json:"description"`
+	EvidenceLinks []string            `bson:"evidence_links" json:"evidence_links"`
+	Status        LabelProposalStatus `bson:"status" json:"status"`
+
+	SubmitterID uint   `bson:"submitter_id" json:"submitter_id"`
+	Submitter   string `bson:"submitter" json:"submitter"`
+
+	ReviewerID *uint      `bson:"reviewer_id,omitempty" json:"reviewer_id,omitempty"`
+	Reviewer   *string    `bson:"reviewer,omitempty" json:"reviewer,omitempty"`
+	ReviewNote *string    `bson:"review_note,omitempty" json:"review_note,omitempty"`
+	ReviewedAt *time.Time `bson:"reviewed_at,omitempty" json:"reviewed_at,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// LabelProposalFilters represents filters for listing label proposals
+type LabelProposalFilters struct {
+	Status      *LabelProposalStatus `json:"status,omitempty"`
+	Kind        *LabelProposalKind   `json:"kind,omitempty"`
+	Address     *string              `json:"address,omitempty"`
+	SubmitterID *uint                `json:"submitter_id,omitempty"`
+}
+
+// SubmitterReputation tracks how a user's proposals fared in moderation
+type SubmitterReputation struct {
+	UserID    uint      `bson:"user_id" json:"user_id"`
+	Submitted int       `bson:"submitted" json:"submitted"`
+	Approved  int       `bson:"approved" json:"approved"`
+	Rejected  int       `bson:"rejected" json:"rejected"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// Pending is the number of the user's proposals still awaiting moderation
+func (r *SubmitterReputation) Pending() int {
+	if pending := r.Submitted - r.Approved - r.Rejected; pending > 0 {
+		return pending
+	}
+	return 0
+}
+
+// Score is the smoothed share of the user's reviewed proposals that were
+// approved (0-1). New submitters start at 0.5 and move towards their record as
+// proposals are reviewed.
+func (r *SubmitterReputation) Score() float64 {
+	return float64(r.Approved+1) / float64(r.Approved+r.Rejected+2)
+}
+
+// ScamReportRiskFactor converts the number of distinct users whose scam reports
+// for an address were approved into the scam risk factor (0-100). One approved
+// report is already a strong signal; corroboration raises it towards the cap.
+func ScamReportRiskFactor(reporters int) int {
+	if reporters <= 0 {
+		return 0
+	}
+	factor := 45 + 15*reporters
+	if factor > 95 {
+		factor = 95
+	}
+	return factor
+}
@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+// MEVType represents the kind of MEV extraction
+type MEVType string
+
+const (
+	MEVTypeSandwich  MEVType = "SANDWICH"
+	MEVTypeArbitrage MEVType = "ARBITRAGE"
+)
+
+// MEVVictim is a transaction that was sandwiched, executing at a worse price
+type MEVVictim struct {
+	Address     string `bson:"address" json:"address"`
+	Transaction string `bson:"transaction" json:"transaction"`
+}
+
+// MEVActivity is a sandwich attack or atomic arbitrage found in a block.
+// Extracted value is in raw units of ExtractedToken, the token the bot started
+// and ended with; ExtractedValueETH is set when that token is WETH.
+type MEVActivity struct {
+	ID          string  `bson:"id" json:"id"`
+	Type        MEVType `bson:"type" json:"type"`
+	BotAddress  string  `bson:"bot_address" json:"bot_address"`
+	BotContract *string `bson:"bot_contract,omitempty" json:"bot_contract,omitempty"`
+	// Transactions are in block order: front-run and back-run for a sandwich,
+	// the single arbitrage transaction otherwise
+	Transactions      []string    `bson:"transactions" json:"transactions"`
+	Victims           []MEVVictim `bson:"victims,omitempty" json:"victims,omitempty"`
+	Pools             []string    `bson:"pools" json:"pools"`
+	BlockNumber       string      `bson:"block_number" json:"block_number"`
+	Network           string      `bson:"network" json:"network"`
+	ExtractedToken    string      `bson:"extracted_token" json:"extracted_token"`
+	ExtractedValue    string      `bson:"extracted_value" json:"extracted_value"`
+	ExtractedValueETH *float64    `bson:"extracted_value_eth,omitempty" json:"extracted_value_eth,omitempty"`
+	// DedupeKey identifies the activity so re-processing a block does not record it twice
+	DedupeKey  string    `bson:"dedupe_key" json:"dedupe_key"`
+	Timestamp  time.Time `bson:"timestamp" json:"timestamp"`
+	DetectedAt time.Time `bson:"detected_at" json:"detected_at"`
+}
+
+// MEVFilters represents filters for MEV activity queries
+type MEVFilters struct {
+	// WalletAddress matches the bot or any victim
+	WalletAddress *string  `json:"wallet_address,omitempty"`
+	Type          *MEVType `json:"type,omitempty"`
+}
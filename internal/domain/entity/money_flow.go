@@ -72,8 +72,8 @@ type TopToken struct {
 
 // TimeRange represents a time range
 type TimeRange struct {
-	Start time.Time `json:"start"`
-	End   time.Time `json:"end"`
+	Start time.Time `bson:"start" json:"start"`
+	End   time.Time `bson:"end" json:"end"`
 }
 
 // MoneyFlowSummary represents summary statistics for money flow
@@ -29,6 +29,8 @@ type NetworkInfo struct {
 	Description       *string         `json:"description,omitempty"`
 	Website           *string         `json:"website,omitempty"`
 	Explorer          *string         `json:"explorer,omitempty"`
+	// Stale marks data served from cache while Neo4j is unavailable
+	Stale bool `json:"stale"`
 }
 
 // NetworkStats represents statistics for a specific network
@@ -41,6 +43,8 @@ type NetworkStats struct {
 	WalletTypes       WalletTypeDistribution `json:"wallet_types"`
 	RiskDistribution  RiskDistribution       `json:"risk_distribution"`
 	LastUpdate        time.Time              `json:"last_update"`
+	// Stale marks statistics served from cache while Neo4j is unavailable
+	Stale bool `json:"stale"`
 }
 
 // WalletTypeDistribution represents the distribution of wallet types
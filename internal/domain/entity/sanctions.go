@@ -0,0 +1,131 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	evmAddressPattern    = regexp.MustCompile(`^0x[0-9a-f]{40}$`)
+	base58AddressPattern = regexp.MustCompile(`^[1-9A-HJ-NP-Za-km-z]{25,44}$`)
+	bech32AddressPattern = regexp.MustCompile(`^[a-z]{2,4}1[02-9ac-hj-np-z]{6,87}$`)
+)
+
+// SanctionsSource identifies where a sanctions list came from
+type SanctionsSource string
+
+const (
+	SanctionsSourceOFACSDN    SanctionsSource = "OFAC_SDN"
+	SanctionsSourceGenericCSV SanctionsSource = "GENERIC_CSV"
+)
+
+// SanctionsListFormat represents the file format of an imported sanctions list
+type SanctionsListFormat string
+
+const (
+	SanctionsListFormatOFACXML    SanctionsListFormat = "OFAC_XML"
+	SanctionsListFormatOFACCSV    SanctionsListFormat = "OFAC_CSV"
+	SanctionsListFormatGenericCSV SanctionsListFormat = "GENERIC_CSV"
+)
+
+// Source returns the sanctions source a list format belongs to
+func (f SanctionsListFormat) Source() SanctionsSource {
+	switch f {
+	case SanctionsListFormatOFACXML, SanctionsListFormatOFACCSV:
+		return SanctionsSourceOFACSDN
+	default:
+		return SanctionsSourceGenericCSV
+	}
+}
+
+// SanctionsListVersion records a single import of a sanctions list.
+// Only the latest version of each source is active.
+type SanctionsListVersion struct {
+	ID              string              `bson:"id" json:"id"`
+	Source          SanctionsSource     `bson:"source" json:"source"`
+	Format          SanctionsListFormat `bson:"format" json:"format"`
+	FileName        string              `bson:"file_name" json:"file_name"`
+	Checksum        string              `bson:"checksum" json:"checksum"`
+	PublishedAt     *time.Time          `bson:"published_at,omitempty" json:"published_at,omitempty"`
+	ImportedAt      time.Time           `bson:"imported_at" json:"imported_at"`
+	ImportedBy      string              `bson:"imported_by" json:"imported_by"`
+	EntryCount      int                 `bson:"entry_count" json:"entry_count"`
+	AddedCount      int                 `bson:"added_count" json:"added_count"`
+	RemovedCount    int                 `bson:"removed_count" json:"removed_count"`
+	PreviousVersion *string             `bson:"previous_version,omitempty" json:"previous_version,omitempty"`
+	Active          bool                `bson:"active" json:"active"`
+	SkippedRows     int                 `bson:"skipped_rows" json:"skipped_rows"`
+}
+
+// SanctionedAddress is a blockchain address designated on a sanctions list
+type SanctionedAddress struct {
+	Address     string          `bson:"address" json:"address"`
+	Currency    string          `bson:"currency" json:"currency"`
+	EntityName  string          `bson:"entity_name" json:"entity_name"`
+	EntityID    string          `bson:"entity_id" json:"entity_id"`
+	Programs    []string        `bson:"programs" json:"programs"`
+	Source      SanctionsSource `bson:"source" json:"source"`
+	ListVersion string          `bson:"list_version" json:"list_version"`
+	Remarks     string          `bson:"remarks,omitempty" json:"remarks,omitempty"`
+}
+
+// SanctionsExposure describes a sanctioned address reachable from a screened wallet
+type SanctionsExposure struct {
+	SanctionedAddress string `json:"sanctioned_address"`
+	Hops              int    `json:"hops"`
+}
+
+// SanctionsScreeningResult is the outcome of screening a single address
+type SanctionsScreeningResult struct {
+	Address      string              `json:"address"`
+	DirectMatch  bool                `json:"direct_match"`
+	Matches      []SanctionedAddress `json:"matches"`
+	Exposures    []SanctionsExposure `json:"exposures"`
+	MaxHops      int                 `json:"max_hops"`
+	ListVersions []string            `json:"list_versions"`
+	AlertIDs     []string            `json:"alert_ids"`
+	ScreenedAt   time.Time           `json:"screened_at"`
+}
+
+// SanctionsImportResult summarises an import together with the alerts it raised
+type SanctionsImportResult struct {
+	Version           *SanctionsListVersion `json:"version"`
+	ScreenedWallets   int                   `json:"screened_wallets"`
+	AlertsRaised      int                   `json:"alerts_raised"`
+	UnchangedChecksum bool                  `json:"unchanged_checksum"`
+}
+
+// IsHit reports whether the address matched or is exposed to a sanctioned address
+func (r *SanctionsScreeningResult) IsHit() bool {
+	return r.DirectMatch || len(r.Exposures) > 0
+}
+
+// ClosestExposure returns the exposure with the fewest hops, if any
+func (r *SanctionsScreeningResult) ClosestExposure() *SanctionsExposure {
+	var closest *SanctionsExposure
+	for i := range r.Exposures {
+		if closest == nil || r.Exposures[i].Hops < closest.Hops {
+			closest = &r.Exposures[i]
+		}
+	}
+	return closest
+}
+
+// NormalizeAddress canonicalises an address for sanctions matching.
+// EVM addresses are case-insensitive; other chains (e.g. base58) are kept as-is.
+func NormalizeAddress(address string) string {
+	address = strings.TrimSpace(address)
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		return strings.ToLower(address)
+	}
+	return address
+}
+
+// IsValidAddress reports whether a normalized address looks like an EVM,
+// base58 (e.g. BTC, TRON) or bech32 address
+func IsValidAddress(address string) bool {
+	return evmAddressPattern.MatchString(address) ||
+		base58AddressPattern.MatchString(address) ||
+		bech32AddressPattern.MatchString(address)
+}
@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"time"
+)
+
+// ScreeningJobStatus represents the lifecycle state of a bulk screening job
+type ScreeningJobStatus string
+
+const (
+	ScreeningJobStatusPending   ScreeningJobStatus = "PENDING"
+	ScreeningJobStatusRunning   ScreeningJobStatus = "RUNNING"
+	ScreeningJobStatusCompleted ScreeningJobStatus = "COMPLETED"
+	ScreeningJobStatusFailed    ScreeningJobStatus = "FAILED"
+)
+
+// ScreeningOptions controls what a bulk screening computes per address
+type ScreeningOptions struct {
+	Hops            int  `bson:"hops" json:"hops"`
+	IncludeExposure bool `bson:"include_exposure" json:"include_exposure"`
+	IncludeLabels   bool `bson:"include_labels" json:"include_labels"`
+	RaiseAlerts     bool `bson:"raise_alerts" json:"raise_alerts"`
+}
+
+// ScreeningJob tracks a bulk address screening request
+type ScreeningJob struct {
+	ID             string             `bson:"id" json:"id"`
+	Status         ScreeningJobStatus `bson:"status" json:"status"`
+	RequestedBy    uint               `bson:"requested_by" json:"requested_by"`
+	Source         string             `bson:"source" json:"source"` // API or CSV upload file name
+	Options        ScreeningOptions   `bson:"options" json:"options"`
+	TotalAddresses int                `bson:"total_addresses" json:"total_addresses"`
+	Processed      int                `bson:"processed" json:"processed"`
+	SanctionsHits  int                `bson:"sanctions_hits" json:"sanctions_hits"`
+	ExposureHits   int                `bson:"exposure_hits" json:"exposure_hits"`
+	InvalidCount   int                `bson:"invalid_count" json:"invalid_count"`
+	Error          *string            `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	StartedAt      *time.Time         `bson:"started_at,omitempty" json:"started_at,omitempty"`
+	CompletedAt    *time.Time         `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+}
+
+// AddressScreeningResult is the per-address outcome of a bulk screening job
+type AddressScreeningResult struct {
+	JobID               string    `bson:"job_id" json:"-"`
+	Index               int       `bson:"index" json:"index"`
+	Address             string    `bson:"address" json:"address"`
+	Valid               bool      `bson:"valid" json:"valid"`
+	RiskScore           *int      `bson:"risk_score,omitempty" json:"risk_score,omitempty"`
+	RiskLevel           RiskLevel `bson:"risk_level" json:"risk_level"`
+	Sanctioned          bool      `bson:"sanctioned" json:"sanctioned"`
+	SanctionsPrograms   []string  `bson:"sanctions_programs" json:"sanctions_programs"`
+	SanctionedEntity    *string   `bson:"sanctioned_entity,omitempty" json:"sanctioned_entity,omitempty"`
+	DirectExposure      int       `bson:"direct_exposure" json:"direct_exposure"`
+	IndirectExposure    int       `bson:"indirect_exposure" json:"indirect_exposure"`
+	ClosestExposureHops *int      `bson:"closest_exposure_hops,omitempty" json:"closest_exposure_hops,omitempty"`
+	Labels              []string  `bson:"labels" json:"labels"`
+	AlertIDs            []string  `bson:"alert_ids" json:"alert_ids"`
+	Error               *string   `bson:"error,omitempty" json:"error,omitempty"`
+}
+
+// IsFinished reports whether the job has reached a terminal state
+func (j *ScreeningJob) IsFinished() bool {
+	return j.Status == ScreeningJobStatusCompleted || j.Status == ScreeningJobStatusFailed
+}
+
+// Progress returns the fraction of addresses processed, between 0 and 1
+func (j *ScreeningJob) Progress() float64 {
+	if j.TotalAddresses == 0 {
+		return 1
+	}
+	return float64(j.Processed) / float64(j.TotalAddresses)
+}
+
+// IsHit reports whether the address is sanctioned or exposed to a sanctioned address
+func (r *AddressScreeningResult) IsHit() bool {
+	return r.Sanctioned || r.DirectExposure > 0 || r.IndirectExposure > 0
+}
@@ -0,0 +1,113 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SearchQueryKind is what a search query looks like, which decides the lookups it runs
+type SearchQueryKind string
+
+const (
+	// SearchQueryKindAddress is a complete wallet address
+	SearchQueryKindAddress SearchQueryKind = "ADDRESS"
+	// SearchQueryKindTransactionHash is a complete transaction hash
+	SearchQueryKindTransactionHash SearchQueryKind = "TRANSACTION_HASH"
+	// SearchQueryKindHexPrefix is the start of an address or transaction hash
+	SearchQueryKindHexPrefix SearchQueryKind = "HEX_PREFIX"
+	// SearchQueryKindBlockNumber is a block number
+	SearchQueryKindBlockNumber SearchQueryKind = "BLOCK_NUMBER"
+	// SearchQueryKindENSName is a dotted name such as vitalik.eth
+	SearchQueryKindENSName SearchQueryKind = "ENS_NAME"
+	// SearchQueryKindText is free text matched against labels, names and token symbols
+	SearchQueryKindText SearchQueryKind = "TEXT"
+)
+
+// SearchResultType is the kind of record a search result points to
+type SearchResultType string
+
+const (
+	SearchResultTypeWallet      SearchResultType = "WALLET"
+	SearchResultTypeTransaction SearchResultType = "TRANSACTION"
+	SearchResultTypeBlock       SearchResultType = "BLOCK"
+	SearchResultTypeToken       SearchResultType = "TOKEN"
+	SearchResultTypeLabel       SearchResultType = "LABEL"
+)
+
+// MinHexPrefixLength is the shortest hex prefix ("0x" and four digits) searched
+// as an address or hash prefix; shorter prefixes match too much to be useful
+const MinHexPrefixLength = 6
+
+var (
+	transactionHashPattern = regexp.MustCompile(`^0x[0-9a-f]{64}$`)
+	hexPrefixPattern       = regexp.MustCompile(`^0x[0-9a-f]+$`)
+	blockNumberPattern     = regexp.MustCompile(`^[0-9]{1,12}$`)
+	ensNamePattern         = regexp.MustCompile(`^([a-z0-9_-]+\.)+[a-z]{2,}$`)
+)
+
+// DetectSearchKind classifies a search query
+func DetectSearchKind(query string) SearchQueryKind {
+	query = strings.TrimSpace(query)
+	normalized := NormalizeAddress(query)
+
+	switch {
+	case transactionHashPattern.MatchString(normalized):
+		return SearchQueryKindTransactionHash
+	case IsValidAddress(normalized):
+		return SearchQueryKindAddress
+	case len(normalized) >= MinHexPrefixLength && hexPrefixPattern.MatchString(normalized):
+		return SearchQueryKindHexPrefix
+	case blockNumberPattern.MatchString(query):
+		return SearchQueryKindBlockNumber
+	case ensNamePattern.MatchString(strings.ToLower(query)):
+		return SearchQueryKindENSName
+	default:
+		return SearchQueryKindText
+	}
+}
+
+// SearchResult is one ranked hit of a unified search
+type SearchResult struct {
+	Type SearchResultType `json:"type"`
+	// ID identifies the record within its type: an address, transaction hash,
+	// block number, token contract address or label ID
+	ID       string  `json:"id"`
+	Title    string  `json:"title"`
+	Subtitle *string `json:"subtitle,omitempty"`
+	// RelevanceScore ranks results across types (0-1); exact matches score 1
+	RelevanceScore float64 `json:"relevance_score"`
+	// MatchedField is the field the query matched, such as "address" or "label"
+	MatchedField string `json:"matched_field"`
+
+	Address         *string        `json:"address,omitempty"`
+	TransactionHash *string        `json:"transaction_hash,omitempty"`
+	BlockNumber     *string        `json:"block_number,omitempty"`
+	WalletType      *WalletType    `json:"wallet_type,omitempty"`
+	RiskLevel       *RiskLevel     `json:"risk_level,omitempty"`
+	LabelCategory   *LabelCategory `json:"label_category,omitempty"`
+	Timestamp       *time.Time     `json:"timestamp,omitempty"`
+}
+
+// SearchResponse is the ranked result of a unified search
+type SearchResponse struct {
+	Query   string          `json:"query"`
+	Kind    SearchQueryKind `json:"kind"`
+	Results []SearchResult  `json:"results"`
+}
+
+// TokenMatch is a token found by symbol, name or contract address
+type TokenMatch struct {
+	Address          string  `json:"address"`
+	Symbol           string  `json:"symbol"`
+	Name             string  `json:"name"`
+	TransactionCount int64   `json:"transaction_count"`
+	RelevanceScore   float64 `json:"relevance_score"`
+	MatchedField     string  `json:"matched_field"`
+}
+
+// LabelMatch is a registry label found by text search
+type LabelMatch struct {
+	Label          AddressLabel `json:"label"`
+	RelevanceScore float64      `json:"relevance_score"`
+}
@@ -23,37 +23,44 @@ const (
 	AlertStatusActive        AlertStatus = "ACTIVE"
 	AlertStatusResolved      AlertStatus = "RESOLVED"
 	AlertStatusInvestigating AlertStatus = "INVESTIGATING"
+	AlertStatusFalsePositive AlertStatus = "FALSE_POSITIVE"
 )
 
 // SecurityAlert represents a security alert in the system
 type SecurityAlert struct {
-	ID                   string                 `json:"id"`
-	Type                 AlertType              `json:"type"`
-	Severity             AlertSeverity          `json:"severity"`
-	Title                string                 `json:"title"`
-	Description          string                 `json:"description"`
-	WalletAddress        string                 `json:"wallet_address"`
-	Timestamp            time.Time              `json:"timestamp"`
-	Status               AlertStatus            `json:"status"`
-	Confidence           int                    `json:"confidence"` // 0-100
-	RelatedTransactions  []string               `json:"related_transactions"`
-	ActionRequired       bool                   `json:"action_required"`
-	Metadata             map[string]interface{} `json:"metadata,omitempty"`
+	ID                   string                 `bson:"id" json:"id"`
+	Type                 AlertType              `bson:"type" json:"type"`
+	Severity             AlertSeverity          `bson:"severity" json:"severity"`
+	Title                string                 `bson:"title" json:"title"`
+	Description          string                 `bson:"description" json:"description"`
+	WalletAddress        string                 `bson:"wallet_address" json:"wallet_address"`
+	Timestamp            time.Time              `bson:"timestamp" json:"timestamp"`
+	Status               AlertStatus            `bson:"status" json:"status"`
+	Confidence           int                    `bson:"confidence" json:"confidence"` // 0-100
+	RelatedTransactions  []string               `bson:"related_transactions" json:"related_transactions"`
+	ActionRequired       bool                   `bson:"action_required" json:"action_required"`
+	Metadata             map[string]interface{} `bson:"metadata,omitempty" json:"metadata,omitempty"`
 	
 	// Resolution fields
-	ResolvedAt           *time.Time             `json:"resolved_at,omitempty"`
-	ResolvedBy           *string                `json:"resolved_by,omitempty"`
-	Resolution           *string                `json:"resolution,omitempty"`
-	ResolutionNotes      *string                `json:"resolution_notes,omitempty"`
+	ResolvedAt           *time.Time             `bson:"resolved_at,omitempty" json:"resolved_at,omitempty"`
+	ResolvedBy           *string                `bson:"resolved_by,omitempty" json:"resolved_by,omitempty"`
+	Resolution           *string                `bson:"resolution,omitempty" json:"resolution,omitempty"`
+	ResolutionNotes      *string                `bson:"resolution_notes,omitempty" json:"resolution_notes,omitempty"`
 	
 	// Investigation fields
-	InvestigatedAt       *time.Time             `json:"investigated_at,omitempty"`
-	InvestigatedBy       *string                `json:"investigated_by,omitempty"`
-	InvestigationNotes   *string                `json:"investigation_notes,omitempty"`
+	InvestigatedAt       *time.Time             `bson:"investigated_at,omitempty" json:"investigated_at,omitempty"`
+	InvestigatedBy       *string                `bson:"investigated_by,omitempty" json:"investigated_by,omitempty"`
+	InvestigationNotes   *string                `bson:"investigation_notes,omitempty" json:"investigation_notes,omitempty"`
 	
+	// DedupeKey identifies the underlying finding; an alert is raised at most once per key
+	DedupeKey            string                 `bson:"dedupe_key,omitempty" json:"dedupe_key,omitempty"`
+
+	// CaseID is the security case the alert is being handled in
+	CaseID               *string                `bson:"case_id,omitempty" json:"case_id,omitempty"`
+
 	// System fields
-	CreatedAt            time.Time              `json:"created_at"`
-	UpdatedAt            time.Time              `json:"updated_at"`
+	CreatedAt            time.Time              `bson:"created_at" json:"created_at"`
+	UpdatedAt            time.Time              `bson:"updated_at" json:"updated_at"`
 }
 
 // SecurityAlertFilters represents filters for security alert queries
@@ -76,19 +83,20 @@ type SecurityAlertResult struct {
 
 // ComplianceReport represents a compliance report
 type ComplianceReport struct {
-	ID               string                 `json:"id"`
-	WalletAddress    string                 `json:"wallet_address"`
-	ReportType       ComplianceReportType   `json:"report_type"`
-	GeneratedAt      time.Time              `json:"generated_at"`
-	GeneratedBy      string                 `json:"generated_by"`
-	Summary          ComplianceSummary      `json:"summary"`
-	Findings         []ComplianceFinding    `json:"findings"`
-	Recommendations  []string               `json:"recommendations"`
-	RiskAssessment   ComplianceRiskAssessment `json:"risk_assessment"`
-	RegulatoryFlags  []RegulatoryFlag       `json:"regulatory_flags"`
-	TimeRange        TimeRange              `json:"time_range"`
-	Status           ComplianceReportStatus `json:"status"`
-	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+	ID               string                 `bson:"id" json:"id"`
+	WalletAddress    string                 `bson:"wallet_address" json:"wallet_address"`
+	ReportType       ComplianceReportType   `bson:"report_type" json:"report_type"`
+	GeneratedAt      time.Time              `bson:"generated_at" json:"generated_at"`
+	GeneratedBy      string                 `bson:"generated_by" json:"generated_by"`
+	Summary          ComplianceSummary      `bson:"summary" json:"summary"`
+	Findings         []ComplianceFinding    `bson:"findings" json:"findings"`
+	Recommendations  []string               `bson:"recommendations" json:"recommendations"`
+	RiskAssessment   ComplianceRiskAssessment `bson:"risk_assessment" json:"risk_assessment"`
+	RegulatoryFlags  []RegulatoryFlag       `bson:"regulatory_flags" json:"regulatory_flags"`
+	TimeRange        TimeRange              `bson:"time_range" json:"time_range"`
+	Status           ComplianceReportStatus `bson:"status" json:"status"`
+	Narrative        *ComplianceNarrative   `bson:"narrative,omitempty" json:"narrative,omitempty"`
+	Metadata         map[string]interface{} `bson:"metadata,omitempty" json:"metadata,omitempty"`
 }
 
 // ComplianceReportType represents different types of compliance reports
@@ -116,28 +124,28 @@ const (
 
 // ComplianceSummary represents a summary of compliance findings
 type ComplianceSummary struct {
-	TotalTransactions    int64   `json:"total_transactions"`
-	TotalVolume          string  `json:"total_volume"`
-	TotalVolumeUSD       float64 `json:"total_volume_usd"`
-	HighRiskTransactions int64   `json:"high_risk_transactions"`
-	SuspiciousPatterns   int64   `json:"suspicious_patterns"`
-	RegulatoryViolations int64   `json:"regulatory_violations"`
-	OverallRiskScore     float64 `json:"overall_risk_score"`
-	ComplianceScore      float64 `json:"compliance_score"`
+	TotalTransactions    int64   `bson:"total_transactions" json:"total_transactions"`
+	TotalVolume          string  `bson:"total_volume" json:"total_volume"`
+	TotalVolumeUSD       float64 `bson:"total_volume_usd" json:"total_volume_usd"`
+	HighRiskTransactions int64   `bson:"high_risk_transactions" json:"high_risk_transactions"`
+	SuspiciousPatterns   int64   `bson:"suspicious_patterns" json:"suspicious_patterns"`
+	RegulatoryViolations int64   `bson:"regulatory_violations" json:"regulatory_violations"`
+	OverallRiskScore     float64 `bson:"overall_risk_score" json:"overall_risk_score"`
+	ComplianceScore      float64 `bson:"compliance_score" json:"compliance_score"`
 }
 
 // ComplianceFinding represents a specific compliance finding
 type ComplianceFinding struct {
-	ID                   string                 `json:"id"`
-	Type                 ComplianceFindingType  `json:"type"`
-	Severity             AlertSeverity          `json:"severity"`
-	Title                string                 `json:"title"`
-	Description          string                 `json:"description"`
-	Evidence             []string               `json:"evidence"`
-	RelatedTransactions  []string               `json:"related_transactions"`
-	RegulatoryReference  *string                `json:"regulatory_reference,omitempty"`
-	Recommendation       string                 `json:"recommendation"`
-	Metadata             map[string]interface{} `json:"metadata,omitempty"`
+	ID                   string                 `bson:"id" json:"id"`
+	Type                 ComplianceFindingType  `bson:"type" json:"type"`
+	Severity             AlertSeverity          `bson:"severity" json:"severity"`
+	Title                string                 `bson:"title" json:"title"`
+	Description          string                 `bson:"description" json:"description"`
+	Evidence             []string               `bson:"evidence" json:"evidence"`
+	RelatedTransactions  []string               `bson:"related_transactions" json:"related_transactions"`
+	RegulatoryReference  *string                `bson:"regulatory_reference,omitempty" json:"regulatory_reference,omitempty"`
+	Recommendation       string                 `bson:"recommendation" json:"recommendation"`
+	Metadata             map[string]interface{} `bson:"metadata,omitempty" json:"metadata,omitempty"`
 }
 
 // ComplianceFindingType represents different types of compliance findings
@@ -156,28 +164,28 @@ const (
 
 // ComplianceRiskAssessment represents a risk assessment for compliance
 type ComplianceRiskAssessment struct {
-	OverallRisk          RiskLevel              `json:"overall_risk"`
-	GeographicRisk       RiskLevel              `json:"geographic_risk"`
-	TransactionRisk      RiskLevel              `json:"transaction_risk"`
-	CounterpartyRisk     RiskLevel              `json:"counterparty_risk"`
-	ProductRisk          RiskLevel              `json:"product_risk"`
-	RiskFactors          []string               `json:"risk_factors"`
-	MitigatingFactors    []string               `json:"mitigating_factors"`
-	RecommendedActions   []string               `json:"recommended_actions"`
-	NextReviewDate       time.Time              `json:"next_review_date"`
+	OverallRisk          RiskLevel              `bson:"overall_risk" json:"overall_risk"`
+	GeographicRisk       RiskLevel              `bson:"geographic_risk" json:"geographic_risk"`
+	TransactionRisk      RiskLevel              `bson:"transaction_risk" json:"transaction_risk"`
+	CounterpartyRisk     RiskLevel              `bson:"counterparty_risk" json:"counterparty_risk"`
+	ProductRisk          RiskLevel              `bson:"product_risk" json:"product_risk"`
+	RiskFactors          []string               `bson:"risk_factors" json:"risk_factors"`
+	MitigatingFactors    []string               `bson:"mitigating_factors" json:"mitigating_factors"`
+	RecommendedActions   []string               `bson:"recommended_actions" json:"recommended_actions"`
+	NextReviewDate       time.Time              `bson:"next_review_date" json:"next_review_date"`
 }
 
 // RegulatoryFlag represents a regulatory flag or violation
 type RegulatoryFlag struct {
-	Type                 RegulatoryFlagType     `json:"type"`
-	Jurisdiction         string                 `json:"jurisdiction"`
-	Regulation           string                 `json:"regulation"`
-	Description          string                 `json:"description"`
-	Severity             AlertSeverity          `json:"severity"`
-	RequiredAction       string                 `json:"required_action"`
-	Deadline             *time.Time             `json:"deadline,omitempty"`
-	Status               RegulatoryFlagStatus   `json:"status"`
-	Metadata             map[string]interface{} `json:"metadata,omitempty"`
+	Type                 RegulatoryFlagType     `bson:"type" json:"type"`
+	Jurisdiction         string                 `bson:"jurisdiction" json:"jurisdiction"`
+	Regulation           string                 `bson:"regulation" json:"regulation"`
+	Description          string                 `bson:"description" json:"description"`
+	Severity             AlertSeverity          `bson:"severity" json:"severity"`
+	RequiredAction       string                 `bson:"required_action" json:"required_action"`
+	Deadline             *time.Time             `bson:"deadline,omitempty" json:"deadline,omitempty"`
+	Status               RegulatoryFlagStatus   `bson:"status" json:"status"`
+	Metadata             map[string]interface{} `bson:"metadata,omitempty" json:"metadata,omitempty"`
 }
 
 // RegulatoryFlagType represents different types of regulatory flags
@@ -214,6 +222,7 @@ type AIResponse struct {
 	GeneratedAt      time.Time `json:"generated_at"`
 	Model            string   `json:"model"`
 	TokensUsed       int      `json:"tokens_used"`
+	Cached           bool     `json:"cached"`
 }
 
 // AIContext represents context for AI queries
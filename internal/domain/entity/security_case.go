@@ -0,0 +1,131 @@
+package entity
+
+import "time"
+
+// CaseStatus represents the investigation status of a security case
+type CaseStatus string
+
+const (
+	CaseStatusActive        CaseStatus = "ACTIVE"
+	CaseStatusInvestigating CaseStatus = "INVESTIGATING"
+	CaseStatusResolved      CaseStatus = "RESOLVED"
+	CaseStatusFalsePositive CaseStatus = "FALSE_POSITIVE"
+)
+
+// CaseSLAStatus summarizes a case's progress against its SLA deadlines
+type CaseSLAStatus string
+
+const (
+	CaseSLAStatusOnTrack  CaseSLAStatus = "ON_TRACK"
+	CaseSLAStatusAtRisk   CaseSLAStatus = "AT_RISK"
+	CaseSLAStatusBreached CaseSLAStatus = "BREACHED"
+	CaseSLAStatusMet      CaseSLAStatus = "MET"
+)
+
+// caseAtRiskFraction is the share of the resolution window left at which an open
+// case is considered at risk of breaching its SLA
+const caseAtRiskFraction = 0.25
+
+// SecurityCase groups related security alerts into one investigation
+type SecurityCase struct {
+	ID              string        `bson:"id" json:"id"`
+	Title           string        `bson:"title" json:"title"`
+	Description     string        `bson:"description" json:"description"`
+	Severity        AlertSeverity `bson:"severity" json:"severity"`
+	Status          CaseStatus    `bson:"status" json:"status"`
+	AlertIDs        []string      `bson:"alert_ids" json:"alert_ids"`
+	WalletAddresses []string      `bson:"wallet_addresses" json:"wallet_addresses"`
+
+	// Assignment
+	AssigneeID *uint      `bson:"assignee_id,omitempty" json:"assignee_id,omitempty"`
+	Assignee   *string    `bson:"assignee,omitempty" json:"assignee,omitempty"`
+	AssignedAt *time.Time `bson:"assigned_at,omitempty" json:"assigned_at,omitempty"`
+
+	// SLA deadlines, set from the severity when the case is opened or escalated
+	ResponseDueAt   time.Time `bson:"response_due_at" json:"response_due_at"`
+	ResolutionDueAt time.Time `bson:"resolution_due_at" json:"resolution_due_at"`
+
+	// Investigation and resolution
+	InvestigationStartedAt *time.Time `bson:"investigation_started_at,omitempty" json:"investigation_started_at,omitempty"`
+	ClosedAt               *time.Time `bson:"closed_at,omitempty" json:"closed_at,omitempty"`
+	ClosedBy               *string    `bson:"closed_by,omitempty" json:"closed_by,omitempty"`
+	Resolution             *string    `bson:"resolution,omitempty" json:"resolution,omitempty"`
+
+	CommentCount int    `bson:"comment_count" json:"comment_count"`
+	CreatedByID  uint   `bson:"created_by_id" json:"created_by_id"`
+	CreatedBy    string `bson:"created_by" json:"created_by"`
+	// Version is incremented on every update so concurrent changes are detected
+	Version   int       `bson:"version" json:"version"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// CaseComment is a note in a case's discussion; replies reference their parent
+type CaseComment struct {
+	ID          string           `bson:"id" json:"id"`
+	CaseID      string           `bson:"case_id" json:"case_id"`
+	ParentID    *string          `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+	AuthorID    uint             `bson:"author_id" json:"author_id"`
+	Author      string           `bson:"author" json:"author"`
+	Body        string           `bson:"body" json:"body"`
+	Attachments []CaseAttachment `bson:"attachments" json:"attachments"`
+	CreatedAt   time.Time        `bson:"created_at" json:"created_at"`
+}
+
+// CaseAttachmentType identifies what a comment attachment refers to
+type CaseAttachmentType string
+
+const (
+	CaseAttachmentTypeTransaction      CaseAttachmentType = "TRANSACTION"
+	CaseAttachmentTypeWallet           CaseAttachmentType = "WALLET"
+	CaseAttachmentTypeComplianceReport CaseAttachmentType = "COMPLIANCE_REPORT"
+	CaseAttachmentTypeLink             CaseAttachmentType = "LINK"
+)
+
+// CaseAttachment is a piece of evidence attached to a comment by reference
+type CaseAttachment struct {
+	Type      CaseAttachmentType `bson:"type" json:"type"`
+	Reference string             `bson:"reference" json:"reference"`
+	Name      *string            `bson:"name,omitempty" json:"name,omitempty"`
+}
+
+// CaseFilters represents filters for listing security cases
+type CaseFilters struct {
+	Statuses      []CaseStatus   `json:"statuses,omitempty"`
+	AssigneeID    *uint          `json:"assignee_id,omitempty"`
+	Severity      *AlertSeverity `json:"severity,omitempty"`
+	WalletAddress *string        `json:"wallet_address,omitempty"`
+	Unassigned    bool           `json:"unassigned,omitempty"`
+}
+
+// IsOpen reports whether the case still needs work
+func (c *SecurityCase) IsOpen() bool {
+	return c.Status == CaseStatusActive || c.Status == CaseStatusInvestigating
+}
+
+// SLAStatus reports the case's standing against its deadlines at now. Open cases
+// breach when investigation did not start by the response deadline or the case is
+// not closed by the resolution deadline; closed cases met their SLA if they were
+// closed in time.
+func (c *SecurityCase) SLAStatus(now time.Time) CaseSLAStatus {
+	if !c.IsOpen() {
+		if c.ClosedAt != nil && c.ClosedAt.After(c.ResolutionDueAt) {
+			return CaseSLAStatusBreached
+		}
+		return CaseSLAStatusMet
+	}
+
+	if now.After(c.ResolutionDueAt) {
+		return CaseSLAStatusBreached
+	}
+	if c.InvestigationStartedAt == nil && now.After(c.ResponseDueAt) ||
+		c.InvestigationStartedAt != nil && c.InvestigationStartedAt.After(c.ResponseDueAt) {
+		return CaseSLAStatusBreached
+	}
+
+	window := c.ResolutionDueAt.Sub(c.CreatedAt)
+	if window > 0 && float64(c.ResolutionDueAt.Sub(now)) < float64(window)*caseAtRiskFraction {
+		return CaseSLAStatusAtRisk
+	}
+	return CaseSLAStatusOnTrack
+}
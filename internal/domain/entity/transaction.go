@@ -59,6 +59,8 @@ type Transaction struct {
 	CrawledAt            time.Time          `bson:"crawled_at" json:"crawled_at"`
 	Network              string             `bson:"network" json:"network"`
 	ProcessedAt          *time.Time         `bson:"processed_at,omitempty" json:"processed_at,omitempty"`
+	// Logs are the receipt's event logs in log index order
+	Logs []TransactionLog `bson:"logs,omitempty" json:"logs,omitempty"`
 
 	// Enhanced fields for GraphQL
 	Timestamp       time.Time         `json:"timestamp"`
@@ -102,6 +102,8 @@ type WalletNetwork struct {
 	Nodes    []Wallet           `json:"nodes"`
 	Links    []WalletConnection `json:"links"`
 	Metadata NetworkMetadata    `json:"metadata"`
+	// Stale marks a network served from cache while Neo4j is unavailable
+	Stale bool `json:"stale"`
 }
 
 // NetworkMetadata contains metadata about the wallet network
@@ -208,13 +210,25 @@ const (
 
 // WalletSearchResult represents a search result for wallets
 type WalletSearchResult struct {
-	Address          string   `json:"address"`
-	Label            *string  `json:"label,omitempty"`
-	Tags             []string `json:"tags"`
-	RiskScore        *float64 `json:"risk_score,omitempty"`
-	TransactionCount int64    `json:"transaction_count"`
-	Balance          *string  `json:"balance,omitempty"`
-	RelevanceScore   float64  `json:"relevance_score"`
+	Address          string     `json:"address"`
+	Label            *string    `json:"label,omitempty"`
+	Tags             []string   `json:"tags"`
+	WalletType       WalletType `json:"wallet_type"`
+	RiskLevel        RiskLevel  `json:"risk_level"`
+	RiskScore        *float64   `json:"risk_score,omitempty"`
+	TransactionCount int64      `json:"transaction_count"`
+	Balance          *string    `json:"balance,omitempty"`
+	ENSName          *string    `json:"ens_name,omitempty"`
+	// RelevanceScore ranks the result against the query (0-1); exact matches score 1
+	RelevanceScore float64 `json:"relevance_score"`
+	// MatchedField is the wallet property the query matched
+	MatchedField string `json:"matched_field"`
+
+	// Attribution from the label registry
+	LabelEntity     *string        `json:"label_entity,omitempty"`
+	LabelCategory   *LabelCategory `json:"label_category,omitempty"`
+	LabelSource     *string        `json:"label_source,omitempty"`
+	LabelConfidence *float64       `json:"label_confidence,omitempty"`
 }
 
 // WalletUpdate represents real-time updates for a wallet
@@ -253,6 +267,9 @@ type DashboardStats struct {
 	AverageRiskScore    float64   `json:"average_risk_score"`
 	RecentActivity      int64     `json:"recent_activity"`
 	LastUpdate          time.Time `json:"last_update"`
+	// Stale marks statistics served from cache while the datastores
+	// computing them are unavailable
+	Stale bool `json:"stale"`
 }
 
 // Helper methods for WalletType
@@ -13,7 +13,7 @@ type WatchedWallet struct {
 	UserID           uint               `json:"user_id" gorm:"not null;index"`
 	Address          string             `json:"address" gorm:"not null;index"`
 	Label            *string            `json:"label,omitempty"`
-	Tags             []WatchedWalletTag `json:"tags" gorm:"many2many:watched_wallet_tags;"`
+	Tags             []WatchedWalletTag `json:"tags" gorm:"many2many:watched_wallet_tag_associations;joinForeignKey:WatchedWalletID;joinReferences:TagID"`
 	AddedAt          time.Time          `json:"added_at" gorm:"autoCreateTime"`
 	LastActivity     *time.Time         `json:"last_activity,omitempty"`
 	Balance          *string            `json:"balance,omitempty"`
@@ -31,12 +31,13 @@ type WatchedWallet struct {
 
 // WatchedWalletTag represents tags for watched wallets
 type WatchedWalletTag struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Name      string         `json:"name" gorm:"uniqueIndex;not null"`
-	Color     *string        `json:"color,omitempty"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Name        string         `json:"name" gorm:"uniqueIndex;not null"`
+	Color       *string        `json:"color,omitempty"`
+	Description *string        `json:"description,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 }
 
 // CustomThresholds represents custom alert thresholds for a watched wallet
@@ -96,21 +97,23 @@ type WatchListStats struct {
 
 // User represents a user in the system
 type User struct {
-	ID              uint            `json:"id" gorm:"primaryKey"`
-	Email           string          `json:"email" gorm:"uniqueIndex;not null"`
-	Username        *string         `json:"username,omitempty" gorm:"uniqueIndex"`
-	PasswordHash    string          `json:"-" gorm:"not null"`
-	FirstName       *string         `json:"first_name,omitempty"`
-	LastName        *string         `json:"last_name,omitempty"`
-	Role            UserRole        `json:"role" gorm:"default:'USER'"`
-	IsActive        bool            `json:"is_active" gorm:"default:true"`
-	EmailVerified   bool            `json:"email_verified" gorm:"default:false"`
-	EmailVerifiedAt *time.Time      `json:"email_verified_at,omitempty"`
-	LastLoginAt     *time.Time      `json:"last_login_at,omitempty"`
-	WatchedWallets  []WatchedWallet `json:"watched_wallets" gorm:"foreignKey:UserID"`
-	CreatedAt       time.Time       `json:"created_at"`
-	UpdatedAt       time.Time       `json:"updated_at"`
-	DeletedAt       gorm.DeletedAt  `json:"deleted_at,omitempty" gorm:"index"`
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	Email           string     `json:"email" gorm:"uniqueIndex;not null"`
+	Username        *string    `json:"username,omitempty" gorm:"uniqueIndex"`
+	PasswordHash    string     `json:"-" gorm:"not null"`
+	FirstName       *string    `json:"first_name,omitempty"`
+	LastName        *string    `json:"last_name,omitempty"`
+	Role            UserRole   `json:"role" gorm:"default:'USER'"`
+	IsActive        bool       `json:"is_active" gorm:"default:true"`
+	EmailVerified   bool       `json:"email_verified" gorm:"default:false"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	LastLoginAt     *time.Time `json:"last_login_at,omitempty"`
+	// AIDailyTokenLimit overrides the role's daily AI token budget; 0 means unlimited
+	AIDailyTokenLimit *int            `json:"ai_daily_token_limit,omitempty"`
+	WatchedWallets    []WatchedWallet `json:"watched_wallets" gorm:"foreignKey:UserID"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt  `json:"deleted_at,omitempty" gorm:"index"`
 }
 
 // UserRole represents different user roles
@@ -18,6 +18,7 @@ type WalletRepository interface {
 	GetWalletRankings(ctx context.Context, category entity.RankingCategory, networkID *string, limit, offset int) (*entity.WalletRankingResult, error)
 
 	// Search Operations
+	// SearchWallets finds wallets by address, address prefix, ENS name or label text
 	SearchWallets(ctx context.Context, query string, limit int) ([]entity.WalletSearchResult, error)
 
 	// Risk Operations
@@ -25,6 +26,16 @@ type WalletRepository interface {
 	GetRiskScores(ctx context.Context, addresses []string) ([]entity.RiskScore, error)
 	UpdateRiskScore(ctx context.Context, address string, manualFlags []string, whitelistStatus *bool) (*entity.RiskScore, error)
 
+	// Classification
+	// UpdateWalletType sets the type of the known wallets among addresses
+	UpdateWalletType(ctx context.Context, addresses []string, walletType entity.WalletType) error
+	// UpdateClassification stores a classifier result on the wallet
+	UpdateClassification(ctx context.Context, classification *entity.WalletClassification) error
+	// UpdateAttribution stores the label registry's resolved attribution on the wallet
+	UpdateAttribution(ctx context.Context, attribution *entity.AddressAttribution) error
+	// UpdateScamReports stores the number of approved community scam reports on the wallet
+	UpdateScamReports(ctx context.Context, address string, reports int) error
+
 	// Statistics
 	GetWalletStats(ctx context.Context, address string) (*entity.WalletStats, error)
 }
@@ -33,14 +44,22 @@ type WalletRepository interface {
 type TransactionRepository interface {
 	// Transaction Operations
 	GetTransactionsByWallet(ctx context.Context, walletAddress string, limit, offset int64) ([]entity.Transaction, error)
+	GetTransactionsInRange(ctx context.Context, walletAddress string, timeRange entity.TimeRange, limit int64) ([]entity.Transaction, error)
 	GetPairwiseTransactions(ctx context.Context, walletA, walletB string, limit, offset int64, filters *entity.TransactionFilters) (*entity.PairwiseTransactionResult, error)
 	GetTransaction(ctx context.Context, hash string) (*entity.Transaction, error)
+	// GetTransactionsAfter returns transactions crawled after cursor, in crawl order
+	GetTransactionsAfter(ctx context.Context, cursor entity.TransactionCursor, limit int64) ([]entity.Transaction, error)
+	// GetTransactionsInBlocks returns every transaction in the blocks, in block order
+	GetTransactionsInBlocks(ctx context.Context, blockNumbers []string) ([]entity.Transaction, error)
 
 	// Money Flow Operations
 	GetMoneyFlowData(ctx context.Context, walletAddress string, filters *entity.MoneyFlowFilters) (*entity.MoneyFlowData, error)
 
 	// Search Operations
+	// SearchTransactions finds transactions by hash, hash or address prefix, address or block number
 	SearchTransactions(ctx context.Context, query string, limit int64) ([]entity.Transaction, error)
+	// SearchTokens finds tokens by contract address, symbol or name prefix
+	SearchTokens(ctx context.Context, query string, limit int64) ([]entity.TokenMatch, error)
 
 	// Statistics
 	GetTransactionStats(ctx context.Context, timeRange *entity.TimeRange) (map[string]interface{}, error)
@@ -69,6 +88,7 @@ type WatchListRepository interface {
 	AddWatchedWallet(ctx context.Context, wallet *entity.WatchedWallet) error
 	UpdateWatchedWallet(ctx context.Context, wallet *entity.WatchedWallet) error
 	RemoveWatchedWallet(ctx context.Context, userID uint, walletID uint) error
+	GetAllWatchedAddresses(ctx context.Context) ([]string, error)
 
 	// Watch List Statistics
 	GetWatchListStats(ctx context.Context, userID uint) (*entity.WatchListStats, error)
@@ -92,11 +112,131 @@ type SecurityRepository interface {
 	UpdateSecurityAlert(ctx context.Context, alert *entity.SecurityAlert) error
 	AcknowledgeSecurityAlert(ctx context.Context, alertID string) error
 	ResolveSecurityAlert(ctx context.Context, alertID, resolution, notes string) error
+	// CreateSecurityAlertIfNew creates the alert unless one with the same dedupe key
+	// exists; the boolean reports whether it was created
+	CreateSecurityAlertIfNew(ctx context.Context, alert *entity.SecurityAlert) (bool, error)
+
+	// Detection Checkpoints
+	GetDetectionCheckpoint(ctx context.Context, name string) (*entity.DetectionCheckpoint, error)
+	SaveDetectionCheckpoint(ctx context.Context, checkpoint *entity.DetectionCheckpoint) error
 
 	// Compliance Operations
-	GenerateComplianceReport(ctx context.Context, walletAddress string, reportType entity.ComplianceReportType, timeRange entity.TimeRange) (*entity.ComplianceReport, error)
+	CreateComplianceReport(ctx context.Context, report *entity.ComplianceReport) error
+	UpdateComplianceReport(ctx context.Context, report *entity.ComplianceReport) error
 	GetComplianceReport(ctx context.Context, reportID string) (*entity.ComplianceReport, error)
 	GetComplianceReports(ctx context.Context, filters map[string]interface{}) ([]entity.ComplianceReport, error)
+	TransitionComplianceReport(ctx context.Context, reportID string, from, to entity.ComplianceReportStatus) (bool, error)
+	UpdateComplianceReportNarrative(ctx context.Context, reportID string, status entity.ComplianceReportStatus, expected, narrative *entity.ComplianceNarrative, metadata map[string]interface{}) (bool, error)
+
+	// Compliance Report History (append-only)
+	AppendComplianceReportEvent(ctx context.Context, event *entity.ComplianceReportEvent) error
+	GetComplianceReportHistory(ctx context.Context, reportID string) ([]entity.ComplianceReportEvent, error)
+}
+
+// SanctionsRepository defines the interface for sanctions list data access
+type SanctionsRepository interface {
+	// List Versions
+	CreateListVersion(ctx context.Context, version *entity.SanctionsListVersion, entries []entity.SanctionedAddress) error
+	GetListVersion(ctx context.Context, versionID string) (*entity.SanctionsListVersion, error)
+	GetActiveListVersion(ctx context.Context, source entity.SanctionsSource) (*entity.SanctionsListVersion, error)
+	GetListVersions(ctx context.Context, source *entity.SanctionsSource, limit int) ([]entity.SanctionsListVersion, error)
+	GetListEntries(ctx context.Context, versionID string) ([]entity.SanctionedAddress, error)
+
+	// Screening
+	FindSanctionedAddresses(ctx context.Context, addresses []string) ([]entity.SanctionedAddress, error)
+	FindExposures(ctx context.Context, address string, maxHops int) ([]entity.SanctionsExposure, error)
+}
+
+// ScreeningRepository defines the interface for bulk screening job data access
+type ScreeningRepository interface {
+	// Job Operations
+	CreateJob(ctx context.Context, job *entity.ScreeningJob) error
+	UpdateJob(ctx context.Context, job *entity.ScreeningJob) error
+	GetJob(ctx context.Context, jobID string) (*entity.ScreeningJob, error)
+	FailUnfinishedJobs(ctx context.Context, createdBefore time.Time, message string) (int64, error)
+
+	// Result Operations
+	SaveResults(ctx context.Context, results []entity.AddressScreeningResult) error
+	GetResults(ctx context.Context, jobID string, limit, offset int) ([]entity.AddressScreeningResult, error)
+	IterateResults(ctx context.Context, jobID string, fn func(entity.AddressScreeningResult) error) error
+}
+
+// CaseRepository defines the interface for security case data access
+type CaseRepository interface {
+	// Case Operations
+	CreateCase(ctx context.Context, securityCase *entity.SecurityCase) error
+	GetCase(ctx context.Context, caseID string) (*entity.SecurityCase, error)
+	GetCases(ctx context.Context, filters *entity.CaseFilters, limit, offset int) ([]entity.SecurityCase, error)
+	// UpdateCase replaces the case if it is still at expectedVersion; the boolean
+	// reports whether it was applied
+	UpdateCase(ctx context.Context, securityCase *entity.SecurityCase, expectedVersion int) (bool, error)
+
+	// Comment Operations
+	AddComment(ctx context.Context, comment *entity.CaseComment) error
+	GetComments(ctx context.Context, caseID string) ([]entity.CaseComment, error)
+}
+
+// MEVRepository defines the interface for MEV activity data access
+type MEVRepository interface {
+	// SaveMEVActivity records an activity unless one with the same dedupe key
+	// exists; the boolean reports whether it was recorded
+	SaveMEVActivity(ctx context.Context, activity *entity.MEVActivity) (bool, error)
+	GetMEVActivities(ctx context.Context, filters *entity.MEVFilters, limit, offset int) ([]entity.MEVActivity, error)
+}
+
+// ClassificationRepository defines the interface for wallet classification data access
+type ClassificationRepository interface {
+	// SaveClassification replaces the wallet's classification
+	SaveClassification(ctx context.Context, classification *entity.WalletClassification) error
+	GetClassification(ctx context.Context, address string) (*entity.WalletClassification, error)
+}
+
+// LabelRepository defines the interface for address label registry data access
+type LabelRepository interface {
+	// CreateLabel creates the label unless its source already has a label of the
+	// same name for the address; the boolean reports whether it was created
+	CreateLabel(ctx context.Context, label *entity.AddressLabel) (bool, error)
+	UpdateLabel(ctx context.Context, label *entity.AddressLabel) error
+	DeleteLabel(ctx context.Context, id string) (bool, error)
+	GetLabel(ctx context.Context, id string) (*entity.AddressLabel, error)
+	GetLabels(ctx context.Context, filters *entity.LabelFilters, limit, offset int) ([]entity.AddressLabel, error)
+	// GetLabelsByAddress returns every label for the address, expired or not
+	GetLabelsByAddress(ctx context.Context, address string) ([]entity.AddressLabel, error)
+	// UpsertLabels creates or replaces labels by address, source and name
+	UpsertLabels(ctx context.Context, labels []entity.AddressLabel) (created, updated int, err error)
+	// GetAddressesWithValidityChanges returns addresses with a label that became
+	// valid or expired in (from, to]
+	GetAddressesWithValidityChanges(ctx context.Context, from, to time.Time) ([]string, error)
+	// SearchLabels finds currently valid labels by the words of their name,
+	// entity or tags, best match first
+	SearchLabels(ctx context.Context, query string, limit int) ([]entity.LabelMatch, error)
+
+	// Label Imports
+	CreateLabelImport(ctx context.Context, labelImport *entity.LabelImport) error
+	GetLabelImports(ctx context.Context, limit int) ([]entity.LabelImport, error)
+}
+
+// LabelProposalRepository defines the interface for community label proposal data access
+type LabelProposalRepository interface {
+	CreateProposal(ctx context.Context, proposal *entity.LabelProposal) error
+	GetProposal(ctx context.Context, id string) (*entity.LabelProposal, error)
+	// GetProposals lists proposals with filters, oldest first so the moderation
+	// queue is worked in submission order
+	GetProposals(ctx context.Context, filters *entity.LabelProposalFilters, limit, offset int) ([]entity.LabelProposal, error)
+	CountProposals(ctx context.Context, filters *entity.LabelProposalFilters) (int, error)
+	// ReviewProposal stores a moderation decision if the proposal is still
+	// pending; the boolean reports whether it was
+	ReviewProposal(ctx context.Context, proposal *entity.LabelProposal) (bool, error)
+	// ReopenProposal returns a reviewed proposal to pending if it still has
+	// the decision stored by ReviewProposal; the boolean reports whether it did
+	ReopenProposal(ctx context.Context, proposal *entity.LabelProposal) (bool, error)
+	// CountApprovedScamReporters returns how many distinct users had a scam
+	// report for the address approved
+	CountApprovedScamReporters(ctx context.Context, address string) (int, error)
+
+	// Submitter Reputation
+	GetReputation(ctx context.Context, userID uint) (*entity.SubmitterReputation, error)
+	IncrementReputation(ctx context.Context, userID uint, submitted, approved, rejected int) (*entity.SubmitterReputation, error)
 }
 
 // UserRepository defines the interface for user data access
@@ -149,8 +289,36 @@ type CacheRepository interface {
 type AIRepository interface {
 	// AI Assistant Operations
 	AskAI(ctx context.Context, question string, context *entity.AIContext, walletAddress *string) (*entity.AIResponse, error)
+	// ChatAI answers a question in the context of earlier messages, passing answer
+	// text to onDelta as it is generated when onDelta is non-nil
+	ChatAI(ctx context.Context, req *entity.AIChatRequest, onDelta func(delta string)) (*entity.AIResponse, error)
 
 	// AI Model Management
 	GetAvailableModels(ctx context.Context) ([]string, error)
 	GetModelInfo(ctx context.Context, modelName string) (map[string]interface{}, error)
+
+	// DraftReportNarrative writes the narrative section of a compliance report from
+	// its findings and evidence transactions, citing findings as [F-001]
+	DraftReportNarrative(ctx context.Context, report *entity.ComplianceReport, evidence []entity.Transaction) (*entity.AIResponse, error)
+}
+
+// AIConversationRepository defines the interface for AI conversation data access
+type AIConversationRepository interface {
+	// Conversations
+	CreateConversation(ctx context.Context, conversation *entity.AIConversation) error
+	GetConversation(ctx context.Context, userID, conversationID uint) (*entity.AIConversation, error)
+	GetConversations(ctx context.Context, userID uint, limit, offset int) ([]entity.AIConversation, error)
+	DeleteConversation(ctx context.Context, userID, conversationID uint) error
+
+	// Messages
+	AddMessage(ctx context.Context, message *entity.AIMessage) error
+	GetMessages(ctx context.Context, conversationID uint, limit, offset int) ([]entity.AIMessage, error)
+	GetRecentMessages(ctx context.Context, conversationID uint, limit int) ([]entity.AIMessage, error)
+}
+
+// AIUsageRepository defines the interface for AI token usage accounting
+type AIUsageRepository interface {
+	RecordUsage(ctx context.Context, usage *entity.AIUsage) error
+	GetTokensUsedSince(ctx context.Context, userID uint, since time.Time) (int, error)
+	GetDailyUsage(ctx context.Context, userID uint, since time.Time) ([]entity.AIDailyUsage, error)
 }
@@ -0,0 +1,98 @@
+package assistant
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"unicode"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/infrastructure/cache"
+
+	"go.uber.org/zap"
+)
+
+const answerCachePrefix = "ai:answer:"
+
+// stopWords are dropped from questions when building cache keys so that
+// rephrasings such as "What is the risk of this wallet?" and "what's this
+// wallet's risk" share an answer
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "is": true, "are": true, "was": true, "were": true,
+	"be": true, "of": true, "for": true, "to": true, "in": true, "on": true, "at": true,
+	"this": true, "that": true, "these": true, "those": true, "it": true, "its": true,
+	"what": true, "whats": true, "s": true, "do": true, "does": true, "did": true,
+	"can": true, "could": true, "you": true, "me": true, "please": true, "tell": true,
+	"about": true, "wallet": true, "wallets": true, "address": true, "and": true,
+}
+
+// cachedAnswer returns a stored answer for the request if there is one.
+// Only wallet questions without conversation history are cached, since
+// history changes what the right answer is.
+func (s *Service) cachedAnswer(ctx context.Context, req *entity.AIChatRequest) (*entity.AIResponse, bool) {
+	key, ok := s.answerCacheKey(req)
+	if !ok {
+		return nil, false
+	}
+
+	var response entity.AIResponse
+	if err := s.cacheRepo.Get(ctx, key, &response); err != nil {
+		if !errors.Is(err, cache.ErrCacheMiss) {
+			s.logger.Warn("Failed to read cached AI answer", zap.Error(err))
+		}
+		return nil, false
+	}
+
+	response.Cached = true
+	response.TokensUsed = 0
+	return &response, true
+}
+
+// cacheAnswer stores an answer for reuse by later identical questions
+func (s *Service) cacheAnswer(ctx context.Context, req *entity.AIChatRequest, response *entity.AIResponse) {
+	key, ok := s.answerCacheKey(req)
+	if !ok {
+		return
+	}
+	if err := s.cacheRepo.Set(ctx, key, response, s.config.OpenAIResponseCacheTTL); err != nil {
+		s.logger.Warn("Failed to cache AI answer", zap.Error(err))
+	}
+}
+
+// answerCacheKey derives the cache key from the wallet, the normalized question
+// and everything else that shapes the answer
+func (s *Service) answerCacheKey(req *entity.AIChatRequest) (string, bool) {
+	if s.config.OpenAIResponseCacheTTL <= 0 || len(req.History) > 0 ||
+		req.WalletAddress == nil || *req.WalletAddress == "" {
+		return "", false
+	}
+
+	parts := []string{
+		entity.NormalizeAddress(*req.WalletAddress),
+		normalizeQuestion(req.Question),
+		s.config.OpenAIModel,
+	}
+	if req.Context != nil {
+		parts = append(parts, req.Context.UserRole, req.Context.AnalysisType, req.Context.Timeframe, req.Context.NetworkID)
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return answerCachePrefix + hex.EncodeToString(sum[:]), true
+}
+
+// normalizeQuestion lowercases the question and reduces it to its significant words
+func normalizeQuestion(question string) string {
+	words := strings.FieldsFunc(strings.ToLower(question), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	significant := words[:0]
+	for _, word := range words {
+		if !stopWords[word] {
+			significant = append(significant, word)
+		}
+	}
+	return strings.Join(significant, " ")
+}
@@ -0,0 +1,336 @@
+package assistant
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/config"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+	"crypto-bubble-map-be/internal/infrastructure/monitoring"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// maxHistoryMessages caps how many earlier messages are loaded before the
+	// token budget is applied
+	maxHistoryMessages = 50
+	// defaultContextTokens is used when OpenAIContextTokens is not configured
+	defaultContextTokens = 3000
+	// titleLength is the number of characters of the first question used as the title
+	titleLength = 80
+	// messageOverheadTokens approximates the per-message framing the API adds
+	messageOverheadTokens = 4
+)
+
+// Question is a question asked in a new or existing conversation
+type Question struct {
+	ConversationID *uint
+	Question       string
+	Context        *entity.AIContext
+	WalletAddress  *string
+}
+
+// Turn is a question that has been stored and is ready to be answered
+type Turn struct {
+	User         *entity.User
+	Conversation *entity.AIConversation
+	Request      *entity.AIChatRequest
+}
+
+// Service keeps per-user AI conversations and answers questions with the
+// conversation history that fits in the context token budget. Every answer is
+// checked against the user's daily token budget and recorded as usage.
+type Service struct {
+	conversationRepo repository.AIConversationRepository
+	usageRepo        repository.AIUsageRepository
+	userRepo         repository.UserRepository
+	aiRepo           repository.AIRepository
+	cacheRepo        repository.CacheRepository
+	config           *config.ExternalConfig
+	metrics          *monitoring.MetricsCollector
+	logger           *zap.Logger
+}
+
+// NewService creates a new AI assistant conversation service
+func NewService(
+	conversationRepo repository.AIConversationRepository,
+	usageRepo repository.AIUsageRepository,
+	userRepo repository.UserRepository,
+	aiRepo repository.AIRepository,
+	cacheRepo repository.CacheRepository,
+	cfg *config.ExternalConfig,
+	metrics *monitoring.MetricsCollector,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		conversationRepo: conversationRepo,
+		usageRepo:        usageRepo,
+		userRepo:         userRepo,
+		aiRepo:           aiRepo,
+		cacheRepo:        cacheRepo,
+		config:           cfg,
+		metrics:          metrics,
+		logger:           logger,
+	}
+}
+
+// AskOnce answers a standalone question without storing it in a conversation
+func (s *Service) AskOnce(ctx context.Context, user *entity.User, question string, aiContext *entity.AIContext, walletAddress *string) (*entity.AIResponse, error) {
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return nil, apperrors.NewValidationError("question", "Question is required")
+	}
+	if err := s.CheckBudget(ctx, user); err != nil {
+		return nil, err
+	}
+
+	req := &entity.AIChatRequest{
+		Question:      question,
+		Context:       withUserRole(aiContext, user),
+		WalletAddress: walletAddress,
+	}
+	if response, ok := s.cachedAnswer(ctx, req); ok {
+		s.RecordUsage(ctx, user, nil, response)
+		return response, nil
+	}
+
+	response, err := s.aiRepo.AskAI(ctx, req.Question, req.Context, req.WalletAddress)
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeAIServiceFailure, "Failed to generate answer", err.Error())
+	}
+	s.RecordUsage(ctx, user, nil, response)
+	s.cacheAnswer(ctx, req, response)
+	return response, nil
+}
+
+// Ask stores the question, answers it and stores the answer
+func (s *Service) Ask(ctx context.Context, user *entity.User, q Question) (*entity.AIConversationTurn, error) {
+	turn, err := s.StartTurn(ctx, user, q)
+	if err != nil {
+		return nil, err
+	}
+	return s.Answer(ctx, turn, nil)
+}
+
+// StartTurn opens the conversation (creating it for a first question), stores the
+// question and loads the history the answer will be based on
+func (s *Service) StartTurn(ctx context.Context, user *entity.User, q Question) (*Turn, error) {
+	question := strings.TrimSpace(q.Question)
+	if question == "" {
+		return nil, apperrors.NewValidationError("question", "Question is required")
+	}
+	if err := s.CheckBudget(ctx, user); err != nil {
+		return nil, err
+	}
+
+	var (
+		conversation *entity.AIConversation
+		history      []entity.AIMessage
+		err          error
+	)
+	if q.ConversationID != nil {
+		conversation, err = s.Conversation(ctx, user, *q.ConversationID)
+		if err != nil {
+			return nil, err
+		}
+
+		recent, err := s.conversationRepo.GetRecentMessages(ctx, conversation.ID, maxHistoryMessages)
+		if err != nil {
+			return nil, err
+		}
+		history = fitHistory(recent, s.contextTokens())
+	} else {
+		conversation = &entity.AIConversation{
+			UserID: user.ID,
+			Title:  title(question),
+		}
+		if q.WalletAddress != nil && *q.WalletAddress != "" {
+			address := entity.NormalizeAddress(*q.WalletAddress)
+			conversation.WalletAddress = &address
+		}
+		if err := s.conversationRepo.CreateConversation(ctx, conversation); err != nil {
+			return nil, err
+		}
+	}
+
+	walletAddress := q.WalletAddress
+	if walletAddress == nil {
+		walletAddress = conversation.WalletAddress
+	}
+
+	message := &entity.AIMessage{
+		ConversationID: conversation.ID,
+		Role:           entity.AIMessageRoleUser,
+		Content:        question,
+		TokensUsed:     entity.EstimateTokens(question),
+		CreatedAt:      time.Now(),
+	}
+	if err := s.conversationRepo.AddMessage(ctx, message); err != nil {
+		return nil, err
+	}
+
+	return &Turn{
+		User:         user,
+		Conversation: conversation,
+		Request: &entity.AIChatRequest{
+			Question:      question,
+			History:       history,
+			Context:       withUserRole(q.Context, user),
+			WalletAddress: walletAddress,
+		},
+	}, nil
+}
+
+// Answer generates and stores the answer to a started turn, passing answer text
+// to onDelta as it is generated when onDelta is non-nil. A cached answer is
+// passed to onDelta in one piece.
+func (s *Service) Answer(ctx context.Context, turn *Turn, onDelta func(string)) (*entity.AIConversationTurn, error) {
+	response, cached := s.cachedAnswer(ctx, turn.Request)
+	if cached {
+		if onDelta != nil {
+			onDelta(response.Answer)
+		}
+	} else {
+		var err error
+		response, err = s.aiRepo.ChatAI(ctx, turn.Request, onDelta)
+		if err != nil {
+			return nil, apperrors.NewAppError(apperrors.ErrCodeAIServiceFailure, "Failed to generate answer", err.Error())
+		}
+		s.cacheAnswer(ctx, turn.Request, response)
+	}
+	conversationID := turn.Conversation.ID
+	s.RecordUsage(ctx, turn.User, &conversationID, response)
+
+	confidence := response.Confidence
+	model := response.Model
+	message := &entity.AIMessage{
+		ConversationID:   turn.Conversation.ID,
+		Role:             entity.AIMessageRoleAssistant,
+		Content:          response.Answer,
+		Sources:          response.Sources,
+		RelatedQuestions: response.RelatedQuestions,
+		ActionItems:      response.ActionItems,
+		Confidence:       &confidence,
+		Model:            &model,
+		TokensUsed:       response.TokensUsed,
+		CreatedAt:        response.GeneratedAt,
+	}
+	if err := s.conversationRepo.AddMessage(ctx, message); err != nil {
+		return nil, err
+	}
+
+	conversation := *turn.Conversation
+	conversation.MessageCount += 2
+	conversation.TokensUsed += entity.EstimateTokens(turn.Request.Question) + response.TokensUsed
+	conversation.UpdatedAt = message.CreatedAt
+
+	s.logger.Info("Answered AI conversation question",
+		zap.Uint("conversationID", conversation.ID),
+		zap.Uint("userID", conversation.UserID),
+		zap.Int("historyMessages", len(turn.Request.History)),
+		zap.Int("tokens", response.TokensUsed),
+		zap.Bool("cached", cached))
+
+	return &entity.AIConversationTurn{
+		Conversation: &conversation,
+		Message:      message,
+	}, nil
+}
+
+// Conversations lists the user's conversations, most recently active first
+func (s *Service) Conversations(ctx context.Context, user *entity.User, limit, offset int) ([]entity.AIConversation, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.conversationRepo.GetConversations(ctx, user.ID, limit, offset)
+}
+
+// Conversation returns one of the user's conversations
+func (s *Service) Conversation(ctx context.Context, user *entity.User, conversationID uint) (*entity.AIConversation, error) {
+	conversation, err := s.conversationRepo.GetConversation(ctx, user.ID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeConversationNotFound, "Conversation not found",
+			strconv.FormatUint(uint64(conversationID), 10))
+	}
+	return conversation, nil
+}
+
+// Messages lists a conversation's messages in the order they were written
+func (s *Service) Messages(ctx context.Context, conversationID uint, limit, offset int) ([]entity.AIMessage, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.conversationRepo.GetMessages(ctx, conversationID, limit, offset)
+}
+
+// DeleteConversation deletes one of the user's conversations
+func (s *Service) DeleteConversation(ctx context.Context, user *entity.User, conversationID uint) error {
+	if _, err := s.Conversation(ctx, user, conversationID); err != nil {
+		return err
+	}
+	return s.conversationRepo.DeleteConversation(ctx, user.ID, conversationID)
+}
+
+func (s *Service) contextTokens() int {
+	if s.config.OpenAIContextTokens > 0 {
+		return s.config.OpenAIContextTokens
+	}
+	return defaultContextTokens
+}
+
+// withUserRole copies the request context and sets the asking user's role on it
+func withUserRole(aiContext *entity.AIContext, user *entity.User) *entity.AIContext {
+	result := &entity.AIContext{}
+	if aiContext != nil {
+		*result = *aiContext
+	}
+	result.UserRole = string(user.Role)
+	return result
+}
+
+// fitHistory keeps the most recent messages whose combined size fits in budget
+// tokens. A partial exchange at the start is dropped so the history never opens
+// with an answer to a question the model cannot see.
+func fitHistory(messages []entity.AIMessage, budget int) []entity.AIMessage {
+	start := len(messages)
+	used := 0
+	for i := len(messages) - 1; i >= 0; i-- {
+		cost := entity.EstimateTokens(messages[i].Content) + messageOverheadTokens
+		if used+cost > budget {
+			break
+		}
+		used += cost
+		start = i
+	}
+
+	for start < len(messages) && messages[start].Role != entity.AIMessageRoleUser {
+		start++
+	}
+	return messages[start:]
+}
+
+// title derives a conversation title from its first question
+func title(question string) string {
+	question = strings.Join(strings.Fields(question), " ")
+	if utf8.RuneCountInString(question) <= titleLength {
+		return question
+	}
+	runes := []rune(question)
+	return fmt.Sprintf("%s…", strings.TrimSpace(string(runes[:titleLength])))
+}
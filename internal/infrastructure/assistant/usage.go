@@ -0,0 +1,166 @@
+package assistant
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// maxUsageDays bounds the daily history returned in usage reports
+	maxUsageDays = 90
+)
+
+// Usage reports a user's AI token usage for the last days UTC days, including today
+func (s *Service) Usage(ctx context.Context, user *entity.User, days int) (*entity.AIUsageReport, error) {
+	if days <= 0 {
+		days = 7
+	}
+	if days > maxUsageDays {
+		days = maxUsageDays
+	}
+
+	today := startOfDay(time.Now())
+	usedToday, err := s.usageRepo.GetTokensUsedSince(ctx, user.ID, today)
+	if err != nil {
+		return nil, err
+	}
+	daily, err := s.usageRepo.GetDailyUsage(ctx, user.ID, today.AddDate(0, 0, -(days-1)))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entity.AIUsageReport{
+		UserID:    user.ID,
+		Role:      user.Role,
+		UsedToday: usedToday,
+		ResetsAt:  today.AddDate(0, 0, 1),
+		Days:      daily,
+	}
+	if limit := s.dailyTokenLimit(user); limit > 0 {
+		remaining := limit - usedToday
+		if remaining < 0 {
+			remaining = 0
+		}
+		report.DailyLimit = &limit
+		report.RemainingToday = &remaining
+	}
+	return report, nil
+}
+
+// SetDailyTokenLimit sets a user's own daily token budget, overriding their
+// role's budget; a nil limit restores the role budget
+func (s *Service) SetDailyTokenLimit(ctx context.Context, userID uint, limit *int) (*entity.User, error) {
+	if limit != nil && *limit < 0 {
+		return nil, apperrors.NewValidationError("limit", "Limit must not be negative")
+	}
+
+	user, err := s.User(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.AIDailyTokenLimit = limit
+	if err := s.userRepo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Updated AI daily token limit",
+		zap.Uint("userID", user.ID),
+		zap.Any("limit", limit))
+	return user, nil
+}
+
+// User returns the user whose usage is reported or limited
+func (s *Service) User(ctx context.Context, userID uint) (*entity.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeAuthUserNotFound, "User not found",
+			fmt.Sprintf("%d", userID))
+	}
+	return user, nil
+}
+
+// CheckBudget rejects the request when the user has used up today's token budget
+func (s *Service) CheckBudget(ctx context.Context, user *entity.User) error {
+	limit := s.dailyTokenLimit(user)
+	if limit <= 0 {
+		return nil
+	}
+
+	used, err := s.usageRepo.GetTokensUsedSince(ctx, user.ID, startOfDay(time.Now()))
+	if err != nil {
+		return err
+	}
+	if used < limit {
+		return nil
+	}
+
+	s.metrics.Counter("ai_budget_exceeded_total",
+		map[string]string{"role": string(user.Role)},
+		"AI requests rejected because the daily token budget was used up")
+	s.logger.Warn("AI daily token budget exceeded",
+		zap.Uint("userID", user.ID),
+		zap.Int("used", used),
+		zap.Int("limit", limit))
+
+	return apperrors.NewAppError(apperrors.ErrCodeAIQuotaExceeded, "Daily AI token budget exceeded",
+		fmt.Sprintf("Used %d of %d tokens today; the budget resets at midnight UTC", used, limit)).
+		WithMetadata("used", used).
+		WithMetadata("limit", limit)
+}
+
+// RecordUsage stores and meters the tokens an answer consumed. Failures are
+// logged rather than returned so an answer that was already paid for is not lost.
+func (s *Service) RecordUsage(ctx context.Context, user *entity.User, conversationID *uint, response *entity.AIResponse) {
+	usage := &entity.AIUsage{
+		UserID:         user.ID,
+		Role:           user.Role,
+		ConversationID: conversationID,
+		Model:          response.Model,
+		TokensUsed:     response.TokensUsed,
+		Cached:         response.Cached,
+		CreatedAt:      time.Now(),
+	}
+	if err := s.usageRepo.RecordUsage(ctx, usage); err != nil {
+		s.logger.Warn("Failed to record AI usage", zap.Uint("userID", user.ID), zap.Error(err))
+	}
+
+	s.metrics.Counter("ai_requests_total",
+		map[string]string{"role": string(user.Role), "cached": fmt.Sprintf("%t", response.Cached)},
+		"AI assistant requests")
+	s.metrics.Add("ai_tokens_total", float64(response.TokensUsed),
+		map[string]string{"role": string(user.Role), "model": response.Model},
+		"Tokens consumed by AI assistant requests")
+}
+
+// dailyTokenLimit returns the user's daily token budget; 0 means unlimited
+func (s *Service) dailyTokenLimit(user *entity.User) int {
+	if user.AIDailyTokenLimit != nil {
+		return *user.AIDailyTokenLimit
+	}
+
+	switch user.Role {
+	case entity.UserRoleAdmin:
+		return s.config.OpenAIDailyTokensAdmin
+	case entity.UserRoleModerator:
+		return s.config.OpenAIDailyTokensModerator
+	case entity.UserRoleAnalyst:
+		return s.config.OpenAIDailyTokensAnalyst
+	default:
+		return s.config.OpenAIDailyTokensUser
+	}
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
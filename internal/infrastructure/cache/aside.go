@@ -0,0 +1,474 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/infrastructure/config"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+	"crypto-bubble-map-be/internal/infrastructure/monitoring"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// lockTTL bounds how long a crashed loader can hold a key's fill lock
+	lockTTL = 10 * time.Second
+	// lockPollInterval is how often a waiting reader checks for the filled value
+	lockPollInterval = 25 * time.Millisecond
+
+	// invalidationChannel carries the keys one replica invalidated to the others
+	invalidationChannel = "cache:invalidations"
+)
+
+// invalidationMessage is published when a replica invalidates cached keys
+type invalidationMessage struct {
+	Origin string   `json:"origin"`
+	Keys   []string `json:"keys"`
+}
+
+// Aside implements two-tier cache-aside reads: an in-process LRU in front of
+// Redis. Misses are filled by a single caller per key: the first to take the
+// key's lock with SetNX loads the value while the others wait for it to
+// appear, so an expired hot key costs one database query rather than one per
+// request. Values are tagged with the addresses they include so that a change
+// to an address drops exactly the cached reads that show it, on every replica.
+//
+// Values served from the local tier are shared between callers and must not be
+// modified.
+type Aside struct {
+	redis    *RedisClient
+	local    *LocalCache
+	localTTL time.Duration
+	tagTTL   time.Duration
+	origin   string
+	monitor  *monitoring.PerformanceMonitor
+	logger   *zap.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewAside creates a two-tier cache-aside helper on the Redis client
+func NewAside(redis *RedisClient, cfg *config.CacheConfig, monitor *monitoring.PerformanceMonitor, logger *zap.Logger) *Aside {
+	// Tag sets must outlive every key they track
+	ttl := cfg.TTL
+	tagTTL := lockTTL
+	for _, d := range []time.Duration{ttl.WalletNetwork, ttl.WalletRankings, ttl.WalletData, ttl.DashboardStats,
+		ttl.RiskScores, ttl.NetworkStats, ttl.TransactionData} {
+		if d > tagTTL {
+			tagTTL = d
+		}
+	}
+
+	a := &Aside{
+		redis:   redis,
+		tagTTL:  tagTTL,
+		origin:  newOrigin(),
+		monitor: monitor,
+		logger:  logger,
+	}
+	if cfg.Local.Enabled && cfg.Local.MaxBytes > 0 && cfg.Local.TTL > 0 {
+		a.local = NewLocalCache(cfg.Local.MaxBytes)
+		a.localTTL = cfg.Local.TTL
+	}
+	return a
+}
+
+// newOrigin identifies this replica in invalidation messages
+func newOrigin() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format(time.RFC3339Nano)
+	}
+	return hex.EncodeToString(b)
+}
+
+// Start listens for invalidations published by other replicas. It does nothing
+// when the local tier is disabled.
+func (a *Aside) Start() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cancel != nil || a.local == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancel = cancel
+	a.done = make(chan struct{})
+
+	go func() {
+		defer close(a.done)
+		pubsub := a.redis.Subscribe(ctx, invalidationChannel)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				a.applyInvalidation(msg.Payload)
+			}
+		}
+	}()
+
+	a.logger.Info("Started local cache invalidation listener",
+		zap.String("origin", a.origin),
+		zap.Duration("localTTL", a.localTTL))
+}
+
+// Stop stops listening for invalidations
+func (a *Aside) Stop() {
+	a.mu.Lock()
+	cancel, done := a.cancel, a.done
+	a.cancel = nil
+	a.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+func (a *Aside) applyInvalidation(payload string) {
+	var msg invalidationMessage
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		a.logger.Warn("Ignoring malformed cache invalidation", zap.Error(err))
+		return
+	}
+	if msg.Origin == a.origin {
+		return
+	}
+	a.local.Delete(msg.Keys...)
+}
+
+// Load reads key into dest, which must be a pointer. On a miss, fill loads the
+// value into dest and returns the addresses it includes; the value is then
+// cached for ttl, nil results included. Redis failures never fail the read:
+// the value is loaded from the database instead.
+func (a *Aside) Load(ctx context.Context, key string, ttl time.Duration, dest interface{}, fill func(ctx context.Context) ([]string, error)) error {
+	if ttl <= 0 {
+		_, err := fill(ctx)
+		return err
+	}
+	if a.getLocal(key, dest) {
+		return nil
+	}
+
+	found, err := a.getRedis(ctx, key, ttl, dest)
+	if found {
+		return nil
+	}
+	if err != nil {
+		_, err := fill(ctx)
+		return err
+	}
+
+	lockKey := key + ":lock"
+	acquired, err := a.redis.SetNX(ctx, lockKey, 1, lockTTL)
+	if err != nil {
+		_, err := fill(ctx)
+		return err
+	}
+	if acquired {
+		defer func() {
+			if err := a.redis.Delete(context.WithoutCancel(ctx), lockKey); err != nil {
+				a.logger.Warn("Failed to release cache fill lock", zap.String("key", key), zap.Error(err))
+			}
+		}()
+	} else {
+		filled, err := a.wait(ctx, key, lockKey, ttl, dest)
+		if err != nil {
+			return err
+		}
+		if filled {
+			return nil
+		}
+		// The loader gave up without filling the key; load it here without caching
+		// so that a failing query is not retried by every waiter at once
+		_, err = fill(ctx)
+		return err
+	}
+
+	addresses, err := fill(ctx)
+	if err != nil {
+		return err
+	}
+	a.Store(ctx, key, ttl, reflect.ValueOf(dest).Elem().Interface(), addresses...)
+	return nil
+}
+
+// LoadOrStale is Load for reads that are better served stale than not at all.
+// Every value filled is also kept, untagged, under a separate key for
+// staleTTL; when fill fails because a dependency is down, that copy is read
+// into dest instead and reported as stale. Errors caused by the request
+// itself are returned as is.
+func (a *Aside) LoadOrStale(ctx context.Context, key string, ttl, staleTTL time.Duration, dest interface{}, fill func(ctx context.Context) ([]string, error)) (bool, error) {
+	if staleTTL <= 0 {
+		return false, a.Load(ctx, key, ttl, dest, fill)
+	}
+
+	staleKey := key + ":stale"
+	err := a.Load(ctx, key, ttl, dest, func(ctx context.Context) ([]string, error) {
+		addresses, err := fill(ctx)
+		if err == nil {
+			if _, err := a.redis.setJSON(ctx, staleKey, reflect.ValueOf(dest).Elem().Interface(), staleTTL); err != nil {
+				a.logger.Debug("Failed to keep stale copy", zap.String("key", key), zap.Error(err))
+			}
+		}
+		return addresses, err
+	})
+	if err == nil {
+		return false, nil
+	}
+	if appErr, ok := apperrors.AsAppError(err); ok && appErr.HTTPStatus < 500 {
+		return false, err
+	}
+
+	start := time.Now()
+	_, staleErr := a.redis.getJSON(ctx, staleKey, dest)
+	a.track("stale_get", staleErr == nil, start)
+	if staleErr != nil {
+		return false, err
+	}
+
+	a.logger.Warn("Serving stale cached value", zap.String("key", key), zap.Error(err))
+	return true, nil
+}
+
+// wait polls for another caller to fill key, reporting whether it did before
+// its lock was released or expired
+func (a *Aside) wait(ctx context.Context, key, lockKey string, ttl time.Duration, dest interface{}) (bool, error) {
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-ticker.C:
+		}
+
+		found, err := a.getRedis(ctx, key, ttl, dest)
+		if found {
+			return true, nil
+		}
+		if err != nil {
+			return false, nil
+		}
+
+		locked, err := a.redis.Exists(ctx, lockKey)
+		if err != nil || !locked {
+			// The value may have been stored just before the lock was released
+			found, _ := a.getRedis(ctx, key, ttl, dest)
+			return found, nil
+		}
+	}
+}
+
+// getLocal reads key from the local tier into dest
+func (a *Aside) getLocal(key string, dest interface{}) bool {
+	if a.local == nil {
+		return false
+	}
+
+	start := time.Now()
+	value, ok := a.local.Get(key)
+	if ok {
+		target := reflect.ValueOf(dest).Elem()
+		v := reflect.ValueOf(value)
+		// A key read with a different type than it was stored with is a miss
+		if v.IsValid() && v.Type().AssignableTo(target.Type()) {
+			target.Set(v)
+		} else {
+			ok = false
+		}
+	}
+	a.track("local_get", ok, start)
+	return ok
+}
+
+// getRedis reads key from Redis into dest, keeping a hit in the local tier.
+// A miss is reported without an error.
+func (a *Aside) getRedis(ctx context.Context, key string, ttl time.Duration, dest interface{}) (bool, error) {
+	start := time.Now()
+	size, err := a.redis.getJSON(ctx, key, dest)
+	a.track("redis_get", err == nil, start)
+	if err != nil {
+		if errors.Is(err, ErrCacheMiss) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	a.setLocal(key, reflect.ValueOf(dest).Elem().Interface(), size, ttl)
+	return true, nil
+}
+
+func (a *Aside) setLocal(key string, value interface{}, size int, ttl time.Duration) {
+	if a.local == nil {
+		return
+	}
+	if ttl > a.localTTL {
+		ttl = a.localTTL
+	}
+	for evicted := a.local.Set(key, value, size, ttl); evicted > 0; evicted-- {
+		a.track("local_evict", false, time.Now())
+	}
+}
+
+func (a *Aside) track(operation string, hit bool, start time.Time) {
+	if a.monitor != nil {
+		a.monitor.TrackCacheOperation(operation, hit, time.Since(start))
+	}
+}
+
+// Store caches value under key for ttl, tagged with the addresses it includes.
+// Local reads of the key must use value's type.
+func (a *Aside) Store(ctx context.Context, key string, ttl time.Duration, value interface{}, addresses ...string) {
+	if ttl <= 0 {
+		return
+	}
+	size, err := a.redis.setJSON(ctx, key, value, ttl)
+	if err != nil {
+		return
+	}
+
+	if len(addresses) > 0 {
+		if err := a.redis.TagKey(ctx, key, a.tagTTL, addressTags(addresses)...); err != nil {
+			// An untagged value would survive invalidation, so drop it instead
+			_ = a.redis.Delete(ctx, key)
+			return
+		}
+	}
+	a.setLocal(key, value, size, ttl)
+}
+
+// GetEach reads several keys, decoding the value of keys[i] into dest(i), and
+// reports which were cached. Redis failures are reported as misses.
+func (a *Aside) GetEach(ctx context.Context, keys []string, dest func(i int) interface{}) []bool {
+	found := make([]bool, len(keys))
+
+	var remote []int
+	for i, key := range keys {
+		if a.getLocal(key, dest(i)) {
+			found[i] = true
+		} else {
+			remote = append(remote, i)
+		}
+	}
+	if len(remote) == 0 {
+		return found
+	}
+
+	remoteKeys := make([]string, len(remote))
+	for j, i := range remote {
+		remoteKeys[j] = keys[i]
+	}
+	start := time.Now()
+	sizes, err := a.redis.getEachJSON(ctx, remoteKeys, func(j int) interface{} { return dest(remote[j]) })
+	if err != nil {
+		return found
+	}
+	for j, i := range remote {
+		hit := sizes[j] > 0
+		a.track("redis_get", hit, start)
+		if hit {
+			found[i] = true
+			a.setLocal(keys[i], reflect.ValueOf(dest(i)).Elem().Interface(), sizes[j], a.localTTL)
+		}
+	}
+	return found
+}
+
+// InvalidateAddresses drops every cached read that includes one of the
+// addresses, here and on the other replicas
+func (a *Aside) InvalidateAddresses(ctx context.Context, addresses ...string) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	keys, err := a.redis.InvalidateTags(ctx, addressTags(addresses)...)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if a.local != nil {
+		a.local.Delete(keys...)
+	}
+	if err := a.redis.Publish(ctx, invalidationChannel, invalidationMessage{Origin: a.origin, Keys: keys}); err != nil {
+		a.logger.Warn("Failed to publish cache invalidation", zap.Int("keys", len(keys)), zap.Error(err))
+	}
+
+	a.logger.Debug("Invalidated cached reads",
+		zap.Int("addresses", len(addresses)),
+		zap.Int("keys", len(keys)))
+	return nil
+}
+
+// invalidationBatchSize bounds the keys deleted and published per message
+// when a namespace is invalidated
+const invalidationBatchSize = 1000
+
+// InvalidateNamespace drops every cached read whose key is in namespace, the
+// key prefix before the first colon such as "wallet_rankings", here and on the
+// other replicas. It returns the number of keys dropped.
+func (a *Aside) InvalidateNamespace(ctx context.Context, namespace string) (int, error) {
+	keys, err := a.redis.ScanKeys(ctx, namespace+":*")
+	if err != nil {
+		return 0, err
+	}
+
+	for start := 0; start < len(keys); start += invalidationBatchSize {
+		end := start + invalidationBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batch := keys[start:end]
+
+		if err := a.redis.Delete(ctx, batch...); err != nil {
+			return start, err
+		}
+		if a.local != nil {
+			a.local.Delete(batch...)
+		}
+		if err := a.redis.Publish(ctx, invalidationChannel, invalidationMessage{Origin: a.origin, Keys: batch}); err != nil {
+			a.logger.Warn("Failed to publish cache invalidation", zap.Int("keys", len(batch)), zap.Error(err))
+		}
+	}
+
+	a.logger.Info("Invalidated cache namespace",
+		zap.String("namespace", namespace),
+		zap.Int("keys", len(keys)))
+	return len(keys), nil
+}
+
+func addressTags(addresses []string) []string {
+	seen := make(map[string]bool, len(addresses))
+	tags := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		tag := "address:" + entity.NormalizeAddress(address)
+		if address == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// localEntryOverhead approximates the bookkeeping cost of an entry beyond its
+// value, so that many tiny values still count against the size bound
+const localEntryOverhead = 128
+
+type localEntry struct {
+	key       string
+	value     interface{}
+	size      int64
+	expiresAt time.Time
+}
+
+// LocalCache is an in-process LRU cache with per-entry expiry, bounded by the
+// total size of its entries. Entry sizes are given by the caller, usually the
+// length of the value's encoding.
+type LocalCache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	bytes    int64
+	entries  map[string]*list.Element
+	order    *list.List // front is most recently used
+}
+
+// NewLocalCache creates a local cache holding up to maxBytes of entries
+func NewLocalCache(maxBytes int64) *LocalCache {
+	return &LocalCache{
+		maxBytes: maxBytes,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the value stored under key unless it has expired
+func (c *LocalCache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*localEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// Set stores value under key for ttl and returns how many entries were evicted
+// to make room. Values larger than the whole cache are not stored.
+func (c *LocalCache) Set(key string, value interface{}, size int, ttl time.Duration) int {
+	entrySize := int64(size+len(key)) + localEntryOverhead
+	if ttl <= 0 || entrySize > c.maxBytes {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+
+	evicted := 0
+	for c.bytes+entrySize > c.maxBytes {
+		oldest := c.order.Back()
+		if oldest == nil {
+			break
+		}
+		c.remove(oldest)
+		evicted++
+	}
+
+	c.entries[key] = c.order.PushFront(&localEntry{
+		key:       key,
+		value:     value,
+		size:      entrySize,
+		expiresAt: time.Now().Add(ttl),
+	})
+	c.bytes += entrySize
+	return evicted
+}
+
+// Delete removes keys from the cache
+func (c *LocalCache) Delete(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := c.entries[key]; ok {
+			c.remove(element)
+		}
+	}
+}
+
+// Purge removes every entry
+func (c *LocalCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	c.bytes = 0
+}
+
+// Len returns the number of entries, expired ones included
+func (c *LocalCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Bytes returns the total size of the entries
+func (c *LocalCache) Bytes() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.bytes
+}
+
+func (c *LocalCache) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*localEntry)
+	delete(c.entries, entry.key)
+	c.bytes -= entry.size
+}
@@ -3,11 +3,14 @@ package cache
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"crypto-bubble-map-be/internal/infrastructure/config"
+	"crypto-bubble-map-be/internal/infrastructure/resilience"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
@@ -20,29 +23,37 @@ type RedisClient struct {
 	ttl    *config.TTLConfig
 }
 
-// NewRedisClient creates a new Redis client
-func NewRedisClient(cfg *config.RedisConfig, ttlCfg *config.TTLConfig, logger *zap.Logger) (*RedisClient, error) {
+// NewRedisClient creates a new Redis client. Commands are guarded by the
+// Redis circuit breaker from breakers and retried by go-redis, up to
+// MaxRetries times with the configured exponential backoff.
+func NewRedisClient(cfg *config.RedisConfig, ttlCfg *config.TTLConfig, breakers *resilience.Registry, logger *zap.Logger) (*RedisClient, error) {
+	retry := breakers.RetryPolicy()
+
 	// Configure Redis client
 	rdb := redis.NewClient(&redis.Options{
-		Addr:         cfg.GetAddr(),
-		Password:     cfg.Password,
-		DB:           cfg.DB,
-		MaxRetries:   cfg.MaxRetries,
-		PoolSize:     cfg.PoolSize,
-		MinIdleConns: cfg.MinIdleConns,
-		DialTimeout:  cfg.DialTimeout,
-		ReadTimeout:  cfg.ReadTimeout,
-		WriteTimeout: cfg.WriteTimeout,
+		Addr:            cfg.GetAddr(),
+		Password:        cfg.Password,
+		DB:              cfg.DB,
+		MaxRetries:      cfg.MaxRetries,
+		MinRetryBackoff: retry.InitialBackoff,
+		MaxRetryBackoff: retry.MaxBackoff,
+		PoolSize:        cfg.PoolSize,
+		MinIdleConns:    cfg.MinIdleConns,
+		DialTimeout:     cfg.DialTimeout,
+		ReadTimeout:     cfg.ReadTimeout,
+		WriteTimeout:    cfg.WriteTimeout,
 	})
 
-	// Test connection
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	if err := rdb.Ping(ctx).Err(); err != nil {
-		return nil, fmt.Errorf("failed to ping Redis: %w", err)
+	// Spans per command; a no-op until tracing is enabled
+	if err := redisotel.InstrumentTracing(rdb); err != nil {
+		return nil, fmt.Errorf("failed to instrument Redis client: %w", err)
+	}
+	if breaker := breakers.Breaker(resilience.DependencyRedis, isRedisFailure); breaker != nil {
+		rdb.AddHook(breakerHook{breaker: breaker})
 	}
 
+	// Connectivity is verified by the health manager, so that the server can
+	// start while Redis is unreachable
 	client := &RedisClient{
 		client: rdb,
 		logger: logger,
@@ -50,7 +61,7 @@ func NewRedisClient(cfg *config.RedisConfig, ttlCfg *config.TTLConfig, logger *z
 		ttl:    ttlCfg,
 	}
 
-	logger.Info("Redis client initialized successfully",
+	logger.Info("Redis client initialized",
 		zap.String("addr", cfg.GetAddr()),
 		zap.Int("db", cfg.DB),
 	)
@@ -58,6 +69,53 @@ func NewRedisClient(cfg *config.RedisConfig, ttlCfg *config.TTLConfig, logger *z
 	return client, nil
 }
 
+// breakerHook guards commands with a circuit breaker. Hooks run around
+// go-redis' own retries, so the breaker sees one outcome per command.
+type breakerHook struct {
+	breaker *resilience.Breaker
+}
+
+// DialHook implements redis.Hook
+func (h breakerHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements redis.Hook
+func (h breakerHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.breaker.Allow(); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		err := next(ctx, cmd)
+		h.breaker.Record(err)
+		return err
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook
+func (h breakerHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := h.breaker.Allow(); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		err := next(ctx, cmds)
+		h.breaker.Record(err)
+		return err
+	}
+}
+
+// isRedisFailure reports whether err means Redis is unreachable or timing
+// out. Misses and error replies, e.g. WRONGTYPE, come from a working server;
+// anything else is a network, timeout or connection pool error.
+func isRedisFailure(err error) bool {
+	var replyErr redis.Error
+	return !errors.Is(err, redis.Nil) && !errors.Is(err, context.Canceled) && !errors.As(err, &replyErr)
+}
+
 // Close closes the Redis connection
 func (c *RedisClient) Close() error {
 	return c.client.Close()
@@ -70,13 +128,19 @@ func (c *RedisClient) GetClient() *redis.Client {
 
 // Set stores a value in Redis with TTL
 func (c *RedisClient) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	_, err := c.setJSON(ctx, key, value, ttl)
+	return err
+}
+
+// setJSON stores a value in Redis with TTL and returns its encoded size
+func (c *RedisClient) setJSON(ctx context.Context, key string, value interface{}, ttl time.Duration) (int, error) {
 	data, err := json.Marshal(value)
 	if err != nil {
 		c.logger.Error("Failed to marshal value for Redis",
 			zap.String("key", key),
 			zap.Error(err),
 		)
-		return err
+		return 0, err
 	}
 
 	if err := c.client.Set(ctx, key, data, ttl).Err(); err != nil {
@@ -85,24 +149,30 @@ func (c *RedisClient) Set(ctx context.Context, key string, value interface{}, tt
 			zap.Duration("ttl", ttl),
 			zap.Error(err),
 		)
-		return err
+		return 0, err
 	}
 
-	return nil
+	return len(data), nil
 }
 
 // Get retrieves a value from Redis
 func (c *RedisClient) Get(ctx context.Context, key string, dest interface{}) error {
+	_, err := c.getJSON(ctx, key, dest)
+	return err
+}
+
+// getJSON retrieves a value from Redis and returns its encoded size
+func (c *RedisClient) getJSON(ctx context.Context, key string, dest interface{}) (int, error) {
 	data, err := c.client.Get(ctx, key).Result()
 	if err != nil {
 		if err == redis.Nil {
-			return ErrCacheMiss
+			return 0, ErrCacheMiss
 		}
 		c.logger.Error("Failed to get value from Redis",
 			zap.String("key", key),
 			zap.Error(err),
 		)
-		return err
+		return 0, err
 	}
 
 	if err := json.Unmarshal([]byte(data), dest); err != nil {
@@ -110,10 +180,10 @@ func (c *RedisClient) Get(ctx context.Context, key string, dest interface{}) err
 			zap.String("key", key),
 			zap.Error(err),
 		)
-		return err
+		return 0, err
 	}
 
-	return nil
+	return len(data), nil
 }
 
 // Delete removes a key from Redis
@@ -128,6 +198,24 @@ func (c *RedisClient) Delete(ctx context.Context, keys ...string) error {
 	return nil
 }
 
+// ScanKeys returns the keys matching a glob pattern. It iterates with SCAN, so
+// it does not block Redis the way KEYS does on a large database.
+func (c *RedisClient) ScanKeys(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	iter := c.client.Scan(ctx, 0, pattern, 1000).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		c.logger.Error("Failed to scan keys in Redis",
+			zap.String("pattern", pattern),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return keys, nil
+}
+
 // Exists checks if a key exists in Redis
 func (c *RedisClient) Exists(ctx context.Context, key string) (bool, error) {
 	count, err := c.client.Exists(ctx, key).Result()
@@ -230,6 +318,56 @@ func (c *RedisClient) GetMultiple(ctx context.Context, keys []string) (map[strin
 	return data, nil
 }
 
+// GetEach retrieves several values, decoding the value of keys[i] into dest(i),
+// and reports which keys were found
+func (c *RedisClient) GetEach(ctx context.Context, keys []string, dest func(i int) interface{}) ([]bool, error) {
+	sizes, err := c.getEachJSON(ctx, keys, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make([]bool, len(keys))
+	for i, size := range sizes {
+		found[i] = size > 0
+	}
+	return found, nil
+}
+
+// getEachJSON is GetEach reporting the encoded size of each value found, and
+// zero for the keys that were not
+func (c *RedisClient) getEachJSON(ctx context.Context, keys []string, dest func(i int) interface{}) ([]int, error) {
+	sizes := make([]int, len(keys))
+	if len(keys) == 0 {
+		return sizes, nil
+	}
+
+	results, err := c.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		c.logger.Error("Failed to get multiple values from Redis",
+			zap.Int("count", len(keys)),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	for i, result := range results {
+		data, ok := result.(string)
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal([]byte(data), dest(i)); err != nil {
+			c.logger.Warn("Failed to unmarshal value from Redis",
+				zap.String("key", keys[i]),
+				zap.Error(err),
+			)
+			continue
+		}
+		sizes[i] = len(data)
+	}
+
+	return sizes, nil
+}
+
 // SetMultiple sets multiple key-value pairs
 func (c *RedisClient) SetMultiple(ctx context.Context, data map[string]interface{}, ttl time.Duration) error {
 	pipe := c.client.TxPipeline()
@@ -259,6 +397,104 @@ func (c *RedisClient) SetMultiple(ctx context.Context, data map[string]interface
 	return nil
 }
 
+// Tag sets
+
+// TagKey records key under each tag so that InvalidateTags can find it. The tag
+// sets expire after ttl, which must outlive the tagged keys.
+func (c *RedisClient) TagKey(ctx context.Context, key string, ttl time.Duration, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	pipe := c.client.Pipeline()
+	for _, tag := range tags {
+		pipe.SAdd(ctx, tagSetKey(tag), key)
+		pipe.Expire(ctx, tagSetKey(tag), ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.logger.Error("Failed to tag key in Redis",
+			zap.String("key", key),
+			zap.Int("tags", len(tags)),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// InvalidateTags deletes every key recorded under the tags and returns the keys
+func (c *RedisClient) InvalidateTags(ctx context.Context, tags ...string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	pipe := c.client.Pipeline()
+	members := make([]*redis.StringSliceCmd, len(tags))
+	for i, tag := range tags {
+		members[i] = pipe.SMembers(ctx, tagSetKey(tag))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		c.logger.Error("Failed to read tag sets from Redis",
+			zap.Strings("tags", tags),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	var keys []string
+	tagKeys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys = append(keys, members[i].Val()...)
+		tagKeys[i] = tagSetKey(tag)
+	}
+
+	pipe = c.client.Pipeline()
+	if len(keys) > 0 {
+		pipe.Del(ctx, keys...)
+	}
+	pipe.Del(ctx, tagKeys...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.logger.Error("Failed to delete tagged keys from Redis",
+			zap.Strings("tags", tags),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func tagSetKey(tag string) string {
+	return "cache_tags:" + tag
+}
+
+// Pub/sub
+
+// Publish sends a JSON-encoded message to a channel
+func (c *RedisClient) Publish(ctx context.Context, channel string, message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	if err := c.client.Publish(ctx, channel, data).Err(); err != nil {
+		c.logger.Error("Failed to publish message to Redis",
+			zap.String("channel", channel),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// Subscribe subscribes to channels. The subscription reconnects by itself
+// until it is closed.
+func (c *RedisClient) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	return c.client.Subscribe(ctx, channels...)
+}
+
 // Cache-specific methods with predefined TTLs
 
 // SetWalletNetwork caches wallet network data
@@ -345,6 +581,77 @@ func (c *RedisClient) CheckRateLimit(ctx context.Context, key string, limit int6
 	return current <= limit, nil
 }
 
+// TokenBucketResult describes the outcome of a token bucket charge
+type TokenBucketResult struct {
+	Allowed    bool
+	Capacity   int64
+	Remaining  int64
+	RetryAfter time.Duration
+	ResetAfter time.Duration
+}
+
+// tokenBucketScript atomically refills and charges a token bucket stored as a hash.
+// KEYS[1] = bucket key
+// ARGV[1] = capacity, ARGV[2] = refill rate (tokens per second), ARGV[3] = cost, ARGV[4] = now (ms)
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+local now = tonumber(ARGV[4])
+
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil then
+	tokens = capacity
+	ts = now
+end
+
+local elapsed = math.max(0, now - ts) / 1000
+tokens = math.min(capacity, tokens + elapsed * rate)
+
+local allowed = 0
+local retry_ms = 0
+if tokens >= cost then
+	tokens = tokens - cost
+	allowed = 1
+else
+	retry_ms = math.ceil((cost - tokens) / rate * 1000)
+end
+
+local reset_ms = math.ceil((capacity - tokens) / rate * 1000)
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", tostring(now))
+redis.call("PEXPIRE", KEYS[1], math.max(reset_ms, 1000))
+
+return {allowed, math.floor(tokens), retry_ms, reset_ms}
+`)
+
+// TakeTokens charges cost tokens from the bucket identified by key. The bucket holds
+// at most capacity tokens and refills continuously at refillPerMinute tokens per minute.
+func (c *RedisClient) TakeTokens(ctx context.Context, key string, capacity, refillPerMinute, cost int64) (*TokenBucketResult, error) {
+	if capacity <= 0 || refillPerMinute <= 0 {
+		return nil, fmt.Errorf("invalid token bucket parameters: capacity=%d refill=%d", capacity, refillPerMinute)
+	}
+
+	rate := float64(refillPerMinute) / 60.0
+	res, err := tokenBucketScript.Run(ctx, c.client,
+		[]string{fmt.Sprintf("token_bucket:%s", key)},
+		capacity, rate, cost, time.Now().UnixMilli(),
+	).Int64Slice()
+	if err != nil {
+		c.logger.Error("Failed to charge token bucket", zap.String("key", key), zap.Error(err))
+		return nil, err
+	}
+
+	return &TokenBucketResult{
+		Allowed:    res[0] == 1,
+		Capacity:   capacity,
+		Remaining:  res[1],
+		RetryAfter: time.Duration(res[2]) * time.Millisecond,
+		ResetAfter: time.Duration(res[3]) * time.Millisecond,
+	}, nil
+}
+
 // Session management
 
 // SetSession stores session data
@@ -0,0 +1,142 @@
+package cases
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+
+	"go.uber.org/zap"
+)
+
+const (
+	maxCommentLength      = 10000
+	maxCommentAttachments = 10
+)
+
+// transactionHashPattern matches EVM transaction hashes
+var transactionHashPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+
+// AddComment adds a comment to a case's discussion, optionally as a reply to
+// another comment on the same case. Attachments reference evidence by ID or URL.
+func (s *Service) AddComment(ctx context.Context, caseID string, user *entity.User, body string, parentID *string, attachments []entity.CaseAttachment) (*entity.CaseComment, error) {
+	if err := requireAnalyst(user, "comment on a security case"); err != nil {
+		return nil, err
+	}
+
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, apperrors.NewValidationError("body", "A comment body is required")
+	}
+	if len(body) > maxCommentLength {
+		return nil, apperrors.NewValidationError("body", fmt.Sprintf("A comment may be at most %d characters", maxCommentLength))
+	}
+	if len(attachments) > maxCommentAttachments {
+		return nil, apperrors.NewValidationError("attachments", fmt.Sprintf("A comment may have at most %d attachments", maxCommentAttachments))
+	}
+
+	securityCase, err := s.loadCase(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID != nil {
+		comments, err := s.caseRepo.GetComments(ctx, securityCase.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !hasComment(comments, *parentID) {
+			return nil, apperrors.NewValidationError("parentId", "The comment being replied to is not part of this case")
+		}
+	}
+
+	validated := make([]entity.CaseAttachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		normalized, err := s.validateAttachment(ctx, attachment)
+		if err != nil {
+			return nil, err
+		}
+		validated = append(validated, normalized)
+	}
+
+	comment := &entity.CaseComment{
+		CaseID:      securityCase.ID,
+		ParentID:    parentID,
+		AuthorID:    user.ID,
+		Author:      user.Email,
+		Body:        body,
+		Attachments: validated,
+	}
+	if err := s.caseRepo.AddComment(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug("Security case comment added",
+		zap.String("caseID", securityCase.ID),
+		zap.String("commentID", comment.ID),
+		zap.Int("attachments", len(validated)))
+
+	return comment, nil
+}
+
+// Comments returns a case's comments in the order they were written
+func (s *Service) Comments(ctx context.Context, caseID string) ([]entity.CaseComment, error) {
+	return s.caseRepo.GetComments(ctx, caseID)
+}
+
+// validateAttachment checks that an attachment's reference is well formed for its
+// type and returns it in canonical form
+func (s *Service) validateAttachment(ctx context.Context, attachment entity.CaseAttachment) (entity.CaseAttachment, error) {
+	reference := strings.TrimSpace(attachment.Reference)
+
+	switch attachment.Type {
+	case entity.CaseAttachmentTypeTransaction:
+		if !transactionHashPattern.MatchString(reference) {
+			return attachment, apperrors.NewValidationError("attachments", fmt.Sprintf("%q is not a transaction hash", reference))
+		}
+		reference = strings.ToLower(reference)
+	case entity.CaseAttachmentTypeWallet:
+		reference = entity.NormalizeAddress(reference)
+		if !entity.IsValidAddress(reference) {
+			return attachment, apperrors.NewValidationError("attachments", fmt.Sprintf("%q is not a wallet address", reference))
+		}
+	case entity.CaseAttachmentTypeComplianceReport:
+		report, err := s.securityRepo.GetComplianceReport(ctx, reference)
+		if err != nil {
+			return attachment, err
+		}
+		if report == nil {
+			return attachment, apperrors.NewValidationError("attachments", fmt.Sprintf("Compliance report %s not found", reference))
+		}
+	case entity.CaseAttachmentTypeLink:
+		link, err := url.Parse(reference)
+		if err != nil || (link.Scheme != "http" && link.Scheme != "https") || link.Host == "" {
+			return attachment, apperrors.NewValidationError("attachments", fmt.Sprintf("%q is not an http(s) URL", reference))
+		}
+	default:
+		return attachment, apperrors.NewValidationError("attachments", fmt.Sprintf("Unsupported attachment type %q", attachment.Type))
+	}
+
+	attachment.Reference = reference
+	if attachment.Name != nil {
+		name := strings.TrimSpace(*attachment.Name)
+		attachment.Name = &name
+		if name == "" {
+			attachment.Name = nil
+		}
+	}
+	return attachment, nil
+}
+
+func hasComment(comments []entity.CaseComment, id string) bool {
+	for _, comment := range comments {
+		if comment.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,361 @@
+package cases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/config"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultCaseLimit = 20
+	maxCaseLimit     = 100
+)
+
+// severityRank orders alert severities so a case takes the severity of its worst alert
+var severityRank = map[entity.AlertSeverity]int{
+	entity.AlertSeverityLow:      1,
+	entity.AlertSeverityMedium:   2,
+	entity.AlertSeverityHigh:     3,
+	entity.AlertSeverityCritical: 4,
+}
+
+// Service manages security cases: groups of related alerts that analysts
+// investigate together under severity-based SLAs
+type Service struct {
+	caseRepo     repository.CaseRepository
+	securityRepo repository.SecurityRepository
+	userRepo     repository.UserRepository
+	config       *config.ComplianceConfig
+	logger       *zap.Logger
+}
+
+// NewService creates a new security case service
+func NewService(
+	caseRepo repository.CaseRepository,
+	securityRepo repository.SecurityRepository,
+	userRepo repository.UserRepository,
+	cfg *config.ComplianceConfig,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		caseRepo:     caseRepo,
+		securityRepo: securityRepo,
+		userRepo:     userRepo,
+		config:       cfg,
+		logger:       logger,
+	}
+}
+
+// CreateCaseInput describes a new security case
+type CreateCaseInput struct {
+	Title       string
+	Description string
+	AlertIDs    []string
+	AssigneeID  *uint
+}
+
+// CreateCase opens a case for a set of alerts. The case takes the severity of its
+// most severe alert, and its SLA deadlines follow from that severity.
+func (s *Service) CreateCase(ctx context.Context, input CreateCaseInput, user *entity.User) (*entity.SecurityCase, error) {
+	if err := requireAnalyst(user, "open a security case"); err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return nil, apperrors.NewValidationError("title", "A case title is required")
+	}
+
+	alerts, err := s.loadAlerts(ctx, dedupe(input.AlertIDs), "")
+	if err != nil {
+		return nil, err
+	}
+	if len(alerts) == 0 {
+		return nil, apperrors.NewValidationError("alertIds", "A case needs at least one alert")
+	}
+
+	now := time.Now().UTC()
+	securityCase := &entity.SecurityCase{
+		Title:           title,
+		Description:     strings.TrimSpace(input.Description),
+		Status:          entity.CaseStatusActive,
+		AlertIDs:        []string{},
+		WalletAddresses: []string{},
+		CreatedByID:     user.ID,
+		CreatedBy:       user.Email,
+		CreatedAt:       now,
+	}
+	addAlerts(securityCase, alerts)
+	s.applySLA(securityCase)
+
+	if input.AssigneeID != nil {
+		if err := s.assign(ctx, securityCase, *input.AssigneeID, user); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.caseRepo.CreateCase(ctx, securityCase); err != nil {
+		return nil, err
+	}
+	s.linkAlerts(ctx, securityCase.ID, alerts)
+
+	s.logger.Info("Security case opened",
+		zap.String("caseID", securityCase.ID),
+		zap.String("severity", string(securityCase.Severity)),
+		zap.Int("alerts", len(securityCase.AlertIDs)),
+		zap.String("actor", user.Email))
+
+	return securityCase, nil
+}
+
+// AddAlerts adds alerts to an open case. If an added alert is more severe than the
+// case, the case is escalated and its SLA deadlines tightened accordingly.
+func (s *Service) AddAlerts(ctx context.Context, caseID string, alertIDs []string, user *entity.User) (*entity.SecurityCase, error) {
+	if err := requireAnalyst(user, "change a security case"); err != nil {
+		return nil, err
+	}
+
+	securityCase, err := s.loadCase(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if !securityCase.IsOpen() {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeInvalidTransition,
+			"Security case is closed",
+			fmt.Sprintf("Alerts cannot be added to a case in status %s", securityCase.Status))
+	}
+
+	var newIDs []string
+	for _, id := range dedupe(alertIDs) {
+		if !contains(securityCase.AlertIDs, id) {
+			newIDs = append(newIDs, id)
+		}
+	}
+	if len(newIDs) == 0 {
+		return securityCase, nil
+	}
+
+	alerts, err := s.loadAlerts(ctx, newIDs, securityCase.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedVersion := securityCase.Version
+	previousSeverity := securityCase.Severity
+	addAlerts(securityCase, alerts)
+	if securityCase.Severity != previousSeverity {
+		s.applySLA(securityCase)
+	}
+
+	if err := s.save(ctx, securityCase, expectedVersion); err != nil {
+		return nil, err
+	}
+	s.linkAlerts(ctx, securityCase.ID, alerts)
+
+	if securityCase.Severity != previousSeverity {
+		s.logger.Info("Security case escalated",
+			zap.String("caseID", securityCase.ID),
+			zap.String("from", string(previousSeverity)),
+			zap.String("to", string(securityCase.Severity)))
+	}
+
+	return securityCase, nil
+}
+
+// Case returns a security case by ID, or nil if there is none
+func (s *Service) Case(ctx context.Context, caseID string) (*entity.SecurityCase, error) {
+	return s.caseRepo.GetCase(ctx, caseID)
+}
+
+// Cases lists security cases, soonest resolution deadline first
+func (s *Service) Cases(ctx context.Context, filters *entity.CaseFilters, limit, offset int) ([]entity.SecurityCase, error) {
+	if limit <= 0 {
+		limit = defaultCaseLimit
+	}
+	if limit > maxCaseLimit {
+		limit = maxCaseLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.caseRepo.GetCases(ctx, filters, limit, offset)
+}
+
+// MyOpenCases lists the open cases assigned to user, soonest resolution deadline first
+func (s *Service) MyOpenCases(ctx context.Context, user *entity.User) ([]entity.SecurityCase, error) {
+	filters := &entity.CaseFilters{
+		Statuses:   []entity.CaseStatus{entity.CaseStatusActive, entity.CaseStatusInvestigating},
+		AssigneeID: &user.ID,
+	}
+	return s.caseRepo.GetCases(ctx, filters, maxCaseLimit, 0)
+}
+
+// Alerts returns the alerts grouped in a case
+func (s *Service) Alerts(ctx context.Context, securityCase *entity.SecurityCase) ([]entity.SecurityAlert, error) {
+	alerts := make([]entity.SecurityAlert, 0, len(securityCase.AlertIDs))
+	for _, id := range securityCase.AlertIDs {
+		alert, err := s.securityRepo.GetSecurityAlert(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if alert != nil {
+			alerts = append(alerts, *alert)
+		}
+	}
+	return alerts, nil
+}
+
+// loadAlerts loads the alerts to add to a case. Closed alerts and alerts that are
+// already handled in another open case are rejected.
+func (s *Service) loadAlerts(ctx context.Context, alertIDs []string, caseID string) ([]entity.SecurityAlert, error) {
+	alerts := make([]entity.SecurityAlert, 0, len(alertIDs))
+	for _, id := range alertIDs {
+		alert, err := s.securityRepo.GetSecurityAlert(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if alert == nil {
+			return nil, apperrors.NewValidationError("alertIds", fmt.Sprintf("Security alert %s not found", id))
+		}
+		if alert.Status != entity.AlertStatusActive && alert.Status != entity.AlertStatusInvestigating {
+			return nil, apperrors.NewValidationError("alertIds",
+				fmt.Sprintf("Security alert %s is %s and cannot be added to a case", id, alert.Status))
+		}
+		if alert.CaseID != nil && *alert.CaseID != caseID {
+			other, err := s.caseRepo.GetCase(ctx, *alert.CaseID)
+			if err != nil {
+				return nil, err
+			}
+			if other != nil && other.IsOpen() {
+				return nil, apperrors.NewValidationError("alertIds",
+					fmt.Sprintf("Security alert %s is already handled in case %s", id, other.ID)).
+					WithMetadata("caseId", other.ID)
+			}
+		}
+		alerts = append(alerts, *alert)
+	}
+	return alerts, nil
+}
+
+// linkAlerts records the case on its alerts. The case is the source of truth for
+// membership, so failures are logged rather than undoing the case change.
+func (s *Service) linkAlerts(ctx context.Context, caseID string, alerts []entity.SecurityAlert) {
+	for i := range alerts {
+		alert := &alerts[i]
+		alert.CaseID = &caseID
+		alert.UpdatedAt = time.Now()
+		if err := s.securityRepo.UpdateSecurityAlert(ctx, alert); err != nil {
+			s.logger.Warn("Failed to link security alert to case",
+				zap.String("caseID", caseID),
+				zap.String("alertID", alert.ID),
+				zap.Error(err))
+		}
+	}
+}
+
+// applySLA sets the case's deadlines from its severity, measured from when it was
+// opened. Deadlines only ever move earlier, so escalation cannot extend them.
+func (s *Service) applySLA(securityCase *entity.SecurityCase) {
+	response, resolution := s.slaFor(securityCase.Severity)
+	responseDue := securityCase.CreatedAt.Add(response)
+	resolutionDue := securityCase.CreatedAt.Add(resolution)
+
+	if securityCase.ResponseDueAt.IsZero() || responseDue.Before(securityCase.ResponseDueAt) {
+		securityCase.ResponseDueAt = responseDue
+	}
+	if securityCase.ResolutionDueAt.IsZero() || resolutionDue.Before(securityCase.ResolutionDueAt) {
+		securityCase.ResolutionDueAt = resolutionDue
+	}
+}
+
+// slaFor returns the configured response and resolution times for a severity
+func (s *Service) slaFor(severity entity.AlertSeverity) (time.Duration, time.Duration) {
+	switch severity {
+	case entity.AlertSeverityCritical:
+		return s.config.CaseResponseSLACritical, s.config.CaseResolutionSLACritical
+	case entity.AlertSeverityHigh:
+		return s.config.CaseResponseSLAHigh, s.config.CaseResolutionSLAHigh
+	case entity.AlertSeverityMedium:
+		return s.config.CaseResponseSLAMedium, s.config.CaseResolutionSLAMedium
+	default:
+		return s.config.CaseResponseSLALow, s.config.CaseResolutionSLALow
+	}
+}
+
+// save writes a changed case, failing if it was changed concurrently
+func (s *Service) save(ctx context.Context, securityCase *entity.SecurityCase, expectedVersion int) error {
+	applied, err := s.caseRepo.UpdateCase(ctx, securityCase, expectedVersion)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return apperrors.NewAppError(apperrors.ErrCodeInvalidTransition,
+			"Security case was changed concurrently",
+			"Reload the case and try again")
+	}
+	return nil
+}
+
+func (s *Service) loadCase(ctx context.Context, caseID string) (*entity.SecurityCase, error) {
+	securityCase, err := s.caseRepo.GetCase(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if securityCase == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeCaseNotFound, "Security case not found", caseID)
+	}
+	return securityCase, nil
+}
+
+// addAlerts adds alerts and their wallets to a case and raises its severity to
+// that of the most severe alert
+func addAlerts(securityCase *entity.SecurityCase, alerts []entity.SecurityAlert) {
+	for _, alert := range alerts {
+		securityCase.AlertIDs = append(securityCase.AlertIDs, alert.ID)
+		address := entity.NormalizeAddress(alert.WalletAddress)
+		if address != "" && !contains(securityCase.WalletAddresses, address) {
+			securityCase.WalletAddresses = append(securityCase.WalletAddresses, address)
+		}
+		if severityRank[alert.Severity] > severityRank[securityCase.Severity] {
+			securityCase.Severity = alert.Severity
+		}
+	}
+}
+
+func requireAnalyst(user *entity.User, action string) error {
+	if !user.IsAnalyst() {
+		return apperrors.NewAuthError(apperrors.ErrCodeAuthPermissionDenied,
+			fmt.Sprintf("Analyst role required to %s", action))
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func dedupe(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v != "" && !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,220 @@
+package cases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+
+	"go.uber.org/zap"
+)
+
+// transitions is the case workflow: an active case is investigated and then
+// resolved, and an open case may be closed as a false positive at any point
+var transitions = map[entity.CaseStatus][]entity.CaseStatus{
+	entity.CaseStatusActive:        {entity.CaseStatusInvestigating, entity.CaseStatusFalsePositive},
+	entity.CaseStatusInvestigating: {entity.CaseStatusResolved, entity.CaseStatusFalsePositive},
+}
+
+// AssignCase assigns an open case to an analyst. Moderators may assign cases to
+// anyone; analysts may only take cases themselves.
+func (s *Service) AssignCase(ctx context.Context, caseID string, assigneeID uint, user *entity.User) (*entity.SecurityCase, error) {
+	if err := requireAnalyst(user, "assign a security case"); err != nil {
+		return nil, err
+	}
+
+	securityCase, err := s.loadCase(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if !securityCase.IsOpen() {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeInvalidTransition,
+			"Security case is closed",
+			fmt.Sprintf("A case in status %s cannot be reassigned", securityCase.Status))
+	}
+	if securityCase.AssigneeID != nil && *securityCase.AssigneeID == assigneeID {
+		return securityCase, nil
+	}
+
+	expectedVersion := securityCase.Version
+	if err := s.assign(ctx, securityCase, assigneeID, user); err != nil {
+		return nil, err
+	}
+	if err := s.save(ctx, securityCase, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Security case assigned",
+		zap.String("caseID", securityCase.ID),
+		zap.Uint("assigneeID", assigneeID),
+		zap.String("actor", user.Email))
+
+	return securityCase, nil
+}
+
+// StartInvestigation moves an active case to INVESTIGATING, which stops the
+// response timer. An unassigned case is assigned to the analyst starting it.
+func (s *Service) StartInvestigation(ctx context.Context, caseID string, user *entity.User, notes *string) (*entity.SecurityCase, error) {
+	return s.transition(ctx, caseID, entity.CaseStatusInvestigating, user, func(securityCase *entity.SecurityCase, now time.Time) {
+		securityCase.InvestigationStartedAt = &now
+	}, func(alert *entity.SecurityAlert) {
+		if alert.IsActive() {
+			alert.StartInvestigation(user.Email, stringValue(notes))
+		}
+	})
+}
+
+// ResolveCase closes an investigated case with its outcome and resolves its alerts
+func (s *Service) ResolveCase(ctx context.Context, caseID string, user *entity.User, resolution string) (*entity.SecurityCase, error) {
+	resolution = strings.TrimSpace(resolution)
+	if resolution == "" {
+		return nil, apperrors.NewValidationError("resolution", "A resolution is required")
+	}
+	return s.transition(ctx, caseID, entity.CaseStatusResolved, user, func(securityCase *entity.SecurityCase, now time.Time) {
+		securityCase.Resolution = &resolution
+	}, func(alert *entity.SecurityAlert) {
+		alert.Resolve(user.Email, resolution, "")
+	})
+}
+
+// MarkFalsePositive closes an open case because its alerts turned out not to be
+// genuine, marking the alerts as false positives as well
+func (s *Service) MarkFalsePositive(ctx context.Context, caseID string, user *entity.User, reason string) (*entity.SecurityCase, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, apperrors.NewValidationError("reason", "A reason is required to mark a case as a false positive")
+	}
+	return s.transition(ctx, caseID, entity.CaseStatusFalsePositive, user, func(securityCase *entity.SecurityCase, now time.Time) {
+		securityCase.Resolution = &reason
+	}, func(alert *entity.SecurityAlert) {
+		alert.Resolve(user.Email, reason, "")
+		alert.Status = entity.AlertStatusFalsePositive
+	})
+}
+
+// transition moves a case to a new status and applies the matching change to its
+// alerts. Only the assignee or a moderator may change an assigned case's status.
+func (s *Service) transition(ctx context.Context, caseID string, to entity.CaseStatus, user *entity.User, updateCase func(*entity.SecurityCase, time.Time), updateAlert func(*entity.SecurityAlert)) (*entity.SecurityCase, error) {
+	if err := requireAnalyst(user, "change a security case"); err != nil {
+		return nil, err
+	}
+
+	securityCase, err := s.loadCase(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed(securityCase.Status, to) {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeInvalidTransition,
+			"Invalid case status transition",
+			fmt.Sprintf("Cannot move a case from %s to %s", securityCase.Status, to))
+	}
+	if securityCase.AssigneeID != nil && *securityCase.AssigneeID != user.ID && !user.IsModerator() {
+		return nil, apperrors.NewAuthError(apperrors.ErrCodeAuthPermissionDenied,
+			"Only the case assignee or a moderator can change the case status")
+	}
+
+	expectedVersion := securityCase.Version
+	from := securityCase.Status
+	now := time.Now().UTC()
+
+	if securityCase.AssigneeID == nil {
+		if err := s.assign(ctx, securityCase, user.ID, user); err != nil {
+			return nil, err
+		}
+	}
+	securityCase.Status = to
+	if !securityCase.IsOpen() {
+		closedBy := user.Email
+		securityCase.ClosedAt = &now
+		securityCase.ClosedBy = &closedBy
+	}
+	updateCase(securityCase, now)
+
+	if err := s.save(ctx, securityCase, expectedVersion); err != nil {
+		return nil, err
+	}
+	s.updateAlerts(ctx, securityCase, updateAlert)
+
+	s.logger.Info("Security case transitioned",
+		zap.String("caseID", securityCase.ID),
+		zap.String("from", string(from)),
+		zap.String("to", string(to)),
+		zap.String("sla", string(securityCase.SLAStatus(now))),
+		zap.String("actor", user.Email))
+
+	return securityCase, nil
+}
+
+// updateAlerts applies a status change to every alert in the case that is still open
+func (s *Service) updateAlerts(ctx context.Context, securityCase *entity.SecurityCase, update func(*entity.SecurityAlert)) {
+	for _, id := range securityCase.AlertIDs {
+		alert, err := s.securityRepo.GetSecurityAlert(ctx, id)
+		if err != nil || alert == nil {
+			s.logger.Warn("Failed to load case alert",
+				zap.String("caseID", securityCase.ID),
+				zap.String("alertID", id),
+				zap.Error(err))
+			continue
+		}
+		if alert.Status != entity.AlertStatusActive && alert.Status != entity.AlertStatusInvestigating {
+			continue
+		}
+
+		update(alert)
+		if err := s.securityRepo.UpdateSecurityAlert(ctx, alert); err != nil {
+			s.logger.Warn("Failed to update case alert",
+				zap.String("caseID", securityCase.ID),
+				zap.String("alertID", id),
+				zap.Error(err))
+		}
+	}
+}
+
+// assign sets the case assignee after checking that actor may assign it and that
+// the assignee is an active analyst
+func (s *Service) assign(ctx context.Context, securityCase *entity.SecurityCase, assigneeID uint, actor *entity.User) error {
+	if assigneeID != actor.ID && !actor.IsModerator() {
+		return apperrors.NewAuthError(apperrors.ErrCodeAuthPermissionDenied,
+			"Moderator role required to assign a case to someone else")
+	}
+
+	assignee := actor
+	if assigneeID != actor.ID {
+		var err error
+		assignee, err = s.userRepo.GetUserByID(ctx, assigneeID)
+		if err != nil {
+			return err
+		}
+		if assignee == nil {
+			return apperrors.NewAppError(apperrors.ErrCodeAuthUserNotFound, "Assignee not found", fmt.Sprintf("%d", assigneeID))
+		}
+	}
+	if !assignee.IsActive || !assignee.IsAnalyst() {
+		return apperrors.NewValidationError("assigneeId", "Cases can only be assigned to active analysts")
+	}
+
+	id, email, now := assignee.ID, assignee.Email, time.Now().UTC()
+	securityCase.AssigneeID = &id
+	securityCase.Assignee = &email
+	securityCase.AssignedAt = &now
+	return nil
+}
+
+func allowed(from, to entity.CaseStatus) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
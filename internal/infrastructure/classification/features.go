@@ -0,0 +1,79 @@
+package classification
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+)
+
+// computeFeatures derives behaviour features from the wallet and a sample of its
+// most recent transactions
+func computeFeatures(wallet *entity.Wallet, transactions []entity.Transaction) entity.WalletFeatures {
+	address := entity.NormalizeAddress(wallet.Address)
+	features := entity.WalletFeatures{
+		SampledTransactions: len(transactions),
+		ConnectionCount:     int64(wallet.ConnectionCount),
+	}
+	if wallet.Balance != nil {
+		// Wallet balances are stored in ETH, not wei
+		features.BalanceETH, _ = strconv.ParseFloat(strings.TrimSpace(*wallet.Balance), 64)
+	}
+
+	senders := make(map[string]struct{})
+	recipients := make(map[string]struct{})
+	var contractCalls int
+	var earliest, latest time.Time
+	for _, tx := range transactions {
+		from := entity.NormalizeAddress(tx.From)
+		to := ""
+		if tx.To != nil {
+			to = entity.NormalizeAddress(*tx.To)
+		}
+
+		switch address {
+		case from:
+			features.Outgoing++
+			if to != "" {
+				recipients[to] = struct{}{}
+			}
+			if hasInputData(tx) {
+				contractCalls++
+			}
+		case to:
+			features.Incoming++
+			senders[from] = struct{}{}
+		}
+
+		if earliest.IsZero() || tx.Timestamp.Before(earliest) {
+			earliest = tx.Timestamp
+		}
+		if tx.Timestamp.After(latest) {
+			latest = tx.Timestamp
+		}
+	}
+
+	features.DistinctSenders = len(senders)
+	features.DistinctRecipients = len(recipients)
+	if features.Outgoing > 0 {
+		features.ContractCallRatio = float64(contractCalls) / float64(features.Outgoing)
+	}
+	if len(transactions) > 0 {
+		// A sample spanning less than an hour is treated as an hour, so a burst of
+		// a few transactions does not extrapolate to an implausible daily rate
+		days := latest.Sub(earliest).Hours() / 24
+		if days < 1.0/24 {
+			days = 1.0 / 24
+		}
+		features.TransactionsPerDay = float64(len(transactions)) / days
+	}
+
+	return features
+}
+
+// hasInputData reports whether a transaction carries calldata, i.e. calls a contract
+func hasInputData(tx entity.Transaction) bool {
+	data := strings.TrimSpace(tx.Data)
+	return data != "" && data != "0x"
+}
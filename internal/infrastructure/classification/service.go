@@ -0,0 +1,402 @@
+package classification
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/config"
+	"crypto-bubble-map-be/internal/infrastructure/monitoring"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// checkpointName identifies the classifier's position in the transaction stream
+	checkpointName = "wallet_classifier"
+	// transactionsPerWallet sizes the transaction pages scanned for active wallets
+	transactionsPerWallet = 5
+	// runTimeout bounds a single run
+	runTimeout = 5 * time.Minute
+)
+
+// typePriority breaks ties between equally scored types, most specific first
+var typePriority = []entity.WalletType{
+	entity.WalletTypeBlacklisted,
+	entity.WalletTypeSuspicious,
+	entity.WalletTypeMEVBot,
+	entity.WalletTypeArbitrageBot,
+	entity.WalletTypeExchange,
+	entity.WalletTypeBridge,
+	entity.WalletTypeMiner,
+	entity.WalletTypeMarketMaker,
+	entity.WalletTypeDefi,
+	entity.WalletTypeContract,
+	entity.WalletTypeWhale,
+	entity.WalletTypeRegular,
+}
+
+// RunResult summarizes one incremental classification run
+type RunResult struct {
+	Transactions int
+	Classified   int
+	Changed      int
+	Skipped      int
+}
+
+// Service assigns wallet types from label data, contract detection, balances,
+// counterparty fan-in, behaviour features and the MEV, sanctions and alert
+// records. Each signal is weighted evidence for a type; the best supported type
+// wins and its score is the classification's confidence.
+//
+// When started, the service re-classifies wallets as new transactions involving
+// them are crawled, so classifications follow wallet behaviour incrementally.
+type Service struct {
+	walletRepo         repository.WalletRepository
+	transactionRepo    repository.TransactionRepository
+	classificationRepo repository.ClassificationRepository
+	mevRepo            repository.MEVRepository
+	sanctionsRepo      repository.SanctionsRepository
+	securityRepo       repository.SecurityRepository
+	config             *config.ClassifierConfig
+	metrics            *monitoring.MetricsCollector
+	logger             *zap.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewService creates a new wallet classification service
+func NewService(
+	walletRepo repository.WalletRepository,
+	transactionRepo repository.TransactionRepository,
+	classificationRepo repository.ClassificationRepository,
+	mevRepo repository.MEVRepository,
+	sanctionsRepo repository.SanctionsRepository,
+	securityRepo repository.SecurityRepository,
+	cfg *config.ClassifierConfig,
+	metrics *monitoring.MetricsCollector,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		walletRepo:         walletRepo,
+		transactionRepo:    transactionRepo,
+		classificationRepo: classificationRepo,
+		mevRepo:            mevRepo,
+		sanctionsRepo:      sanctionsRepo,
+		securityRepo:       securityRepo,
+		config:             cfg,
+		metrics:            metrics,
+		logger:             logger,
+	}
+}
+
+// Classification returns the wallet's latest classification, or nil if it has
+// not been classified
+func (s *Service) Classification(ctx context.Context, address string) (*entity.WalletClassification, error) {
+	return s.classificationRepo.GetClassification(ctx, address)
+}
+
+// Classify classifies the wallet now and stores the result on the wallet
+func (s *Service) Classify(ctx context.Context, address string) (*entity.WalletClassification, error) {
+	address = entity.NormalizeAddress(address)
+
+	wallet, err := s.walletRepo.GetWallet(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := s.transactionRepo.GetTransactionsByWallet(ctx, address, s.config.SampleSize, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	features := computeFeatures(wallet, transactions)
+	evidence, err := s.collectEvidence(ctx, address, wallet, features)
+	if err != nil {
+		return nil, err
+	}
+
+	classification := score(evidence)
+	classification.Address = address
+	classification.Features = features
+	if wallet.WalletType != "" {
+		previousType := wallet.WalletType
+		classification.PreviousType = &previousType
+	}
+	classification.ClassifiedAt = time.Now()
+
+	if err := s.classificationRepo.SaveClassification(ctx, classification); err != nil {
+		return nil, err
+	}
+	if err := s.walletRepo.UpdateClassification(ctx, classification); err != nil {
+		return nil, err
+	}
+
+	s.metrics.Counter("classifier_wallets_total",
+		map[string]string{"wallet_type": string(classification.WalletType)},
+		"Wallets classified, by assigned type")
+	if wallet.WalletType != classification.WalletType {
+		s.metrics.Counter("classifier_type_changes_total", nil,
+			"Classifications that changed a wallet's type")
+		s.logger.Debug("Wallet type changed",
+			zap.String("address", address),
+			zap.String("from", string(wallet.WalletType)),
+			zap.String("to", string(classification.WalletType)),
+			zap.Float64("confidence", classification.Confidence))
+	}
+
+	return classification, nil
+}
+
+// collectEvidence gathers the evidence from every signal
+func (s *Service) collectEvidence(ctx context.Context, address string, wallet *entity.Wallet, features entity.WalletFeatures) ([]entity.ClassificationEvidence, error) {
+	evidence := []entity.ClassificationEvidence{{
+		WalletType:  entity.WalletTypeRegular,
+		Source:      entity.ClassificationSourceDefault,
+		Description: "No more specific type is supported",
+		Weight:      defaultWeight,
+	}}
+
+	if wallet.WalletType == entity.WalletTypeBlacklisted {
+		// Blacklisting is an enforcement decision and is never lifted automatically
+		evidence = append(evidence, entity.ClassificationEvidence{
+			WalletType:  entity.WalletTypeBlacklisted,
+			Source:      entity.ClassificationSourceManual,
+			Description: "Wallet is blacklisted",
+			Weight:      0.99,
+		})
+	}
+
+	evidence = append(evidence, labelEvidence(wallet)...)
+	evidence = append(evidence, s.contractEvidence(wallet, features)...)
+	evidence = append(evidence, s.balanceEvidence(features)...)
+	evidence = append(evidence, s.fanInEvidence(wallet, features)...)
+	evidence = append(evidence, s.behaviourEvidence(features)...)
+
+	lookups := []struct {
+		name   string
+		lookup func(context.Context, string) ([]entity.ClassificationEvidence, error)
+	}{
+		{"MEV activity", s.mevEvidence},
+		{"sanctions", s.sanctionsEvidence},
+		{"security alerts", s.alertEvidence},
+	}
+	for _, l := range lookups {
+		found, err := l.lookup(ctx, address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up %s: %w", l.name, err)
+		}
+		evidence = append(evidence, found...)
+	}
+
+	return evidence, nil
+}
+
+// score combines the evidence per type as independent probabilities
+// (1 - Π(1 - weight)) and picks the highest scoring type
+func score(evidence []entity.ClassificationEvidence) *entity.WalletClassification {
+	remaining := make(map[entity.WalletType]float64)
+	for _, e := range evidence {
+		if _, ok := remaining[e.WalletType]; !ok {
+			remaining[e.WalletType] = 1
+		}
+		remaining[e.WalletType] *= 1 - e.Weight
+	}
+
+	scores := make([]entity.WalletTypeScore, 0, len(remaining))
+	for walletType, r := range remaining {
+		scores = append(scores, entity.WalletTypeScore{WalletType: walletType, Score: 1 - r})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return priority(scores[i].WalletType) < priority(scores[j].WalletType)
+	})
+
+	winner := scores[0]
+	var supporting []entity.ClassificationEvidence
+	for _, e := range evidence {
+		if e.WalletType == winner.WalletType {
+			supporting = append(supporting, e)
+		}
+	}
+	sort.SliceStable(supporting, func(i, j int) bool {
+		return supporting[i].Weight > supporting[j].Weight
+	})
+
+	return &entity.WalletClassification{
+		WalletType: winner.WalletType,
+		Confidence: winner.Score,
+		Evidence:   supporting,
+		Scores:     scores,
+	}
+}
+
+func priority(walletType entity.WalletType) int {
+	for i, t := range typePriority {
+		if t == walletType {
+			return i
+		}
+	}
+	return len(typePriority)
+}
+
+// Start re-classifies active wallets every configured interval until Stop is called
+func (s *Service) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			s.runScheduled(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	s.logger.Info("Started wallet classifier", zap.Duration("interval", s.config.Interval))
+}
+
+// Stop stops the schedule and waits for a run in progress to finish
+func (s *Service) Stop() {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+	s.logger.Info("Stopped wallet classifier")
+}
+
+func (s *Service) runScheduled(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, runTimeout)
+	defer cancel()
+
+	result, err := s.RunOnce(runCtx)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Error("Wallet classification run failed", zap.Error(err))
+		}
+		return
+	}
+	if result.Classified > 0 {
+		s.logger.Info("Wallet classification run completed",
+			zap.Int("transactions", result.Transactions),
+			zap.Int("classified", result.Classified),
+			zap.Int("changed", result.Changed),
+			zap.Int("skipped", result.Skipped))
+	}
+}
+
+// RunOnce re-classifies the wallets involved in transactions crawled since the
+// checkpoint, up to BatchSize wallets. Wallets classified within
+// MinReclassifyInterval are skipped; the checkpoint only advances past
+// transactions whose wallets were all considered.
+func (s *Service) RunOnce(ctx context.Context) (*RunResult, error) {
+	checkpoint, err := s.securityRepo.GetDetectionCheckpoint(ctx, checkpointName)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint == nil {
+		checkpoint = &entity.DetectionCheckpoint{
+			Name:   checkpointName,
+			Cursor: entity.TransactionCursor{CrawledAt: time.Now()},
+		}
+	}
+
+	transactions, err := s.transactionRepo.GetTransactionsAfter(ctx, checkpoint.Cursor, int64(s.config.BatchSize*transactionsPerWallet))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RunResult{}
+	seen := make(map[string]struct{})
+	for _, tx := range transactions {
+		addresses := []string{entity.NormalizeAddress(tx.From)}
+		if tx.To != nil {
+			addresses = append(addresses, entity.NormalizeAddress(*tx.To))
+		}
+		if len(seen) > 0 && len(seen)+len(addresses) > s.config.BatchSize {
+			break
+		}
+
+		for _, address := range addresses {
+			if _, ok := seen[address]; ok || address == "" {
+				continue
+			}
+			seen[address] = struct{}{}
+
+			classified, changed, err := s.reclassify(ctx, address)
+			if err != nil {
+				if ctx.Err() != nil {
+					return result, ctx.Err()
+				}
+				// Wallets not yet in the graph cannot be classified; try again next time they transact
+				s.logger.Debug("Failed to classify wallet", zap.String("address", address), zap.Error(err))
+				continue
+			}
+			if !classified {
+				result.Skipped++
+				continue
+			}
+			result.Classified++
+			if changed {
+				result.Changed++
+			}
+		}
+
+		result.Transactions++
+		checkpoint.Cursor = entity.TransactionCursor{CrawledAt: tx.CrawledAt, ID: tx.ID}
+	}
+
+	if result.Transactions > 0 {
+		checkpoint.Processed += int64(result.Transactions)
+		checkpoint.UpdatedAt = time.Now()
+		if err := s.securityRepo.SaveDetectionCheckpoint(ctx, checkpoint); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// reclassify classifies the wallet unless it was classified recently
+func (s *Service) reclassify(ctx context.Context, address string) (classified, changed bool, err error) {
+	previous, err := s.classificationRepo.GetClassification(ctx, address)
+	if err != nil {
+		return false, false, err
+	}
+	if previous != nil && time.Since(previous.ClassifiedAt) < s.config.MinReclassifyInterval {
+		return false, false, nil
+	}
+
+	classification, err := s.Classify(ctx, address)
+	if err != nil {
+		return false, false, err
+	}
+	changed = classification.PreviousType == nil || *classification.PreviousType != classification.WalletType
+	return true, changed, nil
+}
@@ -0,0 +1,274 @@
+package classification
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+)
+
+const (
+	// defaultWeight is the prior for REGULAR, which any real signal outweighs
+	defaultWeight = 0.3
+	// minBehaviourSample is how many transactions behaviour rules need before they apply
+	minBehaviourSample = 20
+	// maxEvidenceRecords caps the MEV activities and alerts read per wallet
+	maxEvidenceRecords = 50
+)
+
+// labelRule maps keywords found in a wallet's label or tags to a wallet type
+type labelRule struct {
+	walletType entity.WalletType
+	weight     float64
+	keywords   []string
+}
+
+// labelRules are checked in order; every matching rule contributes evidence
+var labelRules = []labelRule{
+	{entity.WalletTypeExchange, 0.9, []string{"exchange", "binance", "coinbase", "kraken", "okx", "bitfinex", "huobi", "kucoin", "gemini", "bybit", "hot wallet", "cold wallet"}},
+	{entity.WalletTypeBridge, 0.9, []string{"bridge", "wormhole", "multichain", "stargate", "hop protocol"}},
+	{entity.WalletTypeMiner, 0.9, []string{"miner", "mining", "ethermine", "f2pool", "sparkpool", "nanopool"}},
+	{entity.WalletTypeMarketMaker, 0.85, []string{"market maker", "wintermute", "jump trading", "cumberland"}},
+	{entity.WalletTypeMEVBot, 0.85, []string{"mev", "flashbots", "sandwich"}},
+	{entity.WalletTypeArbitrageBot, 0.8, []string{"arbitrage", "arb bot"}},
+	{entity.WalletTypeDefi, 0.8, []string{"uniswap", "sushiswap", "curve", "aave", "compound", "balancer", "1inch", "router", "vault", "lending", "dex"}},
+}
+
+// tagWeightFactor discounts tag matches, which are less curated than labels
+const tagWeightFactor = 0.85
+
+// labelEvidence matches the wallet's label and tags against labelRules
+func labelEvidence(wallet *entity.Wallet) []entity.ClassificationEvidence {
+	var evidence []entity.ClassificationEvidence
+	for _, rule := range labelRules {
+		if wallet.Label != nil {
+			if keyword, ok := matchKeyword(*wallet.Label, rule.keywords); ok {
+				evidence = append(evidence, entity.ClassificationEvidence{
+					WalletType:  rule.walletType,
+					Source:      entity.ClassificationSourceLabel,
+					Description: fmt.Sprintf("Label %q mentions %q", *wallet.Label, keyword),
+					Weight:      rule.weight,
+				})
+				continue
+			}
+		}
+		for _, tag := range wallet.Tags {
+			if keyword, ok := matchKeyword(tag, rule.keywords); ok {
+				evidence = append(evidence, entity.ClassificationEvidence{
+					WalletType:  rule.walletType,
+					Source:      entity.ClassificationSourceLabel,
+					Description: fmt.Sprintf("Tag %q mentions %q", tag, keyword),
+					Weight:      rule.weight * tagWeightFactor,
+				})
+				break
+			}
+		}
+	}
+	return evidence
+}
+
+func matchKeyword(text string, keywords []string) (string, bool) {
+	text = strings.ToLower(text)
+	for _, keyword := range keywords {
+		if strings.Contains(text, keyword) {
+			return keyword, true
+		}
+	}
+	return "", false
+}
+
+// contractEvidence recognizes contracts from the wallet node, or from a history
+// of only receiving calls. Heavily used contracts are most likely DeFi protocols.
+func (s *Service) contractEvidence(wallet *entity.Wallet, features entity.WalletFeatures) []entity.ClassificationEvidence {
+	var evidence []entity.ClassificationEvidence
+	isContract := wallet.IsContract
+	if isContract {
+		evidence = append(evidence, entity.ClassificationEvidence{
+			WalletType:  entity.WalletTypeContract,
+			Source:      entity.ClassificationSourceContract,
+			Description: "Address has contract code",
+			Weight:      0.8,
+		})
+	} else if features.Outgoing == 0 && features.Incoming >= minBehaviourSample {
+		// Contracts cannot originate transactions
+		isContract = true
+		evidence = append(evidence, entity.ClassificationEvidence{
+			WalletType:  entity.WalletTypeContract,
+			Source:      entity.ClassificationSourceContract,
+			Description: fmt.Sprintf("Received %d transactions and never sent one", features.Incoming),
+			Weight:      0.6,
+		})
+	}
+
+	if isContract && features.DistinctSenders >= s.config.ExchangeMinSenders {
+		evidence = append(evidence, entity.ClassificationEvidence{
+			WalletType:  entity.WalletTypeDefi,
+			Source:      entity.ClassificationSourceContract,
+			Description: fmt.Sprintf("Contract called by %d distinct wallets", features.DistinctSenders),
+			Weight:      0.7,
+		})
+	}
+	return evidence
+}
+
+// balanceEvidence flags whales, more confidently the further above the threshold
+func (s *Service) balanceEvidence(features entity.WalletFeatures) []entity.ClassificationEvidence {
+	threshold := s.config.WhaleBalanceETH
+	if threshold <= 0 || features.BalanceETH < threshold {
+		return nil
+	}
+	// 0.5 at the threshold, rising to 0.8 at ten times it
+	weight := 0.5 + 0.3*math.Min(math.Log10(features.BalanceETH/threshold), 1)
+	return []entity.ClassificationEvidence{{
+		WalletType:  entity.WalletTypeWhale,
+		Source:      entity.ClassificationSourceBalance,
+		Description: fmt.Sprintf("Holds %.2f ETH (whale threshold %.0f ETH)", features.BalanceETH, threshold),
+		Weight:      weight,
+	}}
+}
+
+// fanInEvidence recognizes exchange deposit and hot wallets, which receive from
+// many unrelated senders
+func (s *Service) fanInEvidence(wallet *entity.Wallet, features entity.WalletFeatures) []entity.ClassificationEvidence {
+	minSenders := s.config.ExchangeMinSenders
+	if minSenders <= 0 || wallet.IsContract || features.Outgoing == 0 || features.DistinctSenders < minSenders {
+		return nil
+	}
+
+	if features.DistinctRecipients*5 <= features.DistinctSenders {
+		// Deposit wallets sweep many deposits to a few exchange wallets
+		return []entity.ClassificationEvidence{{
+			WalletType:  entity.WalletTypeExchange,
+			Source:      entity.ClassificationSourceFanIn,
+			Description: fmt.Sprintf("Receives from %d distinct senders and forwards to %d", features.DistinctSenders, features.DistinctRecipients),
+			Weight:      0.65,
+		}}
+	}
+	if features.DistinctRecipients >= minSenders {
+		// Hot wallets both take deposits and pay out withdrawals
+		return []entity.ClassificationEvidence{{
+			WalletType:  entity.WalletTypeExchange,
+			Source:      entity.ClassificationSourceFanIn,
+			Description: fmt.Sprintf("Transacts with %d distinct senders and %d distinct recipients", features.DistinctSenders, features.DistinctRecipients),
+			Weight:      0.5,
+		}}
+	}
+	return nil
+}
+
+// behaviourEvidence recognizes bots, which send transactions at a rate no person
+// does and almost all of them to contracts
+func (s *Service) behaviourEvidence(features entity.WalletFeatures) []entity.ClassificationEvidence {
+	if features.SampledTransactions < minBehaviourSample || features.Outgoing < minBehaviourSample {
+		return nil
+	}
+	if features.TransactionsPerDay < s.config.BotMinDailyTransactions || features.ContractCallRatio < 0.9 {
+		return nil
+	}
+	return []entity.ClassificationEvidence{{
+		WalletType:  entity.WalletTypeArbitrageBot,
+		Source:      entity.ClassificationSourceBehaviour,
+		Description: fmt.Sprintf("Sends %.0f transactions a day, %.0f%% of them contract calls", features.TransactionsPerDay, features.ContractCallRatio*100),
+		Weight:      0.5,
+	}}
+}
+
+// mevEvidence uses the MEV activities recorded for the wallet as the bot
+func (s *Service) mevEvidence(ctx context.Context, address string) ([]entity.ClassificationEvidence, error) {
+	activities, err := s.mevRepo.GetMEVActivities(ctx, &entity.MEVFilters{WalletAddress: &address}, maxEvidenceRecords, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var sandwiches, arbitrages int
+	for _, activity := range activities {
+		isBot := entity.NormalizeAddress(activity.BotAddress) == address ||
+			(activity.BotContract != nil && entity.NormalizeAddress(*activity.BotContract) == address)
+		if !isBot {
+			continue
+		}
+		switch activity.Type {
+		case entity.MEVTypeSandwich:
+			sandwiches++
+		case entity.MEVTypeArbitrage:
+			arbitrages++
+		}
+	}
+
+	var evidence []entity.ClassificationEvidence
+	if sandwiches > 0 {
+		evidence = append(evidence, entity.ClassificationEvidence{
+			WalletType:  entity.WalletTypeMEVBot,
+			Source:      entity.ClassificationSourceMEV,
+			Description: fmt.Sprintf("Ran %d sandwich attacks", sandwiches),
+			Weight:      repeatedWeight(0.9, sandwiches),
+		})
+	}
+	if arbitrages > 0 {
+		evidence = append(evidence, entity.ClassificationEvidence{
+			WalletType:  entity.WalletTypeArbitrageBot,
+			Source:      entity.ClassificationSourceMEV,
+			Description: fmt.Sprintf("Executed %d atomic arbitrages", arbitrages),
+			Weight:      repeatedWeight(0.85, arbitrages),
+		})
+	}
+	return evidence, nil
+}
+
+// sanctionsEvidence blacklists wallets on an active sanctions list
+func (s *Service) sanctionsEvidence(ctx context.Context, address string) ([]entity.ClassificationEvidence, error) {
+	matches, err := s.sanctionsRepo.FindSanctionedAddresses(ctx, []string{address})
+	if err != nil {
+		return nil, err
+	}
+
+	var evidence []entity.ClassificationEvidence
+	for _, match := range matches {
+		evidence = append(evidence, entity.ClassificationEvidence{
+			WalletType:  entity.WalletTypeBlacklisted,
+			Source:      entity.ClassificationSourceSanctions,
+			Description: fmt.Sprintf("Listed on the %s sanctions list", match.Source),
+			Weight:      0.99,
+		})
+	}
+	return evidence, nil
+}
+
+// alertEvidence marks wallets with active, confident, high severity alerts as suspicious
+func (s *Service) alertEvidence(ctx context.Context, address string) ([]entity.ClassificationEvidence, error) {
+	status := entity.AlertStatusActive
+	minConfidence := 70
+	result, err := s.securityRepo.GetSecurityAlerts(ctx, &entity.SecurityAlertFilters{
+		WalletAddress: &address,
+		Status:        &status,
+		MinConfidence: &minConfidence,
+	}, maxEvidenceRecords, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts int
+	for _, alert := range result.Alerts {
+		// MEV alerts describe the bot's behaviour, which mevEvidence already covers
+		if alert.IsHighPriority() && alert.Type != entity.AlertTypeMEV {
+			alerts++
+		}
+	}
+	if alerts == 0 {
+		return nil, nil
+	}
+	return []entity.ClassificationEvidence{{
+		WalletType:  entity.WalletTypeSuspicious,
+		Source:      entity.ClassificationSourceAlerts,
+		Description: fmt.Sprintf("%d active high severity security alerts", alerts),
+		Weight:      repeatedWeight(0.5, alerts),
+	}}, nil
+}
+
+// repeatedWeight combines n independent observations of weight w, capped so a
+// pattern alone never reaches certainty
+func repeatedWeight(weight float64, n int) float64 {
+	return math.Min(1-math.Pow(1-weight, float64(n)), 0.97)
+}
@@ -0,0 +1,122 @@
+package compliance
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+)
+
+// weiPerEther converts on-chain values to ETH
+var weiPerEther = new(big.Float).SetFloat64(1e18)
+
+// Analyzer produces the findings, recommendations and regulatory flags of one
+// report type. The service fills the summary and risk assessment around it.
+type Analyzer interface {
+	Type() entity.ComplianceReportType
+	Analyze(ctx context.Context, input *Input, report *entity.ComplianceReport) error
+}
+
+// Transfer is a wallet transaction normalized for analysis
+type Transfer struct {
+	Hash         string
+	Counterparty string
+	Direction    entity.TransactionDirection
+	ValueETH     float64
+	ValueUSD     float64
+	IsContract   bool
+	RiskLevel    entity.RiskLevel
+	Timestamp    time.Time
+}
+
+// Thresholds holds the monetary and timing limits analyzers apply
+type Thresholds struct {
+	CTRThresholdUSD   float64
+	StructuringWindow time.Duration
+}
+
+// Input is the data gathered once per report and shared with the analyzer
+type Input struct {
+	WalletAddress string
+	TimeRange     entity.TimeRange
+	GeneratedAt   time.Time
+
+	// Transfers are ordered oldest first
+	Transfers []Transfer
+	// Truncated is set when the wallet had more transactions than were analyzed
+	Truncated bool
+
+	Wallet    *entity.Wallet
+	RiskScore *entity.RiskScore
+
+	// SanctionedWallet is set when the wallet itself is on an active sanctions list
+	SanctionedWallet *entity.SanctionedAddress
+	// SanctionedCounterparties maps listed counterparties to their list entry
+	SanctionedCounterparties map[string]entity.SanctionedAddress
+	// Exposures are sanctioned addresses reachable through the transaction graph
+	Exposures []entity.SanctionsExposure
+
+	Thresholds Thresholds
+}
+
+// newTransfer normalizes a transaction from the perspective of wallet
+func newTransfer(wallet string, tx entity.Transaction, priceUSD float64) Transfer {
+	t := Transfer{
+		Hash:      tx.Hash,
+		ValueETH:  weiToETH(tx.Value),
+		RiskLevel: tx.RiskLevel,
+		Timestamp: tx.Timestamp,
+	}
+	t.ValueUSD = t.ValueETH * priceUSD
+
+	if strings.EqualFold(tx.From, wallet) {
+		t.Direction = entity.TransactionDirectionOutgoing
+		if tx.To != nil {
+			t.Counterparty = entity.NormalizeAddress(*tx.To)
+		} else if tx.ContractAddress != nil {
+			t.Counterparty = entity.NormalizeAddress(*tx.ContractAddress)
+		}
+	} else {
+		t.Direction = entity.TransactionDirectionIncoming
+		t.Counterparty = entity.NormalizeAddress(tx.From)
+	}
+	t.IsContract = tx.TransactionType == entity.TransactionTypeContractCall
+
+	return t
+}
+
+// weiToETH parses a decimal or 0x-prefixed wei amount. Unparseable values count as zero.
+func weiToETH(value string) float64 {
+	wei, ok := new(big.Int).SetString(strings.TrimSpace(value), 0)
+	if !ok {
+		return 0
+	}
+	eth, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), weiPerEther).Float64()
+	return eth
+}
+
+// Counterparties returns the distinct counterparties of the transfers
+func (in *Input) Counterparties() []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, t := range in.Transfers {
+		if t.Counterparty != "" && !seen[t.Counterparty] {
+			seen[t.Counterparty] = true
+			out = append(out, t.Counterparty)
+		}
+	}
+	return out
+}
+
+// TransfersWith returns the transfers exchanged with a counterparty
+func (in *Input) TransfersWith(counterparty string) []Transfer {
+	var out []Transfer
+	for _, t := range in.Transfers {
+		if t.Counterparty == counterparty {
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,263 @@
+package compliance
+
+import (
+	"context"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+)
+
+const (
+	// sarFilingPeriod is the time to file a SAR after initial detection (31 CFR 1020.320(b)(3))
+	sarFilingPeriod = 30 * 24 * time.Hour
+	// sarThresholdUSD is the suspicious amount above which a SAR is required
+	sarThresholdUSD = 5000
+	// ctrFilingPeriod is the time to file a CTR after the transaction date (31 CFR 1010.306(a)(1))
+	ctrFilingPeriod = 15 * 24 * time.Hour
+	// ofacBlockingReportBusinessDays is the time to report blocked property (31 CFR 501.603)
+	ofacBlockingReportBusinessDays = 10
+	// amlReviewPeriod is the time to complete an enhanced due diligence review
+	amlReviewPeriod = 30 * 24 * time.Hour
+)
+
+// DefaultAnalyzers returns the built-in analyzer for every supported report type
+func DefaultAnalyzers() []Analyzer {
+	return []Analyzer{
+		&AMLAnalyzer{},
+		&SARAnalyzer{},
+		&CTRAnalyzer{},
+		&OFACAnalyzer{},
+		&RiskAssessmentAnalyzer{},
+	}
+}
+
+// AMLAnalyzer screens for money laundering typologies: structuring, smurfing,
+// layering and dealings with sanctioned parties
+type AMLAnalyzer struct{}
+
+// Type returns the report type this analyzer fills
+func (a *AMLAnalyzer) Type() entity.ComplianceReportType {
+	return entity.ComplianceReportTypeAML
+}
+
+// Analyze adds AML findings and flags wallets that need enhanced due diligence
+func (a *AMLAnalyzer) Analyze(ctx context.Context, in *Input, report *entity.ComplianceReport) error {
+	report.Findings = append(report.Findings, detectStructuring(in)...)
+	report.Findings = append(report.Findings, detectSmurfing(in)...)
+	report.Findings = append(report.Findings, detectLayering(in)...)
+	report.Findings = append(report.Findings, detectSanctionedCounterparties(in)...)
+
+	if high := report.GetHighSeverityFindings(); len(high) > 0 {
+		deadline := in.GeneratedAt.Add(amlReviewPeriod)
+		report.RegulatoryFlags = append(report.RegulatoryFlags, entity.RegulatoryFlag{
+			Type:           entity.RegulatoryFlagTypeAML,
+			Jurisdiction:   "US",
+			Regulation:     "Bank Secrecy Act AML program (31 U.S.C. 5318(h))",
+			Description:    "High-severity money laundering indicators require enhanced due diligence",
+			Severity:       maxSeverity(high),
+			RequiredAction: "Complete enhanced due diligence and decide whether to file a SAR",
+			Deadline:       &deadline,
+			Status:         entity.RegulatoryFlagStatusActive,
+			Metadata:       map[string]interface{}{"finding_count": len(high)},
+		})
+	}
+
+	report.Recommendations = append(report.Recommendations,
+		"Verify the source of funds for transfers referenced in the findings",
+		"Continue transaction monitoring for the typologies identified")
+	if len(report.Findings) == 0 {
+		report.Recommendations = append(report.Recommendations,
+			"No laundering typologies detected; maintain standard monitoring")
+	}
+
+	return nil
+}
+
+// SARAnalyzer decides whether the activity warrants a Suspicious Activity Report
+type SARAnalyzer struct{}
+
+// Type returns the report type this analyzer fills
+func (a *SARAnalyzer) Type() entity.ComplianceReportType {
+	return entity.ComplianceReportTypeSAR
+}
+
+// Analyze adds the suspicious activity findings and a SAR filing flag when required
+func (a *SARAnalyzer) Analyze(ctx context.Context, in *Input, report *entity.ComplianceReport) error {
+	report.Findings = append(report.Findings, detectStructuring(in)...)
+	report.Findings = append(report.Findings, detectSmurfing(in)...)
+	report.Findings = append(report.Findings, detectLayering(in)...)
+	report.Findings = append(report.Findings, detectSanctionedCounterparties(in)...)
+	report.Findings = append(report.Findings, detectIndirectExposure(in)...)
+
+	suspicious := suspiciousAmountUSD(in, report.Findings)
+	high := report.GetHighSeverityFindings()
+
+	if len(high) > 0 && suspicious >= sarThresholdUSD {
+		deadline := in.GeneratedAt.Add(sarFilingPeriod)
+		report.RegulatoryFlags = append(report.RegulatoryFlags, entity.RegulatoryFlag{
+			Type:           entity.RegulatoryFlagTypeSAR,
+			Jurisdiction:   "US",
+			Regulation:     "31 CFR 1020.320",
+			Description:    "Suspicious activity at or above the SAR threshold was detected",
+			Severity:       maxSeverity(high),
+			RequiredAction: "File a Suspicious Activity Report with FinCEN",
+			Deadline:       &deadline,
+			Status:         entity.RegulatoryFlagStatusActive,
+			Metadata: map[string]interface{}{
+				"suspicious_amount_usd": suspicious,
+				"finding_count":         len(high),
+			},
+		})
+		report.Recommendations = append(report.Recommendations,
+			"Prepare the SAR narrative from the findings and their evidence transactions",
+			"Do not notify the wallet owner that a SAR is being filed")
+	} else {
+		report.Recommendations = append(report.Recommendations,
+			"Activity does not currently meet SAR filing criteria; document the decision and continue monitoring")
+	}
+
+	if report.Metadata != nil {
+		report.Metadata["suspicious_amount_usd"] = suspicious
+	}
+
+	return nil
+}
+
+// CTRAnalyzer finds days on which the wallet crossed the currency transaction reporting threshold
+type CTRAnalyzer struct{}
+
+// Type returns the report type this analyzer fills
+func (a *CTRAnalyzer) Type() entity.ComplianceReportType {
+	return entity.ComplianceReportTypeCTR
+}
+
+// Analyze adds threshold findings with one CTR flag per reportable day
+func (a *CTRAnalyzer) Analyze(ctx context.Context, in *Input, report *entity.ComplianceReport) error {
+	crossings := detectThresholdCrossings(in)
+	report.Findings = append(report.Findings, crossings...)
+	report.Findings = append(report.Findings, detectStructuring(in)...)
+
+	for _, finding := range crossings {
+		day, err := time.Parse("2006-01-02", finding.Metadata["date"].(string))
+		if err != nil {
+			continue
+		}
+		deadline := day.Add(ctrFilingPeriod)
+		report.RegulatoryFlags = append(report.RegulatoryFlags, entity.RegulatoryFlag{
+			Type:           entity.RegulatoryFlagTypeCTR,
+			Jurisdiction:   "US",
+			Regulation:     "31 CFR 1010.311",
+			Description:    finding.Description,
+			Severity:       finding.Severity,
+			RequiredAction: "File a Currency Transaction Report with FinCEN",
+			Deadline:       &deadline,
+			Status:         entity.RegulatoryFlagStatusActive,
+			Metadata:       finding.Metadata,
+		})
+	}
+
+	if len(crossings) == 0 {
+		report.Recommendations = append(report.Recommendations,
+			"No reportable currency transactions in the period")
+	} else {
+		report.Recommendations = append(report.Recommendations,
+			"File a CTR for each flagged day before its deadline",
+			"Aggregate transactions by beneficial owner when the owner controls multiple wallets")
+	}
+
+	return nil
+}
+
+// OFACAnalyzer checks the wallet and its counterparties against the sanctions lists
+type OFACAnalyzer struct{}
+
+// Type returns the report type this analyzer fills
+func (a *OFACAnalyzer) Type() entity.ComplianceReportType {
+	return entity.ComplianceReportTypeOFAC
+}
+
+// Analyze adds sanctions findings and a blocking report flag for direct matches
+func (a *OFACAnalyzer) Analyze(ctx context.Context, in *Input, report *entity.ComplianceReport) error {
+	direct := detectSanctionedCounterparties(in)
+	report.Findings = append(report.Findings, direct...)
+	report.Findings = append(report.Findings, detectIndirectExposure(in)...)
+
+	if len(direct) > 0 {
+		deadline := addBusinessDays(in.GeneratedAt, ofacBlockingReportBusinessDays)
+		report.RegulatoryFlags = append(report.RegulatoryFlags, entity.RegulatoryFlag{
+			Type:           entity.RegulatoryFlagTypeOFAC,
+			Jurisdiction:   "US",
+			Regulation:     "31 CFR 501.603",
+			Description:    "Property or interests in property of a sanctioned person were involved",
+			Severity:       entity.AlertSeverityCritical,
+			RequiredAction: "Block the funds and submit a blocked property report to OFAC",
+			Deadline:       &deadline,
+			Status:         entity.RegulatoryFlagStatusActive,
+			Metadata:       map[string]interface{}{"finding_count": len(direct)},
+		})
+		report.Recommendations = append(report.Recommendations,
+			"Block all further activity with the sanctioned addresses",
+			"Retain records of the blocked transactions for five years")
+	} else {
+		report.Recommendations = append(report.Recommendations,
+			"No direct sanctions matches; re-screen when a new list version is imported")
+	}
+
+	return nil
+}
+
+// RiskAssessmentAnalyzer runs every detector to inform a full customer risk assessment
+type RiskAssessmentAnalyzer struct{}
+
+// Type returns the report type this analyzer fills
+func (a *RiskAssessmentAnalyzer) Type() entity.ComplianceReportType {
+	return entity.ComplianceReportTypeRiskAssessment
+}
+
+// Analyze adds findings from all detectors; regulatory flags are left to the dedicated reports
+func (a *RiskAssessmentAnalyzer) Analyze(ctx context.Context, in *Input, report *entity.ComplianceReport) error {
+	report.Findings = append(report.Findings, detectStructuring(in)...)
+	report.Findings = append(report.Findings, detectSmurfing(in)...)
+	report.Findings = append(report.Findings, detectLayering(in)...)
+	report.Findings = append(report.Findings, detectSanctionedCounterparties(in)...)
+	report.Findings = append(report.Findings, detectIndirectExposure(in)...)
+	report.Findings = append(report.Findings, detectThresholdCrossings(in)...)
+
+	report.Recommendations = append(report.Recommendations,
+		"Review the risk assessment at the next review date or when new findings appear")
+	if len(report.GetHighSeverityFindings()) > 0 {
+		report.Recommendations = append(report.Recommendations,
+			"Generate AML and OFAC reports to determine filing obligations")
+	}
+
+	return nil
+}
+
+// suspiciousAmountUSD sums the distinct transfers referenced by findings
+func suspiciousAmountUSD(in *Input, findings []entity.ComplianceFinding) float64 {
+	referenced := make(map[string]bool)
+	for _, f := range findings {
+		for _, hash := range f.RelatedTransactions {
+			referenced[hash] = true
+		}
+	}
+
+	var total float64
+	for _, t := range in.Transfers {
+		if referenced[t.Hash] {
+			total += t.ValueUSD
+		}
+	}
+	return total
+}
+
+// addBusinessDays adds n weekdays to t
+func addBusinessDays(t time.Time, n int) time.Time {
+	for n > 0 {
+		t = t.AddDate(0, 0, 1)
+		if t.Weekday() != time.Saturday && t.Weekday() != time.Sunday {
+			n--
+		}
+	}
+	return t
+}
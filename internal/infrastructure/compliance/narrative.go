@@ -0,0 +1,222 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+
+	"go.uber.org/zap"
+)
+
+// maxNarrativeTransactions caps the evidence transactions sent with a narrative request
+const maxNarrativeTransactions = 25
+
+// UsageMeter enforces and records users' AI token budgets
+type UsageMeter interface {
+	CheckBudget(ctx context.Context, user *entity.User) error
+	RecordUsage(ctx context.Context, user *entity.User, conversationID *uint, response *entity.AIResponse)
+}
+
+// DraftNarrative has the AI assistant draft the report's narrative from its findings
+// and evidence transactions. The draft cites findings by ID and the report is marked
+// as containing AI content. A narrative that has been edited is not redrafted.
+func (s *Service) DraftNarrative(ctx context.Context, reportID string, user *entity.User) (*entity.ComplianceReport, error) {
+	report, err := s.loadEditableReport(ctx, reportID, user)
+	if err != nil {
+		return nil, err
+	}
+	if report.Narrative != nil && report.Narrative.IsEdited() {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeInvalidTransition,
+			"Narrative has been edited",
+			"Edit the narrative instead of redrafting it so human changes are not lost")
+	}
+	if len(report.Findings) == 0 {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeInsufficientData,
+			"Report has no findings", "A narrative is drafted from the report's findings")
+	}
+	if err := s.usage.CheckBudget(ctx, user); err != nil {
+		return nil, err
+	}
+
+	response, err := s.aiRepo.DraftReportNarrative(ctx, report, s.evidenceTransactions(ctx, report))
+	if err != nil {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeAIServiceFailure, "Failed to draft narrative", err.Error())
+	}
+	s.usage.RecordUsage(ctx, user, nil, response)
+
+	draftedAt := time.Now().UTC().Truncate(time.Millisecond)
+	citations := report.NarrativeCitations(response.Answer)
+	narrative := &entity.ComplianceNarrative{
+		Text:      response.Answer,
+		Citations: citations,
+		AIDraft: &entity.ComplianceNarrativeDraft{
+			Text:       response.Answer,
+			Citations:  citations,
+			Model:      response.Model,
+			TokensUsed: response.TokensUsed,
+			DraftedBy:  user.Email,
+			DraftedAt:  draftedAt,
+		},
+		Edits: []entity.ComplianceNarrativeEdit{},
+	}
+
+	metadata := copyMetadata(report.Metadata)
+	metadata["ai_generated"] = true
+	metadata["ai_generated_sections"] = "narrative"
+	metadata["ai_model"] = response.Model
+	metadata["ai_drafted_by"] = user.Email
+	metadata["ai_drafted_at"] = draftedAt.Format(time.RFC3339)
+	metadata["narrative_human_edited"] = false
+
+	if len(citations) == 0 {
+		s.logger.Warn("AI narrative draft cites no findings", zap.String("reportID", reportID))
+	}
+	comment := fmt.Sprintf("Drafted with %s citing %d of %d findings", response.Model, len(citations), len(report.Findings))
+
+	return s.saveNarrative(ctx, report, narrative, metadata, entity.ComplianceReportActionNarrativeDrafted, user, &comment)
+}
+
+// EditNarrative records a human revision of the report's narrative. The AI draft, if
+// any, is kept unchanged alongside the revisions.
+func (s *Service) EditNarrative(ctx context.Context, reportID string, user *entity.User, text string, comment *string) (*entity.ComplianceReport, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, apperrors.NewValidationError("text", "Narrative text is required")
+	}
+
+	report, err := s.loadEditableReport(ctx, reportID, user)
+	if err != nil {
+		return nil, err
+	}
+
+	narrative := &entity.ComplianceNarrative{Edits: []entity.ComplianceNarrativeEdit{}}
+	if report.Narrative != nil {
+		narrative.AIDraft = report.Narrative.AIDraft
+		narrative.Edits = append(narrative.Edits, report.Narrative.Edits...)
+	}
+
+	editedAt := time.Now().UTC().Truncate(time.Millisecond)
+	edit := entity.ComplianceNarrativeEdit{
+		Text:      text,
+		Citations: report.NarrativeCitations(text),
+		EditedBy:  user.Email,
+		EditedAt:  editedAt,
+	}
+	narrative.Edits = append(narrative.Edits, edit)
+	narrative.Text = edit.Text
+	narrative.Citations = edit.Citations
+
+	metadata := copyMetadata(report.Metadata)
+	metadata["narrative_human_edited"] = true
+	metadata["narrative_edited_by"] = user.Email
+	metadata["narrative_edited_at"] = editedAt.Format(time.RFC3339)
+
+	return s.saveNarrative(ctx, report, narrative, metadata, entity.ComplianceReportActionNarrativeEdited, user, comment)
+}
+
+// loadEditableReport loads a report whose narrative user may change. Narratives can
+// only change while the report is with its author, not while it is being reviewed.
+func (s *Service) loadEditableReport(ctx context.Context, reportID string, user *entity.User) (*entity.ComplianceReport, error) {
+	if !user.IsAnalyst() {
+		return nil, apperrors.NewAuthError(apperrors.ErrCodeAuthPermissionDenied,
+			"Analyst role required to change a report narrative")
+	}
+
+	report, err := s.loadReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status != entity.ComplianceReportStatusDraft && report.Status != entity.ComplianceReportStatusRejected {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeInvalidTransition,
+			"Report narrative cannot be changed",
+			fmt.Sprintf("The narrative of a report in status %s cannot be changed", report.Status))
+	}
+	return report, nil
+}
+
+// saveNarrative stores the narrative and records the change in the report history,
+// restoring the previous narrative if the history entry cannot be written. The
+// narrative is only stored while the report still has the narrative it was loaded
+// with, and only restored while it still has the one stored here, so that neither
+// overwrites a change made concurrently, e.g. an edit saved during an AI draft.
+func (s *Service) saveNarrative(ctx context.Context, report *entity.ComplianceReport, narrative *entity.ComplianceNarrative, metadata map[string]interface{}, action entity.ComplianceReportAction, user *entity.User, comment *string) (*entity.ComplianceReport, error) {
+	history, err := s.securityRepo.GetComplianceReportHistory(ctx, report.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := s.securityRepo.UpdateComplianceReportNarrative(ctx, report.ID, report.Status, report.Narrative, narrative, metadata)
+	if err != nil {
+		return nil, err
+	}
+	if !applied {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeInvalidTransition,
+			"Report narrative cannot be changed",
+			"The report was changed concurrently; reload it and try again")
+	}
+
+	previousNarrative, previousMetadata := report.Narrative, report.Metadata
+	report.Narrative = narrative
+	report.Metadata = metadata
+
+	if err := s.appendEvent(ctx, report, history, action, report.Status, user, comment); err != nil {
+		reverted, revertErr := s.securityRepo.UpdateComplianceReportNarrative(ctx, report.ID, report.Status, narrative, previousNarrative, previousMetadata)
+		if revertErr != nil {
+			s.logger.Error("Failed to revert unrecorded compliance report narrative change",
+				zap.String("reportID", report.ID),
+				zap.Error(revertErr))
+		} else if !reverted {
+			s.logger.Warn("Unrecorded compliance report narrative change was superseded before it could be reverted",
+				zap.String("reportID", report.ID))
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Compliance report narrative changed",
+		zap.String("reportID", report.ID),
+		zap.String("action", string(action)),
+		zap.Strings("citations", narrative.Citations),
+		zap.String("actor", user.Email))
+
+	return report, nil
+}
+
+// evidenceTransactions loads the transactions the report's findings refer to
+func (s *Service) evidenceTransactions(ctx context.Context, report *entity.ComplianceReport) []entity.Transaction {
+	var hashes []string
+	for _, finding := range report.Findings {
+		hashes = append(hashes, finding.RelatedTransactions...)
+	}
+	hashes = dedupe(hashes)
+	if len(hashes) > maxNarrativeTransactions {
+		hashes = hashes[:maxNarrativeTransactions]
+	}
+
+	transactions := make([]entity.Transaction, 0, len(hashes))
+	for _, hash := range hashes {
+		tx, err := s.transactionRepo.GetTransaction(ctx, hash)
+		if err != nil {
+			s.logger.Warn("Failed to load narrative evidence transaction",
+				zap.String("reportID", report.ID),
+				zap.String("hash", hash),
+				zap.Error(err))
+			continue
+		}
+		if tx != nil {
+			transactions = append(transactions, *tx)
+		}
+	}
+	return transactions
+}
+
+func copyMetadata(metadata map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(metadata)+6)
+	for k, v := range metadata {
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,408 @@
+package compliance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+)
+
+const (
+	// structuringFloor is the fraction of the reporting threshold above which a
+	// transfer counts as "just below" it
+	structuringFloor = 0.8
+	// structuringMinTransfers is how many near-threshold transfers in one window count as structuring
+	structuringMinTransfers = 3
+	// smurfingMinSenders is how many distinct small senders in one window count as smurfing
+	smurfingMinSenders = 5
+	// layeringWindow is how quickly funds must leave again to count as a pass-through
+	layeringWindow = time.Hour
+	// layeringRetention is the minimum fraction of an incoming amount forwarded in a pass-through
+	layeringRetention = 0.9
+	// layeringMinHops is how many pass-throughs count as layering
+	layeringMinHops = 3
+	// maxEvidence bounds the evidence lines attached to one finding
+	maxEvidence = 25
+)
+
+// detectStructuring looks for repeated transfers just below the reporting
+// threshold inside one structuring window
+func detectStructuring(in *Input) []entity.ComplianceFinding {
+	threshold := in.Thresholds.CTRThresholdUSD
+	if threshold <= 0 {
+		return nil
+	}
+
+	var candidates []Transfer
+	for _, t := range in.Transfers {
+		if t.ValueUSD >= threshold*structuringFloor && t.ValueUSD < threshold {
+			candidates = append(candidates, t)
+		}
+	}
+
+	clusters := windowClusters(candidates, in.Thresholds.StructuringWindow, structuringMinTransfers)
+
+	findings := make([]entity.ComplianceFinding, 0, len(clusters))
+	for _, cluster := range clusters {
+		total := sumUSD(cluster)
+		severity := entity.AlertSeverityMedium
+		if total >= threshold {
+			severity = entity.AlertSeverityHigh
+		}
+		reference := "31 U.S.C. 5324"
+		findings = append(findings, entity.ComplianceFinding{
+			Type:     entity.ComplianceFindingTypeStructuring,
+			Severity: severity,
+			Title:    "Transfers structured below the reporting threshold",
+			Description: fmt.Sprintf("%d transfers between %s and %s each fell just below the %s reporting threshold, totalling %s.",
+				len(cluster), formatTime(cluster[0].Timestamp), formatTime(cluster[len(cluster)-1].Timestamp),
+				formatUSD(threshold), formatUSD(total)),
+			Evidence:            evidence(cluster),
+			RelatedTransactions: hashes(cluster),
+			RegulatoryReference: &reference,
+			Recommendation:      "Review the source of funds and whether the transfers were split to avoid currency transaction reporting",
+			Metadata: map[string]interface{}{
+				"total_usd":      total,
+				"transfer_count": len(cluster),
+			},
+		})
+	}
+
+	return findings
+}
+
+// detectSmurfing looks for many distinct senders depositing small amounts that
+// together exceed the reporting threshold within one window
+func detectSmurfing(in *Input) []entity.ComplianceFinding {
+	threshold := in.Thresholds.CTRThresholdUSD
+	if threshold <= 0 {
+		return nil
+	}
+
+	var incoming []Transfer
+	for _, t := range in.Transfers {
+		if t.Direction == entity.TransactionDirectionIncoming && t.ValueUSD > 0 && t.ValueUSD < threshold*structuringFloor {
+			incoming = append(incoming, t)
+		}
+	}
+
+	var findings []entity.ComplianceFinding
+	for _, cluster := range windowClusters(incoming, in.Thresholds.StructuringWindow, smurfingMinSenders) {
+		senders := distinctCounterparties(cluster)
+		total := sumUSD(cluster)
+		if len(senders) < smurfingMinSenders || total < threshold {
+			continue
+		}
+		reference := "31 U.S.C. 5324"
+		findings = append(findings, entity.ComplianceFinding{
+			Type:     entity.ComplianceFindingTypeSmurfing,
+			Severity: entity.AlertSeverityHigh,
+			Title:    "Deposits aggregated from many small senders",
+			Description: fmt.Sprintf("%d distinct senders deposited %s in %d transfers between %s and %s.",
+				len(senders), formatUSD(total), len(cluster),
+				formatTime(cluster[0].Timestamp), formatTime(cluster[len(cluster)-1].Timestamp)),
+			Evidence:            evidence(cluster),
+			RelatedTransactions: hashes(cluster),
+			RegulatoryReference: &reference,
+			Recommendation:      "Identify the depositing wallets and whether they are controlled by a common party",
+			Metadata: map[string]interface{}{
+				"total_usd":    total,
+				"sender_count": len(senders),
+			},
+		})
+	}
+
+	return findings
+}
+
+// detectLayering looks for funds that are forwarded almost in full shortly after arriving
+func detectLayering(in *Input) []entity.ComplianceFinding {
+	var pairs []Transfer
+	used := make(map[string]bool)
+
+	for i, incoming := range in.Transfers {
+		if incoming.Direction != entity.TransactionDirectionIncoming || incoming.ValueETH <= 0 {
+			continue
+		}
+		for _, outgoing := range in.Transfers[i+1:] {
+			if outgoing.Timestamp.Sub(incoming.Timestamp) > layeringWindow {
+				break
+			}
+			if outgoing.Direction != entity.TransactionDirectionOutgoing || used[outgoing.Hash] {
+				continue
+			}
+			if outgoing.Counterparty == incoming.Counterparty {
+				continue
+			}
+			if outgoing.ValueETH >= incoming.ValueETH*layeringRetention && outgoing.ValueETH <= incoming.ValueETH {
+				used[outgoing.Hash] = true
+				pairs = append(pairs, incoming, outgoing)
+				break
+			}
+		}
+	}
+
+	hops := len(pairs) / 2
+	if hops < layeringMinHops {
+		return nil
+	}
+
+	severity := entity.AlertSeverityMedium
+	if hops >= 2*layeringMinHops {
+		severity = entity.AlertSeverityHigh
+	}
+
+	return []entity.ComplianceFinding{{
+		Type:     entity.ComplianceFindingTypeLayering,
+		Severity: severity,
+		Title:    "Funds passed through the wallet",
+		Description: fmt.Sprintf("On %d occasions at least %.0f%% of an incoming transfer was forwarded to a different wallet within %s.",
+			hops, layeringRetention*100, layeringWindow),
+		Evidence:            evidence(pairs),
+		RelatedTransactions: hashes(pairs),
+		Recommendation:      "Trace the forwarded funds to their destination and assess whether the wallet is used as an intermediary",
+		Metadata: map[string]interface{}{
+			"pass_through_count": hops,
+		},
+	}}
+}
+
+// detectSanctionedCounterparties reports transfers with addresses on a sanctions list
+func detectSanctionedCounterparties(in *Input) []entity.ComplianceFinding {
+	var findings []entity.ComplianceFinding
+
+	if in.SanctionedWallet != nil {
+		reference := strings.Join(in.SanctionedWallet.Programs, ", ")
+		findings = append(findings, entity.ComplianceFinding{
+			Type:     entity.ComplianceFindingTypeSanctionsViolation,
+			Severity: entity.AlertSeverityCritical,
+			Title:    "Wallet is on a sanctions list",
+			Description: fmt.Sprintf("The wallet is listed by %s as belonging to %s.",
+				in.SanctionedWallet.Source, entityName(*in.SanctionedWallet)),
+			Evidence:            []string{fmt.Sprintf("List version %s", in.SanctionedWallet.ListVersion)},
+			RelatedTransactions: hashes(in.Transfers),
+			RegulatoryReference: optional(reference),
+			Recommendation:      "Block all activity with this wallet and file a blocked property report",
+			Metadata: map[string]interface{}{
+				"programs":     in.SanctionedWallet.Programs,
+				"list_version": in.SanctionedWallet.ListVersion,
+			},
+		})
+	}
+
+	counterparties := make([]string, 0, len(in.SanctionedCounterparties))
+	for address := range in.SanctionedCounterparties {
+		counterparties = append(counterparties, address)
+	}
+	sort.Strings(counterparties)
+
+	for _, address := range counterparties {
+		entry := in.SanctionedCounterparties[address]
+		transfers := in.TransfersWith(address)
+		if len(transfers) == 0 {
+			continue
+		}
+		reference := strings.Join(entry.Programs, ", ")
+		findings = append(findings, entity.ComplianceFinding{
+			Type:     entity.ComplianceFindingTypeSanctionsViolation,
+			Severity: entity.AlertSeverityCritical,
+			Title:    "Transactions with a sanctioned address",
+			Description: fmt.Sprintf("%d transfers totalling %s were exchanged with %s, listed by %s as belonging to %s.",
+				len(transfers), formatUSD(sumUSD(transfers)), address, entry.Source, entityName(entry)),
+			Evidence:            evidence(transfers),
+			RelatedTransactions: hashes(transfers),
+			RegulatoryReference: optional(reference),
+			Recommendation:      "Freeze related funds and escalate to the sanctions compliance officer",
+			Metadata: map[string]interface{}{
+				"sanctioned_address": address,
+				"programs":           entry.Programs,
+				"list_version":       entry.ListVersion,
+			},
+		})
+	}
+
+	return findings
+}
+
+// detectIndirectExposure reports sanctioned addresses reachable through intermediaries
+func detectIndirectExposure(in *Input) []entity.ComplianceFinding {
+	var indirect []entity.SanctionsExposure
+	for _, e := range in.Exposures {
+		if e.Hops > 1 {
+			indirect = append(indirect, e)
+		}
+	}
+	if len(indirect) == 0 {
+		return nil
+	}
+
+	sort.Slice(indirect, func(i, j int) bool { return indirect[i].Hops < indirect[j].Hops })
+
+	severity := entity.AlertSeverityMedium
+	if indirect[0].Hops == 2 {
+		severity = entity.AlertSeverityHigh
+	}
+
+	lines := make([]string, 0, len(indirect))
+	for _, e := range indirect {
+		if len(lines) == maxEvidence {
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s at %d hops", e.SanctionedAddress, e.Hops))
+	}
+
+	return []entity.ComplianceFinding{{
+		Type:     entity.ComplianceFindingTypeSanctionsViolation,
+		Severity: severity,
+		Title:    "Indirect exposure to sanctioned addresses",
+		Description: fmt.Sprintf("%d sanctioned addresses are reachable through the transaction graph; the closest is %d hops away.",
+			len(indirect), indirect[0].Hops),
+		Evidence:            lines,
+		RelatedTransactions: []string{},
+		Recommendation:      "Review the intermediary wallets connecting this wallet to sanctioned addresses",
+		Metadata: map[string]interface{}{
+			"exposure_count": len(indirect),
+			"closest_hops":   indirect[0].Hops,
+		},
+	}}
+}
+
+// detectThresholdCrossings reports days on which the wallet moved more than the
+// reporting threshold in one direction
+func detectThresholdCrossings(in *Input) []entity.ComplianceFinding {
+	threshold := in.Thresholds.CTRThresholdUSD
+	if threshold <= 0 {
+		return nil
+	}
+
+	type dayKey struct {
+		day       string
+		direction entity.TransactionDirection
+	}
+	days := make(map[dayKey][]Transfer)
+	var order []dayKey
+	for _, t := range in.Transfers {
+		key := dayKey{t.Timestamp.UTC().Format("2006-01-02"), t.Direction}
+		if _, ok := days[key]; !ok {
+			order = append(order, key)
+		}
+		days[key] = append(days[key], t)
+	}
+
+	var findings []entity.ComplianceFinding
+	for _, key := range order {
+		transfers := days[key]
+		total := sumUSD(transfers)
+		if total <= threshold {
+			continue
+		}
+		reference := "31 CFR 1010.311"
+		findings = append(findings, entity.ComplianceFinding{
+			Type:     entity.ComplianceFindingTypeThresholdViolation,
+			Severity: entity.AlertSeverityMedium,
+			Title:    "Daily volume above the currency transaction reporting threshold",
+			Description: fmt.Sprintf("%s %s on %s across %d transfers exceeded the %s threshold.",
+				strings.ToLower(string(key.direction)), formatUSD(total), key.day, len(transfers), formatUSD(threshold)),
+			Evidence:            evidence(transfers),
+			RelatedTransactions: hashes(transfers),
+			RegulatoryReference: &reference,
+			Recommendation:      "File a currency transaction report for the aggregated daily amount",
+			Metadata: map[string]interface{}{
+				"date":      key.day,
+				"direction": string(key.direction),
+				"total_usd": total,
+			},
+		})
+	}
+
+	return findings
+}
+
+// windowClusters groups transfers (oldest first) into non-overlapping runs that
+// fit in window and contain at least minSize transfers
+func windowClusters(transfers []Transfer, window time.Duration, minSize int) [][]Transfer {
+	if window <= 0 {
+		window = 24 * time.Hour
+	}
+
+	var clusters [][]Transfer
+	for start := 0; start < len(transfers); {
+		end := start
+		for end+1 < len(transfers) && transfers[end+1].Timestamp.Sub(transfers[start].Timestamp) <= window {
+			end++
+		}
+		if end-start+1 >= minSize {
+			clusters = append(clusters, transfers[start:end+1])
+			start = end + 1
+			continue
+		}
+		start++
+	}
+	return clusters
+}
+
+func distinctCounterparties(transfers []Transfer) map[string]bool {
+	out := make(map[string]bool)
+	for _, t := range transfers {
+		out[t.Counterparty] = true
+	}
+	return out
+}
+
+func sumUSD(transfers []Transfer) float64 {
+	var total float64
+	for _, t := range transfers {
+		total += t.ValueUSD
+	}
+	return total
+}
+
+func hashes(transfers []Transfer) []string {
+	out := make([]string, 0, len(transfers))
+	for _, t := range transfers {
+		out = append(out, t.Hash)
+	}
+	return out
+}
+
+// evidence describes each transfer on one line, bounded by maxEvidence
+func evidence(transfers []Transfer) []string {
+	out := make([]string, 0, len(transfers))
+	for i, t := range transfers {
+		if i == maxEvidence {
+			out = append(out, fmt.Sprintf("... and %d more transfers", len(transfers)-maxEvidence))
+			break
+		}
+		preposition := "to"
+		if t.Direction == entity.TransactionDirectionIncoming {
+			preposition = "from"
+		}
+		out = append(out, fmt.Sprintf("%s: %.6f ETH (%s) %s %s at %s",
+			t.Hash, t.ValueETH, formatUSD(t.ValueUSD), preposition, t.Counterparty, formatTime(t.Timestamp)))
+	}
+	return out
+}
+
+func entityName(entry entity.SanctionedAddress) string {
+	if entry.EntityName != "" {
+		return entry.EntityName
+	}
+	return "an unnamed entity"
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func formatUSD(v float64) string {
+	return fmt.Sprintf("$%.2f", v)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
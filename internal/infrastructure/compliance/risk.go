@@ -0,0 +1,265 @@
+package compliance
+
+import (
+	"fmt"
+	"strings"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+)
+
+// jurisdictionPrograms are sanctions programs tied to a comprehensively sanctioned jurisdiction
+var jurisdictionPrograms = []string{"IRAN", "DPRK", "SYRIA", "CUBA", "RUSSIA", "VENEZUELA", "BELARUS", "UKRAINE-EO13685"}
+
+var riskLevelRank = map[entity.RiskLevel]int{
+	entity.RiskLevelUnknown:  0,
+	entity.RiskLevelLow:      1,
+	entity.RiskLevelMedium:   2,
+	entity.RiskLevelHigh:     3,
+	entity.RiskLevelCritical: 4,
+}
+
+var severityRank = map[entity.AlertSeverity]int{
+	entity.AlertSeverityLow:      1,
+	entity.AlertSeverityMedium:   2,
+	entity.AlertSeverityHigh:     3,
+	entity.AlertSeverityCritical: 4,
+}
+
+// severityPenalty is deducted from the compliance score for each finding
+var severityPenalty = map[entity.AlertSeverity]float64{
+	entity.AlertSeverityLow:      2,
+	entity.AlertSeverityMedium:   5,
+	entity.AlertSeverityHigh:     15,
+	entity.AlertSeverityCritical: 30,
+}
+
+// riskLevelScore maps an overall risk level onto the 0-100 risk score scale
+var riskLevelScore = map[entity.RiskLevel]float64{
+	entity.RiskLevelUnknown:  0,
+	entity.RiskLevelLow:      15,
+	entity.RiskLevelMedium:   45,
+	entity.RiskLevelHigh:     75,
+	entity.RiskLevelCritical: 95,
+}
+
+// assessRisk rates the wallet on each risk dimension from the input and the report findings
+func assessRisk(in *Input, findings []entity.ComplianceFinding) entity.ComplianceRiskAssessment {
+	a := entity.ComplianceRiskAssessment{
+		RiskFactors:        []string{},
+		MitigatingFactors:  []string{},
+		RecommendedActions: []string{},
+	}
+
+	a.GeographicRisk = geographicRisk(in, &a)
+	a.TransactionRisk = transactionRisk(in, findings, &a)
+	a.CounterpartyRisk = counterpartyRisk(in, &a)
+	a.ProductRisk = productRisk(in, &a)
+
+	a.OverallRisk = maxRiskLevel(a.GeographicRisk, a.TransactionRisk, a.CounterpartyRisk, a.ProductRisk)
+	if in.RiskScore != nil {
+		a.OverallRisk = maxRiskLevel(a.OverallRisk, in.RiskScore.RiskLevel)
+		if in.RiskScore.RiskLevel == entity.RiskLevelHigh || in.RiskScore.RiskLevel == entity.RiskLevelCritical {
+			a.RiskFactors = append(a.RiskFactors, fmt.Sprintf("Wallet risk score is %d (%s)", in.RiskScore.TotalScore, in.RiskScore.RiskLevel))
+		}
+	}
+
+	switch a.OverallRisk {
+	case entity.RiskLevelCritical:
+		a.RecommendedActions = append(a.RecommendedActions,
+			"Suspend the relationship pending senior compliance review",
+			"Escalate to the BSA/AML officer")
+		a.NextReviewDate = in.GeneratedAt.AddDate(0, 1, 0)
+	case entity.RiskLevelHigh:
+		a.RecommendedActions = append(a.RecommendedActions,
+			"Apply enhanced due diligence",
+			"Increase transaction monitoring frequency")
+		a.NextReviewDate = in.GeneratedAt.AddDate(0, 3, 0)
+	case entity.RiskLevelMedium:
+		a.RecommendedActions = append(a.RecommendedActions,
+			"Review counterparties and source of funds")
+		a.NextReviewDate = in.GeneratedAt.AddDate(0, 6, 0)
+	default:
+		a.RecommendedActions = append(a.RecommendedActions,
+			"Maintain standard monitoring")
+		a.NextReviewDate = in.GeneratedAt.AddDate(1, 0, 0)
+	}
+
+	return a
+}
+
+// geographicRisk is driven by sanctions programs tied to a jurisdiction
+func geographicRisk(in *Input, a *entity.ComplianceRiskAssessment) entity.RiskLevel {
+	programs := make(map[string]bool)
+	if in.SanctionedWallet != nil {
+		for _, p := range in.SanctionedWallet.Programs {
+			programs[p] = true
+		}
+	}
+	for _, entry := range in.SanctionedCounterparties {
+		for _, p := range entry.Programs {
+			programs[p] = true
+		}
+	}
+
+	var jurisdictions []string
+	for p := range programs {
+		for _, j := range jurisdictionPrograms {
+			if strings.HasPrefix(strings.ToUpper(p), j) {
+				jurisdictions = append(jurisdictions, p)
+				break
+			}
+		}
+	}
+
+	switch {
+	case len(jurisdictions) > 0:
+		a.RiskFactors = append(a.RiskFactors, fmt.Sprintf("Activity linked to sanctioned jurisdiction programs: %s", strings.Join(jurisdictions, ", ")))
+		return entity.RiskLevelCritical
+	case len(programs) > 0:
+		return entity.RiskLevelHigh
+	case len(in.Exposures) > 0:
+		return entity.RiskLevelMedium
+	default:
+		a.MitigatingFactors = append(a.MitigatingFactors, "No links to sanctioned jurisdictions")
+		return entity.RiskLevelLow
+	}
+}
+
+// transactionRisk is driven by the typology and threshold findings
+func transactionRisk(in *Input, findings []entity.ComplianceFinding, a *entity.ComplianceRiskAssessment) entity.RiskLevel {
+	level := entity.RiskLevelLow
+	for _, f := range findings {
+		switch f.Type {
+		case entity.ComplianceFindingTypeStructuring, entity.ComplianceFindingTypeSmurfing,
+			entity.ComplianceFindingTypeLayering, entity.ComplianceFindingTypeThresholdViolation,
+			entity.ComplianceFindingTypeUnusualPattern:
+			level = maxRiskLevel(level, severityToRiskLevel(f.Severity))
+			a.RiskFactors = append(a.RiskFactors, f.Title)
+		}
+	}
+
+	if len(in.Transfers) == 0 {
+		a.MitigatingFactors = append(a.MitigatingFactors, "No transactions in the reporting period")
+	} else if level == entity.RiskLevelLow {
+		a.MitigatingFactors = append(a.MitigatingFactors, "No structuring, smurfing or layering patterns detected")
+	}
+	if in.Truncated {
+		a.RiskFactors = append(a.RiskFactors, "Transaction history exceeded the analysis limit; older activity was not analyzed")
+		level = maxRiskLevel(level, entity.RiskLevelMedium)
+	}
+
+	return level
+}
+
+// counterpartyRisk is driven by sanctioned and exposed counterparties
+func counterpartyRisk(in *Input, a *entity.ComplianceRiskAssessment) entity.RiskLevel {
+	if in.SanctionedWallet != nil || len(in.SanctionedCounterparties) > 0 {
+		a.RiskFactors = append(a.RiskFactors, fmt.Sprintf("%d sanctioned counterparties", len(in.SanctionedCounterparties)))
+		return entity.RiskLevelCritical
+	}
+
+	closest := 0
+	for _, e := range in.Exposures {
+		if closest == 0 || e.Hops < closest {
+			closest = e.Hops
+		}
+	}
+
+	switch {
+	case closest == 2:
+		a.RiskFactors = append(a.RiskFactors, "Sanctioned address within two hops")
+		return entity.RiskLevelHigh
+	case closest > 2:
+		a.RiskFactors = append(a.RiskFactors, fmt.Sprintf("Sanctioned address within %d hops", closest))
+		return entity.RiskLevelMedium
+	default:
+		a.MitigatingFactors = append(a.MitigatingFactors, "No sanctioned counterparties or exposure")
+		return entity.RiskLevelLow
+	}
+}
+
+// productRisk is driven by the kind of wallet and how it is used
+func productRisk(in *Input, a *entity.ComplianceRiskAssessment) entity.RiskLevel {
+	level := entity.RiskLevelLow
+
+	if in.Wallet != nil {
+		switch in.Wallet.WalletType {
+		case entity.WalletTypeSuspicious, entity.WalletTypeBlacklisted:
+			a.RiskFactors = append(a.RiskFactors, fmt.Sprintf("Wallet classified as %s", in.Wallet.WalletType))
+			level = entity.RiskLevelHigh
+		case entity.WalletTypeMEVBot, entity.WalletTypeArbitrageBot, entity.WalletTypeBridge, entity.WalletTypeDefi:
+			a.RiskFactors = append(a.RiskFactors, fmt.Sprintf("Wallet classified as %s", in.Wallet.WalletType))
+			level = entity.RiskLevelMedium
+		case entity.WalletTypeExchange:
+			a.MitigatingFactors = append(a.MitigatingFactors, "Wallet belongs to a known exchange")
+		}
+	}
+
+	contractCalls := 0
+	for _, t := range in.Transfers {
+		if t.IsContract {
+			contractCalls++
+		}
+	}
+	if len(in.Transfers) > 0 && contractCalls*2 > len(in.Transfers) {
+		a.RiskFactors = append(a.RiskFactors, "Majority of activity is contract interaction")
+		level = maxRiskLevel(level, entity.RiskLevelMedium)
+	}
+
+	return level
+}
+
+// finalizeSummary derives the summary counters and scores from the completed report
+func finalizeSummary(report *entity.ComplianceReport) {
+	summary := &report.Summary
+
+	summary.SuspiciousPatterns = 0
+	summary.ComplianceScore = 100
+	for _, f := range report.Findings {
+		switch f.Type {
+		case entity.ComplianceFindingTypeThresholdViolation, entity.ComplianceFindingTypeSanctionsViolation:
+		default:
+			summary.SuspiciousPatterns++
+		}
+		summary.ComplianceScore -= severityPenalty[f.Severity]
+	}
+	if summary.ComplianceScore < 0 {
+		summary.ComplianceScore = 0
+	}
+
+	summary.RegulatoryViolations = int64(len(report.GetActiveRegulatoryFlags()))
+	summary.OverallRiskScore = riskLevelScore[report.RiskAssessment.OverallRisk]
+}
+
+func severityToRiskLevel(s entity.AlertSeverity) entity.RiskLevel {
+	switch s {
+	case entity.AlertSeverityCritical:
+		return entity.RiskLevelCritical
+	case entity.AlertSeverityHigh:
+		return entity.RiskLevelHigh
+	case entity.AlertSeverityMedium:
+		return entity.RiskLevelMedium
+	default:
+		return entity.RiskLevelLow
+	}
+}
+
+func maxRiskLevel(levels ...entity.RiskLevel) entity.RiskLevel {
+	max := entity.RiskLevelLow
+	for _, l := range levels {
+		if riskLevelRank[l] > riskLevelRank[max] {
+			max = l
+		}
+	}
+	return max
+}
+
+func maxSeverity(findings []entity.ComplianceFinding) entity.AlertSeverity {
+	max := entity.AlertSeverityLow
+	for _, f := range findings {
+		if severityRank[f.Severity] > severityRank[max] {
+			max = f.Severity
+		}
+	}
+	return max
+}
@@ -0,0 +1,271 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/config"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// Service generates compliance reports by running the analyzer registered for
+// the requested report type over a wallet's activity
+type Service struct {
+	transactionRepo repository.TransactionRepository
+	walletRepo      repository.WalletRepository
+	securityRepo    repository.SecurityRepository
+	sanctionsRepo   repository.SanctionsRepository
+	aiRepo          repository.AIRepository
+	usage           UsageMeter
+	config          *config.ComplianceConfig
+	logger          *zap.Logger
+
+	analyzers map[entity.ComplianceReportType]Analyzer
+}
+
+// NewService creates a new compliance report service with the default analyzers registered
+func NewService(
+	transactionRepo repository.TransactionRepository,
+	walletRepo repository.WalletRepository,
+	securityRepo repository.SecurityRepository,
+	sanctionsRepo repository.SanctionsRepository,
+	aiRepo repository.AIRepository,
+	usage UsageMeter,
+	cfg *config.ComplianceConfig,
+	logger *zap.Logger,
+) *Service {
+	s := &Service{
+		transactionRepo: transactionRepo,
+		walletRepo:      walletRepo,
+		securityRepo:    securityRepo,
+		sanctionsRepo:   sanctionsRepo,
+		aiRepo:          aiRepo,
+		usage:           usage,
+		config:          cfg,
+		logger:          logger,
+		analyzers:       make(map[entity.ComplianceReportType]Analyzer),
+	}
+
+	for _, analyzer := range DefaultAnalyzers() {
+		s.Register(analyzer)
+	}
+
+	return s
+}
+
+// Register installs an analyzer, replacing any existing analyzer for its report type
+func (s *Service) Register(analyzer Analyzer) {
+	s.analyzers[analyzer.Type()] = analyzer
+}
+
+// SupportedReportTypes returns the report types that have an analyzer
+func (s *Service) SupportedReportTypes() []entity.ComplianceReportType {
+	types := make([]entity.ComplianceReportType, 0, len(s.analyzers))
+	for t := range s.analyzers {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// GenerateReport analyzes a wallet's activity in timeRange and stores the result as a
+// DRAFT report, opening its history with a GENERATED entry
+func (s *Service) GenerateReport(ctx context.Context, walletAddress string, reportType entity.ComplianceReportType, timeRange entity.TimeRange, user *entity.User) (*entity.ComplianceReport, error) {
+	analyzer, ok := s.analyzers[reportType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported compliance report type: %s", reportType)
+	}
+	if timeRange.End.IsZero() {
+		timeRange.End = time.Now()
+	}
+	if !timeRange.Start.Before(timeRange.End) {
+		return nil, fmt.Errorf("invalid time range: start must be before end")
+	}
+
+	input, err := s.buildInput(ctx, entity.NormalizeAddress(walletAddress), timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entity.ComplianceReport{
+		ID:              primitive.NewObjectID().Hex(),
+		WalletAddress:   input.WalletAddress,
+		ReportType:      reportType,
+		GeneratedAt:     input.GeneratedAt,
+		GeneratedBy:     user.Email,
+		TimeRange:       timeRange,
+		Status:          entity.ComplianceReportStatusDraft,
+		Summary:         summarize(input),
+		Findings:        []entity.ComplianceFinding{},
+		Recommendations: []string{},
+		RegulatoryFlags: []entity.RegulatoryFlag{},
+		Metadata: map[string]interface{}{
+			"analyzed_transactions": len(input.Transfers),
+			"truncated":             input.Truncated,
+			"reference_price_usd":   s.config.ReferencePriceUSD,
+		},
+	}
+
+	if err := analyzer.Analyze(ctx, input, report); err != nil {
+		return nil, fmt.Errorf("failed to analyze %s report: %w", reportType, err)
+	}
+
+	for i := range report.Findings {
+		report.Findings[i].ID = fmt.Sprintf("F-%03d", i+1)
+	}
+	report.Recommendations = dedupe(report.Recommendations)
+	report.RiskAssessment = assessRisk(input, report.Findings)
+	finalizeSummary(report)
+
+	if err := s.securityRepo.CreateComplianceReport(ctx, report); err != nil {
+		return nil, fmt.Errorf("failed to save compliance report: %w", err)
+	}
+
+	if err := s.appendEvent(ctx, report, nil, entity.ComplianceReportActionGenerated, "", user, nil); err != nil {
+		return nil, fmt.Errorf("failed to record compliance report history: %w", err)
+	}
+
+	s.logger.Info("Generated compliance report",
+		zap.String("reportID", report.ID),
+		zap.String("walletAddress", report.WalletAddress),
+		zap.String("reportType", string(reportType)),
+		zap.Int("findings", len(report.Findings)),
+		zap.Int("regulatoryFlags", len(report.RegulatoryFlags)))
+
+	return report, nil
+}
+
+// Report returns a compliance report
+func (s *Service) Report(ctx context.Context, reportID string) (*entity.ComplianceReport, error) {
+	return s.loadReport(ctx, reportID)
+}
+
+// RegenerateReport generates a new DRAFT report for the wallet, type and time
+// range of an existing report, e.g. after source data or analyzers changed.
+// The existing report and its history are left as they are.
+func (s *Service) RegenerateReport(ctx context.Context, reportID string, user *entity.User) (*entity.ComplianceReport, error) {
+	original, err := s.loadReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.GenerateReport(ctx, original.WalletAddress, original.ReportType, original.TimeRange, user)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Regenerated compliance report",
+		zap.String("reportID", report.ID),
+		zap.String("originalReportID", original.ID),
+		zap.String("actor", user.Email))
+	return report, nil
+}
+
+// buildInput loads the wallet's transactions, profile and sanctions context
+func (s *Service) buildInput(ctx context.Context, walletAddress string, timeRange entity.TimeRange) (*Input, error) {
+	input := &Input{
+		WalletAddress:            walletAddress,
+		TimeRange:                timeRange,
+		GeneratedAt:              time.Now(),
+		SanctionedCounterparties: make(map[string]entity.SanctionedAddress),
+		Thresholds: Thresholds{
+			CTRThresholdUSD:   s.config.CTRThresholdUSD,
+			StructuringWindow: s.config.StructuringWindow,
+		},
+	}
+
+	limit := s.config.MaxReportTransactions
+	if limit <= 0 {
+		limit = 10000
+	}
+	transactions, err := s.transactionRepo.GetTransactionsInRange(ctx, walletAddress, timeRange, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load transactions: %w", err)
+	}
+	input.Truncated = int64(len(transactions)) >= limit
+
+	input.Transfers = make([]Transfer, 0, len(transactions))
+	for _, tx := range transactions {
+		input.Transfers = append(input.Transfers, newTransfer(walletAddress, tx, s.config.ReferencePriceUSD))
+	}
+	sort.SliceStable(input.Transfers, func(i, j int) bool {
+		return input.Transfers[i].Timestamp.Before(input.Transfers[j].Timestamp)
+	})
+
+	// The wallet profile and risk score enrich the assessment but are not required
+	if wallet, err := s.walletRepo.GetWallet(ctx, walletAddress); err != nil {
+		s.logger.Warn("Failed to load wallet for compliance report", zap.String("walletAddress", walletAddress), zap.Error(err))
+	} else {
+		input.Wallet = wallet
+	}
+	if score, err := s.walletRepo.GetRiskScore(ctx, walletAddress); err != nil {
+		s.logger.Warn("Failed to load risk score for compliance report", zap.String("walletAddress", walletAddress), zap.Error(err))
+	} else {
+		input.RiskScore = score
+	}
+
+	addresses := append([]string{walletAddress}, input.Counterparties()...)
+	listed, err := s.sanctionsRepo.FindSanctionedAddresses(ctx, addresses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to screen counterparties: %w", err)
+	}
+	for _, entry := range listed {
+		if entry.Address == walletAddress {
+			match := entry
+			input.SanctionedWallet = &match
+			continue
+		}
+		input.SanctionedCounterparties[entry.Address] = entry
+	}
+
+	exposures, err := s.sanctionsRepo.FindExposures(ctx, walletAddress, s.config.ScreeningHops)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sanctions exposure: %w", err)
+	}
+	input.Exposures = exposures
+
+	return input, nil
+}
+
+// summarize computes the activity totals common to every report type
+func summarize(in *Input) entity.ComplianceSummary {
+	summary := entity.ComplianceSummary{
+		TotalTransactions: int64(len(in.Transfers)),
+		ComplianceScore:   100,
+	}
+
+	var volumeETH float64
+	for _, t := range in.Transfers {
+		volumeETH += t.ValueETH
+		summary.TotalVolumeUSD += t.ValueUSD
+
+		_, sanctioned := in.SanctionedCounterparties[t.Counterparty]
+		if sanctioned || t.RiskLevel == entity.RiskLevelHigh || t.RiskLevel == entity.RiskLevelCritical {
+			summary.HighRiskTransactions++
+		}
+	}
+	if in.SanctionedWallet != nil {
+		summary.HighRiskTransactions = summary.TotalTransactions
+	}
+	summary.TotalVolume = fmt.Sprintf("%.6f", volumeETH)
+
+	return summary
+}
+
+func dedupe(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
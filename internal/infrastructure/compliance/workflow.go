@@ -0,0 +1,274 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+
+	"go.uber.org/zap"
+)
+
+// transition describes one allowed move in the report workflow
+type transition struct {
+	from      []entity.ComplianceReportStatus
+	to        entity.ComplianceReportStatus
+	authorize func(*entity.User) bool
+	role      string
+	fourEyes  bool
+}
+
+// transitions is the report workflow: analysts submit drafts (or rejected reports
+// after rework); compliance officers (moderators and admins) approve, reject and
+// record filing. Approval must come from someone other than the generator and submitter.
+var transitions = map[entity.ComplianceReportAction]transition{
+	entity.ComplianceReportActionSubmitted: {
+		from:      []entity.ComplianceReportStatus{entity.ComplianceReportStatusDraft, entity.ComplianceReportStatusRejected},
+		to:        entity.ComplianceReportStatusPending,
+		authorize: (*entity.User).IsAnalyst,
+		role:      "Analyst",
+	},
+	entity.ComplianceReportActionApproved: {
+		from:      []entity.ComplianceReportStatus{entity.ComplianceReportStatusPending},
+		to:        entity.ComplianceReportStatusApproved,
+		authorize: (*entity.User).IsModerator,
+		role:      "Compliance officer",
+		fourEyes:  true,
+	},
+	entity.ComplianceReportActionRejected: {
+		from:      []entity.ComplianceReportStatus{entity.ComplianceReportStatusPending},
+		to:        entity.ComplianceReportStatusRejected,
+		authorize: (*entity.User).IsModerator,
+		role:      "Compliance officer",
+	},
+	entity.ComplianceReportActionFiled: {
+		from:      []entity.ComplianceReportStatus{entity.ComplianceReportStatusApproved},
+		to:        entity.ComplianceReportStatusSubmitted,
+		authorize: (*entity.User).IsModerator,
+		role:      "Compliance officer",
+	},
+}
+
+// SubmitReport sends a draft or reworked report for approval
+func (s *Service) SubmitReport(ctx context.Context, reportID string, user *entity.User, comment *string) (*entity.ComplianceReport, error) {
+	return s.transition(ctx, reportID, entity.ComplianceReportActionSubmitted, user, comment)
+}
+
+// ApproveReport approves a pending report. The approver may not be the report's
+// generator or submitter.
+func (s *Service) ApproveReport(ctx context.Context, reportID string, user *entity.User, comment *string) (*entity.ComplianceReport, error) {
+	return s.transition(ctx, reportID, entity.ComplianceReportActionApproved, user, comment)
+}
+
+// RejectReport returns a pending report to its author with a reason
+func (s *Service) RejectReport(ctx context.Context, reportID string, user *entity.User, reason string) (*entity.ComplianceReport, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, apperrors.NewValidationError("reason", "A rejection reason is required")
+	}
+	return s.transition(ctx, reportID, entity.ComplianceReportActionRejected, user, &reason)
+}
+
+// MarkReportFiled records that an approved report was filed with the regulator
+func (s *Service) MarkReportFiled(ctx context.Context, reportID string, user *entity.User, filingReference string) (*entity.ComplianceReport, error) {
+	if strings.TrimSpace(filingReference) == "" {
+		return nil, apperrors.NewValidationError("filingReference", "A filing reference is required")
+	}
+	return s.transition(ctx, reportID, entity.ComplianceReportActionFiled, user, &filingReference)
+}
+
+// ReportHistory returns a report's workflow history in order
+func (s *Service) ReportHistory(ctx context.Context, reportID string) ([]entity.ComplianceReportEvent, error) {
+	return s.securityRepo.GetComplianceReportHistory(ctx, reportID)
+}
+
+// VerifyReport recomputes the history hash chain and checks that the report content
+// still matches the hash recorded by the last history entry
+func (s *Service) VerifyReport(ctx context.Context, reportID string) (*entity.ComplianceReportVerification, error) {
+	report, err := s.loadReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.securityRepo.GetComplianceReportHistory(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentHash, err := report.ContentHash()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.ComplianceReportVerification{
+		ReportID:    reportID,
+		ChainIntact: len(history) > 0,
+		EventCount:  len(history),
+		CurrentHash: currentHash,
+		VerifiedAt:  time.Now(),
+	}
+
+	previous := ""
+	for i, event := range history {
+		if event.Sequence != i+1 || event.PreviousHash != previous || event.ComputeHash() != event.Hash {
+			sequence := event.Sequence
+			result.ChainIntact = false
+			result.BrokenAtSequence = &sequence
+			break
+		}
+		previous = event.Hash
+	}
+
+	if len(history) > 0 {
+		last := history[len(history)-1]
+		result.RecordedHash = last.ReportHash
+		result.ContentUnchanged = last.ReportHash == currentHash && last.ToStatus == report.Status
+	}
+	result.Valid = result.ChainIntact && result.ContentUnchanged
+
+	if !result.Valid {
+		s.logger.Warn("Compliance report failed verification",
+			zap.String("reportID", reportID),
+			zap.Bool("chainIntact", result.ChainIntact),
+			zap.Bool("contentUnchanged", result.ContentUnchanged))
+	}
+
+	return result, nil
+}
+
+// transition applies a workflow action and appends it to the report history
+func (s *Service) transition(ctx context.Context, reportID string, action entity.ComplianceReportAction, user *entity.User, comment *string) (*entity.ComplianceReport, error) {
+	rule := transitions[action]
+	if !rule.authorize(user) {
+		return nil, apperrors.NewAuthError(apperrors.ErrCodeAuthPermissionDenied,
+			fmt.Sprintf("%s role required to mark a report %s", rule.role, strings.ToLower(string(action))))
+	}
+
+	report, err := s.loadReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !allowedFrom(rule, report.Status) {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeInvalidTransition,
+			"Invalid report status transition",
+			fmt.Sprintf("Cannot %s a report in status %s", strings.ToLower(string(action)), report.Status))
+	}
+
+	history, err := s.securityRepo.GetComplianceReportHistory(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	if rule.fourEyes && isAuthorOf(user, report, history) {
+		return nil, apperrors.NewAuthError(apperrors.ErrCodeAuthPermissionDenied,
+			"A report must be approved by someone other than its generator or submitter")
+	}
+
+	from := report.Status
+	applied, err := s.securityRepo.TransitionComplianceReport(ctx, reportID, from, rule.to)
+	if err != nil {
+		return nil, err
+	}
+	if !applied {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeInvalidTransition,
+			"Invalid report status transition",
+			"The report was changed concurrently; reload it and try again")
+	}
+	report.Status = rule.to
+
+	if err := s.appendEvent(ctx, report, history, action, from, user, comment); err != nil {
+		// Keep status and history consistent: undo the transition we could not record
+		if _, revertErr := s.securityRepo.TransitionComplianceReport(ctx, reportID, rule.to, from); revertErr != nil {
+			s.logger.Error("Failed to revert unrecorded compliance report transition",
+				zap.String("reportID", reportID),
+				zap.Error(revertErr))
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Compliance report transitioned",
+		zap.String("reportID", reportID),
+		zap.String("action", string(action)),
+		zap.String("from", string(from)),
+		zap.String("to", string(rule.to)),
+		zap.String("actor", user.Email))
+
+	return report, nil
+}
+
+// appendEvent chains a new history entry after the existing ones
+func (s *Service) appendEvent(ctx context.Context, report *entity.ComplianceReport, history []entity.ComplianceReportEvent, action entity.ComplianceReportAction, from entity.ComplianceReportStatus, user *entity.User, comment *string) error {
+	reportHash, err := report.ContentHash()
+	if err != nil {
+		return err
+	}
+
+	event := &entity.ComplianceReportEvent{
+		ReportID:   report.ID,
+		Sequence:   len(history) + 1,
+		Action:     action,
+		FromStatus: from,
+		ToStatus:   report.Status,
+		Comment:    comment,
+		ReportHash: reportHash,
+		CreatedAt:  time.Now().UTC().Truncate(time.Millisecond),
+	}
+	if user != nil {
+		event.ActorID = user.ID
+		event.Actor = user.Email
+		event.ActorRole = user.Role
+	}
+	if len(history) > 0 {
+		event.PreviousHash = history[len(history)-1].Hash
+	}
+	event.Hash = event.ComputeHash()
+
+	return s.securityRepo.AppendComplianceReportEvent(ctx, event)
+}
+
+func (s *Service) loadReport(ctx context.Context, reportID string) (*entity.ComplianceReport, error) {
+	report, err := s.securityRepo.GetComplianceReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeReportNotFound, "Compliance report not found", reportID)
+	}
+	return report, nil
+}
+
+func allowedFrom(rule transition, status entity.ComplianceReportStatus) bool {
+	for _, from := range rule.from {
+		if from == status {
+			return true
+		}
+	}
+	return false
+}
+
+// isAuthorOf reports whether user generated the report, drafted or edited its
+// narrative, or submitted its current revision
+func isAuthorOf(user *entity.User, report *entity.ComplianceReport, history []entity.ComplianceReportEvent) bool {
+	if strings.EqualFold(report.GeneratedBy, user.Email) {
+		return true
+	}
+
+	submitted := false
+	for i := len(history) - 1; i >= 0; i-- {
+		switch history[i].Action {
+		case entity.ComplianceReportActionSubmitted:
+			if !submitted && history[i].ActorID == user.ID {
+				return true
+			}
+			submitted = true
+		case entity.ComplianceReportActionNarrativeDrafted, entity.ComplianceReportActionNarrativeEdited:
+			if history[i].ActorID == user.ID {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -18,7 +18,12 @@ type Config struct {
 	GraphQL    GraphQLConfig    `mapstructure:"graphql"`
 	External   ExternalConfig   `mapstructure:"external"`
 	Monitoring MonitoringConfig `mapstructure:"monitoring"`
+	Resilience ResilienceConfig `mapstructure:"resilience"`
 	Security   SecurityConfig   `mapstructure:"security"`
+	Compliance ComplianceConfig `mapstructure:"compliance"`
+	Detection  DetectionConfig  `mapstructure:"detection"`
+	Classifier ClassifierConfig `mapstructure:"classifier"`
+	Labels     LabelsConfig     `mapstructure:"labels"`
 	App        AppConfig        `mapstructure:"app"`
 }
 
@@ -41,6 +46,16 @@ type DatabaseConfig struct {
 	Neo4j      Neo4jConfig      `mapstructure:"neo4j"`
 	MongoDB    MongoDBConfig    `mapstructure:"mongodb"`
 	PostgreSQL PostgreSQLConfig `mapstructure:"postgresql"`
+	Migrations MigrationConfig  `mapstructure:"migrations"`
+}
+
+// MigrationConfig holds configuration for the schema migration runner
+type MigrationConfig struct {
+	// OnStartup applies pending migrations when the server starts
+	OnStartup bool `mapstructure:"on_startup"`
+	// LockTimeout bounds how long a replica waits for another replica's
+	// migration lock before giving up
+	LockTimeout time.Duration `mapstructure:"lock_timeout"`
 }
 
 // Neo4jConfig holds Neo4j configuration
@@ -79,8 +94,19 @@ type PostgreSQLConfig struct {
 
 // CacheConfig holds cache configuration
 type CacheConfig struct {
-	Redis RedisConfig `mapstructure:"redis"`
-	TTL   TTLConfig   `mapstructure:"ttl"`
+	Redis RedisConfig      `mapstructure:"redis"`
+	TTL   TTLConfig        `mapstructure:"ttl"`
+	Local LocalCacheConfig `mapstructure:"local"`
+}
+
+// LocalCacheConfig holds configuration for the in-process cache tier in front of Redis
+type LocalCacheConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MaxBytes bounds the total encoded size of the cached values
+	MaxBytes int64 `mapstructure:"max_bytes"`
+	// TTL caps how long a value is served locally. Replicas invalidate each
+	// other over pub/sub; the cap bounds staleness when a message is lost.
+	TTL time.Duration `mapstructure:"ttl"`
 }
 
 // RedisConfig holds Redis configuration
@@ -97,14 +123,21 @@ type RedisConfig struct {
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
 }
 
-// TTLConfig holds TTL configuration for different cache types
+// TTLConfig holds TTL configuration for different cache types. A zero TTL
+// disables caching of that type.
 type TTLConfig struct {
 	WalletNetwork   time.Duration `mapstructure:"wallet_network"`
 	WalletRankings  time.Duration `mapstructure:"wallet_rankings"`
+	WalletData      time.Duration `mapstructure:"wallet_data"`
 	DashboardStats  time.Duration `mapstructure:"dashboard_stats"`
 	RiskScores      time.Duration `mapstructure:"risk_scores"`
 	NetworkStats    time.Duration `mapstructure:"network_stats"`
 	TransactionData time.Duration `mapstructure:"transaction_data"`
+
+	// NetworkStale is how long network data and wallet networks are kept after
+	// they expire, to be served marked stale while Neo4j or MongoDB is
+	// unavailable; 0 disables it
+	NetworkStale time.Duration `mapstructure:"network_stale"`
 }
 
 // JWTConfig holds JWT configuration
@@ -124,6 +157,7 @@ type GraphQLConfig struct {
 	DepthLimit            int  `mapstructure:"depth_limit"`
 	EnableQueryValidation bool `mapstructure:"enable_query_validation"`
 	EnableTracing         bool `mapstructure:"enable_tracing"`
+	CostUnit              int  `mapstructure:"cost_unit"`
 }
 
 // ExternalConfig holds external service configuration
@@ -135,16 +169,61 @@ type ExternalConfig struct {
 	OpenAIAPIKey    string `mapstructure:"openai_api_key"`
 	OpenAIModel     string `mapstructure:"openai_model"`
 	OpenAIBaseURL   string `mapstructure:"openai_base_url"`
+
+	// OpenAIMaxToolRounds bounds how many rounds of tool calls the assistant may
+	// make before it must answer with the data it has
+	OpenAIMaxToolRounds int `mapstructure:"openai_max_tool_rounds"`
+	// OpenAIContextTokens is the token budget for earlier conversation messages
+	// sent with each question; the oldest messages are dropped first
+	OpenAIContextTokens int `mapstructure:"openai_context_tokens"`
+
+	// Daily AI token budgets per user by role, reset at midnight UTC; 0 means
+	// unlimited. A user's own limit, when set, takes precedence.
+	OpenAIDailyTokensUser      int `mapstructure:"openai_daily_tokens_user"`
+	OpenAIDailyTokensAnalyst   int `mapstructure:"openai_daily_tokens_analyst"`
+	OpenAIDailyTokensModerator int `mapstructure:"openai_daily_tokens_moderator"`
+	OpenAIDailyTokensAdmin     int `mapstructure:"openai_daily_tokens_admin"`
+	// OpenAIResponseCacheTTL is how long answers to wallet questions without
+	// conversation history are reused; 0 disables the cache
+	OpenAIResponseCacheTTL time.Duration `mapstructure:"openai_response_cache_ttl"`
 }
 
 // MonitoringConfig holds monitoring configuration
 type MonitoringConfig struct {
-	EnableMetrics   bool   `mapstructure:"enable_metrics"`
-	MetricsPort     int    `mapstructure:"metrics_port"`
-	EnableTracing   bool   `mapstructure:"enable_tracing"`
-	JaegerEndpoint  string `mapstructure:"jaeger_endpoint"`
-	EnableProfiling bool   `mapstructure:"enable_profiling"`
-	ProfilingPort   int    `mapstructure:"profiling_port"`
+	EnableMetrics   bool `mapstructure:"enable_metrics"`
+	MetricsPort     int  `mapstructure:"metrics_port"`
+	EnableTracing   bool `mapstructure:"enable_tracing"`
+	EnableProfiling bool `mapstructure:"enable_profiling"`
+	ProfilingPort   int  `mapstructure:"profiling_port"`
+
+	// OTLPEndpoint is the OTLP/HTTP collector traces are exported to when
+	// tracing is enabled, e.g. a local Jaeger or OpenTelemetry Collector
+	OTLPEndpoint string `mapstructure:"otlp_endpoint"`
+	// TracingServiceName identifies this service in exported spans
+	TracingServiceName string `mapstructure:"tracing_service_name"`
+	// TracingSampleRatio is the fraction of new traces recorded; requests that
+	// arrive with a sampled trace context are always recorded
+	TracingSampleRatio float64 `mapstructure:"tracing_sample_ratio"`
+
+	// HealthCheckInterval is how often datastores are checked in the
+	// background, so that degraded capabilities recover without a restart
+	HealthCheckInterval time.Duration `mapstructure:"health_check_interval"`
+}
+
+// ResilienceConfig holds the circuit breaker and retry settings applied to
+// each external dependency
+type ResilienceConfig struct {
+	// BreakerFailureThreshold is the number of consecutive failures after
+	// which a dependency's circuit breaker opens
+	BreakerFailureThreshold int `mapstructure:"breaker_failure_threshold"`
+	// BreakerOpenTimeout is how long an open breaker rejects calls before
+	// letting a single probe through
+	BreakerOpenTimeout time.Duration `mapstructure:"breaker_open_timeout"`
+	// RetryMaxAttempts bounds the attempts made for one call, including the
+	// first; 1 disables retries
+	RetryMaxAttempts    int           `mapstructure:"retry_max_attempts"`
+	RetryInitialBackoff time.Duration `mapstructure:"retry_initial_backoff"`
+	RetryMaxBackoff     time.Duration `mapstructure:"retry_max_backoff"`
 }
 
 // SecurityConfig holds security configuration
@@ -160,6 +239,86 @@ type SecurityConfig struct {
 	SessionTimeout          time.Duration `mapstructure:"session_timeout"`
 }
 
+// ComplianceConfig holds sanctions screening and compliance configuration
+type ComplianceConfig struct {
+	SanctionsDataDir string `mapstructure:"sanctions_data_dir"`
+	ScreeningHops    int    `mapstructure:"screening_hops"`
+	MaxScreeningHops int    `mapstructure:"max_screening_hops"`
+
+	BulkScreeningBatchSize    int `mapstructure:"bulk_screening_batch_size"`
+	BulkScreeningSyncLimit    int `mapstructure:"bulk_screening_sync_limit"`
+	BulkScreeningMaxAddresses int `mapstructure:"bulk_screening_max_addresses"`
+
+	ReferencePriceUSD     float64       `mapstructure:"reference_price_usd"`
+	CTRThresholdUSD       float64       `mapstructure:"ctr_threshold_usd"`
+	StructuringWindow     time.Duration `mapstructure:"structuring_window"`
+	MaxReportTransactions int64         `mapstructure:"max_report_transactions"`
+
+	// Security case SLAs by severity: investigation must start within the response
+	// time and the case must be closed within the resolution time
+	CaseResponseSLACritical   time.Duration `mapstructure:"case_response_sla_critical"`
+	CaseResponseSLAHigh       time.Duration `mapstructure:"case_response_sla_high"`
+	CaseResponseSLAMedium     time.Duration `mapstructure:"case_response_sla_medium"`
+	CaseResponseSLALow        time.Duration `mapstructure:"case_response_sla_low"`
+	CaseResolutionSLACritical time.Duration `mapstructure:"case_resolution_sla_critical"`
+	CaseResolutionSLAHigh     time.Duration `mapstructure:"case_resolution_sla_high"`
+	CaseResolutionSLAMedium   time.Duration `mapstructure:"case_resolution_sla_medium"`
+	CaseResolutionSLALow      time.Duration `mapstructure:"case_resolution_sla_low"`
+}
+
+// DetectionConfig holds configuration for the detection rules that raise security
+// alerts from new transactions
+type DetectionConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	Interval        time.Duration `mapstructure:"interval"`
+	BatchSize       int64         `mapstructure:"batch_size"`
+	InitialLookback time.Duration `mapstructure:"initial_lookback"`
+
+	// Peel chains: a wallet forwards most of what it received and "peels" off the rest
+	PeelChainMinHops      int           `mapstructure:"peel_chain_min_hops"`
+	PeelChainMaxPeelRatio float64       `mapstructure:"peel_chain_max_peel_ratio"`
+	PeelChainHopWindow    time.Duration `mapstructure:"peel_chain_hop_window"`
+	PeelChainMinValueETH  float64       `mapstructure:"peel_chain_min_value_eth"`
+
+	// Fan-out/fan-in: many distinct counterparties within a short window
+	FanMinCounterparties int           `mapstructure:"fan_min_counterparties"`
+	FanWindow            time.Duration `mapstructure:"fan_window"`
+
+	// MEV: sandwich attacks and atomic arbitrage; bots can be tagged MEV_BOT or ARBITRAGE_BOT
+	MEVTagWallets bool `mapstructure:"mev_tag_wallets"`
+}
+
+// ClassifierConfig represents wallet type classification configuration
+type ClassifierConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Interval time.Duration `mapstructure:"interval"`
+	// BatchSize caps the wallets classified per run
+	BatchSize int `mapstructure:"batch_size"`
+	// MinReclassifyInterval skips wallets classified more recently than this
+	MinReclassifyInterval time.Duration `mapstructure:"min_reclassify_interval"`
+	// SampleSize is how many recent transactions behaviour features are computed from
+	SampleSize int64 `mapstructure:"sample_size"`
+
+	WhaleBalanceETH         float64 `mapstructure:"whale_balance_eth"`
+	ExchangeMinSenders      int     `mapstructure:"exchange_min_senders"`
+	BotMinDailyTransactions float64 `mapstructure:"bot_min_daily_transactions"`
+}
+
+// LabelsConfig represents address label registry configuration
+type LabelsConfig struct {
+	// DataDir is the only directory label packs are imported from
+	DataDir string `mapstructure:"data_dir"`
+	// MaxImportRows caps the rows of a single label pack
+	MaxImportRows int `mapstructure:"max_import_rows"`
+	// DefaultConfidence applies to labels that do not state a confidence
+	DefaultConfidence float64 `mapstructure:"default_confidence"`
+	// ValiditySweepInterval is how often labels that became valid or expired are
+	// propagated to the graph
+	ValiditySweepInterval time.Duration `mapstructure:"validity_sweep_interval"`
+	// MaxPendingProposals caps the community proposals a user may have awaiting moderation
+	MaxPendingProposals int `mapstructure:"max_pending_proposals"`
+}
+
 // AppConfig holds application-specific configuration
 type AppConfig struct {
 	Environment               string        `mapstructure:"environment"`
@@ -169,6 +328,10 @@ type AppConfig struct {
 	RiskScoreUpdateInterval   time.Duration `mapstructure:"risk_score_update_interval"`
 	WalletStatsUpdateInterval time.Duration `mapstructure:"wallet_stats_update_interval"`
 	CacheCleanupInterval      time.Duration `mapstructure:"cache_cleanup_interval"`
+
+	// RequireDatastores makes startup fail if any datastore is unreachable.
+	// By default the server starts degraded and serves what it can.
+	RequireDatastores bool `mapstructure:"require_datastores"`
 }
 
 // Load loads configuration from environment variables and config files
@@ -344,6 +507,10 @@ func bindEnvironmentVariables() {
 	viper.BindEnv("database.postgresql.max_idle_conns", "POSTGRES_MAX_IDLE_CONNS")
 	viper.BindEnv("database.postgresql.conn_max_lifetime", "POSTGRES_CONN_MAX_LIFETIME")
 
+	// Migration configuration
+	viper.BindEnv("database.migrations.on_startup", "MIGRATE_ON_STARTUP")
+	viper.BindEnv("database.migrations.lock_timeout", "MIGRATION_LOCK_TIMEOUT")
+
 	// Redis configuration
 	viper.BindEnv("cache.redis.host", "REDIS_HOST")
 	viper.BindEnv("cache.redis.port", "REDIS_PORT")
@@ -368,11 +535,68 @@ func bindEnvironmentVariables() {
 	viper.BindEnv("security.rate_limit_requests_per_minute", "RATE_LIMIT_REQUESTS_PER_MINUTE")
 	viper.BindEnv("security.rate_limit_burst", "RATE_LIMIT_BURST")
 
+	// Compliance configuration
+	viper.BindEnv("compliance.sanctions_data_dir", "SANCTIONS_DATA_DIR")
+	viper.BindEnv("compliance.screening_hops", "SANCTIONS_SCREENING_HOPS")
+	viper.BindEnv("compliance.max_screening_hops", "SANCTIONS_MAX_SCREENING_HOPS")
+	viper.BindEnv("compliance.bulk_screening_batch_size", "BULK_SCREENING_BATCH_SIZE")
+	viper.BindEnv("compliance.bulk_screening_sync_limit", "BULK_SCREENING_SYNC_LIMIT")
+	viper.BindEnv("compliance.bulk_screening_max_addresses", "BULK_SCREENING_MAX_ADDRESSES")
+	viper.BindEnv("compliance.reference_price_usd", "COMPLIANCE_REFERENCE_PRICE_USD")
+	viper.BindEnv("compliance.ctr_threshold_usd", "COMPLIANCE_CTR_THRESHOLD_USD")
+	viper.BindEnv("compliance.structuring_window", "COMPLIANCE_STRUCTURING_WINDOW")
+	viper.BindEnv("compliance.max_report_transactions", "COMPLIANCE_MAX_REPORT_TRANSACTIONS")
+	viper.BindEnv("compliance.case_response_sla_critical", "CASE_RESPONSE_SLA_CRITICAL")
+	viper.BindEnv("compliance.case_response_sla_high", "CASE_RESPONSE_SLA_HIGH")
+	viper.BindEnv("compliance.case_response_sla_medium", "CASE_RESPONSE_SLA_MEDIUM")
+	viper.BindEnv("compliance.case_response_sla_low", "CASE_RESPONSE_SLA_LOW")
+	viper.BindEnv("compliance.case_resolution_sla_critical", "CASE_RESOLUTION_SLA_CRITICAL")
+	viper.BindEnv("compliance.case_resolution_sla_high", "CASE_RESOLUTION_SLA_HIGH")
+	viper.BindEnv("compliance.case_resolution_sla_medium", "CASE_RESOLUTION_SLA_MEDIUM")
+	viper.BindEnv("compliance.case_resolution_sla_low", "CASE_RESOLUTION_SLA_LOW")
+
+	// Detection configuration
+	viper.BindEnv("detection.enabled", "DETECTION_ENABLED")
+	viper.BindEnv("detection.interval", "DETECTION_INTERVAL")
+	viper.BindEnv("detection.batch_size", "DETECTION_BATCH_SIZE")
+	viper.BindEnv("detection.initial_lookback", "DETECTION_INITIAL_LOOKBACK")
+	viper.BindEnv("detection.peel_chain_min_hops", "DETECTION_PEEL_CHAIN_MIN_HOPS")
+	viper.BindEnv("detection.peel_chain_max_peel_ratio", "DETECTION_PEEL_CHAIN_MAX_PEEL_RATIO")
+	viper.BindEnv("detection.peel_chain_hop_window", "DETECTION_PEEL_CHAIN_HOP_WINDOW")
+	viper.BindEnv("detection.peel_chain_min_value_eth", "DETECTION_PEEL_CHAIN_MIN_VALUE_ETH")
+	viper.BindEnv("detection.fan_min_counterparties", "DETECTION_FAN_MIN_COUNTERPARTIES")
+	viper.BindEnv("detection.fan_window", "DETECTION_FAN_WINDOW")
+	viper.BindEnv("detection.mev_tag_wallets", "DETECTION_MEV_TAG_WALLETS")
+
+	// Classifier configuration
+	viper.BindEnv("classifier.enabled", "CLASSIFIER_ENABLED")
+	viper.BindEnv("classifier.interval", "CLASSIFIER_INTERVAL")
+	viper.BindEnv("classifier.batch_size", "CLASSIFIER_BATCH_SIZE")
+	viper.BindEnv("classifier.min_reclassify_interval", "CLASSIFIER_MIN_RECLASSIFY_INTERVAL")
+	viper.BindEnv("classifier.sample_size", "CLASSIFIER_SAMPLE_SIZE")
+	viper.BindEnv("classifier.whale_balance_eth", "CLASSIFIER_WHALE_BALANCE_ETH")
+	viper.BindEnv("classifier.exchange_min_senders", "CLASSIFIER_EXCHANGE_MIN_SENDERS")
+	viper.BindEnv("classifier.bot_min_daily_transactions", "CLASSIFIER_BOT_MIN_DAILY_TRANSACTIONS")
+
+	// Label registry configuration
+	viper.BindEnv("labels.data_dir", "LABELS_DATA_DIR")
+	viper.BindEnv("labels.max_import_rows", "LABELS_MAX_IMPORT_ROWS")
+	viper.BindEnv("labels.default_confidence", "LABELS_DEFAULT_CONFIDENCE")
+	viper.BindEnv("labels.validity_sweep_interval", "LABELS_VALIDITY_SWEEP_INTERVAL")
+	viper.BindEnv("labels.max_pending_proposals", "LABELS_MAX_PENDING_PROPOSALS")
+
 	// Cache TTL configuration
 	viper.BindEnv("cache.ttl.wallet_network", "CACHE_TTL_WALLET_NETWORK")
 	viper.BindEnv("cache.ttl.wallet_rankings", "CACHE_TTL_WALLET_RANKINGS")
 	viper.BindEnv("cache.ttl.dashboard_stats", "CACHE_TTL_DASHBOARD_STATS")
 	viper.BindEnv("cache.ttl.risk_scores", "CACHE_TTL_RISK_SCORES")
+	viper.BindEnv("cache.ttl.wallet_data", "CACHE_TTL_WALLET_DATA")
+	viper.BindEnv("cache.ttl.network_stats", "CACHE_TTL_NETWORK_STATS")
+	viper.BindEnv("cache.ttl.transaction_data", "CACHE_TTL_TRANSACTION_DATA")
+	viper.BindEnv("cache.ttl.network_stale", "CACHE_TTL_NETWORK_STALE")
+	viper.BindEnv("cache.local.enabled", "CACHE_LOCAL_ENABLED")
+	viper.BindEnv("cache.local.max_bytes", "CACHE_LOCAL_MAX_BYTES")
+	viper.BindEnv("cache.local.ttl", "CACHE_LOCAL_TTL")
 
 	// External APIs
 	viper.BindEnv("external.ethereum_rpc_url", "ETHEREUM_RPC_URL")
@@ -382,24 +606,43 @@ func bindEnvironmentVariables() {
 	viper.BindEnv("external.openai_api_key", "OPENAI_API_KEY")
 	viper.BindEnv("external.openai_model", "OPENAI_MODEL")
 	viper.BindEnv("external.openai_base_url", "OPENAI_BASE_URL")
+	viper.BindEnv("external.openai_max_tool_rounds", "OPENAI_MAX_TOOL_ROUNDS")
+	viper.BindEnv("external.openai_context_tokens", "OPENAI_CONTEXT_TOKENS")
+	viper.BindEnv("external.openai_daily_tokens_user", "OPENAI_DAILY_TOKENS_USER")
+	viper.BindEnv("external.openai_daily_tokens_analyst", "OPENAI_DAILY_TOKENS_ANALYST")
+	viper.BindEnv("external.openai_daily_tokens_moderator", "OPENAI_DAILY_TOKENS_MODERATOR")
+	viper.BindEnv("external.openai_daily_tokens_admin", "OPENAI_DAILY_TOKENS_ADMIN")
+	viper.BindEnv("external.openai_response_cache_ttl", "OPENAI_RESPONSE_CACHE_TTL")
 
 	// GraphQL configuration
 	viper.BindEnv("graphql.playground_enabled", "GRAPHQL_PLAYGROUND_ENABLED")
 	viper.BindEnv("graphql.introspection_enabled", "GRAPHQL_INTROSPECTION_ENABLED")
 	viper.BindEnv("graphql.complexity_limit", "GRAPHQL_COMPLEXITY_LIMIT")
 	viper.BindEnv("graphql.depth_limit", "GRAPHQL_DEPTH_LIMIT")
+	viper.BindEnv("graphql.cost_unit", "GRAPHQL_COST_UNIT")
 
 	// Monitoring configuration
 	viper.BindEnv("monitoring.enable_metrics", "ENABLE_METRICS")
 	viper.BindEnv("monitoring.metrics_port", "METRICS_PORT")
 	viper.BindEnv("monitoring.enable_tracing", "ENABLE_TRACING")
-	viper.BindEnv("monitoring.jaeger_endpoint", "JAEGER_ENDPOINT")
+	viper.BindEnv("monitoring.otlp_endpoint", "OTEL_EXPORTER_OTLP_ENDPOINT")
+	viper.BindEnv("monitoring.tracing_service_name", "OTEL_SERVICE_NAME")
+	viper.BindEnv("monitoring.tracing_sample_ratio", "TRACING_SAMPLE_RATIO")
+	viper.BindEnv("monitoring.health_check_interval", "HEALTH_CHECK_INTERVAL")
+
+	// Resilience configuration
+	viper.BindEnv("resilience.breaker_failure_threshold", "BREAKER_FAILURE_THRESHOLD")
+	viper.BindEnv("resilience.breaker_open_timeout", "BREAKER_OPEN_TIMEOUT")
+	viper.BindEnv("resilience.retry_max_attempts", "RETRY_MAX_ATTEMPTS")
+	viper.BindEnv("resilience.retry_initial_backoff", "RETRY_INITIAL_BACKOFF")
+	viper.BindEnv("resilience.retry_max_backoff", "RETRY_MAX_BACKOFF")
 
 	// Background jobs
 	viper.BindEnv("app.enable_background_jobs", "ENABLE_BACKGROUND_JOBS")
 	viper.BindEnv("app.risk_score_update_interval", "RISK_SCORE_UPDATE_INTERVAL")
 	viper.BindEnv("app.wallet_stats_update_interval", "WALLET_STATS_UPDATE_INTERVAL")
 	viper.BindEnv("app.cache_cleanup_interval", "CACHE_CLEANUP_INTERVAL")
+	viper.BindEnv("app.require_datastores", "REQUIRE_DATASTORES")
 }
 
 // setDefaults sets default configuration values
@@ -443,6 +686,10 @@ func setDefaults() {
 	viper.SetDefault("database.postgresql.max_idle_conns", 5)
 	viper.SetDefault("database.postgresql.conn_max_lifetime", "5m")
 
+	// Migration defaults
+	viper.SetDefault("database.migrations.on_startup", true)
+	viper.SetDefault("database.migrations.lock_timeout", "5m")
+
 	// Redis defaults
 	viper.SetDefault("cache.redis.host", "localhost")
 	viper.SetDefault("cache.redis.port", 6379)
@@ -458,10 +705,15 @@ func setDefaults() {
 	// Cache TTL defaults
 	viper.SetDefault("cache.ttl.wallet_network", "5m")
 	viper.SetDefault("cache.ttl.wallet_rankings", "10m")
+	viper.SetDefault("cache.ttl.wallet_data", "5m")
 	viper.SetDefault("cache.ttl.dashboard_stats", "3m")
 	viper.SetDefault("cache.ttl.risk_scores", "15m")
 	viper.SetDefault("cache.ttl.network_stats", "5m")
 	viper.SetDefault("cache.ttl.transaction_data", "1m")
+	viper.SetDefault("cache.ttl.network_stale", "24h")
+	viper.SetDefault("cache.local.enabled", true)
+	viper.SetDefault("cache.local.max_bytes", 64<<20)
+	viper.SetDefault("cache.local.ttl", "30s")
 
 	// JWT defaults
 	viper.SetDefault("jwt.secret", "your-secret-key")
@@ -477,17 +729,36 @@ func setDefaults() {
 	viper.SetDefault("graphql.depth_limit", 15)
 	viper.SetDefault("graphql.enable_query_validation", true)
 	viper.SetDefault("graphql.enable_tracing", false)
+	viper.SetDefault("graphql.cost_unit", 50)
 
 	// Monitoring defaults
 	viper.SetDefault("monitoring.enable_metrics", true)
 	viper.SetDefault("monitoring.metrics_port", 9090)
 	viper.SetDefault("monitoring.enable_tracing", false)
+	viper.SetDefault("monitoring.otlp_endpoint", "http://localhost:4318")
+	viper.SetDefault("monitoring.tracing_service_name", "crypto-bubble-map-be")
+	viper.SetDefault("monitoring.tracing_sample_ratio", 1.0)
+	viper.SetDefault("monitoring.health_check_interval", "15s")
 	viper.SetDefault("monitoring.enable_profiling", false)
 	viper.SetDefault("monitoring.profiling_port", 6060)
 
+	// Resilience defaults
+	viper.SetDefault("resilience.breaker_failure_threshold", 5)
+	viper.SetDefault("resilience.breaker_open_timeout", "30s")
+	viper.SetDefault("resilience.retry_max_attempts", 3)
+	viper.SetDefault("resilience.retry_initial_backoff", "100ms")
+	viper.SetDefault("resilience.retry_max_backoff", "2s")
+
 	// External API defaults
 	viper.SetDefault("external.openai_model", "gpt-3.5-turbo")
 	viper.SetDefault("external.openai_base_url", "https://api.openai.com/v1")
+	viper.SetDefault("external.openai_max_tool_rounds", 5)
+	viper.SetDefault("external.openai_context_tokens", 3000)
+	viper.SetDefault("external.openai_daily_tokens_user", 20000)
+	viper.SetDefault("external.openai_daily_tokens_analyst", 100000)
+	viper.SetDefault("external.openai_daily_tokens_moderator", 50000)
+	viper.SetDefault("external.openai_daily_tokens_admin", 0)
+	viper.SetDefault("external.openai_response_cache_ttl", "15m")
 
 	// Security defaults
 	viper.SetDefault("security.enable_rate_limiting", true)
@@ -499,6 +770,56 @@ func setDefaults() {
 	viper.SetDefault("security.password_require_special", true)
 	viper.SetDefault("security.session_timeout", "24h")
 
+	// Compliance defaults
+	viper.SetDefault("compliance.sanctions_data_dir", "./data/sanctions")
+	viper.SetDefault("compliance.screening_hops", 2)
+	viper.SetDefault("compliance.max_screening_hops", 4)
+	viper.SetDefault("compliance.bulk_screening_batch_size", 200)
+	viper.SetDefault("compliance.bulk_screening_sync_limit", 100)
+	viper.SetDefault("compliance.bulk_screening_max_addresses", 50000)
+	viper.SetDefault("compliance.reference_price_usd", 3000.0)
+	viper.SetDefault("compliance.ctr_threshold_usd", 10000.0)
+	viper.SetDefault("compliance.structuring_window", "24h")
+	viper.SetDefault("compliance.max_report_transactions", 10000)
+	viper.SetDefault("compliance.case_response_sla_critical", "1h")
+	viper.SetDefault("compliance.case_response_sla_high", "4h")
+	viper.SetDefault("compliance.case_response_sla_medium", "24h")
+	viper.SetDefault("compliance.case_response_sla_low", "72h")
+	viper.SetDefault("compliance.case_resolution_sla_critical", "24h")
+	viper.SetDefault("compliance.case_resolution_sla_high", "72h")
+	viper.SetDefault("compliance.case_resolution_sla_medium", "168h")
+	viper.SetDefault("compliance.case_resolution_sla_low", "720h")
+
+	// Detection defaults
+	viper.SetDefault("detection.enabled", true)
+	viper.SetDefault("detection.interval", "1m")
+	viper.SetDefault("detection.batch_size", 500)
+	viper.SetDefault("detection.initial_lookback", "24h")
+	viper.SetDefault("detection.peel_chain_min_hops", 3)
+	viper.SetDefault("detection.peel_chain_max_peel_ratio", 0.2)
+	viper.SetDefault("detection.peel_chain_hop_window", "6h")
+	viper.SetDefault("detection.peel_chain_min_value_eth", 1.0)
+	viper.SetDefault("detection.fan_min_counterparties", 10)
+	viper.SetDefault("detection.fan_window", "1h")
+	viper.SetDefault("detection.mev_tag_wallets", true)
+
+	// Classifier defaults
+	viper.SetDefault("classifier.enabled", true)
+	viper.SetDefault("classifier.interval", "5m")
+	viper.SetDefault("classifier.batch_size", 200)
+	viper.SetDefault("classifier.min_reclassify_interval", "6h")
+	viper.SetDefault("classifier.sample_size", 500)
+	viper.SetDefault("classifier.whale_balance_eth", 1000.0)
+	viper.SetDefault("classifier.exchange_min_senders", 100)
+	viper.SetDefault("classifier.bot_min_daily_transactions", 50.0)
+
+	// Label registry defaults
+	viper.SetDefault("labels.data_dir", "./data/labels")
+	viper.SetDefault("labels.max_import_rows", 100000)
+	viper.SetDefault("labels.default_confidence", 0.8)
+	viper.SetDefault("labels.validity_sweep_interval", "15m")
+	viper.SetDefault("labels.max_pending_proposals", 20)
+
 	// App defaults
 	viper.SetDefault("app.environment", "development")
 	viper.SetDefault("app.log_level", "info")
@@ -507,6 +828,7 @@ func setDefaults() {
 	viper.SetDefault("app.risk_score_update_interval", "1h")
 	viper.SetDefault("app.wallet_stats_update_interval", "30m")
 	viper.SetDefault("app.cache_cleanup_interval", "6h")
+	viper.SetDefault("app.require_datastores", false)
 }
 
 // Validate validates the configuration
@@ -531,6 +853,23 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("PostgreSQL host must be set")
 	}
 
+	if c.Monitoring.EnableTracing {
+		if c.Monitoring.OTLPEndpoint == "" {
+			return fmt.Errorf("OTLP endpoint must be set when tracing is enabled")
+		}
+		if c.Monitoring.TracingSampleRatio < 0 || c.Monitoring.TracingSampleRatio > 1 {
+			return fmt.Errorf("invalid tracing sample ratio: %g", c.Monitoring.TracingSampleRatio)
+		}
+	}
+
+	if c.Resilience.BreakerFailureThreshold <= 0 {
+		return fmt.Errorf("invalid circuit breaker failure threshold: %d", c.Resilience.BreakerFailureThreshold)
+	}
+
+	if c.Resilience.RetryMaxAttempts <= 0 {
+		return fmt.Errorf("invalid retry max attempts: %d", c.Resilience.RetryMaxAttempts)
+	}
+
 	return nil
 }
 
@@ -6,12 +6,25 @@ import (
 
 	"crypto-bubble-map-be/graph"
 	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/assistant"
 	"crypto-bubble-map-be/internal/infrastructure/cache"
+	"crypto-bubble-map-be/internal/infrastructure/cases"
+	"crypto-bubble-map-be/internal/infrastructure/classification"
+	"crypto-bubble-map-be/internal/infrastructure/compliance"
 	"crypto-bubble-map-be/internal/infrastructure/config"
 	"crypto-bubble-map-be/internal/infrastructure/database"
+	"crypto-bubble-map-be/internal/infrastructure/detection"
 	"crypto-bubble-map-be/internal/infrastructure/external"
+	"crypto-bubble-map-be/internal/infrastructure/health"
+	"crypto-bubble-map-be/internal/infrastructure/labels"
 	"crypto-bubble-map-be/internal/infrastructure/logger"
+	"crypto-bubble-map-be/internal/infrastructure/migrations"
+	"crypto-bubble-map-be/internal/infrastructure/monitoring"
 	repoImpl "crypto-bubble-map-be/internal/infrastructure/repository"
+	"crypto-bubble-map-be/internal/infrastructure/resilience"
+	"crypto-bubble-map-be/internal/infrastructure/sanctions"
+	"crypto-bubble-map-be/internal/infrastructure/screening"
+	"crypto-bubble-map-be/internal/infrastructure/search"
 
 	"go.uber.org/fx"
 	"go.uber.org/zap"
@@ -25,11 +38,22 @@ type Container struct {
 	MongoDB    *database.MongoClient
 	PostgreSQL *database.PostgreSQLClient
 	Redis      *cache.RedisClient
+	Cache      *cache.Aside
+	Tracing    *monitoring.Tracing
+	Breakers   *resilience.Registry
+	Health     *health.HealthManager
 	Resolver   *graph.Resolver
+	Detection  *detection.Runner
+	Classifier *classification.Service
+	Labels     *labels.Service
+	Compliance *compliance.Service
+	Users      repository.UserRepository
+	Wallets    repository.WalletRepository
 }
 
-// NewContainer creates a new dependency injection container
-func NewContainer() *fx.App {
+// NewContainer creates a new dependency injection container. Additional
+// options, such as fx.Populate to extract the Container, are appended.
+func NewContainer(opts ...fx.Option) *fx.App {
 	return fx.New(
 		// Configuration
 		fx.Provide(config.Load),
@@ -42,6 +66,14 @@ func NewContainer() *fx.App {
 		fx.Provide(NewMongoClient),
 		fx.Provide(NewPostgreSQLClient),
 		fx.Provide(NewRedisClient),
+		fx.Provide(NewCacheAside),
+
+		// Monitoring
+		fx.Provide(NewMetricsCollector),
+		fx.Provide(NewPerformanceMonitor),
+		fx.Provide(NewTracing),
+		fx.Provide(NewBreakerRegistry),
+		fx.Provide(NewHealthManager),
 
 		// Repositories
 		fx.Provide(NewWalletRepository),
@@ -52,12 +84,34 @@ func NewContainer() *fx.App {
 		fx.Provide(NewUserRepository),
 		fx.Provide(NewCacheRepository),
 		fx.Provide(NewAIRepository),
+		fx.Provide(NewSanctionsRepository),
+		fx.Provide(NewScreeningRepository),
+		fx.Provide(NewAIConversationRepository),
+		fx.Provide(NewAIUsageRepository),
+		fx.Provide(NewCaseRepository),
+		fx.Provide(NewMEVRepository),
+		fx.Provide(NewClassificationRepository),
+		fx.Provide(NewLabelRepository),
+		fx.Provide(NewLabelProposalRepository),
+
+		// Services
+		fx.Provide(NewSanctionsService),
+		fx.Provide(NewScreeningService),
+		fx.Provide(NewComplianceService),
+		fx.Provide(NewAssistantService),
+		fx.Provide(NewCasesService),
+		fx.Provide(NewDetectionRunner),
+		fx.Provide(NewClassifierService),
+		fx.Provide(NewLabelService),
+		fx.Provide(NewSearchService),
 
 		// GraphQL Resolver
 		fx.Provide(NewGraphQLResolver),
 
 		// Container
 		fx.Provide(NewContainerStruct),
+
+		fx.Options(opts...),
 	)
 }
 
@@ -72,39 +126,80 @@ func NewLogger(cfg *config.Config) (*logger.Logger, error) {
 	return logger.NewLogger(loggerCfg)
 }
 
-func NewNeo4jClient(cfg *config.Config, logger *logger.Logger) (*database.Neo4jClient, error) {
-	return database.NewNeo4jClient(&cfg.Database.Neo4j, logger.Logger)
+func NewNeo4jClient(cfg *config.Config, monitor *monitoring.PerformanceMonitor, breakers *resilience.Registry, logger *logger.Logger) (*database.Neo4jClient, error) {
+	return database.NewNeo4jClient(&cfg.Database.Neo4j, monitor, breakers, logger.Logger)
+}
+
+func NewMongoClient(cfg *config.Config, monitor *monitoring.PerformanceMonitor, breakers *resilience.Registry, logger *logger.Logger) (*database.MongoClient, error) {
+	return database.NewMongoClient(&cfg.Database.MongoDB, monitor, breakers, logger.Logger)
+}
+
+func NewPostgreSQLClient(cfg *config.Config, monitor *monitoring.PerformanceMonitor, breakers *resilience.Registry, logger *logger.Logger) (*database.PostgreSQLClient, error) {
+	return database.NewPostgreSQLClient(&cfg.Database.PostgreSQL, monitor, breakers, logger.Logger)
+}
+
+func NewRedisClient(cfg *config.Config, breakers *resilience.Registry, logger *logger.Logger) (*cache.RedisClient, error) {
+	return cache.NewRedisClient(&cfg.Cache.Redis, &cfg.Cache.TTL, breakers, logger.Logger)
 }
 
-func NewMongoClient(cfg *config.Config, logger *logger.Logger) (*database.MongoClient, error) {
-	return database.NewMongoClient(&cfg.Database.MongoDB, logger.Logger)
+func NewCacheAside(redis *cache.RedisClient, monitor *monitoring.PerformanceMonitor, cfg *config.Config, logger *logger.Logger) *cache.Aside {
+	return cache.NewAside(redis, &cfg.Cache, monitor, logger.Logger)
 }
 
-func NewPostgreSQLClient(cfg *config.Config, logger *logger.Logger) (*database.PostgreSQLClient, error) {
-	return database.NewPostgreSQLClient(&cfg.Database.PostgreSQL, logger.Logger)
+// Monitoring providers
+
+func NewMetricsCollector(logger *logger.Logger) *monitoring.MetricsCollector {
+	return monitoring.NewMetricsCollector(logger.Logger)
+}
+
+func NewPerformanceMonitor(collector *monitoring.MetricsCollector, logger *logger.Logger) *monitoring.PerformanceMonitor {
+	return monitoring.NewPerformanceMonitor(collector, logger.Logger)
+}
+
+func NewTracing(cfg *config.Config, logger *logger.Logger) (*monitoring.Tracing, error) {
+	return monitoring.NewTracing(&cfg.Monitoring, cfg.App.Environment, logger.Logger)
+}
+
+func NewBreakerRegistry(cfg *config.Config, monitor *monitoring.PerformanceMonitor, logger *logger.Logger) *resilience.Registry {
+	return resilience.NewRegistry(&cfg.Resilience, monitor, logger.Logger)
 }
 
-func NewRedisClient(cfg *config.Config, logger *logger.Logger) (*cache.RedisClient, error) {
-	return cache.NewRedisClient(&cfg.Cache.Redis, &cfg.Cache.TTL, logger.Logger)
+func NewHealthManager(
+	cfg *config.Config,
+	postgres *database.PostgreSQLClient,
+	mongo *database.MongoClient,
+	neo4j *database.Neo4jClient,
+	redis *cache.RedisClient,
+	breakers *resilience.Registry,
+	logger *logger.Logger,
+) *health.HealthManager {
+	hm := health.NewHealthManager(cfg, logger.Logger)
+	health.SetupHealthCheckers(hm, postgres, mongo, neo4j, redis, breakers, cfg, logger.Logger)
+	return hm
 }
 
 // Repository providers
 
-func NewWalletRepository(neo4j *database.Neo4jClient, logger *logger.Logger) repository.WalletRepository {
-	return repoImpl.NewNeo4jWalletRepository(neo4j, logger.Logger)
+func NewWalletRepository(neo4j *database.Neo4jClient, aside *cache.Aside, cfg *config.Config, logger *logger.Logger) repository.WalletRepository {
+	walletRepo := repoImpl.NewNeo4jWalletRepository(neo4j, logger.Logger)
+	return repoImpl.NewCachedWalletRepository(walletRepo, aside, &cfg.Cache.TTL, logger.Logger)
 }
 
-func NewTransactionRepository(mongo *database.MongoClient, logger *logger.Logger) repository.TransactionRepository {
-	return repoImpl.NewMongoTransactionRepository(mongo, logger.Logger)
+func NewTransactionRepository(mongo *database.MongoClient, aside *cache.Aside, cfg *config.Config, logger *logger.Logger) repository.TransactionRepository {
+	transactionRepo := repoImpl.NewMongoTransactionRepository(mongo, logger.Logger)
+	return repoImpl.NewCachedTransactionRepository(transactionRepo, aside, &cfg.Cache.TTL, logger.Logger)
 }
 
-func NewNetworkRepository(neo4j *database.Neo4jClient, mongo *database.MongoClient, cfg *config.Config, logger *logger.Logger) repository.NetworkRepository {
-	apiClient := external.NewBlockchainAPIClient(&cfg.External, logger.Logger)
-	return repoImpl.NewNetworkRepository(neo4j, mongo, apiClient, logger.Logger)
+func NewNetworkRepository(neo4j *database.Neo4jClient, mongo *database.MongoClient, aside *cache.Aside, breakers *resilience.Registry, cfg *config.Config, logger *logger.Logger) repository.NetworkRepository {
+	apiClient := external.NewBlockchainAPIClient(&cfg.External, breakers, logger.Logger)
+	networkRepo := repoImpl.NewNetworkRepository(neo4j, mongo, apiClient, logger.Logger)
+	return repoImpl.NewCachedNetworkRepository(networkRepo, aside, &cfg.Cache.TTL, logger.Logger)
 }
 
-func NewWatchListRepository(postgres *database.PostgreSQLClient, logger *logger.Logger) repository.WatchListRepository {
-	return repoImpl.NewPostgreSQLWatchListRepository(postgres, logger.Logger)
+func NewWatchListRepository(postgres *database.PostgreSQLClient, healthManager *health.HealthManager, logger *logger.Logger) repository.WatchListRepository {
+	return repoImpl.NewGuardedWatchListRepository(
+		repoImpl.NewPostgreSQLWatchListRepository(postgres, logger.Logger),
+		func() bool { return healthManager.CapabilityAvailable(health.CapabilityWatchLists) }, logger.Logger)
 }
 
 func NewSecurityRepository(mongo *database.MongoClient, logger *logger.Logger) repository.SecurityRepository {
@@ -119,8 +214,155 @@ func NewCacheRepository(redis *cache.RedisClient, logger *logger.Logger) reposit
 	return repoImpl.NewRedisCacheRepository(redis, logger.Logger)
 }
 
-func NewAIRepository(cfg *config.Config, logger *logger.Logger) repository.AIRepository {
-	return repoImpl.NewOpenAIRepository(&cfg.External, logger.Logger)
+func NewAIRepository(
+	cfg *config.Config,
+	walletRepo repository.WalletRepository,
+	transactionRepo repository.TransactionRepository,
+	securityRepo repository.SecurityRepository,
+	breakers *resilience.Registry,
+	logger *logger.Logger,
+) repository.AIRepository {
+	return repoImpl.NewOpenAIRepository(&cfg.External, walletRepo, transactionRepo, securityRepo, breakers, logger.Logger)
+}
+
+func NewSanctionsRepository(mongo *database.MongoClient, neo4j *database.Neo4jClient, logger *logger.Logger) repository.SanctionsRepository {
+	return repoImpl.NewMongoSanctionsRepository(mongo, neo4j, logger.Logger)
+}
+
+func NewScreeningRepository(mongo *database.MongoClient, logger *logger.Logger) repository.ScreeningRepository {
+	return repoImpl.NewMongoScreeningRepository(mongo, logger.Logger)
+}
+
+func NewAIConversationRepository(postgres *database.PostgreSQLClient, logger *logger.Logger) repository.AIConversationRepository {
+	return repoImpl.NewPostgreSQLAIConversationRepository(postgres, logger.Logger)
+}
+
+func NewAIUsageRepository(postgres *database.PostgreSQLClient, logger *logger.Logger) repository.AIUsageRepository {
+	return repoImpl.NewPostgreSQLAIUsageRepository(postgres, logger.Logger)
+}
+
+func NewCaseRepository(mongo *database.MongoClient, logger *logger.Logger) repository.CaseRepository {
+	return repoImpl.NewMongoCaseRepository(mongo, logger.Logger)
+}
+
+func NewMEVRepository(mongo *database.MongoClient, logger *logger.Logger) repository.MEVRepository {
+	return repoImpl.NewMongoMEVRepository(mongo, logger.Logger)
+}
+
+func NewClassificationRepository(mongo *database.MongoClient, logger *logger.Logger) repository.ClassificationRepository {
+	return repoImpl.NewMongoClassificationRepository(mongo, logger.Logger)
+}
+
+func NewLabelRepository(mongo *database.MongoClient, logger *logger.Logger) repository.LabelRepository {
+	return repoImpl.NewMongoLabelRepository(mongo, logger.Logger)
+}
+
+func NewLabelProposalRepository(mongo *database.MongoClient, logger *logger.Logger) repository.LabelProposalRepository {
+	return repoImpl.NewMongoLabelProposalRepository(mongo, logger.Logger)
+}
+
+// Service providers
+
+func NewSanctionsService(
+	sanctionsRepo repository.SanctionsRepository,
+	securityRepo repository.SecurityRepository,
+	watchListRepo repository.WatchListRepository,
+	cfg *config.Config,
+	logger *logger.Logger,
+) *sanctions.Service {
+	return sanctions.NewService(sanctionsRepo, securityRepo, watchListRepo, &cfg.Compliance, logger.Logger)
+}
+
+func NewScreeningService(
+	walletRepo repository.WalletRepository,
+	screeningRepo repository.ScreeningRepository,
+	sanctionsService *sanctions.Service,
+	cfg *config.Config,
+	logger *logger.Logger,
+) *screening.Service {
+	return screening.NewService(walletRepo, screeningRepo, sanctionsService, &cfg.Compliance, logger.Logger)
+}
+
+func NewComplianceService(
+	transactionRepo repository.TransactionRepository,
+	walletRepo repository.WalletRepository,
+	securityRepo repository.SecurityRepository,
+	sanctionsRepo repository.SanctionsRepository,
+	aiRepo repository.AIRepository,
+	assistantService *assistant.Service,
+	cfg *config.Config,
+	logger *logger.Logger,
+) *compliance.Service {
+	return compliance.NewService(transactionRepo, walletRepo, securityRepo, sanctionsRepo, aiRepo, assistantService, &cfg.Compliance, logger.Logger)
+}
+
+func NewAssistantService(
+	conversationRepo repository.AIConversationRepository,
+	usageRepo repository.AIUsageRepository,
+	userRepo repository.UserRepository,
+	aiRepo repository.AIRepository,
+	cacheRepo repository.CacheRepository,
+	metrics *monitoring.MetricsCollector,
+	cfg *config.Config,
+	logger *logger.Logger,
+) *assistant.Service {
+	return assistant.NewService(conversationRepo, usageRepo, userRepo, aiRepo, cacheRepo, &cfg.External, metrics, logger.Logger)
+}
+
+func NewCasesService(
+	caseRepo repository.CaseRepository,
+	securityRepo repository.SecurityRepository,
+	userRepo repository.UserRepository,
+	cfg *config.Config,
+	logger *logger.Logger,
+) *cases.Service {
+	return cases.NewService(caseRepo, securityRepo, userRepo, &cfg.Compliance, logger.Logger)
+}
+
+func NewDetectionRunner(
+	transactionRepo repository.TransactionRepository,
+	walletRepo repository.WalletRepository,
+	sanctionsRepo repository.SanctionsRepository,
+	securityRepo repository.SecurityRepository,
+	mevRepo repository.MEVRepository,
+	metrics *monitoring.MetricsCollector,
+	cfg *config.Config,
+	logger *logger.Logger,
+) *detection.Runner {
+	return detection.NewRunner(transactionRepo, walletRepo, sanctionsRepo, securityRepo, mevRepo, &cfg.Detection, metrics, logger.Logger)
+}
+
+func NewClassifierService(
+	walletRepo repository.WalletRepository,
+	transactionRepo repository.TransactionRepository,
+	classificationRepo repository.ClassificationRepository,
+	mevRepo repository.MEVRepository,
+	sanctionsRepo repository.SanctionsRepository,
+	securityRepo repository.SecurityRepository,
+	metrics *monitoring.MetricsCollector,
+	cfg *config.Config,
+	logger *logger.Logger,
+) *classification.Service {
+	return classification.NewService(walletRepo, transactionRepo, classificationRepo, mevRepo, sanctionsRepo, securityRepo, &cfg.Classifier, metrics, logger.Logger)
+}
+
+func NewLabelService(
+	labelRepo repository.LabelRepository,
+	labelProposalRepo repository.LabelProposalRepository,
+	walletRepo repository.WalletRepository,
+	cfg *config.Config,
+	logger *logger.Logger,
+) *labels.Service {
+	return labels.NewService(labelRepo, labelProposalRepo, walletRepo, &cfg.Labels, logger.Logger)
+}
+
+func NewSearchService(
+	walletRepo repository.WalletRepository,
+	transactionRepo repository.TransactionRepository,
+	labelRepo repository.LabelRepository,
+	logger *logger.Logger,
+) *search.Service {
+	return search.NewService(walletRepo, transactionRepo, labelRepo, logger.Logger)
 }
 
 // GraphQL resolver provider
@@ -134,6 +376,15 @@ func NewGraphQLResolver(
 	userRepo repository.UserRepository,
 	cacheRepo repository.CacheRepository,
 	aiRepo repository.AIRepository,
+	mevRepo repository.MEVRepository,
+	sanctionsService *sanctions.Service,
+	screeningService *screening.Service,
+	complianceService *compliance.Service,
+	assistantService *assistant.Service,
+	casesService *cases.Service,
+	classifierService *classification.Service,
+	labelService *labels.Service,
+	searchService *search.Service,
 	redis *cache.RedisClient,
 	logger *logger.Logger,
 ) *graph.Resolver {
@@ -146,6 +397,15 @@ func NewGraphQLResolver(
 		userRepo,
 		cacheRepo,
 		aiRepo,
+		mevRepo,
+		sanctionsService,
+		screeningService,
+		complianceService,
+		assistantService,
+		casesService,
+		classifierService,
+		labelService,
+		searchService,
 		redis,
 		logger,
 	)
@@ -160,7 +420,17 @@ func NewContainerStruct(
 	mongo *database.MongoClient,
 	postgres *database.PostgreSQLClient,
 	redis *cache.RedisClient,
+	aside *cache.Aside,
+	tracing *monitoring.Tracing,
+	breakers *resilience.Registry,
+	healthManager *health.HealthManager,
 	resolver *graph.Resolver,
+	detectionRunner *detection.Runner,
+	classifierService *classification.Service,
+	labelService *labels.Service,
+	complianceService *compliance.Service,
+	userRepo repository.UserRepository,
+	walletRepo repository.WalletRepository,
 ) *Container {
 	return &Container{
 		Config:     cfg,
@@ -169,7 +439,40 @@ func NewContainerStruct(
 		MongoDB:    mongo,
 		PostgreSQL: postgres,
 		Redis:      redis,
+		Cache:      aside,
+		Tracing:    tracing,
+		Breakers:   breakers,
+		Health:     healthManager,
 		Resolver:   resolver,
+		Detection:  detectionRunner,
+		Classifier: classifierService,
+		Labels:     labelService,
+		Compliance: complianceService,
+		Users:      userRepo,
+		Wallets:    walletRepo,
+	}
+}
+
+// Close closes the database and cache connections and flushes traces
+func (c *Container) Close(ctx context.Context) {
+	if err := c.Neo4j.Close(ctx); err != nil {
+		c.Logger.Error("Failed to close Neo4j connection", zap.Error(err))
+	}
+
+	if err := c.MongoDB.Close(ctx); err != nil {
+		c.Logger.Error("Failed to close MongoDB connection", zap.Error(err))
+	}
+
+	if err := c.PostgreSQL.Close(); err != nil {
+		c.Logger.Error("Failed to close PostgreSQL connection", zap.Error(err))
+	}
+
+	if err := c.Redis.Close(); err != nil {
+		c.Logger.Error("Failed to close Redis connection", zap.Error(err))
+	}
+
+	if err := c.Tracing.Shutdown(ctx); err != nil {
+		c.Logger.Error("Failed to shutdown tracing", zap.Error(err))
 	}
 }
 
@@ -183,14 +486,28 @@ func RegisterHooks(
 		OnStart: func(ctx context.Context) error {
 			container.Logger.Info("Starting application dependencies")
 
-			// Run database migrations
-			if err := container.PostgreSQL.AutoMigrate(); err != nil {
-				return fmt.Errorf("failed to run migrations: %w", err)
+			// Migrate the reachable datastores; the others are migrated once
+			// they recover, unless every datastore is required
+			var tasks []health.InitTask
+			if container.Config.Database.Migrations.OnStartup {
+				runner := migrations.NewDatastoreRunner(&container.Config.Database.Migrations,
+					container.PostgreSQL, container.MongoDB, container.Neo4j, container.Logger.Logger)
+				tasks = health.DatastoreInitTasks(runner)
+			}
+			if err := container.Health.Initialize(ctx, container.Config.App.RequireDatastores, tasks...); err != nil {
+				return fmt.Errorf("failed to initialize datastores: %w", err)
 			}
 
-			// Create MongoDB indexes
-			if err := container.MongoDB.CreateIndexes(ctx); err != nil {
-				container.Logger.Warn("Failed to create MongoDB indexes", zap.Error(err))
+			container.Health.Start(container.Config.Monitoring.HealthCheckInterval)
+			container.Cache.Start()
+			if container.Config.App.EnableBackgroundJobs && container.Config.Detection.Enabled {
+				container.Detection.Start()
+			}
+			if container.Config.App.EnableBackgroundJobs && container.Config.Classifier.Enabled {
+				container.Classifier.Start()
+			}
+			if container.Config.App.EnableBackgroundJobs {
+				container.Labels.Start()
 			}
 
 			container.Logger.Info("Application dependencies started successfully")
@@ -199,22 +516,14 @@ func RegisterHooks(
 		OnStop: func(ctx context.Context) error {
 			container.Logger.Info("Stopping application dependencies")
 
-			// Close database connections
-			if err := container.Neo4j.Close(ctx); err != nil {
-				container.Logger.Error("Failed to close Neo4j connection", zap.Error(err))
-			}
-
-			if err := container.MongoDB.Close(ctx); err != nil {
-				container.Logger.Error("Failed to close MongoDB connection", zap.Error(err))
-			}
+			container.Detection.Stop()
+			container.Classifier.Stop()
+			container.Labels.Stop()
+			container.Cache.Stop()
+			container.Health.Stop()
 
-			if err := container.PostgreSQL.Close(); err != nil {
-				container.Logger.Error("Failed to close PostgreSQL connection", zap.Error(err))
-			}
-
-			if err := container.Redis.Close(); err != nil {
-				container.Logger.Error("Failed to close Redis connection", zap.Error(err))
-			}
+			// Close database connections
+			container.Close(ctx)
 
 			// Close logger
 			if err := container.Logger.Close(); err != nil {
@@ -0,0 +1,184 @@
+package database
+
+import (
+	"context"
+
+	"crypto-bubble-map-be/internal/infrastructure/resilience"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection is a MongoDB collection whose operations run under the MongoDB
+// circuit breaker. Reads, replacements and deletes are safe to repeat, so
+// they are retried like Execute; inserts and updates may not be, so they are
+// attempted once and left to the driver's own retryable writes.
+type Collection struct {
+	collection *mongo.Collection
+	breaker    *resilience.Breaker
+}
+
+// Name returns the name of the collection
+func (c *Collection) Name() string {
+	return c.collection.Name()
+}
+
+// once runs fn through the breaker without retrying it
+func (c *Collection) once(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := c.breaker.Allow(); err != nil {
+		return err
+	}
+	err := fn(ctx)
+	c.breaker.Record(err)
+	return err
+}
+
+// Find runs a find and returns its cursor
+func (c *Collection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	var cursor *mongo.Cursor
+	err := c.breaker.Execute(ctx, func(ctx context.Context) error {
+		var err error
+		cursor, err = c.collection.Find(ctx, filter, opts...)
+		return err
+	})
+	return cursor, err
+}
+
+// FindOne runs a find for a single document. A missing document is not a
+// failure of MongoDB, so it is returned by Decode as usual.
+func (c *Collection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	var result *mongo.SingleResult
+	err := c.breaker.Execute(ctx, func(ctx context.Context) error {
+		result = c.collection.FindOne(ctx, filter, opts...)
+		return result.Err()
+	})
+	if result == nil {
+		// Rejected by the open breaker before the query ran
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	return result
+}
+
+// Aggregate runs pipeline and returns its cursor
+func (c *Collection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	var cursor *mongo.Cursor
+	err := c.breaker.Execute(ctx, func(ctx context.Context) error {
+		var err error
+		cursor, err = c.collection.Aggregate(ctx, pipeline, opts...)
+		return err
+	})
+	return cursor, err
+}
+
+// CountDocuments counts the documents matching filter
+func (c *Collection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	var count int64
+	err := c.breaker.Execute(ctx, func(ctx context.Context) error {
+		var err error
+		count, err = c.collection.CountDocuments(ctx, filter, opts...)
+		return err
+	})
+	return count, err
+}
+
+// Distinct returns the distinct values of field among the documents matching filter
+func (c *Collection) Distinct(ctx context.Context, field string, filter interface{}, opts ...*options.DistinctOptions) ([]interface{}, error) {
+	var values []interface{}
+	err := c.breaker.Execute(ctx, func(ctx context.Context) error {
+		var err error
+		values, err = c.collection.Distinct(ctx, field, filter, opts...)
+		return err
+	})
+	return values, err
+}
+
+// ReplaceOne replaces the document matching filter
+func (c *Collection) ReplaceOne(ctx context.Context, filter interface{}, replacement interface{}, opts ...*options.ReplaceOptions) (*mongo.UpdateResult, error) {
+	var result *mongo.UpdateResult
+	err := c.breaker.Execute(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = c.collection.ReplaceOne(ctx, filter, replacement, opts...)
+		return err
+	})
+	return result, err
+}
+
+// DeleteOne deletes a document matching filter
+func (c *Collection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	var result *mongo.DeleteResult
+	err := c.breaker.Execute(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = c.collection.DeleteOne(ctx, filter, opts...)
+		return err
+	})
+	return result, err
+}
+
+// InsertOne inserts a document
+func (c *Collection) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	var result *mongo.InsertOneResult
+	err := c.once(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = c.collection.InsertOne(ctx, document, opts...)
+		return err
+	})
+	return result, err
+}
+
+// InsertMany inserts documents
+func (c *Collection) InsertMany(ctx context.Context, documents []interface{}, opts ...*options.InsertManyOptions) (*mongo.InsertManyResult, error) {
+	var result *mongo.InsertManyResult
+	err := c.once(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = c.collection.InsertMany(ctx, documents, opts...)
+		return err
+	})
+	return result, err
+}
+
+// UpdateOne updates a document matching filter
+func (c *Collection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	var result *mongo.UpdateResult
+	err := c.once(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = c.collection.UpdateOne(ctx, filter, update, opts...)
+		return err
+	})
+	return result, err
+}
+
+// BulkWrite runs models as one bulk write
+func (c *Collection) BulkWrite(ctx context.Context, models []mongo.WriteModel, opts ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error) {
+	var result *mongo.BulkWriteResult
+	err := c.once(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = c.collection.BulkWrite(ctx, models, opts...)
+		return err
+	})
+	return result, err
+}
+
+// UpdateMany updates every document matching filter
+func (c *Collection) UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	var result *mongo.UpdateResult
+	err := c.once(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = c.collection.UpdateMany(ctx, filter, update, opts...)
+		return err
+	})
+	return result, err
+}
+
+// FindOneAndUpdate updates a document matching filter and returns it
+func (c *Collection) FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult {
+	var result *mongo.SingleResult
+	err := c.once(ctx, func(ctx context.Context) error {
+		result = c.collection.FindOneAndUpdate(ctx, filter, update, opts...)
+		return result.Err()
+	})
+	if result == nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	return result
+}
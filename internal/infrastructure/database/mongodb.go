@@ -2,34 +2,51 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"crypto-bubble-map-be/internal/infrastructure/config"
+	"crypto-bubble-map-be/internal/infrastructure/monitoring"
+	"crypto-bubble-map-be/internal/infrastructure/resilience"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 	"go.uber.org/zap"
 )
 
+// AddressCollation compares strings case-insensitively, so that a normalized
+// address matches transactions stored with checksummed addresses. Queries using
+// it are served by the indexes created with the same collation.
+var AddressCollation = &options.Collation{Locale: "en", Strength: 2}
+
 // MongoClient wraps the MongoDB client
 type MongoClient struct {
 	client   *mongo.Client
 	database *mongo.Database
+	breaker  *resilience.Breaker
 	logger   *zap.Logger
 	config   *config.MongoDBConfig
 }
 
-// NewMongoClient creates a new MongoDB client
-func NewMongoClient(cfg *config.MongoDBConfig, logger *zap.Logger) (*MongoClient, error) {
+// NewMongoClient creates a new MongoDB client whose queries are guarded and
+// retried by the MongoDB circuit breaker from breakers
+func NewMongoClient(cfg *config.MongoDBConfig, monitor *monitoring.PerformanceMonitor, breakers *resilience.Registry, logger *zap.Logger) (*MongoClient, error) {
 	// Configure client options
 	clientOptions := options.Client().
 		ApplyURI(cfg.URI).
 		SetMaxPoolSize(cfg.MaxPoolSize).
 		SetMinPoolSize(cfg.MinPoolSize).
 		SetConnectTimeout(cfg.ConnectionTimeout).
-		SetSocketTimeout(cfg.SocketTimeout)
+		SetSocketTimeout(cfg.SocketTimeout).
+		// Spans and latency metrics per command; spans are no-ops until
+		// tracing is enabled
+		SetMonitor(newCommandMonitor(otelmongo.NewMonitor(), monitor))
 
 	// Create client
 	client, err := mongo.Connect(context.Background(), clientOptions)
@@ -37,24 +54,19 @@ func NewMongoClient(cfg *config.MongoDBConfig, logger *zap.Logger) (*MongoClient
 		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
 	}
 
-	// Test connection
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	if err := client.Ping(ctx, nil); err != nil {
-		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
-	}
-
+	// Connect does not wait for the server; connectivity is verified by the
+	// health manager, so that the server can start while MongoDB is unreachable
 	database := client.Database(cfg.Database)
 
 	mongoClient := &MongoClient{
 		client:   client,
 		database: database,
+		breaker:  breakers.Breaker(resilience.DependencyMongoDB, isMongoFailure),
 		logger:   logger,
 		config:   cfg,
 	}
 
-	logger.Info("MongoDB client initialized successfully",
+	logger.Info("MongoDB client initialized",
 		zap.String("uri", cfg.URI),
 		zap.String("database", cfg.Database),
 	)
@@ -62,6 +74,44 @@ func NewMongoClient(cfg *config.MongoDBConfig, logger *zap.Logger) (*MongoClient
 	return mongoClient, nil
 }
 
+// newCommandMonitor extends the tracing monitor to track command latency by
+// command and collection, e.g. "find wallets"
+func newCommandMonitor(tracing *event.CommandMonitor, monitor *monitoring.PerformanceMonitor) *event.CommandMonitor {
+	if monitor == nil {
+		return tracing
+	}
+
+	// Finished events do not carry the command, so remember its collection
+	var collections sync.Map
+	track := func(finished event.CommandFinishedEvent, err error) {
+		name := finished.CommandName
+		if collection, ok := collections.LoadAndDelete(finished.RequestID); ok {
+			name += " " + collection.(string)
+		}
+		monitor.TrackDatabaseOperation("mongodb", name, finished.Duration, err)
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, started *event.CommandStartedEvent) {
+			tracing.Started(ctx, started)
+			// Collection commands name their collection in the first element
+			if element, err := started.Command.IndexErr(0); err == nil {
+				if collection, ok := element.Value().StringValueOK(); ok {
+					collections.Store(started.RequestID, collection)
+				}
+			}
+		},
+		Succeeded: func(ctx context.Context, succeeded *event.CommandSucceededEvent) {
+			tracing.Succeeded(ctx, succeeded)
+			track(succeeded.CommandFinishedEvent, nil)
+		},
+		Failed: func(ctx context.Context, failed *event.CommandFailedEvent) {
+			tracing.Failed(ctx, failed)
+			track(failed.CommandFinishedEvent, errors.New(failed.Failure))
+		},
+	}
+}
+
 // Close closes the MongoDB connection
 func (c *MongoClient) Close(ctx context.Context) error {
 	return c.client.Disconnect(ctx)
@@ -72,9 +122,53 @@ func (c *MongoClient) GetDatabase() *mongo.Database {
 	return c.database
 }
 
-// GetCollection returns a collection instance
-func (c *MongoClient) GetCollection(name string) *mongo.Collection {
-	return c.database.Collection(name)
+// GetCollection returns a collection whose operations run under the MongoDB
+// circuit breaker
+func (c *MongoClient) GetCollection(name string) *Collection {
+	return &Collection{collection: c.database.Collection(name), breaker: c.breaker}
+}
+
+// Execute runs fn under the MongoDB circuit breaker, retrying it with
+// backoff while MongoDB is unreachable or timing out. fn must be safe to
+// repeat, e.g. a read or an idempotent upsert.
+func (c *MongoClient) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	return c.breaker.Execute(ctx, fn)
+}
+
+// findAll runs a find on collection and decodes every document into results
+func (c *MongoClient) findAll(ctx context.Context, collection *Collection, filter interface{}, opts *options.FindOptions, results interface{}) error {
+	return c.Execute(ctx, func(ctx context.Context) error {
+		cursor, err := collection.collection.Find(ctx, filter, opts)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+		return cursor.All(ctx, results)
+	})
+}
+
+// aggregateAll runs pipeline on collection and decodes every document into
+// results
+func (c *MongoClient) aggregateAll(ctx context.Context, collection *Collection, pipeline interface{}, results interface{}) error {
+	return c.Execute(ctx, func(ctx context.Context) error {
+		cursor, err := collection.collection.Aggregate(ctx, pipeline)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+		return cursor.All(ctx, results)
+	})
+}
+
+// isMongoFailure reports whether err means MongoDB is unreachable or timing
+// out, rather than e.g. a duplicate key or a missing document
+func isMongoFailure(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, mongo.ErrNoDocuments) {
+		return false
+	}
+	var selectionErr topology.ServerSelectionError
+	return mongo.IsNetworkError(err) || mongo.IsTimeout(err) ||
+		errors.As(err, &selectionErr) || errors.Is(err, mongo.ErrClientDisconnected)
 }
 
 // GetTransactions retrieves transactions from MongoDB
@@ -86,16 +180,9 @@ func (c *MongoClient) GetTransactions(ctx context.Context, filter bson.M, limit
 		SetSkip(skip).
 		SetSort(bson.D{{Key: "crawled_at", Value: -1}})
 
-	cursor, err := collection.Find(ctx, filter, findOptions)
-	if err != nil {
-		c.logger.Error("Failed to find transactions", zap.Error(err))
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
 	var transactions []bson.M
-	if err := cursor.All(ctx, &transactions); err != nil {
-		c.logger.Error("Failed to decode transactions", zap.Error(err))
+	if err := c.findAll(ctx, collection, filter, findOptions, &transactions); err != nil {
+		c.logger.Error("Failed to find transactions", zap.Error(err))
 		return nil, err
 	}
 
@@ -124,8 +211,8 @@ func (c *MongoClient) GetPairwiseTransactions(ctx context.Context, walletA, wall
 		SetSkip(skip).
 		SetSort(bson.D{{Key: "crawled_at", Value: -1}})
 
-	cursor, err := collection.Find(ctx, filter, findOptions)
-	if err != nil {
+	var transactions []bson.M
+	if err := c.findAll(ctx, collection, filter, findOptions, &transactions); err != nil {
 		c.logger.Error("Failed to find pairwise transactions",
 			zap.String("walletA", walletA),
 			zap.String("walletB", walletB),
@@ -133,18 +220,12 @@ func (c *MongoClient) GetPairwiseTransactions(ctx context.Context, walletA, wall
 		)
 		return nil, err
 	}
-	defer cursor.Close(ctx)
-
-	var transactions []bson.M
-	if err := cursor.All(ctx, &transactions); err != nil {
-		c.logger.Error("Failed to decode pairwise transactions", zap.Error(err))
-		return nil, err
-	}
 
 	return transactions, nil
 }
 
-// GetTransactionsByWallet retrieves transactions for a specific wallet
+// GetTransactionsByWallet retrieves transactions for a specific wallet,
+// matching its address case-insensitively
 func (c *MongoClient) GetTransactionsByWallet(ctx context.Context, walletAddress string, limit int64, skip int64) ([]bson.M, error) {
 	collection := c.GetCollection("transactions")
 
@@ -158,23 +239,17 @@ func (c *MongoClient) GetTransactionsByWallet(ctx context.Context, walletAddress
 	findOptions := options.Find().
 		SetLimit(limit).
 		SetSkip(skip).
-		SetSort(bson.D{{Key: "crawled_at", Value: -1}})
+		SetSort(bson.D{{Key: "crawled_at", Value: -1}}).
+		SetCollation(AddressCollation)
 
-	cursor, err := collection.Find(ctx, filter, findOptions)
-	if err != nil {
+	var transactions []bson.M
+	if err := c.findAll(ctx, collection, filter, findOptions, &transactions); err != nil {
 		c.logger.Error("Failed to find wallet transactions",
 			zap.String("wallet", walletAddress),
 			zap.Error(err),
 		)
 		return nil, err
 	}
-	defer cursor.Close(ctx)
-
-	var transactions []bson.M
-	if err := cursor.All(ctx, &transactions); err != nil {
-		c.logger.Error("Failed to decode wallet transactions", zap.Error(err))
-		return nil, err
-	}
 
 	return transactions, nil
 }
@@ -240,8 +315,8 @@ func (c *MongoClient) GetMoneyFlowData(ctx context.Context, walletAddress string
 		{"$limit": limit},
 	}
 
-	cursor, err := collection.Aggregate(ctx, pipeline)
-	if err != nil {
+	var results []bson.M
+	if err := c.aggregateAll(ctx, collection, pipeline, &results); err != nil {
 		c.logger.Error("Failed to aggregate money flow data",
 			zap.String("wallet", walletAddress),
 			zap.String("flowType", flowType),
@@ -249,13 +324,6 @@ func (c *MongoClient) GetMoneyFlowData(ctx context.Context, walletAddress string
 		)
 		return nil, err
 	}
-	defer cursor.Close(ctx)
-
-	var results []bson.M
-	if err := cursor.All(ctx, &results); err != nil {
-		c.logger.Error("Failed to decode money flow data", zap.Error(err))
-		return nil, err
-	}
 
 	return results, nil
 }
@@ -293,19 +361,15 @@ func (c *MongoClient) GetTransactionStats(ctx context.Context, timeRange *TimeRa
 		},
 	}
 
-	cursor, err := collection.Aggregate(ctx, pipeline)
-	if err != nil {
+	var results []bson.M
+	if err := c.aggregateAll(ctx, collection, pipeline, &results); err != nil {
 		c.logger.Error("Failed to get transaction stats", zap.Error(err))
 		return nil, err
 	}
-	defer cursor.Close(ctx)
 
 	var result bson.M
-	if cursor.Next(ctx) {
-		if err := cursor.Decode(&result); err != nil {
-			c.logger.Error("Failed to decode transaction stats", zap.Error(err))
-			return nil, err
-		}
+	if len(results) > 0 {
+		result = results[0]
 	}
 
 	return result, nil
@@ -337,16 +401,9 @@ func (c *MongoClient) GetTopTokens(ctx context.Context, limit int64) ([]bson.M,
 		{"$limit": limit},
 	}
 
-	cursor, err := collection.Aggregate(ctx, pipeline)
-	if err != nil {
-		c.logger.Error("Failed to get top tokens", zap.Error(err))
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
 	var tokens []bson.M
-	if err := cursor.All(ctx, &tokens); err != nil {
-		c.logger.Error("Failed to decode top tokens", zap.Error(err))
+	if err := c.aggregateAll(ctx, collection, pipeline, &tokens); err != nil {
+		c.logger.Error("Failed to get top tokens", zap.Error(err))
 		return nil, err
 	}
 
@@ -374,103 +431,20 @@ func (c *MongoClient) GetRecentActivity(ctx context.Context, limit int64) ([]bso
 		},
 	}
 
-	cursor, err := collection.Aggregate(ctx, pipeline)
-	if err != nil {
-		c.logger.Error("Failed to get recent activity", zap.Error(err))
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
 	var activities []bson.M
-	if err := cursor.All(ctx, &activities); err != nil {
-		c.logger.Error("Failed to decode recent activity", zap.Error(err))
+	if err := c.aggregateAll(ctx, collection, pipeline, &activities); err != nil {
+		c.logger.Error("Failed to get recent activity", zap.Error(err))
 		return nil, err
 	}
 
 	return activities, nil
 }
 
-// SearchTransactions searches transactions by hash or address
-func (c *MongoClient) SearchTransactions(ctx context.Context, query string, limit int64) ([]bson.M, error) {
-	collection := c.GetCollection("transactions")
-
-	filter := bson.M{
-		"$or": []bson.M{
-			{"hash": bson.M{"$regex": query, "$options": "i"}},
-			{"from": bson.M{"$regex": query, "$options": "i"}},
-			{"to": bson.M{"$regex": query, "$options": "i"}},
-		},
-	}
-
-	findOptions := options.Find().
-		SetLimit(limit).
-		SetSort(bson.D{{Key: "crawled_at", Value: -1}})
-
-	cursor, err := collection.Find(ctx, filter, findOptions)
-	if err != nil {
-		c.logger.Error("Failed to search transactions",
-			zap.String("query", query),
-			zap.Error(err),
-		)
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var transactions []bson.M
-	if err := cursor.All(ctx, &transactions); err != nil {
-		c.logger.Error("Failed to decode search results", zap.Error(err))
-		return nil, err
-	}
-
-	return transactions, nil
-}
-
 // Health checks the health of the MongoDB connection
 func (c *MongoClient) Health(ctx context.Context) error {
 	return c.client.Ping(ctx, nil)
 }
 
-// CreateIndexes creates necessary indexes for optimal performance
-func (c *MongoClient) CreateIndexes(ctx context.Context) error {
-	// Transaction indexes
-	transactionCollection := c.GetCollection("transactions")
-
-	indexes := []mongo.IndexModel{
-		{
-			Keys:    bson.D{{Key: "hash", Value: 1}},
-			Options: options.Index().SetUnique(true),
-		},
-		{
-			Keys: bson.D{{Key: "from", Value: 1}},
-		},
-		{
-			Keys: bson.D{{Key: "to", Value: 1}},
-		},
-		{
-			Keys: bson.D{{Key: "crawled_at", Value: -1}},
-		},
-		{
-			Keys: bson.D{{Key: "block_number", Value: 1}},
-		},
-		{
-			Keys: bson.D{
-				{Key: "from", Value: 1},
-				{Key: "to", Value: 1},
-				{Key: "crawled_at", Value: -1},
-			},
-		},
-	}
-
-	_, err := transactionCollection.Indexes().CreateMany(ctx, indexes)
-	if err != nil {
-		c.logger.Error("Failed to create transaction indexes", zap.Error(err))
-		return err
-	}
-
-	c.logger.Info("MongoDB indexes created successfully")
-	return nil
-}
-
 // TimeRange represents a time range for queries
 type TimeRange struct {
 	Start time.Time `json:"start"`
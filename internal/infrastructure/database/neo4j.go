@@ -2,24 +2,37 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"runtime"
+	"strings"
 	"time"
 
 	"crypto-bubble-map-be/internal/infrastructure/config"
+	"crypto-bubble-map-be/internal/infrastructure/monitoring"
+	"crypto-bubble-map-be/internal/infrastructure/resilience"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	neo4jlog "github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 // Neo4jClient wraps the Neo4j driver
 type Neo4jClient struct {
-	driver neo4j.DriverWithContext
-	logger *zap.Logger
-	config *config.Neo4jConfig
+	driver  neo4j.DriverWithContext
+	breaker *resilience.Breaker
+	monitor *monitoring.PerformanceMonitor
+	logger  *zap.Logger
+	config  *config.Neo4jConfig
 }
 
-// NewNeo4jClient creates a new Neo4j client
-func NewNeo4jClient(cfg *config.Neo4jConfig, logger *zap.Logger) (*Neo4jClient, error) {
+// NewNeo4jClient creates a new Neo4j client. Transactions are guarded by the
+// Neo4j circuit breaker from breakers; retrying them is left to the driver,
+// which backs off exponentially for up to MaxTransactionRetryTime.
+func NewNeo4jClient(cfg *config.Neo4jConfig, monitor *monitoring.PerformanceMonitor, breakers *resilience.Registry, logger *zap.Logger) (*Neo4jClient, error) {
 	// Configure authentication
 	auth := neo4j.BasicAuth(cfg.Username, cfg.Password, "")
 
@@ -31,7 +44,7 @@ func NewNeo4jClient(cfg *config.Neo4jConfig, logger *zap.Logger) (*Neo4jClient,
 			config.MaxConnectionPoolSize = cfg.MaxConnectionPoolSize
 			config.ConnectionAcquisitionTimeout = cfg.ConnectionTimeout
 			config.MaxTransactionRetryTime = cfg.MaxTransactionRetryTime
-			config.Log = neo4j.ConsoleLogger(neo4j.INFO)
+			config.Log = &driverLogger{logger: logger.Named("neo4j")}
 		},
 	)
 	if err != nil {
@@ -39,20 +52,16 @@ func NewNeo4jClient(cfg *config.Neo4jConfig, logger *zap.Logger) (*Neo4jClient,
 	}
 
 	client := &Neo4jClient{
-		driver: driver,
-		logger: logger,
-		config: cfg,
+		driver:  driver,
+		breaker: breakers.Breaker(resilience.DependencyNeo4j, isNeo4jFailure),
+		monitor: monitor,
+		logger:  logger,
+		config:  cfg,
 	}
 
-	// Test connection
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	if err := client.VerifyConnectivity(ctx); err != nil {
-		return nil, fmt.Errorf("failed to verify Neo4j connectivity: %w", err)
-	}
-
-	logger.Info("Neo4j client initialized successfully",
+	// Connectivity is verified by the health manager, so that the server can
+	// start while Neo4j is unreachable
+	logger.Info("Neo4j client initialized",
 		zap.String("uri", cfg.URI),
 		zap.String("database", cfg.Database),
 	)
@@ -70,26 +79,228 @@ func (c *Neo4jClient) Close(ctx context.Context) error {
 	return c.driver.Close(ctx)
 }
 
-// ExecuteRead executes a read transaction
+// ExecuteRead executes a read transaction. Its latency is tracked under the
+// name of the calling function, e.g. Neo4jClient.GetWalletNetwork.
 func (c *Neo4jClient) ExecuteRead(ctx context.Context, work neo4j.ManagedTransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	queryName, start := callerName(), time.Now()
+	if err := c.breaker.Allow(); err != nil {
+		return nil, err
+	}
+
 	session := c.driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: c.config.Database,
 		AccessMode:   neo4j.AccessModeRead,
 	})
 	defer session.Close(ctx)
 
-	return session.ExecuteRead(ctx, work, configurers...)
+	ctx, span := startNeo4jSpan(ctx, "neo4j.ExecuteRead", c.config.Database, attribute.String("db.query_name", queryName))
+	defer span.End()
+
+	result, err := session.ExecuteRead(ctx, c.traced(ctx, work), configurers...)
+	c.breaker.Record(err)
+	recordSpanError(span, err)
+	c.track(queryName, start, err)
+	return result, err
 }
 
-// ExecuteWrite executes a write transaction
+// ExecuteWrite executes a write transaction, tracked like ExecuteRead
 func (c *Neo4jClient) ExecuteWrite(ctx context.Context, work neo4j.ManagedTransactionWork, configurers ...func(*neo4j.TransactionConfig)) (interface{}, error) {
+	queryName, start := callerName(), time.Now()
+	if err := c.breaker.Allow(); err != nil {
+		return nil, err
+	}
+
 	session := c.driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: c.config.Database,
 		AccessMode:   neo4j.AccessModeWrite,
 	})
 	defer session.Close(ctx)
 
-	return session.ExecuteWrite(ctx, work, configurers...)
+	ctx, span := startNeo4jSpan(ctx, "neo4j.ExecuteWrite", c.config.Database, attribute.String("db.query_name", queryName))
+	defer span.End()
+
+	result, err := session.ExecuteWrite(ctx, c.traced(ctx, work), configurers...)
+	c.breaker.Record(err)
+	recordSpanError(span, err)
+	c.track(queryName, start, err)
+	return result, err
+}
+
+func (c *Neo4jClient) track(queryName string, start time.Time, err error) {
+	if c.monitor != nil {
+		c.monitor.TrackDatabaseOperation("neo4j", queryName, time.Since(start), err)
+	}
+}
+
+// isNeo4jFailure reports whether err means Neo4j is unreachable or
+// overloaded, rather than e.g. a query being invalid
+func isNeo4jFailure(err error) bool {
+	var (
+		connectivityErr *neo4j.ConnectivityError
+		retryLimitErr   *neo4j.TransactionExecutionLimit
+	)
+	switch {
+	case errors.Is(err, context.Canceled):
+		return false
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &connectivityErr), errors.As(err, &retryLimitErr):
+		return true
+	default:
+		return neo4j.IsRetryable(err)
+	}
+}
+
+// callerName names the function that called the caller of callerName, e.g.
+// "Neo4jClient.GetWalletNetwork", so that query metrics can be
+// broken down without every repository method naming its queries
+func callerName() string {
+	pc, _, _, ok := runtime.Caller(2)
+	if !ok {
+		return "unknown"
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return "unknown"
+	}
+
+	name := fn.Name()
+	// Drop the package path and name
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	// Queries run from closures are named after the enclosing function
+	if i := strings.Index(name, ".func"); i >= 0 {
+		name = name[:i]
+	}
+	return strings.NewReplacer("(*", "", ")", "").Replace(name)
+}
+
+// traced wraps work so that each query it runs gets a span under ctx's. The
+// spans of results that work left unconsumed end when work returns.
+func (c *Neo4jClient) traced(ctx context.Context, work neo4j.ManagedTransactionWork) neo4j.ManagedTransactionWork {
+	return func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		traced := &tracedTransaction{ManagedTransaction: tx, ctx: ctx, database: c.config.Database}
+		defer traced.endResults()
+		return work(traced)
+	}
+}
+
+// tracedTransaction records a span for every Cypher query run in a managed
+// transaction. Queries are parented to the transaction's span rather than the
+// context passed to Run, which is usually the caller's.
+type tracedTransaction struct {
+	neo4j.ManagedTransaction
+	ctx      context.Context
+	database string
+	results  []*tracedResult
+}
+
+// Run runs a Cypher query in a span that ends once its result is consumed
+func (t *tracedTransaction) Run(ctx context.Context, cypher string, params map[string]any) (neo4j.ResultWithContext, error) {
+	_, span := startNeo4jSpan(t.ctx, "neo4j.Run", t.database,
+		attribute.String("db.statement", strings.TrimSpace(cypher)),
+		attribute.String("db.operation", cypherOperation(cypher)))
+
+	result, err := t.ManagedTransaction.Run(ctx, cypher, params)
+	if err != nil {
+		recordSpanError(span, err)
+		span.End()
+		return nil, err
+	}
+
+	traced := &tracedResult{ResultWithContext: result, span: span}
+	t.results = append(t.results, traced)
+	return traced, nil
+}
+
+// endResults ends the spans of results that were never consumed
+func (t *tracedTransaction) endResults() {
+	for _, result := range t.results {
+		result.end(nil)
+	}
+}
+
+// tracedResult ends its query's span when the result is exhausted, collected
+// or consumed, so that the span covers streaming the records as well
+type tracedResult struct {
+	neo4j.ResultWithContext
+	span  trace.Span
+	ended bool
+}
+
+func (r *tracedResult) end(err error) {
+	if r.ended {
+		return
+	}
+	r.ended = true
+	recordSpanError(r.span, err)
+	r.span.End()
+}
+
+// Next advances to the next record, ending the span after the last one
+func (r *tracedResult) Next(ctx context.Context) bool {
+	if r.ResultWithContext.Next(ctx) {
+		return true
+	}
+	r.end(r.Err())
+	return false
+}
+
+// NextRecord advances to the next record, ending the span after the last one
+func (r *tracedResult) NextRecord(ctx context.Context, record **neo4j.Record) bool {
+	if r.ResultWithContext.NextRecord(ctx, record) {
+		return true
+	}
+	r.end(r.Err())
+	return false
+}
+
+// Collect fetches the remaining records and ends the span
+func (r *tracedResult) Collect(ctx context.Context) ([]*neo4j.Record, error) {
+	records, err := r.ResultWithContext.Collect(ctx)
+	r.end(err)
+	return records, err
+}
+
+// Single fetches the only remaining record and ends the span
+func (r *tracedResult) Single(ctx context.Context) (*neo4j.Record, error) {
+	record, err := r.ResultWithContext.Single(ctx)
+	r.end(err)
+	return record, err
+}
+
+// Consume discards the remaining records and ends the span
+func (r *tracedResult) Consume(ctx context.Context) (neo4j.ResultSummary, error) {
+	summary, err := r.ResultWithContext.Consume(ctx)
+	r.end(err)
+	return summary, err
+}
+
+func startNeo4jSpan(ctx context.Context, name, database string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		attribute.String("db.system", "neo4j"),
+		attribute.String("db.name", database))
+	return monitoring.Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+}
+
+func recordSpanError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// cypherOperation returns the leading clause of a query, e.g. MATCH or MERGE
+func cypherOperation(cypher string) string {
+	fields := strings.Fields(cypher)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
 }
 
 // NewSession creates a new Neo4j session
@@ -158,7 +369,8 @@ func (c *Neo4jClient) GetWalletNetwork(ctx context.Context, address string, dept
 	return result.([]map[string]interface{}), nil
 }
 
-// GetWalletInfo retrieves detailed information about a wallet
+// GetWalletInfo retrieves detailed information about a wallet, or nil if there
+// is no wallet with address
 func (c *Neo4jClient) GetWalletInfo(ctx context.Context, address string) (map[string]interface{}, error) {
 	query := `
 		MATCH (w:Wallet {address: $address})
@@ -167,11 +379,14 @@ func (c *Neo4jClient) GetWalletInfo(ctx context.Context, address string) (map[st
 			 sum(r.total_value) as total_volume,
 			 sum(r.tx_count) as total_transactions
 		RETURN w.address as address,
+			   w.label as label,
 			   w.node_type as wallet_type,
 			   w.risk_level as risk_level,
 			   w.confidence_score as confidence_score,
 			   w.total_transactions as transaction_count,
 			   w.balance as balance,
+			   w.is_contract as is_contract,
+			   w.last_classified as last_classified,
 			   w.first_seen as first_seen,
 			   w.last_seen as last_seen,
 			   w.tags as tags,
@@ -190,12 +405,15 @@ func (c *Neo4jClient) GetWalletInfo(ctx context.Context, address string) (map[st
 			return nil, err
 		}
 
-		record, err := result.Single(ctx)
+		records, err := result.Collect(ctx)
 		if err != nil {
 			return nil, err
 		}
+		if len(records) == 0 {
+			return map[string]interface{}(nil), nil
+		}
 
-		return record.AsMap(), nil
+		return records[0].AsMap(), nil
 	})
 
 	if err != nil {
@@ -322,38 +540,92 @@ func (c *Neo4jClient) GetNetworkStats(ctx context.Context, networkID string) (ma
 	return result.(map[string]interface{}), nil
 }
 
-// SearchWallets searches for wallets by address or label
-func (c *Neo4jClient) SearchWallets(ctx context.Context, query string, limit int) ([]map[string]interface{}, error) {
-	cypherQuery := `
-		MATCH (w:Wallet)
-		WHERE w.address CONTAINS $query
-		   OR any(tag in w.tags WHERE tag CONTAINS $query)
-		   OR w.label CONTAINS $query
+// WalletSearchIndex is the full-text index over wallet labels, ENS names and tags
+const WalletSearchIndex = "wallet_search"
+
+// walletSearchReturn is the RETURN clause shared by the wallet search lookups;
+// it expects the matched wallet as w and a relevance score as score
+const walletSearchReturn = `
 		RETURN w.address as address,
 			   w.label as label,
+			   w.label_entity as label_entity,
+			   w.label_category as label_category,
+			   w.label_source as label_source,
+			   w.label_confidence as label_confidence,
+			   w.ens_name as ens_name,
 			   w.node_type as wallet_type,
 			   w.risk_level as risk_level,
-			   w.confidence_score as confidence_score,
 			   w.total_transactions as transaction_count,
 			   w.balance as balance,
-			   w.tags as tags
-		ORDER BY
-			CASE
-				WHEN w.address = $query THEN 1
-				WHEN w.address STARTS WITH $query THEN 2
-				WHEN w.label = $query THEN 3
-				WHEN w.label STARTS WITH $query THEN 4
-				ELSE 5
-			END,
-			w.confidence_score DESC
+			   w.tags as tags,
+			   score
+`
+
+// FindWalletsByAddress returns the wallets with exactly one of the addresses
+func (c *Neo4jClient) FindWalletsByAddress(ctx context.Context, addresses []string) ([]map[string]interface{}, error) {
+	query := `
+		MATCH (w:Wallet)
+		WHERE w.address IN $addresses
+		WITH w, 1.0 as score
+	` + walletSearchReturn
+
+	return c.searchWallets(ctx, "address", query, map[string]interface{}{
+		"addresses": addresses,
+	})
+}
+
+// SearchWalletsByAddressPrefix returns wallets whose address starts with one of
+// the prefixes, shortest address first. STARTS WITH is served by the address index.
+func (c *Neo4jClient) SearchWalletsByAddressPrefix(ctx context.Context, prefixes []string, limit int) ([]map[string]interface{}, error) {
+	query := `
+		UNWIND $prefixes as prefix
+		MATCH (w:Wallet)
+		WHERE w.address STARTS WITH prefix
+		WITH DISTINCT w, 1.0 as score
+	` + walletSearchReturn + `
+		ORDER BY size(address), address
 		LIMIT $limit
 	`
 
+	return c.searchWallets(ctx, "address prefix", query, map[string]interface{}{
+		"prefixes": prefixes,
+		"limit":    limit,
+	})
+}
+
+// FindWalletsByENSName returns the wallets with the ENS name
+func (c *Neo4jClient) FindWalletsByENSName(ctx context.Context, name string) ([]map[string]interface{}, error) {
+	query := `
+		MATCH (w:Wallet)
+		WHERE w.ens_name = $name
+		WITH w, 1.0 as score
+	` + walletSearchReturn
+
+	return c.searchWallets(ctx, "ENS name", query, map[string]interface{}{
+		"name": name,
+	})
+}
+
+// SearchWalletsFullText queries the wallet full-text index with a Lucene query,
+// returning the index's score for each wallet, best first
+func (c *Neo4jClient) SearchWalletsFullText(ctx context.Context, luceneQuery string, limit int) ([]map[string]interface{}, error) {
+	query := `
+		CALL db.index.fulltext.queryNodes($index, $query, {limit: $limit})
+		YIELD node as w, score
+	` + walletSearchReturn + `
+		ORDER BY score DESC
+	`
+
+	return c.searchWallets(ctx, "full-text", query, map[string]interface{}{
+		"index": WalletSearchIndex,
+		"query": luceneQuery,
+		"limit": limit,
+	})
+}
+
+func (c *Neo4jClient) searchWallets(ctx context.Context, lookup, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
 	result, err := c.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
-		result, err := tx.Run(ctx, cypherQuery, map[string]interface{}{
-			"query": query,
-			"limit": limit,
-		})
+		result, err := tx.Run(ctx, query, params)
 		if err != nil {
 			return nil, err
 		}
@@ -363,7 +635,7 @@ func (c *Neo4jClient) SearchWallets(ctx context.Context, query string, limit int
 			return nil, err
 		}
 
-		var wallets []map[string]interface{}
+		wallets := make([]map[string]interface{}, 0, len(records))
 		for _, record := range records {
 			wallets = append(wallets, record.AsMap())
 		}
@@ -373,8 +645,297 @@ func (c *Neo4jClient) SearchWallets(ctx context.Context, query string, limit int
 
 	if err != nil {
 		c.logger.Error("Failed to search wallets",
-			zap.String("query", query),
-			zap.Int("limit", limit),
+			zap.String("lookup", lookup),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	return result.([]map[string]interface{}), nil
+}
+
+// SetSanctionedWallets replaces the set of wallets flagged by a sanctions source.
+// Wallets keep a list of the sources that designate them in sanctions_sources.
+func (c *Neo4jClient) SetSanctionedWallets(ctx context.Context, source string, addresses []string) (int64, error) {
+	clearQuery := `
+		MATCH (w:Wallet)
+		WHERE $source IN coalesce(w.sanctions_sources, [])
+		SET w.sanctions_sources = [s IN w.sanctions_sources WHERE s <> $source]
+	`
+
+	flagQuery := `
+		MATCH (w:Wallet)
+		WHERE w.address IN $addresses
+		SET w.sanctions_sources = [s IN coalesce(w.sanctions_sources, []) WHERE s <> $source] + $source
+		RETURN count(w) as flagged
+	`
+
+	result, err := c.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		if _, err := tx.Run(ctx, clearQuery, map[string]interface{}{"source": source}); err != nil {
+			return nil, err
+		}
+
+		result, err := tx.Run(ctx, flagQuery, map[string]interface{}{
+			"source":    source,
+			"addresses": addresses,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		record, err := result.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		flagged, _ := record.Get("flagged")
+		return flagged, nil
+	})
+
+	if err != nil {
+		c.logger.Error("Failed to set sanctioned wallets",
+			zap.String("source", source),
+			zap.Int("addresses", len(addresses)),
+			zap.Error(err),
+		)
+		return 0, err
+	}
+
+	flagged, _ := result.(int64)
+	return flagged, nil
+}
+
+// SetWalletType sets the node_type of the wallets among addresses, returning how many were updated
+func (c *Neo4jClient) SetWalletType(ctx context.Context, addresses []string, walletType string) (int64, error) {
+	query := `
+		MATCH (w:Wallet)
+		WHERE w.address IN $addresses
+		SET w.node_type = $walletType
+		RETURN count(w) as updated
+	`
+
+	result, err := c.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		result, err := tx.Run(ctx, query, map[string]interface{}{
+			"addresses":  addresses,
+			"walletType": walletType,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		record, err := result.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		updated, _ := record.Get("updated")
+		return updated, nil
+	})
+
+	if err != nil {
+		c.logger.Error("Failed to set wallet type",
+			zap.String("walletType", walletType),
+			zap.Int("addresses", len(addresses)),
+			zap.Error(err),
+		)
+		return 0, err
+	}
+
+	updated, _ := result.(int64)
+	return updated, nil
+}
+
+// SetWalletClassification stores a classifier result on a wallet node
+func (c *Neo4jClient) SetWalletClassification(ctx context.Context, address, walletType string, confidence float64, classifiedAt time.Time) error {
+	query := `
+		MATCH (w:Wallet {address: $address})
+		SET w.node_type = $walletType,
+			w.classification_confidence = $confidence,
+			w.last_classified = $classifiedAt
+	`
+
+	_, err := c.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		_, err := tx.Run(ctx, query, map[string]interface{}{
+			"address":      address,
+			"walletType":   walletType,
+			"confidence":   confidence,
+			"classifiedAt": classifiedAt,
+		})
+		return nil, err
+	})
+
+	if err != nil {
+		c.logger.Error("Failed to set wallet classification",
+			zap.String("address", address),
+			zap.String("walletType", walletType),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// SetWalletAttribution stores an address's resolved labels on its wallet node.
+// A nil label clears the attribution. Tags are also stored as text for the
+// wallet full-text index, which only covers string properties.
+func (c *Neo4jClient) SetWalletAttribution(ctx context.Context, address string, label, labelEntity *string, category, source string, confidence float64, tags, exchanges, protocols []string) error {
+	query := `
+		MATCH (w:Wallet {address: $address})
+		SET w.label = $label,
+			w.label_entity = $labelEntity,
+			w.label_category = $category,
+			w.label_source = $source,
+			w.label_confidence = $confidence,
+			w.tags = $tags,
+			w.tags_text = $tagsText,
+			w.associated_exchanges = $exchanges,
+			w.associated_protocols = $protocols
+	`
+
+	params := map[string]interface{}{
+		"address":     address,
+		"label":       nil,
+		"labelEntity": nil,
+		"category":    nil,
+		"source":      nil,
+		"confidence":  nil,
+		"tags":        tags,
+		"tagsText":    strings.Join(tags, " "),
+		"exchanges":   exchanges,
+		"protocols":   protocols,
+	}
+	if label != nil {
+		params["label"] = *label
+		params["category"] = category
+		params["source"] = source
+		params["confidence"] = confidence
+		if labelEntity != nil {
+			params["labelEntity"] = *labelEntity
+		}
+	}
+
+	_, err := c.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		_, err := tx.Run(ctx, query, params)
+		return nil, err
+	})
+
+	if err != nil {
+		c.logger.Error("Failed to set wallet attribution",
+			zap.String("address", address),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// SetWalletScamReports stores the number of approved community scam reports on a wallet
+func (c *Neo4jClient) SetWalletScamReports(ctx context.Context, address string, reports int) error {
+	query := `
+		MATCH (w:Wallet {address: $address})
+		SET w.scam_reports = $reports
+	`
+
+	_, err := c.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		_, err := tx.Run(ctx, query, map[string]interface{}{
+			"address": address,
+			"reports": reports,
+		})
+		return nil, err
+	})
+
+	if err != nil {
+		c.logger.Error("Failed to set wallet scam reports",
+			zap.String("address", address),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// GetWalletScamReports returns the number of approved community scam reports
+// stored on a wallet; unknown wallets have none
+func (c *Neo4jClient) GetWalletScamReports(ctx context.Context, address string) (int, error) {
+	query := `
+		MATCH (w:Wallet {address: $address})
+		RETURN coalesce(max(w.scam_reports), 0) as scam_reports
+	`
+
+	result, err := c.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		result, err := tx.Run(ctx, query, map[string]interface{}{
+			"address": address,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		record, err := result.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		reports, _ := record.Get("scam_reports")
+		return reports, nil
+	})
+
+	if err != nil {
+		c.logger.Error("Failed to get wallet scam reports",
+			zap.String("address", address),
+			zap.Error(err),
+		)
+		return 0, err
+	}
+
+	reports, _ := result.(int64)
+	return int(reports), nil
+}
+
+// FindSanctionedWithinHops finds sanctioned wallets reachable from address within
+// maxHops. Address must be normalized, as wallet addresses are stored normalized.
+func (c *Neo4jClient) FindSanctionedWithinHops(ctx context.Context, address string, maxHops int) ([]map[string]interface{}, error) {
+	// Variable-length bounds cannot be parameterised, so maxHops is formatted into
+	// the query. shortestPath stops at the first path to each sanctioned wallet
+	// rather than enumerating every path.
+	query := fmt.Sprintf(`
+		MATCH (w:Wallet {address: $address})
+		MATCH (s:Wallet)
+		WHERE s <> w AND size(coalesce(s.sanctions_sources, [])) > 0
+		MATCH path = shortestPath((w)-[:TRANSACTED_WITH*1..%d]-(s))
+		RETURN s.address as sanctioned_address,
+			   length(path) as hops
+		ORDER BY hops ASC
+		LIMIT 100
+	`, maxHops)
+
+	result, err := c.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		result, err := tx.Run(ctx, query, map[string]interface{}{
+			"address": address,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		records, err := result.Collect(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var exposures []map[string]interface{}
+		for _, record := range records {
+			exposures = append(exposures, record.AsMap())
+		}
+
+		return exposures, nil
+	})
+
+	if err != nil {
+		c.logger.Error("Failed to find sanctioned wallets within hops",
+			zap.String("address", address),
+			zap.Int("max_hops", maxHops),
 			zap.Error(err),
 		)
 		return nil, err
@@ -397,3 +958,29 @@ func (c *Neo4jClient) Health(ctx context.Context) error {
 
 	return err
 }
+
+// driverLogger routes the driver's logs to zap, so that they share the
+// application's level and output
+type driverLogger struct {
+	logger *zap.Logger
+}
+
+var _ neo4jlog.Logger = (*driverLogger)(nil)
+
+// Error logs a driver error. The driver retries many of the errors it logs, so
+// they are warnings; failures that are not retried are returned to the caller.
+func (l *driverLogger) Error(name, id string, err error) {
+	l.logger.Warn(err.Error(), zap.String("component", name), zap.String("id", id))
+}
+
+func (l *driverLogger) Warnf(name, id string, msg string, args ...any) {
+	l.logger.Warn(fmt.Sprintf(msg, args...), zap.String("component", name), zap.String("id", id))
+}
+
+func (l *driverLogger) Infof(name, id string, msg string, args ...any) {
+	l.logger.Info(fmt.Sprintf(msg, args...), zap.String("component", name), zap.String("id", id))
+}
+
+func (l *driverLogger) Debugf(name, id string, msg string, args ...any) {
+	l.logger.Debug(fmt.Sprintf(msg, args...), zap.String("component", name), zap.String("id", id))
+}
@@ -2,18 +2,135 @@ package database
 
 import (
 	"context"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"time"
 
 	"crypto-bubble-map-be/internal/domain/entity"
 	"crypto-bubble-map-be/internal/infrastructure/config"
+	"crypto-bubble-map-be/internal/infrastructure/monitoring"
+	"crypto-bubble-map-be/internal/infrastructure/resilience"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	gormLogger "gorm.io/gorm/logger"
+	"gorm.io/plugin/opentelemetry/tracing"
 )
 
+const queryStartKey = "metrics:query_start"
+
+// registerQueryMetrics tracks query latency by operation and table, e.g.
+// "query watch_lists"
+func registerQueryMetrics(db *gorm.DB, monitor *monitoring.PerformanceMonitor) error {
+	before := func(tx *gorm.DB) {
+		tx.InstanceSet(queryStartKey, time.Now())
+	}
+	after := func(operation string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			value, ok := tx.InstanceGet(queryStartKey)
+			if !ok {
+				return
+			}
+			name := operation
+			if tx.Statement.Table != "" {
+				name += " " + tx.Statement.Table
+			}
+			err := tx.Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = nil
+			}
+			monitor.TrackDatabaseOperation("postgresql", name, time.Since(value.(time.Time)), err)
+		}
+	}
+
+	callbacks := db.Callback()
+	errs := []error{
+		callbacks.Create().Before("gorm:create").Register("metrics:before_create", before),
+		callbacks.Create().After("gorm:create").Register("metrics:after_create", after("create")),
+		callbacks.Query().Before("gorm:query").Register("metrics:before_query", before),
+		callbacks.Query().After("gorm:query").Register("metrics:after_query", after("query")),
+		callbacks.Update().Before("gorm:update").Register("metrics:before_update", before),
+		callbacks.Update().After("gorm:update").Register("metrics:after_update", after("update")),
+		callbacks.Delete().Before("gorm:delete").Register("metrics:before_delete", before),
+		callbacks.Delete().After("gorm:delete").Register("metrics:after_delete", after("delete")),
+		callbacks.Row().Before("gorm:row").Register("metrics:before_row", before),
+		callbacks.Row().After("gorm:row").Register("metrics:after_row", after("row")),
+		callbacks.Raw().Before("gorm:raw").Register("metrics:before_raw", before),
+		callbacks.Raw().After("gorm:raw").Register("metrics:after_raw", after("raw")),
+	}
+	return errors.Join(errs...)
+}
+
+const breakerAllowedKey = "breaker:allowed"
+
+// registerCircuitBreaker guards every statement with breaker. A rejected
+// statement fails with the breaker's error before reaching the connection
+// pool.
+func registerCircuitBreaker(db *gorm.DB, breaker *resilience.Breaker) error {
+	before := func(tx *gorm.DB) {
+		if err := breaker.Allow(); err != nil {
+			tx.AddError(err)
+			return
+		}
+		tx.InstanceSet(breakerAllowedKey, true)
+	}
+	after := func(tx *gorm.DB) {
+		// Statements rejected by the breaker are not outcomes of PostgreSQL
+		if _, ok := tx.InstanceGet(breakerAllowedKey); ok {
+			breaker.Record(tx.Error)
+		}
+	}
+
+	callbacks := db.Callback()
+	errs := []error{
+		callbacks.Create().Before("*").Register("breaker:before_create", before),
+		callbacks.Create().After("*").Register("breaker:after_create", after),
+		callbacks.Query().Before("*").Register("breaker:before_query", before),
+		callbacks.Query().After("*").Register("breaker:after_query", after),
+		callbacks.Update().Before("*").Register("breaker:before_update", before),
+		callbacks.Update().After("*").Register("breaker:after_update", after),
+		callbacks.Delete().Before("*").Register("breaker:before_delete", before),
+		callbacks.Delete().After("*").Register("breaker:after_delete", after),
+		callbacks.Row().Before("*").Register("breaker:before_row", before),
+		callbacks.Row().After("*").Register("breaker:after_row", after),
+		callbacks.Raw().Before("*").Register("breaker:before_raw", before),
+		callbacks.Raw().After("*").Register("breaker:after_raw", after),
+	}
+	return errors.Join(errs...)
+}
+
+// isPostgreSQLFailure reports whether err means PostgreSQL is unreachable or
+// out of resources, rather than e.g. a constraint violation
+func isPostgreSQLFailure(err error) bool {
+	var (
+		pgErr      *pgconn.PgError
+		connectErr *pgconn.ConnectError
+		netErr     net.Error
+	)
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, gorm.ErrRecordNotFound):
+		return false
+	case errors.As(err, &pgErr):
+		// The server answered; only connection exceptions, insufficient
+		// resources and operator intervention mean it is failing
+		switch pgErr.Code[:2] {
+		case "08", "53", "57":
+			return true
+		default:
+			return false
+		}
+	default:
+		return errors.As(err, &connectErr) || errors.As(err, &netErr) ||
+			errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.ErrUnexpectedEOF) ||
+			errors.Is(err, context.DeadlineExceeded)
+	}
+}
+
 // PostgreSQLClient wraps the GORM database connection
 type PostgreSQLClient struct {
 	db     *gorm.DB
@@ -21,8 +138,10 @@ type PostgreSQLClient struct {
 	config *config.PostgreSQLConfig
 }
 
-// NewPostgreSQLClient creates a new PostgreSQL client
-func NewPostgreSQLClient(cfg *config.PostgreSQLConfig, logger *zap.Logger) (*PostgreSQLClient, error) {
+// NewPostgreSQLClient creates a new PostgreSQL client. Statements are
+// guarded by the PostgreSQL circuit breaker from breakers; connections found
+// broken are retried by database/sql on a fresh connection.
+func NewPostgreSQLClient(cfg *config.PostgreSQLConfig, monitor *monitoring.PerformanceMonitor, breakers *resilience.Registry, logger *zap.Logger) (*PostgreSQLClient, error) {
 	// Configure GORM logger
 	var gormLogLevel gormLogger.LogLevel
 	switch logger.Level() {
@@ -39,6 +158,9 @@ func NewPostgreSQLClient(cfg *config.PostgreSQLConfig, logger *zap.Logger) (*Pos
 		NowFunc: func() time.Time {
 			return time.Now().UTC()
 		},
+		// Connectivity is verified by the health manager, so that the server
+		// can start while PostgreSQL is unreachable
+		DisableAutomaticPing: true,
 	}
 
 	// Open database connection
@@ -47,6 +169,21 @@ func NewPostgreSQLClient(cfg *config.PostgreSQLConfig, logger *zap.Logger) (*Pos
 		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
 	}
 
+	// Spans per query; a no-op until tracing is enabled
+	if err := db.Use(tracing.NewPlugin(tracing.WithoutMetrics())); err != nil {
+		return nil, fmt.Errorf("failed to instrument PostgreSQL client: %w", err)
+	}
+	if monitor != nil {
+		if err := registerQueryMetrics(db, monitor); err != nil {
+			return nil, fmt.Errorf("failed to register PostgreSQL query metrics: %w", err)
+		}
+	}
+	if breaker := breakers.Breaker(resilience.DependencyPostgreSQL, isPostgreSQLFailure); breaker != nil {
+		if err := registerCircuitBreaker(db, breaker); err != nil {
+			return nil, fmt.Errorf("failed to register PostgreSQL circuit breaker: %w", err)
+		}
+	}
+
 	// Configure connection pool
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -57,21 +194,13 @@ func NewPostgreSQLClient(cfg *config.PostgreSQLConfig, logger *zap.Logger) (*Pos
 	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
 	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
-	// Test connection
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	if err := sqlDB.PingContext(ctx); err != nil {
-		return nil, fmt.Errorf("failed to ping PostgreSQL: %w", err)
-	}
-
 	client := &PostgreSQLClient{
 		db:     db,
 		logger: logger,
 		config: cfg,
 	}
 
-	logger.Info("PostgreSQL client initialized successfully",
+	logger.Info("PostgreSQL client initialized",
 		zap.String("host", cfg.Host),
 		zap.Int("port", cfg.Port),
 		zap.String("database", cfg.Database),
@@ -94,34 +223,6 @@ func (c *PostgreSQLClient) GetDB() *gorm.DB {
 	return c.db
 }
 
-// AutoMigrate runs database migrations
-func (c *PostgreSQLClient) AutoMigrate() error {
-	// Import the UserSession type from repository package
-	type UserSession struct {
-		ID        uint      `gorm:"primaryKey"`
-		UserID    uint      `gorm:"not null;index"`
-		SessionID string    `gorm:"uniqueIndex;not null"`
-		ExpiresAt time.Time `gorm:"not null;index"`
-		CreatedAt time.Time
-		UpdatedAt time.Time
-	}
-
-	err := c.db.AutoMigrate(
-		&entity.User{},
-		&entity.WatchedWallet{},
-		&entity.WatchedWalletTag{},
-		&entity.WalletAlert{},
-		&UserSession{},
-	)
-	if err != nil {
-		c.logger.Error("Failed to run auto migration", zap.Error(err))
-		return err
-	}
-
-	c.logger.Info("Database migration completed successfully")
-	return nil
-}
-
 // User operations
 func (c *PostgreSQLClient) CreateUser(ctx context.Context, user *entity.User) error {
 	if err := c.db.WithContext(ctx).Create(user).Error; err != nil {
@@ -0,0 +1,150 @@
+package detection
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+)
+
+// historyLimit caps the transactions loaded per wallet when detectors look back
+const historyLimit = 1000
+
+// weiPerEther converts on-chain values to ETH
+var weiPerEther = new(big.Float).SetFloat64(1e18)
+
+// Detector is a rule that looks for suspicious activity in a batch of new
+// transactions. Detectors must be safe to run concurrently with other detectors.
+type Detector interface {
+	// Name identifies the detector; it prefixes the dedupe keys of its alerts
+	Name() string
+	Detect(ctx context.Context, batch *Batch) ([]Finding, error)
+}
+
+// Finding is suspicious activity found by a detector. The runner turns each
+// finding into a security alert, at most once per dedupe key.
+type Finding struct {
+	Type                entity.AlertType
+	Severity            entity.AlertSeverity
+	Title               string
+	Description         string
+	WalletAddress       string
+	Confidence          int // 0-100
+	RelatedTransactions []string
+	ActionRequired      bool
+	// DedupeKey identifies the activity within the detector, e.g. the wallet and
+	// time window, so re-detecting it does not raise a second alert
+	DedupeKey string
+	// Timestamp is when the activity took place
+	Timestamp time.Time
+	Metadata  map[string]interface{}
+}
+
+// Batch is a set of newly crawled transactions together with memoized access to
+// the involved wallets' earlier activity
+type Batch struct {
+	// Transactions are in crawl order, oldest first
+	Transactions []entity.Transaction
+	// Start and End span the transactions' timestamps
+	Start time.Time
+	End   time.Time
+
+	transactionRepo repository.TransactionRepository
+	lookback        time.Duration
+
+	mu      sync.Mutex
+	history map[string][]entity.Transaction
+}
+
+// NewBatch creates a batch over transactions. History lookups cover lookback
+// before the earliest transaction.
+func NewBatch(transactions []entity.Transaction, transactionRepo repository.TransactionRepository, lookback time.Duration) *Batch {
+	b := &Batch{
+		Transactions:    transactions,
+		transactionRepo: transactionRepo,
+		lookback:        lookback,
+		history:         make(map[string][]entity.Transaction),
+	}
+	for i, tx := range transactions {
+		if i == 0 || tx.Timestamp.Before(b.Start) {
+			b.Start = tx.Timestamp
+		}
+		if tx.Timestamp.After(b.End) {
+			b.End = tx.Timestamp
+		}
+	}
+	return b
+}
+
+// WalletHistory returns a wallet's transactions from lookback before the batch up
+// to its end, newest first. Results are shared between detectors.
+func (b *Batch) WalletHistory(ctx context.Context, address string) ([]entity.Transaction, error) {
+	b.mu.Lock()
+	transactions, ok := b.history[address]
+	b.mu.Unlock()
+	if ok {
+		return transactions, nil
+	}
+
+	timeRange := entity.TimeRange{Start: b.Start.Add(-b.lookback), End: b.End}
+	transactions, err := b.transactionRepo.GetTransactionsInRange(ctx, address, timeRange, historyLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	b.history[address] = transactions
+	b.mu.Unlock()
+	return transactions, nil
+}
+
+// Addresses returns the distinct sender and recipient addresses in the batch
+func (b *Batch) Addresses() []string {
+	seen := make(map[string]bool)
+	var addresses []string
+	for _, tx := range b.Transactions {
+		for _, address := range []string{tx.From, recipient(tx)} {
+			if address != "" && !seen[address] {
+				seen[address] = true
+				addresses = append(addresses, address)
+			}
+		}
+	}
+	return addresses
+}
+
+// recipient returns the address a transaction sent value to
+func recipient(tx entity.Transaction) string {
+	if tx.To != nil {
+		return *tx.To
+	}
+	if tx.ContractAddress != nil {
+		return *tx.ContractAddress
+	}
+	return ""
+}
+
+// weiToETH parses a decimal or 0x-prefixed wei amount. Unparseable values count as zero.
+func weiToETH(value string) float64 {
+	wei, ok := new(big.Int).SetString(strings.TrimSpace(value), 0)
+	if !ok {
+		return 0
+	}
+	eth, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), weiPerEther).Float64()
+	return eth
+}
+
+// clampConfidence keeps a confidence score within 0-100
+func clampConfidence(confidence float64) int {
+	if confidence < 0 {
+		return 0
+	}
+	if confidence > 100 {
+		return 100
+	}
+	return int(confidence)
+}
@@ -0,0 +1,190 @@
+package detection
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+)
+
+// maxFanRelatedTransactions caps the transactions attached to a fan-out/fan-in alert
+const maxFanRelatedTransactions = 50
+
+// fanExemptTypes are wallet types that legitimately pay or collect from many
+// counterparties at once
+var fanExemptTypes = map[entity.WalletType]bool{
+	entity.WalletTypeExchange:    true,
+	entity.WalletTypeDefi:        true,
+	entity.WalletTypeBridge:      true,
+	entity.WalletTypeMarketMaker: true,
+	entity.WalletTypeMiner:       true,
+}
+
+// fanDirection distinguishes dispersing funds from collecting them
+type fanDirection struct {
+	name     string
+	outgoing bool
+	title    string
+	verb     string
+}
+
+var (
+	fanOut = fanDirection{name: "fan_out", outgoing: true, title: "Rapid fan-out detected", verb: "sent funds to"}
+	fanIn  = fanDirection{name: "fan_in", outgoing: false, title: "Rapid fan-in detected", verb: "received funds from"}
+)
+
+// FanDetector finds wallets that rapidly disperse funds to, or collect funds from,
+// many distinct counterparties, typical of smurfing and of consolidating laundered funds
+type FanDetector struct {
+	MinCounterparties int
+	Window            time.Duration
+
+	walletRepo repository.WalletRepository
+}
+
+// NewFanDetector creates a fan-out/fan-in detector. Exchanges, DeFi protocols,
+// bridges, market makers and miners are exempt.
+func NewFanDetector(walletRepo repository.WalletRepository, minCounterparties int, window time.Duration) *FanDetector {
+	return &FanDetector{
+		MinCounterparties: minCounterparties,
+		Window:            window,
+		walletRepo:        walletRepo,
+	}
+}
+
+// Name identifies the detector
+func (d *FanDetector) Name() string {
+	return "fan_out_fan_in"
+}
+
+// Detect checks each sender and recipient in the batch for a burst of distinct
+// counterparties in the window before its latest transaction
+func (d *FanDetector) Detect(ctx context.Context, batch *Batch) ([]Finding, error) {
+	latestOut := make(map[string]time.Time)
+	latestIn := make(map[string]time.Time)
+	for _, tx := range batch.Transactions {
+		if tx.From != "" && tx.Timestamp.After(latestOut[tx.From]) {
+			latestOut[tx.From] = tx.Timestamp
+		}
+		if to := recipient(tx); to != "" && tx.Timestamp.After(latestIn[to]) {
+			latestIn[to] = tx.Timestamp
+		}
+	}
+
+	var findings []Finding
+	for _, pass := range []struct {
+		direction fanDirection
+		latest    map[string]time.Time
+	}{{fanOut, latestOut}, {fanIn, latestIn}} {
+		for wallet, until := range pass.latest {
+			finding, err := d.check(ctx, batch, wallet, until, pass.direction)
+			if err != nil {
+				return nil, err
+			}
+			if finding != nil {
+				findings = append(findings, *finding)
+			}
+		}
+	}
+
+	return d.dropExempt(ctx, findings)
+}
+
+// check counts the wallet's distinct counterparties in the window ending at until
+func (d *FanDetector) check(ctx context.Context, batch *Batch, wallet string, until time.Time, direction fanDirection) (*Finding, error) {
+	history, err := batch.WalletHistory(ctx, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	since := until.Add(-d.Window)
+	counterparties := make(map[string]bool)
+	var related []string
+	var volume float64
+	for _, tx := range history {
+		if tx.Timestamp.Before(since) || tx.Timestamp.After(until) {
+			continue
+		}
+		var counterparty string
+		if direction.outgoing && strings.EqualFold(tx.From, wallet) {
+			counterparty = recipient(tx)
+		} else if !direction.outgoing && strings.EqualFold(recipient(tx), wallet) {
+			counterparty = tx.From
+		}
+		if counterparty == "" {
+			continue
+		}
+
+		counterparties[entity.NormalizeAddress(counterparty)] = true
+		volume += weiToETH(tx.Value)
+		if len(related) < maxFanRelatedTransactions {
+			related = append(related, tx.Hash)
+		}
+	}
+
+	count := len(counterparties)
+	if count < d.MinCounterparties {
+		return nil, nil
+	}
+
+	severity := entity.AlertSeverityMedium
+	if count >= 3*d.MinCounterparties {
+		severity = entity.AlertSeverityHigh
+	}
+	address := entity.NormalizeAddress(wallet)
+
+	return &Finding{
+		Type:     entity.AlertTypeLaundering,
+		Severity: severity,
+		Title:    direction.title,
+		Description: fmt.Sprintf("Wallet %s %s %d distinct addresses within %s, moving %.4f ETH",
+			address, direction.verb, count, d.Window, volume),
+		WalletAddress:       address,
+		Confidence:          clampConfidence(40 + 50*float64(count-d.MinCounterparties)/float64(2*d.MinCounterparties)),
+		RelatedTransactions: related,
+		ActionRequired:      severity == entity.AlertSeverityHigh,
+		// One alert per wallet, direction and window-sized period
+		DedupeKey: fmt.Sprintf("%s:%s:%d", direction.name, address, until.Truncate(d.Window).Unix()),
+		Timestamp: until,
+		Metadata: map[string]interface{}{
+			"direction":      direction.name,
+			"counterparties": count,
+			"volume_eth":     volume,
+			"window":         d.Window.String(),
+		},
+	}, nil
+}
+
+// dropExempt removes findings for wallets whose type explains the pattern
+func (d *FanDetector) dropExempt(ctx context.Context, findings []Finding) ([]Finding, error) {
+	if len(findings) == 0 || d.walletRepo == nil {
+		return findings, nil
+	}
+
+	addresses := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		addresses = append(addresses, finding.WalletAddress)
+	}
+	wallets, err := d.walletRepo.GetWalletsByAddresses(ctx, addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	exempt := make(map[string]bool)
+	for _, wallet := range wallets {
+		if fanExemptTypes[wallet.WalletType] || wallet.IsWhitelisted {
+			exempt[entity.NormalizeAddress(wallet.Address)] = true
+		}
+	}
+
+	kept := findings[:0]
+	for _, finding := range findings {
+		if !exempt[finding.WalletAddress] {
+			kept = append(kept, finding)
+		}
+	}
+	return kept, nil
+}
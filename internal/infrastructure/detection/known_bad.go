@@ -0,0 +1,162 @@
+package detection
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+)
+
+// badAddress describes why an address is considered known-bad
+type badAddress struct {
+	alertType  entity.AlertType
+	severity   entity.AlertSeverity
+	confidence int
+	reason     string
+}
+
+// KnownBadDetector finds transactions with sanctioned addresses and with wallets
+// that are blacklisted, flagged or classified as suspicious or critical risk
+type KnownBadDetector struct {
+	sanctionsRepo repository.SanctionsRepository
+	walletRepo    repository.WalletRepository
+}
+
+// NewKnownBadDetector creates a known-bad address interaction detector
+func NewKnownBadDetector(sanctionsRepo repository.SanctionsRepository, walletRepo repository.WalletRepository) *KnownBadDetector {
+	return &KnownBadDetector{
+		sanctionsRepo: sanctionsRepo,
+		walletRepo:    walletRepo,
+	}
+}
+
+// Name identifies the detector
+func (d *KnownBadDetector) Name() string {
+	return "known_bad_interaction"
+}
+
+// Detect raises a finding for the counterparty of every transaction with a
+// known-bad address. Receiving from a bad address is reported one severity lower
+// than sending to it, since anyone can send funds to an address.
+func (d *KnownBadDetector) Detect(ctx context.Context, batch *Batch) ([]Finding, error) {
+	bad, err := d.badAddresses(ctx, batch.Addresses())
+	if err != nil {
+		return nil, err
+	}
+	if len(bad) == 0 {
+		return nil, nil
+	}
+
+	var findings []Finding
+	for _, tx := range batch.Transactions {
+		from := entity.NormalizeAddress(tx.From)
+		to := entity.NormalizeAddress(recipient(tx))
+		if from == "" || to == "" {
+			continue
+		}
+
+		if reason, ok := bad[to]; ok {
+			if _, senderBad := bad[from]; !senderBad {
+				findings = append(findings, d.finding(tx, from, to, reason, true))
+			}
+		}
+		if reason, ok := bad[from]; ok {
+			if _, recipientBad := bad[to]; !recipientBad {
+				findings = append(findings, d.finding(tx, to, from, reason, false))
+			}
+		}
+	}
+	return findings, nil
+}
+
+// badAddresses looks up which of the addresses are sanctioned or known-bad wallets
+func (d *KnownBadDetector) badAddresses(ctx context.Context, addresses []string) (map[string]badAddress, error) {
+	normalized := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		normalized = append(normalized, entity.NormalizeAddress(address))
+	}
+
+	bad := make(map[string]badAddress)
+
+	wallets, err := d.walletRepo.GetWalletsByAddresses(ctx, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up wallets: %w", err)
+	}
+	for _, wallet := range wallets {
+		address := entity.NormalizeAddress(wallet.Address)
+		switch {
+		case wallet.IsWhitelisted:
+			continue
+		case wallet.WalletType == entity.WalletTypeBlacklisted:
+			bad[address] = badAddress{entity.AlertTypeScam, entity.AlertSeverityHigh, 85, "blacklisted wallet"}
+		case wallet.IsFlagged || wallet.WalletType == entity.WalletTypeSuspicious || wallet.RiskLevel == entity.RiskLevelCritical:
+			bad[address] = badAddress{entity.AlertTypeSuspicious, entity.AlertSeverityMedium, 60, "flagged wallet"}
+		}
+	}
+
+	// Sanctions take precedence over wallet classifications
+	sanctioned, err := d.sanctionsRepo.FindSanctionedAddresses(ctx, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to screen addresses: %w", err)
+	}
+	for _, entry := range sanctioned {
+		reason := "sanctioned address"
+		if entry.EntityName != "" {
+			reason = fmt.Sprintf("sanctioned address of %s (%s)", entry.EntityName, entry.Source)
+		}
+		bad[entity.NormalizeAddress(entry.Address)] = badAddress{entity.AlertTypeSanctions, entity.AlertSeverityCritical, 95, reason}
+	}
+
+	return bad, nil
+}
+
+// finding reports wallet's transaction with a bad counterparty
+func (d *KnownBadDetector) finding(tx entity.Transaction, wallet, counterparty string, reason badAddress, outgoing bool) Finding {
+	severity, confidence := reason.severity, reason.confidence
+	verb := "sent funds to"
+	if !outgoing {
+		severity = lowerSeverity(severity)
+		confidence -= 15
+		verb = "received funds from"
+	}
+
+	return Finding{
+		Type:                reason.alertType,
+		Severity:            severity,
+		Title:               fmt.Sprintf("Interaction with %s", reason.reason),
+		Description:         fmt.Sprintf("Wallet %s %s %s, a %s", wallet, verb, counterparty, reason.reason),
+		WalletAddress:       wallet,
+		Confidence:          clampConfidence(float64(confidence)),
+		RelatedTransactions: []string{tx.Hash},
+		ActionRequired:      severity == entity.AlertSeverityCritical || severity == entity.AlertSeverityHigh,
+		// One alert per wallet, bad counterparty and day
+		DedupeKey: fmt.Sprintf("%s:%s:%s", wallet, counterparty, tx.Timestamp.UTC().Format("2006-01-02")),
+		Timestamp: tx.Timestamp,
+		Metadata: map[string]interface{}{
+			"counterparty": counterparty,
+			"direction":    strings.ToLower(direction(outgoing)),
+			"reason":       reason.reason,
+			"value_eth":    weiToETH(tx.Value),
+		},
+	}
+}
+
+func direction(outgoing bool) string {
+	if outgoing {
+		return string(entity.TransactionDirectionOutgoing)
+	}
+	return string(entity.TransactionDirectionIncoming)
+}
+
+func lowerSeverity(severity entity.AlertSeverity) entity.AlertSeverity {
+	switch severity {
+	case entity.AlertSeverityCritical:
+		return entity.AlertSeverityHigh
+	case entity.AlertSeverityHigh:
+		return entity.AlertSeverityMedium
+	default:
+		return entity.AlertSeverityLow
+	}
+}
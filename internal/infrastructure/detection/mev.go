@@ -0,0 +1,400 @@
+package detection
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+)
+
+// highValueMEVETH is the extracted value above which MEV is reported as high severity
+const highValueMEVETH = 1.0
+
+// botRetaggable are wallet types that MEV detection may replace with a bot type.
+// Exchanges, protocols and wallets classified as suspicious keep their type.
+var botRetaggable = map[entity.WalletType]bool{
+	"":                        true,
+	entity.WalletTypeRegular:  true,
+	entity.WalletTypeContract: true,
+	entity.WalletTypeWhale:    true,
+}
+
+// poolSwap is a swap together with the transaction that made it
+type poolSwap struct {
+	tx   *entity.Transaction
+	swap swap
+}
+
+// MEVDetector finds sandwich attacks and atomic arbitrage in the blocks of new
+// transactions. Each activity is recorded with its victims and extracted value,
+// and the bots' wallets are classified as MEV_BOT or ARBITRAGE_BOT.
+type MEVDetector struct {
+	TagWallets bool
+
+	transactionRepo repository.TransactionRepository
+	walletRepo      repository.WalletRepository
+	mevRepo         repository.MEVRepository
+}
+
+// NewMEVDetector creates an MEV detector. With tagWallets unset, bot wallets are
+// reported but their type is left unchanged.
+func NewMEVDetector(transactionRepo repository.TransactionRepository, walletRepo repository.WalletRepository, mevRepo repository.MEVRepository, tagWallets bool) *MEVDetector {
+	return &MEVDetector{
+		TagWallets:      tagWallets,
+		transactionRepo: transactionRepo,
+		walletRepo:      walletRepo,
+		mevRepo:         mevRepo,
+	}
+}
+
+// Name identifies the detector
+func (d *MEVDetector) Name() string {
+	return "mev"
+}
+
+// Detect loads every block the batch touches in full, since the transactions
+// around a sandwich victim may have been crawled in another batch, and looks for
+// MEV in each block
+func (d *MEVDetector) Detect(ctx context.Context, batch *Batch) ([]Finding, error) {
+	seen := make(map[string]bool)
+	var blockNumbers []string
+	for _, tx := range batch.Transactions {
+		if tx.BlockNumber != "" && !seen[tx.BlockNumber] {
+			seen[tx.BlockNumber] = true
+			blockNumbers = append(blockNumbers, tx.BlockNumber)
+		}
+	}
+	if len(blockNumbers) == 0 {
+		return nil, nil
+	}
+
+	transactions, err := d.transactionRepo.GetTransactionsInBlocks(ctx, blockNumbers)
+	if err != nil {
+		return nil, err
+	}
+	blocks := make(map[string][]entity.Transaction)
+	for _, tx := range transactions {
+		blocks[tx.BlockNumber] = append(blocks[tx.BlockNumber], tx)
+	}
+
+	var activities []*entity.MEVActivity
+	for _, block := range blocks {
+		sort.SliceStable(block, func(i, j int) bool {
+			return block[i].TransactionIndex < block[j].TransactionIndex
+		})
+		activities = append(activities, findSandwiches(block)...)
+		activities = append(activities, findArbitrage(block)...)
+	}
+
+	findings := make([]Finding, 0, len(activities))
+	bots := make(map[string]entity.WalletType)
+	for _, activity := range activities {
+		if _, err := d.mevRepo.SaveMEVActivity(ctx, activity); err != nil {
+			return nil, err
+		}
+		findings = append(findings, mevFinding(activity))
+
+		walletType := entity.WalletTypeArbitrageBot
+		if activity.Type == entity.MEVTypeSandwich {
+			walletType = entity.WalletTypeMEVBot
+		}
+		for _, address := range botAddresses(activity) {
+			if bots[address] != entity.WalletTypeMEVBot {
+				bots[address] = walletType
+			}
+		}
+	}
+
+	if d.TagWallets {
+		if err := d.tagBots(ctx, bots); err != nil {
+			return nil, err
+		}
+	}
+	return findings, nil
+}
+
+// findSandwiches looks for a bot swapping on a pool before a victim's swap in the
+// same direction and swapping back right after it, within one block in block order
+func findSandwiches(block []entity.Transaction) []*entity.MEVActivity {
+	byPool := make(map[string][]poolSwap)
+	var pools []string
+	for i := range block {
+		tx := &block[i]
+		for _, s := range decodeSwaps(*tx) {
+			if _, ok := byPool[s.Pool]; !ok {
+				pools = append(pools, s.Pool)
+			}
+			byPool[s.Pool] = append(byPool[s.Pool], poolSwap{tx: tx, swap: s})
+		}
+	}
+
+	var activities []*entity.MEVActivity
+	for _, pool := range pools {
+		swaps := byPool[pool]
+		used := make(map[int]bool)
+		for i := 0; i < len(swaps); i++ {
+			if used[i] {
+				continue
+			}
+			front := swaps[i]
+			for j := i + 2; j < len(swaps); j++ {
+				back := swaps[j]
+				if used[j] || back.tx.Hash == front.tx.Hash || !sameBot(front.tx, back.tx) ||
+					back.swap.TokenIn != front.swap.TokenOut || back.swap.TokenOut != front.swap.TokenIn {
+					continue
+				}
+
+				victims := sandwichVictims(front, back, swaps[i+1:j])
+				if len(victims) == 0 {
+					continue
+				}
+				profit := new(big.Int).Sub(back.swap.AmountOut, front.swap.AmountIn)
+				if profit.Sign() <= 0 {
+					continue
+				}
+
+				used[i], used[j] = true, true
+				activities = append(activities, sandwichActivity(front, back, victims, profit))
+				break
+			}
+		}
+	}
+	return activities
+}
+
+// sandwichVictims returns the other traders between front and back that swapped
+// in the same direction as the front-run
+func sandwichVictims(front, back poolSwap, between []poolSwap) []entity.MEVVictim {
+	var victims []entity.MEVVictim
+	for _, candidate := range between {
+		if candidate.tx.Hash == front.tx.Hash || candidate.tx.Hash == back.tx.Hash ||
+			sameBot(candidate.tx, front.tx) || candidate.swap.TokenIn != front.swap.TokenIn {
+			continue
+		}
+		victims = append(victims, entity.MEVVictim{
+			Address:     entity.NormalizeAddress(candidate.tx.From),
+			Transaction: candidate.tx.Hash,
+		})
+	}
+	return victims
+}
+
+func sandwichActivity(front, back poolSwap, victims []entity.MEVVictim, profit *big.Int) *entity.MEVActivity {
+	return &entity.MEVActivity{
+		Type:              entity.MEVTypeSandwich,
+		BotAddress:        entity.NormalizeAddress(front.tx.From),
+		BotContract:       botContract(front.tx),
+		Transactions:      []string{front.tx.Hash, back.tx.Hash},
+		Victims:           victims,
+		Pools:             []string{front.swap.Pool},
+		BlockNumber:       front.tx.BlockNumber,
+		Network:           front.tx.Network,
+		ExtractedToken:    front.swap.TokenIn,
+		ExtractedValue:    profit.String(),
+		ExtractedValueETH: tokenValueETH(front.swap.TokenIn, profit),
+		DedupeKey:         fmt.Sprintf("sandwich:%s:%s", front.tx.Hash, back.tx.Hash),
+		Timestamp:         front.tx.Timestamp,
+	}
+}
+
+// findArbitrage looks for transactions whose swaps form a cycle across at least two
+// pools, each swap spending what the previous one bought, and end with more of the
+// starting token than they spent
+func findArbitrage(block []entity.Transaction) []*entity.MEVActivity {
+	var activities []*entity.MEVActivity
+	for i := range block {
+		tx := &block[i]
+		swaps := decodeSwaps(*tx)
+		if len(swaps) < 2 {
+			continue
+		}
+
+		pools := make([]string, 0, len(swaps))
+		distinct := make(map[string]bool)
+		cycle := true
+		for k, s := range swaps {
+			if k > 0 && s.TokenIn != swaps[k-1].TokenOut {
+				cycle = false
+				break
+			}
+			if !distinct[s.Pool] {
+				distinct[s.Pool] = true
+				pools = append(pools, s.Pool)
+			}
+		}
+		first, last := swaps[0], swaps[len(swaps)-1]
+		if !cycle || len(distinct) < 2 || first.TokenIn != last.TokenOut {
+			continue
+		}
+		profit := new(big.Int).Sub(last.AmountOut, first.AmountIn)
+		if profit.Sign() <= 0 {
+			continue
+		}
+
+		activities = append(activities, &entity.MEVActivity{
+			Type:              entity.MEVTypeArbitrage,
+			BotAddress:        entity.NormalizeAddress(tx.From),
+			BotContract:       botContract(tx),
+			Transactions:      []string{tx.Hash},
+			Pools:             pools,
+			BlockNumber:       tx.BlockNumber,
+			Network:           tx.Network,
+			ExtractedToken:    first.TokenIn,
+			ExtractedValue:    profit.String(),
+			ExtractedValueETH: tokenValueETH(first.TokenIn, profit),
+			DedupeKey:         fmt.Sprintf("arbitrage:%s", tx.Hash),
+			Timestamp:         tx.Timestamp,
+		})
+	}
+	return activities
+}
+
+// sameBot reports whether two transactions come from the same searcher: the same
+// sender, or the same bot contract, since bots often rotate the accounts calling it
+func sameBot(a, b *entity.Transaction) bool {
+	if strings.EqualFold(a.From, b.From) {
+		return true
+	}
+	contractA, contractB := botContract(a), botContract(b)
+	return contractA != nil && contractB != nil && *contractA == *contractB
+}
+
+// botContract returns the contract the transaction called when that contract
+// both paid into and received from the pools, which routers shared by many users
+// do not do: they forward the output to the user
+func botContract(tx *entity.Transaction) *string {
+	if tx.To == nil {
+		return nil
+	}
+	contract := entity.NormalizeAddress(*tx.To)
+	sent, received := false, false
+	for _, log := range tx.Logs {
+		transfer, ok := decodeTransfer(log)
+		if !ok {
+			continue
+		}
+		if transfer.From == contract {
+			sent = true
+		}
+		if transfer.To == contract {
+			received = true
+		}
+	}
+	if !sent || !received {
+		return nil
+	}
+	return &contract
+}
+
+// botAddresses returns the sender and contract of an MEV activity
+func botAddresses(activity *entity.MEVActivity) []string {
+	addresses := []string{activity.BotAddress}
+	if activity.BotContract != nil {
+		addresses = append(addresses, *activity.BotContract)
+	}
+	return addresses
+}
+
+// tagBots classifies bot wallets, upgrading arbitrage bots that were caught
+// sandwiching to MEV bots
+func (d *MEVDetector) tagBots(ctx context.Context, bots map[string]entity.WalletType) error {
+	if len(bots) == 0 {
+		return nil
+	}
+
+	addresses := make([]string, 0, len(bots))
+	for address := range bots {
+		addresses = append(addresses, address)
+	}
+	wallets, err := d.walletRepo.GetWalletsByAddresses(ctx, addresses)
+	if err != nil {
+		return err
+	}
+
+	retag := make(map[entity.WalletType][]string)
+	for _, wallet := range wallets {
+		address := entity.NormalizeAddress(wallet.Address)
+		walletType, ok := bots[address]
+		if !ok || wallet.WalletType == walletType {
+			continue
+		}
+		upgrade := wallet.WalletType == entity.WalletTypeArbitrageBot && walletType == entity.WalletTypeMEVBot
+		if !upgrade && !botRetaggable[wallet.WalletType] {
+			continue
+		}
+		retag[walletType] = append(retag[walletType], address)
+	}
+
+	for walletType, addresses := range retag {
+		if err := d.walletRepo.UpdateWalletType(ctx, addresses, walletType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mevFinding reports an MEV activity against the bot
+func mevFinding(activity *entity.MEVActivity) Finding {
+	value := fmt.Sprintf("%s units of %s", activity.ExtractedValue, activity.ExtractedToken)
+	if activity.ExtractedValueETH != nil {
+		value = fmt.Sprintf("%.4f ETH", *activity.ExtractedValueETH)
+	}
+	highValue := activity.ExtractedValueETH != nil && *activity.ExtractedValueETH >= highValueMEVETH
+
+	finding := Finding{
+		Type:                entity.AlertTypeMEV,
+		WalletAddress:       activity.BotAddress,
+		RelatedTransactions: activity.Transactions,
+		DedupeKey:           activity.DedupeKey,
+		Timestamp:           activity.Timestamp,
+		Metadata: map[string]interface{}{
+			"mev_type":        string(activity.Type),
+			"block_number":    activity.BlockNumber,
+			"pools":           activity.Pools,
+			"extracted_token": activity.ExtractedToken,
+			"extracted_value": activity.ExtractedValue,
+		},
+	}
+	if activity.BotContract != nil {
+		finding.Metadata["bot_contract"] = *activity.BotContract
+	}
+	if activity.ExtractedValueETH != nil {
+		finding.Metadata["extracted_value_eth"] = *activity.ExtractedValueETH
+	}
+
+	switch activity.Type {
+	case entity.MEVTypeSandwich:
+		victims := make([]string, 0, len(activity.Victims))
+		for _, victim := range activity.Victims {
+			victims = append(victims, victim.Address)
+			finding.RelatedTransactions = append(finding.RelatedTransactions, victim.Transaction)
+		}
+		finding.Metadata["victims"] = victims
+
+		finding.Severity = entity.AlertSeverityMedium
+		if highValue || len(victims) > 2 {
+			finding.Severity = entity.AlertSeverityHigh
+		}
+		finding.Title = "Sandwich attack detected"
+		finding.Description = fmt.Sprintf("Wallet %s sandwiched %d transaction(s) in block %s, extracting %s",
+			activity.BotAddress, len(victims), activity.BlockNumber, value)
+		finding.Confidence = 85
+		finding.ActionRequired = finding.Severity == entity.AlertSeverityHigh
+	default:
+		// Arbitrage harms no specific user, so it is informational unless large
+		finding.Severity = entity.AlertSeverityLow
+		if highValue {
+			finding.Severity = entity.AlertSeverityMedium
+		}
+		finding.Title = "Atomic arbitrage detected"
+		finding.Description = fmt.Sprintf("Wallet %s arbitraged across %d pools in block %s, extracting %s",
+			activity.BotAddress, len(activity.Pools), activity.BlockNumber, value)
+		finding.Confidence = 90
+	}
+
+	return finding
+}
@@ -0,0 +1,130 @@
+package detection
+
+import (
+	"math/big"
+	"strings"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+)
+
+// Event signatures used to reconstruct swaps from receipt logs
+const (
+	// Transfer(address indexed from, address indexed to, uint256 value)
+	transferTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	// Uniswap V2 style Swap(address indexed sender, uint amount0In, uint amount1In, uint amount0Out, uint amount1Out, address indexed to)
+	swapV2Topic = "0xd78ad95fa46c994b6551d0da85fc275fe613ce37657fb8d5e3d130840159d822"
+	// Uniswap V3 style Swap(address indexed sender, address indexed recipient, int256 amount0, int256 amount1, uint160 sqrtPriceX96, uint128 liquidity, int24 tick)
+	swapV3Topic = "0xc42079f94a6350d7e6235f29174924f928cc2ac818eb64fed8004e115fbcca67"
+
+	// wethAddress is Wrapped Ether on Ethereum mainnet, which MEV profits are usually taken in
+	wethAddress = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+)
+
+// swap is one trade against a liquidity pool. Tokens and amounts come from the
+// ERC-20 transfers into and out of the pool, which works the same for every pool
+// that emits a V2 or V3 style Swap event.
+type swap struct {
+	Pool      string
+	TokenIn   string
+	AmountIn  *big.Int
+	TokenOut  string
+	AmountOut *big.Int
+}
+
+// tokenTransfer is an ERC-20 Transfer event
+type tokenTransfer struct {
+	Token  string
+	From   string
+	To     string
+	Amount *big.Int
+	// A transfer between two pools is the output of one swap and the input of the next
+	usedIn  bool
+	usedOut bool
+}
+
+// decodeSwaps reconstructs a transaction's swaps in log order. Each Swap event is
+// matched with the closest earlier unmatched transfers into and out of its pool;
+// swaps whose transfers cannot be found are skipped.
+func decodeSwaps(tx entity.Transaction) []swap {
+	transfers := make(map[int]*tokenTransfer)
+	for i, log := range tx.Logs {
+		if transfer, ok := decodeTransfer(log); ok {
+			transfers[i] = transfer
+		}
+	}
+
+	var swaps []swap
+	for i, log := range tx.Logs {
+		if len(log.Topics) == 0 || !isSwapTopic(log.Topics[0]) {
+			continue
+		}
+		pool := entity.NormalizeAddress(log.Address)
+
+		var in, out *tokenTransfer
+		for j := i - 1; j >= 0 && (in == nil || out == nil); j-- {
+			transfer, ok := transfers[j]
+			if !ok {
+				continue
+			}
+			if in == nil && !transfer.usedIn && transfer.To == pool {
+				in = transfer
+			} else if out == nil && !transfer.usedOut && transfer.From == pool {
+				out = transfer
+			}
+		}
+		if in == nil || out == nil || in.Token == out.Token {
+			continue
+		}
+
+		in.usedIn, out.usedOut = true, true
+		swaps = append(swaps, swap{
+			Pool:      pool,
+			TokenIn:   in.Token,
+			AmountIn:  in.Amount,
+			TokenOut:  out.Token,
+			AmountOut: out.Amount,
+		})
+	}
+	return swaps
+}
+
+func isSwapTopic(topic string) bool {
+	topic = strings.ToLower(topic)
+	return topic == swapV2Topic || topic == swapV3Topic
+}
+
+// decodeTransfer decodes an ERC-20 Transfer log. ERC-721 transfers, which index
+// the token ID as a fourth topic, are ignored.
+func decodeTransfer(log entity.TransactionLog) (*tokenTransfer, bool) {
+	if len(log.Topics) != 3 || strings.ToLower(log.Topics[0]) != transferTopic {
+		return nil, false
+	}
+	amount, ok := new(big.Int).SetString(strings.TrimPrefix(strings.TrimPrefix(log.Data, "0x"), "0X"), 16)
+	if !ok {
+		return nil, false
+	}
+	return &tokenTransfer{
+		Token:  entity.NormalizeAddress(log.Address),
+		From:   topicAddress(log.Topics[1]),
+		To:     topicAddress(log.Topics[2]),
+		Amount: amount,
+	}, true
+}
+
+// topicAddress extracts the address from a 32-byte indexed topic
+func topicAddress(topic string) string {
+	topic = strings.ToLower(strings.TrimPrefix(topic, "0x"))
+	if len(topic) < 40 {
+		return ""
+	}
+	return "0x" + topic[len(topic)-40:]
+}
+
+// tokenValueETH converts an amount of token to ETH when the token is WETH
+func tokenValueETH(token string, amount *big.Int) *float64 {
+	if token != wethAddress {
+		return nil
+	}
+	eth := weiToETH(amount.String())
+	return &eth
+}
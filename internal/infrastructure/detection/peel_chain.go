@@ -0,0 +1,182 @@
+package detection
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+)
+
+// maxPeelChainHops bounds how far back a chain is followed from a new transaction
+const maxPeelChainHops = 20
+
+// PeelChainDetector finds peel chains: funds pass through a series of wallets that
+// each forward most of what they received to the next wallet and "peel" off a small
+// part elsewhere, a common way to cash out while obscuring the source
+type PeelChainDetector struct {
+	MinHops      int
+	MaxPeelRatio float64
+	HopWindow    time.Duration
+	MinValueETH  float64
+}
+
+// peelHop is one wallet in a chain: it was funded by Funding and forwarded the
+// remainder in Forward
+type peelHop struct {
+	Wallet    string
+	Funding   entity.Transaction
+	Forward   entity.Transaction
+	PeelRatio float64
+}
+
+// NewPeelChainDetector creates a peel chain detector that reports chains of at
+// least minHops wallets, starting with at least minValueETH
+func NewPeelChainDetector(minHops int, maxPeelRatio float64, hopWindow time.Duration, minValueETH float64) *PeelChainDetector {
+	return &PeelChainDetector{
+		MinHops:      minHops,
+		MaxPeelRatio: maxPeelRatio,
+		HopWindow:    hopWindow,
+		MinValueETH:  minValueETH,
+	}
+}
+
+// Name identifies the detector
+func (d *PeelChainDetector) Name() string {
+	return "peel_chain"
+}
+
+// Detect follows each sufficiently large new transaction back through the wallets
+// that forwarded it and reports chains of at least MinHops peeling wallets
+func (d *PeelChainDetector) Detect(ctx context.Context, batch *Batch) ([]Finding, error) {
+	var findings []Finding
+	for _, tx := range batch.Transactions {
+		if weiToETH(tx.Value) < d.MinValueETH {
+			continue
+		}
+
+		hops, err := d.followChain(ctx, batch, tx)
+		if err != nil {
+			return nil, err
+		}
+		if len(hops) >= d.MinHops {
+			findings = append(findings, d.finding(tx, hops))
+		}
+	}
+	return findings, nil
+}
+
+// followChain walks back from tx while each sending wallet looks like a peel hop,
+// returning the hops newest first
+func (d *PeelChainDetector) followChain(ctx context.Context, batch *Batch, tx entity.Transaction) ([]peelHop, error) {
+	var hops []peelHop
+	forward := tx
+	visited := map[string]bool{}
+
+	for len(hops) < maxPeelChainHops {
+		wallet := forward.From
+		if wallet == "" || visited[strings.ToLower(wallet)] {
+			break
+		}
+		visited[strings.ToLower(wallet)] = true
+
+		history, err := batch.WalletHistory(ctx, wallet)
+		if err != nil {
+			return nil, err
+		}
+		hop, ok := d.peelHop(wallet, forward, history)
+		if !ok {
+			break
+		}
+		hops = append(hops, hop)
+		forward = hop.Funding
+	}
+	return hops, nil
+}
+
+// peelHop checks whether wallet forwarded most of its latest funding in forward
+// and sent the small remainder somewhere else
+func (d *PeelChainDetector) peelHop(wallet string, forward entity.Transaction, history []entity.Transaction) (peelHop, bool) {
+	var funding *entity.Transaction
+	outgoing := 0
+	for i := range history {
+		tx := &history[i]
+		if strings.EqualFold(tx.From, wallet) {
+			if withinWindow(tx.Timestamp, forward.Timestamp, d.HopWindow) {
+				outgoing++
+			}
+			continue
+		}
+		if !strings.EqualFold(recipient(*tx), wallet) || tx.Timestamp.After(forward.Timestamp) {
+			continue
+		}
+		if forward.Timestamp.Sub(tx.Timestamp) > d.HopWindow {
+			continue
+		}
+		if funding == nil || tx.Timestamp.After(funding.Timestamp) {
+			funding = tx
+		}
+	}
+	// A peel hop sends the remainder plus one or two small peels
+	if funding == nil || outgoing < 2 || outgoing > 3 {
+		return peelHop{}, false
+	}
+
+	received := weiToETH(funding.Value)
+	forwarded := weiToETH(forward.Value)
+	if received <= 0 || forwarded >= received {
+		return peelHop{}, false
+	}
+	peelRatio := (received - forwarded) / received
+	if peelRatio > d.MaxPeelRatio {
+		return peelHop{}, false
+	}
+
+	return peelHop{Wallet: wallet, Funding: *funding, Forward: forward, PeelRatio: peelRatio}, true
+}
+
+func (d *PeelChainDetector) finding(tx entity.Transaction, hops []peelHop) Finding {
+	origin := hops[len(hops)-1]
+	wallets := make([]string, 0, len(hops)+1)
+	related := make([]string, 0, len(hops)+1)
+	ratios := make([]float64, 0, len(hops))
+	for i := len(hops) - 1; i >= 0; i-- {
+		wallets = append(wallets, hops[i].Wallet)
+		related = append(related, hops[i].Funding.Hash)
+		ratios = append(ratios, hops[i].PeelRatio)
+	}
+	wallets = append(wallets, recipient(tx))
+	related = append(related, tx.Hash)
+
+	severity := entity.AlertSeverityMedium
+	if len(hops) >= 2*d.MinHops {
+		severity = entity.AlertSeverityHigh
+	}
+
+	return Finding{
+		Type:     entity.AlertTypeLaundering,
+		Severity: severity,
+		Title:    "Peel chain detected",
+		Description: fmt.Sprintf("Funds from %s passed through %d wallets that each forwarded most of the amount and peeled off up to %.0f%%",
+			origin.Funding.From, len(hops), d.MaxPeelRatio*100),
+		WalletAddress:       entity.NormalizeAddress(origin.Funding.From),
+		Confidence:          clampConfidence(50 + 8*float64(len(hops)-d.MinHops)),
+		RelatedTransactions: related,
+		ActionRequired:      severity == entity.AlertSeverityHigh,
+		// The chain is identified by where it started, so extending it does not alert again
+		DedupeKey: origin.Funding.Hash,
+		Timestamp: tx.Timestamp,
+		Metadata: map[string]interface{}{
+			"hops":        len(hops),
+			"wallets":     wallets,
+			"peel_ratios": ratios,
+		},
+	}
+}
+
+// withinWindow reports whether t is within window of reference, either side
+func withinWindow(t, reference time.Time, window time.Duration) bool {
+	d := t.Sub(reference)
+	return d >= -window && d <= window
+}
@@ -0,0 +1,65 @@
+package detection
+
+import "sync"
+
+// Registry holds the detectors the runner evaluates, in registration order
+type Registry struct {
+	mu        sync.RWMutex
+	detectors []Detector
+}
+
+// NewRegistry creates a registry with the given detectors registered
+func NewRegistry(detectors ...Detector) *Registry {
+	r := &Registry{}
+	for _, detector := range detectors {
+		r.Register(detector)
+	}
+	return r
+}
+
+// Register installs a detector, replacing any registered detector with the same name
+func (r *Registry) Register(detector Detector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.detectors {
+		if existing.Name() == detector.Name() {
+			r.detectors[i] = detector
+			return
+		}
+	}
+	r.detectors = append(r.detectors, detector)
+}
+
+// Unregister removes a detector by name
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.detectors {
+		if existing.Name() == name {
+			r.detectors = append(r.detectors[:i], r.detectors[i+1:]...)
+			return
+		}
+	}
+}
+
+// Detectors returns the registered detectors
+func (r *Registry) Detectors() []Detector {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	detectors := make([]Detector, len(r.detectors))
+	copy(detectors, r.detectors)
+	return detectors
+}
+
+// Names returns the names of the registered detectors
+func (r *Registry) Names() []string {
+	detectors := r.Detectors()
+	names := make([]string, len(detectors))
+	for i, detector := range detectors {
+		names[i] = detector.Name()
+	}
+	return names
+}
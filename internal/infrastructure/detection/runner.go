@@ -0,0 +1,294 @@
+package detection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/config"
+	"crypto-bubble-map-be/internal/infrastructure/monitoring"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// checkpointName identifies the runner's position in the transaction stream
+	checkpointName = "transaction_detectors"
+	// maxBatchesPerRun bounds how much backlog a single run works through
+	maxBatchesPerRun = 10
+	// runTimeout bounds a single run
+	runTimeout = 5 * time.Minute
+)
+
+// RunResult summarizes one run over new transactions
+type RunResult struct {
+	Transactions int
+	Findings     int
+	AlertsRaised int
+}
+
+// Runner evaluates the registered detectors over transactions as they are crawled
+// into MongoDB and raises a security alert for each new finding. Progress is
+// checkpointed once a batch was fully processed, so every transaction is
+// evaluated across restarts and failures; a batch evaluated again raises no
+// duplicate alerts.
+type Runner struct {
+	transactionRepo repository.TransactionRepository
+	securityRepo    repository.SecurityRepository
+	registry        *Registry
+	config          *config.DetectionConfig
+	metrics         *monitoring.MetricsCollector
+	logger          *zap.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// DefaultDetectors returns the built-in detectors configured from cfg
+func DefaultDetectors(
+	transactionRepo repository.TransactionRepository,
+	walletRepo repository.WalletRepository,
+	sanctionsRepo repository.SanctionsRepository,
+	mevRepo repository.MEVRepository,
+	cfg *config.DetectionConfig,
+) []Detector {
+	return []Detector{
+		NewPeelChainDetector(cfg.PeelChainMinHops, cfg.PeelChainMaxPeelRatio, cfg.PeelChainHopWindow, cfg.PeelChainMinValueETH),
+		NewFanDetector(walletRepo, cfg.FanMinCounterparties, cfg.FanWindow),
+		NewKnownBadDetector(sanctionsRepo, walletRepo),
+		NewMEVDetector(transactionRepo, walletRepo, mevRepo, cfg.MEVTagWallets),
+	}
+}
+
+// NewRunner creates a detection runner with the default detectors registered
+func NewRunner(
+	transactionRepo repository.TransactionRepository,
+	walletRepo repository.WalletRepository,
+	sanctionsRepo repository.SanctionsRepository,
+	securityRepo repository.SecurityRepository,
+	mevRepo repository.MEVRepository,
+	cfg *config.DetectionConfig,
+	metrics *monitoring.MetricsCollector,
+	logger *zap.Logger,
+) *Runner {
+	return &Runner{
+		transactionRepo: transactionRepo,
+		securityRepo:    securityRepo,
+		registry:        NewRegistry(DefaultDetectors(transactionRepo, walletRepo, sanctionsRepo, mevRepo, cfg)...),
+		config:          cfg,
+		metrics:         metrics,
+		logger:          logger,
+	}
+}
+
+// Registry returns the runner's detector registry, for adding custom detectors
+func (r *Runner) Registry() *Registry {
+	return r.registry
+}
+
+// Start runs the detectors every configured interval until Stop is called
+func (r *Runner) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(r.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			r.runScheduled(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	r.logger.Info("Started detection runner",
+		zap.Duration("interval", r.config.Interval),
+		zap.Strings("detectors", r.registry.Names()))
+}
+
+// Stop stops the schedule and waits for a run in progress to finish
+func (r *Runner) Stop() {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel = nil
+	r.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+	r.logger.Info("Stopped detection runner")
+}
+
+func (r *Runner) runScheduled(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, runTimeout)
+	defer cancel()
+
+	result, err := r.RunOnce(runCtx)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Error("Detection run failed", zap.Error(err))
+		}
+		return
+	}
+	if result.Transactions > 0 {
+		r.logger.Info("Detection run completed",
+			zap.Int("transactions", result.Transactions),
+			zap.Int("findings", result.Findings),
+			zap.Int("alertsRaised", result.AlertsRaised))
+	}
+}
+
+// RunOnce evaluates the detectors over transactions crawled since the checkpoint,
+// a batch at a time, until caught up or maxBatchesPerRun batches were processed
+func (r *Runner) RunOnce(ctx context.Context) (*RunResult, error) {
+	checkpoint, err := r.securityRepo.GetDetectionCheckpoint(ctx, checkpointName)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint == nil {
+		// Start from recent history rather than the whole collection
+		checkpoint = &entity.DetectionCheckpoint{
+			Name:   checkpointName,
+			Cursor: entity.TransactionCursor{CrawledAt: time.Now().Add(-r.config.InitialLookback)},
+		}
+	}
+
+	result := &RunResult{}
+	for i := 0; i < maxBatchesPerRun; i++ {
+		transactions, err := r.transactionRepo.GetTransactionsAfter(ctx, checkpoint.Cursor, r.config.BatchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(transactions) == 0 {
+			break
+		}
+
+		findings, raised, err := r.processBatch(ctx, transactions)
+		result.Findings += findings
+		result.AlertsRaised += raised
+		if err != nil {
+			// The checkpoint stays before the batch so that it is evaluated
+			// again; alerts already raised for it are deduplicated then
+			return result, err
+		}
+		result.Transactions += len(transactions)
+
+		last := transactions[len(transactions)-1]
+		checkpoint.Cursor = entity.TransactionCursor{CrawledAt: last.CrawledAt, ID: last.ID}
+		checkpoint.Processed += int64(len(transactions))
+		checkpoint.AlertsRaised += int64(raised)
+		checkpoint.UpdatedAt = time.Now()
+		if err := r.securityRepo.SaveDetectionCheckpoint(ctx, checkpoint); err != nil {
+			return result, err
+		}
+
+		if int64(len(transactions)) < r.config.BatchSize {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// processBatch runs every detector over the batch and raises alerts for their
+// findings. A failing detector or alert does not stop the others, but the batch
+// fails with every error so that it is not checkpointed.
+func (r *Runner) processBatch(ctx context.Context, transactions []entity.Transaction) (findings, raised int, err error) {
+	var errs []error
+	batch := NewBatch(transactions, r.transactionRepo, r.lookback())
+	r.metrics.Add("detection_transactions_total", float64(len(transactions)), nil,
+		"Transactions evaluated by detectors")
+
+	for _, detector := range r.registry.Detectors() {
+		detected, err := detector.Detect(ctx, batch)
+		if err != nil {
+			r.logger.Error("Detector failed",
+				zap.String("detector", detector.Name()),
+				zap.Int("transactions", len(transactions)),
+				zap.Error(err))
+			r.metrics.Counter("detection_errors_total",
+				map[string]string{"detector": detector.Name()},
+				"Detector runs that failed")
+			errs = append(errs, fmt.Errorf("detector %s failed: %w", detector.Name(), err))
+			continue
+		}
+
+		for _, finding := range detected {
+			findings++
+			created, err := r.securityRepo.CreateSecurityAlertIfNew(ctx, toAlert(detector.Name(), finding))
+			if err != nil {
+				r.logger.Error("Failed to raise alert for finding",
+					zap.String("detector", detector.Name()),
+					zap.String("wallet", finding.WalletAddress),
+					zap.Error(err))
+				errs = append(errs, fmt.Errorf("failed to raise %s alert: %w", detector.Name(), err))
+				continue
+			}
+			if created {
+				raised++
+				r.metrics.Counter("detection_alerts_total",
+					map[string]string{"detector": detector.Name(), "severity": string(finding.Severity)},
+					"Security alerts raised by detectors")
+			}
+		}
+	}
+	return findings, raised, errors.Join(errs...)
+}
+
+// lookback is how far before a batch detectors may need wallet history: the
+// longest peel chain the detector follows, or the fan-out window
+func (r *Runner) lookback() time.Duration {
+	lookback := r.config.PeelChainHopWindow * maxPeelChainHops
+	if r.config.FanWindow > lookback {
+		lookback = r.config.FanWindow
+	}
+	return lookback
+}
+
+// toAlert converts a finding into a security alert, namespacing its dedupe key by detector
+func toAlert(detectorName string, finding Finding) *entity.SecurityAlert {
+	metadata := make(map[string]interface{}, len(finding.Metadata)+1)
+	for key, value := range finding.Metadata {
+		metadata[key] = value
+	}
+	metadata["detector"] = detectorName
+
+	timestamp := finding.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	return &entity.SecurityAlert{
+		Type:                finding.Type,
+		Severity:            finding.Severity,
+		Title:               finding.Title,
+		Description:         finding.Description,
+		WalletAddress:       finding.WalletAddress,
+		Timestamp:           timestamp,
+		Status:              entity.AlertStatusActive,
+		Confidence:          finding.Confidence,
+		RelatedTransactions: finding.RelatedTransactions,
+		ActionRequired:      finding.ActionRequired,
+		Metadata:            metadata,
+		DedupeKey:           detectorName + ":" + finding.DedupeKey,
+	}
+}
@@ -1,8 +1,10 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -34,6 +36,13 @@ const (
 	ErrCodeWalletAlreadyWatched ErrorCode = "WALLET_ALREADY_WATCHED"
 	ErrCodeNetworkNotSupported  ErrorCode = "NETWORK_NOT_SUPPORTED"
 	ErrCodeInsufficientData     ErrorCode = "INSUFFICIENT_DATA"
+	ErrCodeReportNotFound       ErrorCode = "REPORT_NOT_FOUND"
+	ErrCodeInvalidTransition    ErrorCode = "INVALID_STATE_TRANSITION"
+	ErrCodeConversationNotFound ErrorCode = "CONVERSATION_NOT_FOUND"
+	ErrCodeCaseNotFound         ErrorCode = "CASE_NOT_FOUND"
+	ErrCodeLabelNotFound        ErrorCode = "LABEL_NOT_FOUND"
+	ErrCodeProposalNotFound     ErrorCode = "LABEL_PROPOSAL_NOT_FOUND"
+	ErrCodeTransactionNotFound  ErrorCode = "TRANSACTION_NOT_FOUND"
 
 	// External service errors
 	ErrCodeExternalAPIFailure    ErrorCode = "EXTERNAL_API_FAILURE"
@@ -175,7 +184,7 @@ func getHTTPStatusForCode(code ErrorCode) int {
 		return http.StatusForbidden
 
 	// Not found errors -> 404 Not Found
-	case ErrCodeAuthUserNotFound, ErrCodeWalletNotFound:
+	case ErrCodeAuthUserNotFound, ErrCodeWalletNotFound, ErrCodeReportNotFound, ErrCodeConversationNotFound, ErrCodeCaseNotFound, ErrCodeLabelNotFound, ErrCodeProposalNotFound, ErrCodeTransactionNotFound:
 		return http.StatusNotFound
 
 	// Validation errors -> 400 Bad Request
@@ -183,15 +192,15 @@ func getHTTPStatusForCode(code ErrorCode) int {
 		return http.StatusBadRequest
 
 	// Conflict errors -> 409 Conflict
-	case ErrCodeWalletAlreadyWatched:
+	case ErrCodeWalletAlreadyWatched, ErrCodeInvalidTransition:
 		return http.StatusConflict
 
 	// Rate limiting -> 429 Too Many Requests
-	case ErrCodeExternalAPIRateLimit:
+	case ErrCodeExternalAPIRateLimit, ErrCodeAIQuotaExceeded:
 		return http.StatusTooManyRequests
 
 	// Service unavailable -> 503 Service Unavailable
-	case ErrCodeServiceUnavailable, ErrCodeDatabaseTimeout, ErrCodeExternalAPITimeout:
+	case ErrCodeServiceUnavailable, ErrCodeDatabaseConnection, ErrCodeDatabaseTimeout, ErrCodeExternalAPITimeout:
 		return http.StatusServiceUnavailable
 
 	// Resource exhausted -> 507 Insufficient Storage
@@ -204,11 +213,20 @@ func getHTTPStatusForCode(code ErrorCode) int {
 	}
 }
 
+// AsAppError finds the first AppError in err's chain
+func AsAppError(err error) (*AppError, bool) {
+	var appErr *AppError
+	if stderrors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
 // IsRetryable determines if an error is retryable
 func IsRetryable(err error) bool {
-	if appErr, ok := err.(*AppError); ok {
+	if appErr, ok := AsAppError(err); ok {
 		switch appErr.Code {
-		case ErrCodeDatabaseTimeout, ErrCodeExternalAPITimeout, ErrCodeExternalAPIFailure,
+		case ErrCodeDatabaseConnection, ErrCodeDatabaseTimeout, ErrCodeExternalAPITimeout, ErrCodeExternalAPIFailure,
 			ErrCodeServiceUnavailable, ErrCodeCacheTimeout:
 			return true
 		default:
@@ -220,9 +238,9 @@ func IsRetryable(err error) bool {
 
 // IsTemporary determines if an error is temporary
 func IsTemporary(err error) bool {
-	if appErr, ok := err.(*AppError); ok {
+	if appErr, ok := AsAppError(err); ok {
 		switch appErr.Code {
-		case ErrCodeDatabaseTimeout, ErrCodeExternalAPITimeout, ErrCodeServiceUnavailable,
+		case ErrCodeDatabaseConnection, ErrCodeDatabaseTimeout, ErrCodeExternalAPITimeout, ErrCodeServiceUnavailable,
 			ErrCodeCacheTimeout, ErrCodeResourceExhausted:
 			return true
 		default:
@@ -288,8 +306,9 @@ type ErrorMetrics struct {
 	Operation string    `json:"operation"`
 }
 
-// ErrorCollector collects error metrics
+// ErrorCollector collects error metrics. It is safe for concurrent use.
 type ErrorCollector struct {
+	mu      sync.Mutex
 	metrics map[string]*ErrorMetrics
 }
 
@@ -302,8 +321,11 @@ func NewErrorCollector() *ErrorCollector {
 
 // Collect records an error occurrence
 func (ec *ErrorCollector) Collect(err *AppError, service, operation string) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
 	key := fmt.Sprintf("%s:%s:%s", err.Code, service, operation)
-	
+
 	if metric, exists := ec.metrics[key]; exists {
 		metric.Count++
 		metric.LastSeen = time.Now()
@@ -318,12 +340,22 @@ func (ec *ErrorCollector) Collect(err *AppError, service, operation string) {
 	}
 }
 
-// GetMetrics returns all collected metrics
+// GetMetrics returns a copy of all collected metrics
 func (ec *ErrorCollector) GetMetrics() map[string]*ErrorMetrics {
-	return ec.metrics
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
+	result := make(map[string]*ErrorMetrics, len(ec.metrics))
+	for key, metric := range ec.metrics {
+		metricCopy := *metric
+		result[key] = &metricCopy
+	}
+	return result
 }
 
 // Reset clears all metrics
 func (ec *ErrorCollector) Reset() {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
 	ec.metrics = make(map[string]*ErrorMetrics)
 }
@@ -9,24 +9,33 @@ import (
 	"time"
 
 	"crypto-bubble-map-be/internal/infrastructure/config"
+	"crypto-bubble-map-be/internal/infrastructure/resilience"
 
 	"go.uber.org/zap"
 )
 
 // BlockchainAPIClient provides access to external blockchain APIs
 type BlockchainAPIClient struct {
-	config     *config.ExternalConfig
-	logger     *zap.Logger
-	httpClient *http.Client
+	config          *config.ExternalConfig
+	logger          *zap.Logger
+	coinGeckoClient *http.Client
+	etherscanClient *http.Client
 }
 
-// NewBlockchainAPIClient creates a new blockchain API client
-func NewBlockchainAPIClient(cfg *config.ExternalConfig, logger *zap.Logger) *BlockchainAPIClient {
+// NewBlockchainAPIClient creates a new blockchain API client. Each API is
+// guarded by its own circuit breaker from breakers, so that CoinGecko being
+// down does not stop gas price updates.
+func NewBlockchainAPIClient(cfg *config.ExternalConfig, breakers *resilience.Registry, logger *zap.Logger) *BlockchainAPIClient {
 	return &BlockchainAPIClient{
 		config: cfg,
 		logger: logger,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+		coinGeckoClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: resilience.NewTransport(nil, breakers.Breaker(resilience.DependencyCoinGecko, resilience.IsHTTPFailure)),
+		},
+		etherscanClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: resilience.NewTransport(nil, breakers.Breaker(resilience.DependencyEtherscan, resilience.IsHTTPFailure)),
 		},
 	}
 }
@@ -117,7 +126,7 @@ func (c *BlockchainAPIClient) fetchCoinGeckoData(ctx context.Context, networkID
 		req.Header.Set("X-CG-Demo-API-Key", c.config.CoinGeckoAPIKey)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.coinGeckoClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to make request: %w", err)
 	}
@@ -182,7 +191,7 @@ func (c *BlockchainAPIClient) fetchEthereumGasPrice(ctx context.Context, data *N
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.etherscanClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to make request: %w", err)
 	}
@@ -0,0 +1,145 @@
+package health
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Capability is a feature of the API and the components it depends on. The
+// server keeps serving while components are unavailable; capabilities tell
+// clients and operators which features are affected.
+type Capability struct {
+	Name string
+	// Requires lists the components without which the capability is
+	// unavailable
+	Requires []string
+	// DegradedWithout lists the components without which the capability
+	// still works, with reduced performance or freshness
+	DegradedWithout []string
+	// Degradation describes how the capability behaves while degraded
+	Degradation string
+}
+
+// Names of the capabilities reported by readiness
+const (
+	CapabilityWalletQueries = "wallet_queries"
+	CapabilityWalletNetwork = "wallet_network"
+	CapabilityTransactions  = "transactions"
+	CapabilityNetworkStats  = "network_stats"
+	CapabilityWatchLists    = "watch_lists"
+	CapabilityAccounts      = "accounts"
+	CapabilityAIAssistant   = "ai_assistant"
+	CapabilityCompliance    = "compliance"
+	CapabilityRateLimiting  = "rate_limiting"
+)
+
+// DefaultCapabilities are the capabilities of the API server, keyed to the
+// names of the datastore health checkers
+var DefaultCapabilities = []Capability{
+	{
+		Name:            CapabilityWalletQueries,
+		Requires:        []string{"neo4j"},
+		DegradedWithout: []string{"redis"},
+		Degradation:     "served uncached",
+	},
+	{
+		Name:            CapabilityWalletNetwork,
+		DegradedWithout: []string{"neo4j", "redis"},
+		Degradation:     "last cached networks are served marked stale",
+	},
+	{
+		Name:            CapabilityTransactions,
+		Requires:        []string{"mongodb"},
+		DegradedWithout: []string{"redis"},
+		Degradation:     "served uncached",
+	},
+	{
+		Name:            CapabilityNetworkStats,
+		DegradedWithout: []string{"neo4j", "mongodb", "redis"},
+		Degradation:     "last cached values are served marked stale",
+	},
+	{
+		Name:     CapabilityWatchLists,
+		Requires: []string{"postgresql"},
+	},
+	{
+		Name:     CapabilityAccounts,
+		Requires: []string{"postgresql"},
+	},
+	{
+		Name:            CapabilityAIAssistant,
+		Requires:        []string{"postgresql"},
+		DegradedWithout: []string{"redis"},
+		Degradation:     "responses are not cached",
+	},
+	{
+		Name:     CapabilityCompliance,
+		Requires: []string{"mongodb", "neo4j"},
+	},
+	{
+		Name:            CapabilityRateLimiting,
+		DegradedWithout: []string{"redis"},
+		Degradation:     "requests are not rate limited",
+	},
+}
+
+// CapabilityStatus is the current status of a capability
+type CapabilityStatus struct {
+	Name                  string   `json:"name"`
+	Status                Status   `json:"status"`
+	Message               string   `json:"message,omitempty"`
+	UnavailableComponents []string `json:"unavailable_components,omitempty"`
+}
+
+// Capabilities returns the status of every capability from the last health
+// check: unhealthy if a required component is unavailable, degraded if any
+// other component it uses is
+func (hm *HealthManager) Capabilities() []CapabilityStatus {
+	statuses := make([]CapabilityStatus, 0, len(hm.capabilities))
+	for _, capability := range hm.capabilities {
+		statuses = append(statuses, hm.capabilityStatus(capability))
+	}
+	return statuses
+}
+
+// CapabilityAvailable reports whether the named capability can currently
+// serve requests, possibly degraded. Unknown capabilities are available.
+func (hm *HealthManager) CapabilityAvailable(name string) bool {
+	for _, capability := range hm.capabilities {
+		if capability.Name == name {
+			return hm.capabilityStatus(capability).Status != StatusUnhealthy
+		}
+	}
+	return true
+}
+
+// capabilityStatus computes a capability's status from the last health check
+func (hm *HealthManager) capabilityStatus(capability Capability) CapabilityStatus {
+	status := CapabilityStatus{Name: capability.Name, Status: StatusHealthy}
+
+	var missing, degraded []string
+	for _, component := range capability.Requires {
+		if !hm.Available(component) {
+			missing = append(missing, component)
+		}
+	}
+	for _, component := range capability.DegradedWithout {
+		if !hm.Available(component) {
+			degraded = append(degraded, component)
+		}
+	}
+
+	switch {
+	case len(missing) > 0:
+		status.Status = StatusUnhealthy
+		status.Message = fmt.Sprintf("%s unavailable", strings.Join(missing, ", "))
+	case len(degraded) > 0:
+		status.Status = StatusDegraded
+		status.Message = fmt.Sprintf("%s unavailable", strings.Join(degraded, ", "))
+		if capability.Degradation != "" {
+			status.Message += "; " + capability.Degradation
+		}
+	}
+	status.UnavailableComponents = append(missing, degraded...)
+	return status
+}
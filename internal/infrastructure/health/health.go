@@ -2,6 +2,7 @@ package health
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -9,6 +10,8 @@ import (
 	"crypto-bubble-map-be/internal/infrastructure/cache"
 	"crypto-bubble-map-be/internal/infrastructure/config"
 	"crypto-bubble-map-be/internal/infrastructure/database"
+	"crypto-bubble-map-be/internal/infrastructure/migrations"
+	"crypto-bubble-map-be/internal/infrastructure/resilience"
 
 	"go.uber.org/zap"
 )
@@ -49,24 +52,49 @@ type HealthChecker interface {
 	Check(ctx context.Context) ComponentHealth
 }
 
-// HealthManager manages health checks for all system components
+// initialCheckTimeout bounds the startup health check, so that unreachable
+// components delay startup by at most this long
+const initialCheckTimeout = 10 * time.Second
+
+// InitTask prepares a component once it is reachable, e.g. by running
+// migrations or creating indexes
+type InitTask struct {
+	Component string
+	Name      string
+	Run       func(ctx context.Context) error
+}
+
+// HealthManager manages health checks for all system components. Besides
+// answering health endpoints, it tracks which components are available so
+// that the server can run degraded while some of them are down.
 type HealthManager struct {
-	checkers  []HealthChecker
-	config    *config.Config
-	logger    *zap.Logger
-	startTime time.Time
-	mu        sync.RWMutex
-	lastCheck map[string]ComponentHealth
+	checkers     []HealthChecker
+	capabilities []Capability
+	config       *config.Config
+	logger       *zap.Logger
+	startTime    time.Time
+	mu           sync.RWMutex
+	lastCheck    map[string]ComponentHealth
+
+	// pending holds the init tasks of components that were unreachable,
+	// run by the background loop once they recover
+	taskMu  sync.Mutex
+	pending []InitTask
+
+	loopMu sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
 }
 
 // NewHealthManager creates a new health manager
 func NewHealthManager(cfg *config.Config, logger *zap.Logger) *HealthManager {
 	return &HealthManager{
-		checkers:  make([]HealthChecker, 0),
-		config:    cfg,
-		logger:    logger,
-		startTime: time.Now(),
-		lastCheck: make(map[string]ComponentHealth),
+		checkers:     make([]HealthChecker, 0),
+		capabilities: DefaultCapabilities,
+		config:       cfg,
+		logger:       logger,
+		startTime:    time.Now(),
+		lastCheck:    make(map[string]ComponentHealth),
 	}
 }
 
@@ -77,10 +105,13 @@ func (hm *HealthManager) RegisterChecker(checker HealthChecker) {
 	hm.checkers = append(hm.checkers, checker)
 }
 
-// CheckHealth performs health checks on all registered components
+// CheckHealth performs health checks on all registered components. The lock
+// is only held to record results, so that availability lookups do not wait
+// for slow checks.
 func (hm *HealthManager) CheckHealth(ctx context.Context) SystemHealth {
-	hm.mu.Lock()
-	defer hm.mu.Unlock()
+	hm.mu.RLock()
+	checkers := append([]HealthChecker(nil), hm.checkers...)
+	hm.mu.RUnlock()
 
 	components := make(map[string]ComponentHealth)
 	overallStatus := StatusHealthy
@@ -91,9 +122,9 @@ func (hm *HealthManager) CheckHealth(ctx context.Context) SystemHealth {
 		health ComponentHealth
 	}
 
-	resultChan := make(chan result, len(hm.checkers))
+	resultChan := make(chan result, len(checkers))
 
-	for _, checker := range hm.checkers {
+	for _, checker := range checkers {
 		go func(c HealthChecker) {
 			health := c.Check(ctx)
 			resultChan <- result{name: c.Name(), health: health}
@@ -101,11 +132,12 @@ func (hm *HealthManager) CheckHealth(ctx context.Context) SystemHealth {
 	}
 
 	// Collect results
-	for i := 0; i < len(hm.checkers); i++ {
+collect:
+	for i := 0; i < len(checkers); i++ {
 		select {
 		case res := <-resultChan:
 			components[res.name] = res.health
-			hm.lastCheck[res.name] = res.health
+			hm.record(res.health)
 
 			// Determine overall status
 			switch res.health.Status {
@@ -120,9 +152,27 @@ func (hm *HealthManager) CheckHealth(ctx context.Context) SystemHealth {
 		case <-ctx.Done():
 			hm.logger.Warn("Health check timeout", zap.Error(ctx.Err()))
 			overallStatus = StatusUnknown
+			break collect
 		}
 	}
 
+	// Components that did not answer in time are treated as unavailable;
+	// checks cancelled by the caller say nothing about them
+	for _, checker := range checkers {
+		if _, ok := components[checker.Name()]; ok || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			continue
+		}
+		timedOut := ComponentHealth{
+			Name:        checker.Name(),
+			Status:      StatusUnhealthy,
+			Message:     "Health check timed out",
+			LastChecked: time.Now(),
+			Error:       ctx.Err().Error(),
+		}
+		components[timedOut.Name] = timedOut
+		hm.record(timedOut)
+	}
+
 	return SystemHealth{
 		Status:     overallStatus,
 		Timestamp:  time.Now(),
@@ -140,6 +190,172 @@ func (hm *HealthManager) GetLastCheck(componentName string) (ComponentHealth, bo
 	return health, exists
 }
 
+// record stores a check result, logging components becoming unavailable or
+// recovering
+func (hm *HealthManager) record(health ComponentHealth) {
+	hm.mu.Lock()
+	previous, checked := hm.lastCheck[health.Name]
+	hm.lastCheck[health.Name] = health
+	hm.mu.Unlock()
+
+	wasAvailable := !checked || previous.Status != StatusUnhealthy
+	isAvailable := health.Status != StatusUnhealthy
+	switch {
+	case wasAvailable && !isAvailable:
+		hm.logger.Warn("Component unavailable, serving degraded",
+			zap.String("component", health.Name),
+			zap.String("error", health.Error))
+	case !wasAvailable && isAvailable:
+		hm.logger.Info("Component recovered", zap.String("component", health.Name))
+	}
+}
+
+// Available reports whether a component passed its last health check.
+// Components not checked yet are assumed available.
+func (hm *HealthManager) Available(componentName string) bool {
+	health, checked := hm.GetLastCheck(componentName)
+	return !checked || health.Status != StatusUnhealthy
+}
+
+// Initialize checks every component and runs the init tasks of those that
+// are reachable. Unless requireAll is set, the tasks of unreachable
+// components, and tasks that fail, are left to the background loop and the
+// server starts degraded; with requireAll, an unreachable component or a
+// failing task is returned as an error.
+func (hm *HealthManager) Initialize(ctx context.Context, requireAll bool, tasks ...InitTask) error {
+	checkCtx, cancel := context.WithTimeout(ctx, initialCheckTimeout)
+	systemHealth := hm.CheckHealth(checkCtx)
+	cancel()
+
+	unavailable := make([]string, 0)
+	for name, component := range systemHealth.Components {
+		if component.Status == StatusUnhealthy {
+			unavailable = append(unavailable, name)
+		}
+	}
+	if requireAll && len(unavailable) > 0 {
+		return fmt.Errorf("components unavailable: %v", unavailable)
+	}
+
+	for _, task := range tasks {
+		if !hm.Available(task.Component) {
+			hm.deferTask(task)
+			continue
+		}
+		if err := task.Run(ctx); err != nil {
+			if requireAll {
+				return fmt.Errorf("failed to %s: %w", task.Name, err)
+			}
+			hm.logger.Error("Init task failed, retrying in background",
+				zap.String("component", task.Component),
+				zap.String("task", task.Name),
+				zap.Error(err))
+			hm.deferTask(task)
+		}
+	}
+
+	if len(unavailable) > 0 {
+		degraded := make([]string, 0)
+		for _, capability := range hm.Capabilities() {
+			if capability.Status != StatusHealthy {
+				degraded = append(degraded, capability.Name)
+			}
+		}
+		hm.logger.Warn("Starting in degraded mode",
+			zap.Strings("unavailableComponents", unavailable),
+			zap.Strings("degradedCapabilities", degraded))
+	}
+	return nil
+}
+
+// deferTask queues a task until its component is available
+func (hm *HealthManager) deferTask(task InitTask) {
+	hm.taskMu.Lock()
+	defer hm.taskMu.Unlock()
+	hm.pending = append(hm.pending, task)
+}
+
+// runPendingTasks runs the deferred init tasks of available components,
+// keeping those that fail for the next round
+func (hm *HealthManager) runPendingTasks(ctx context.Context) {
+	hm.taskMu.Lock()
+	defer hm.taskMu.Unlock()
+
+	remaining := hm.pending[:0]
+	for _, task := range hm.pending {
+		if !hm.Available(task.Component) {
+			remaining = append(remaining, task)
+			continue
+		}
+		if err := task.Run(ctx); err != nil {
+			hm.logger.Error("Deferred init task failed",
+				zap.String("component", task.Component),
+				zap.String("task", task.Name),
+				zap.Error(err))
+			remaining = append(remaining, task)
+			continue
+		}
+		hm.logger.Info("Deferred init task completed",
+			zap.String("component", task.Component),
+			zap.String("task", task.Name))
+	}
+	hm.pending = remaining
+}
+
+// Start checks every component in the background at interval, so that
+// availability reflects components going down and recovering, and runs
+// deferred init tasks once their component is reachable
+func (hm *HealthManager) Start(interval time.Duration) {
+	hm.loopMu.Lock()
+	defer hm.loopMu.Unlock()
+
+	if hm.cancel != nil || interval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	hm.cancel = cancel
+	hm.done = make(chan struct{})
+
+	go func() {
+		defer close(hm.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				checkCtx, checkCancel := context.WithTimeout(ctx, interval)
+				hm.CheckHealth(checkCtx)
+				checkCancel()
+				if ctx.Err() != nil {
+					return
+				}
+				hm.runPendingTasks(ctx)
+			}
+		}
+	}()
+
+	hm.logger.Info("Health monitoring started", zap.Duration("interval", interval))
+}
+
+// Stop stops the background health checks
+func (hm *HealthManager) Stop() {
+	hm.loopMu.Lock()
+	defer hm.loopMu.Unlock()
+
+	if hm.cancel == nil {
+		return
+	}
+	hm.cancel()
+	<-hm.done
+	hm.cancel = nil
+	hm.logger.Info("Health monitoring stopped")
+}
+
 // PostgreSQLHealthChecker checks PostgreSQL database health
 type PostgreSQLHealthChecker struct {
 	client *database.PostgreSQLClient
@@ -405,6 +621,56 @@ func (c *ExternalAPIHealthChecker) Check(ctx context.Context) ComponentHealth {
 	return health
 }
 
+// CircuitBreakerHealthChecker reports the state of every dependency's
+// circuit breaker. The system is degraded while any breaker is not closed,
+// since requests needing that dependency fail fast.
+type CircuitBreakerHealthChecker struct {
+	breakers *resilience.Registry
+	logger   *zap.Logger
+}
+
+// NewCircuitBreakerHealthChecker creates a new circuit breaker health checker
+func NewCircuitBreakerHealthChecker(breakers *resilience.Registry, logger *zap.Logger) *CircuitBreakerHealthChecker {
+	return &CircuitBreakerHealthChecker{
+		breakers: breakers,
+		logger:   logger,
+	}
+}
+
+// Name returns the checker name
+func (c *CircuitBreakerHealthChecker) Name() string {
+	return "circuit_breakers"
+}
+
+// Check performs the health check
+func (c *CircuitBreakerHealthChecker) Check(ctx context.Context) ComponentHealth {
+	start := time.Now()
+	health := ComponentHealth{
+		Name:        c.Name(),
+		LastChecked: start,
+		Metadata:    make(map[string]interface{}),
+	}
+
+	unavailable := make([]string, 0)
+	for _, snapshot := range c.breakers.Snapshots() {
+		health.Metadata[snapshot.Name] = snapshot
+		if snapshot.State != resilience.StateClosed.String() {
+			unavailable = append(unavailable, snapshot.Name)
+		}
+	}
+
+	if len(unavailable) > 0 {
+		health.Status = StatusDegraded
+		health.Message = fmt.Sprintf("Circuit breakers not closed: %v", unavailable)
+	} else {
+		health.Status = StatusHealthy
+		health.Message = "All circuit breakers are closed"
+	}
+
+	health.Duration = time.Since(start)
+	return health
+}
+
 // SetupHealthCheckers sets up all health checkers
 func SetupHealthCheckers(
 	hm *HealthManager,
@@ -412,6 +678,7 @@ func SetupHealthCheckers(
 	mongo *database.MongoClient,
 	neo4j *database.Neo4jClient,
 	redis *cache.RedisClient,
+	breakers *resilience.Registry,
 	config *config.Config,
 	logger *zap.Logger,
 ) {
@@ -434,4 +701,25 @@ func SetupHealthCheckers(
 
 	// Register external API health checker
 	hm.RegisterChecker(NewExternalAPIHealthChecker(&config.External, logger))
+
+	if breakers != nil {
+		hm.RegisterChecker(NewCircuitBreakerHealthChecker(breakers, logger))
+	}
+}
+
+// DatastoreInitTasks returns a task per store of runner applying its pending
+// migrations. Store names match the health checker names, so the migrations
+// of an unreachable store wait until it recovers.
+func DatastoreInitTasks(runner *migrations.Runner) []InitTask {
+	tasks := make([]InitTask, 0, len(runner.Stores()))
+	for _, store := range runner.Stores() {
+		tasks = append(tasks, InitTask{
+			Component: store,
+			Name:      fmt.Sprintf("run %s migrations", store),
+			Run: func(ctx context.Context) error {
+				return runner.Up(ctx, store)
+			},
+		})
+	}
+	return tasks
 }
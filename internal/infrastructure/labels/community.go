@@ -0,0 +1,377 @@
+package labels
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// communitySource is the source of labels approved from community proposals
+	communitySource = "community"
+	// scamReportName names an approved scam report that did not propose a name
+	scamReportName = "Reported scam"
+
+	maxEvidenceLinks     = 10
+	maxDescriptionLength = 2000
+
+	// Community labels are trusted less than imported ones: their confidence
+	// spans this range according to the submitter's reputation
+	communityMinConfidence = 0.3
+	communityMaxConfidence = 0.75
+)
+
+// ProposalInput describes a user's proposed label, tags or scam report
+type ProposalInput struct {
+	Kind          entity.LabelProposalKind
+	Address       string
+	Name          *string
+	Entity        *string
+	Category      *entity.LabelCategory
+	Tags          []string
+	Description   *string
+	EvidenceLinks []string
+}
+
+// SubmitProposal queues a user's proposal for moderation
+func (s *Service) SubmitProposal(ctx context.Context, input ProposalInput, user *entity.User) (*entity.LabelProposal, error) {
+	proposal, err := newProposal(input)
+	if err != nil {
+		return nil, err
+	}
+	proposal.SubmitterID = user.ID
+	proposal.Submitter = user.Email
+
+	pendingStatus := entity.LabelProposalStatusPending
+	pending, err := s.proposalRepo.CountProposals(ctx, &entity.LabelProposalFilters{
+		Status:      &pendingStatus,
+		SubmitterID: &user.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.config.MaxPendingProposals > 0 && pending >= s.config.MaxPendingProposals {
+		return nil, apperrors.NewValidationError("proposal",
+			fmt.Sprintf("You already have %d proposals awaiting moderation", pending))
+	}
+
+	duplicates, err := s.proposalRepo.CountProposals(ctx, &entity.LabelProposalFilters{
+		Status:      &pendingStatus,
+		Kind:        &proposal.Kind,
+		Address:     &proposal.Address,
+		SubmitterID: &user.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if duplicates > 0 {
+		return nil, apperrors.NewValidationError("address",
+			"You already have a proposal of this kind awaiting moderation for this address")
+	}
+
+	if err := s.proposalRepo.CreateProposal(ctx, proposal); err != nil {
+		return nil, err
+	}
+	if _, err := s.proposalRepo.IncrementReputation(ctx, user.ID, 1, 0, 0); err != nil {
+		s.logger.Warn("Failed to count submitted proposal",
+			zap.String("proposalID", proposal.ID),
+			zap.Error(err))
+	}
+
+	s.logger.Info("Label proposal submitted",
+		zap.String("proposalID", proposal.ID),
+		zap.String("kind", string(proposal.Kind)),
+		zap.String("address", proposal.Address),
+		zap.String("actor", user.Email))
+
+	return proposal, nil
+}
+
+// Proposals lists proposals with filters, oldest first
+func (s *Service) Proposals(ctx context.Context, filters *entity.LabelProposalFilters, limit, offset int) ([]entity.LabelProposal, error) {
+	return s.proposalRepo.GetProposals(ctx, filters, limit, offset)
+}
+
+// Reputation returns a submitter's moderation record
+func (s *Service) Reputation(ctx context.Context, userID uint) (*entity.SubmitterReputation, error) {
+	return s.proposalRepo.GetReputation(ctx, userID)
+}
+
+// ApproveProposal accepts a pending proposal. Its label is stored in the
+// registry as a community label weighted by the submitter's reputation and the
+// address's attribution is propagated; an approved scam report also updates
+// the address's scam report count.
+func (s *Service) ApproveProposal(ctx context.Context, id string, note *string, moderator *entity.User) (*entity.LabelProposal, error) {
+	proposal, err := s.loadPendingProposal(ctx, id, moderator)
+	if err != nil {
+		return nil, err
+	}
+
+	reputation, err := s.proposalRepo.GetReputation(ctx, proposal.SubmitterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.review(ctx, proposal, entity.LabelProposalStatusApproved, optionalString(derefString(note)), moderator); err != nil {
+		return nil, err
+	}
+
+	label := communityLabel(proposal, reputation.Score())
+	if _, _, err := s.labelRepo.UpsertLabels(ctx, []entity.AddressLabel{label}); err != nil {
+		// Keep the proposal and registry consistent: reopen the proposal so
+		// that its approval can be retried
+		if _, reopenErr := s.proposalRepo.ReopenProposal(ctx, proposal); reopenErr != nil {
+			s.logger.Error("Approved label proposal was not stored in the registry nor reopened",
+				zap.String("proposalID", proposal.ID),
+				zap.Error(err),
+				zap.NamedError("reopenError", reopenErr))
+		}
+		return nil, err
+	}
+	s.propagate(ctx, proposal.Address)
+
+	if proposal.Kind == entity.LabelProposalKindScamReport {
+		s.updateScamReports(ctx, proposal.Address)
+	}
+	if _, err := s.proposalRepo.IncrementReputation(ctx, proposal.SubmitterID, 0, 1, 0); err != nil {
+		s.logger.Warn("Failed to count approved proposal",
+			zap.String("proposalID", proposal.ID),
+			zap.Error(err))
+	}
+
+	s.logger.Info("Label proposal approved",
+		zap.String("proposalID", proposal.ID),
+		zap.String("address", proposal.Address),
+		zap.Float64("confidence", label.Confidence),
+		zap.String("actor", moderator.Email))
+
+	return proposal, nil
+}
+
+// RejectProposal declines a pending proposal with a reason for the submitter
+func (s *Service) RejectProposal(ctx context.Context, id, reason string, moderator *entity.User) (*entity.LabelProposal, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, apperrors.NewValidationError("reason", "A reason is required to reject a proposal")
+	}
+
+	proposal, err := s.loadPendingProposal(ctx, id, moderator)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.review(ctx, proposal, entity.LabelProposalStatusRejected, &reason, moderator); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.proposalRepo.IncrementReputation(ctx, proposal.SubmitterID, 0, 0, 1); err != nil {
+		s.logger.Warn("Failed to count rejected proposal",
+			zap.String("proposalID", proposal.ID),
+			zap.Error(err))
+	}
+
+	s.logger.Info("Label proposal rejected",
+		zap.String("proposalID", proposal.ID),
+		zap.String("address", proposal.Address),
+		zap.String("actor", moderator.Email))
+
+	return proposal, nil
+}
+
+// loadPendingProposal loads a proposal the moderator may still review
+func (s *Service) loadPendingProposal(ctx context.Context, id string, moderator *entity.User) (*entity.LabelProposal, error) {
+	proposal, err := s.proposalRepo.GetProposal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if proposal == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeProposalNotFound, "Label proposal not found", id)
+	}
+	if proposal.Status != entity.LabelProposalStatusPending {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeInvalidTransition,
+			"Label proposal already reviewed",
+			fmt.Sprintf("The proposal was %s", strings.ToLower(string(proposal.Status))))
+	}
+	if proposal.SubmitterID == moderator.ID {
+		return nil, apperrors.NewAuthError(apperrors.ErrCodeAuthPermissionDenied,
+			"Moderators cannot review their own proposals")
+	}
+	return proposal, nil
+}
+
+// review records the moderator's decision, failing if another moderator
+// decided the proposal first
+func (s *Service) review(ctx context.Context, proposal *entity.LabelProposal, status entity.LabelProposalStatus, note *string, moderator *entity.User) error {
+	now := time.Now()
+	proposal.Status = status
+	proposal.ReviewerID = &moderator.ID
+	proposal.Reviewer = &moderator.Email
+	proposal.ReviewNote = note
+	proposal.ReviewedAt = &now
+
+	reviewed, err := s.proposalRepo.ReviewProposal(ctx, proposal)
+	if err != nil {
+		return err
+	}
+	if !reviewed {
+		return apperrors.NewAppError(apperrors.ErrCodeInvalidTransition,
+			"Label proposal already reviewed",
+			"Another moderator reviewed the proposal first")
+	}
+	return nil
+}
+
+// updateScamReports stores the number of users whose scam reports for the
+// address were approved on its wallet, where it feeds the scam risk factor
+func (s *Service) updateScamReports(ctx context.Context, address string) {
+	reporters, err := s.proposalRepo.CountApprovedScamReporters(ctx, address)
+	if err == nil {
+		err = s.walletRepo.UpdateScamReports(ctx, address, reporters)
+	}
+	if err != nil {
+		s.logger.Warn("Failed to update wallet scam reports",
+			zap.String("address", address),
+			zap.Error(err))
+	}
+}
+
+// newProposal validates a user's input into a pending proposal
+func newProposal(input ProposalInput) (*entity.LabelProposal, error) {
+	if !input.Kind.IsValid() {
+		return nil, apperrors.NewValidationError("kind", fmt.Sprintf("Unknown proposal kind %q", input.Kind))
+	}
+
+	address := entity.NormalizeAddress(input.Address)
+	if !entity.IsValidAddress(address) {
+		return nil, apperrors.NewValidationError("address", "A valid wallet address is required")
+	}
+
+	links, err := evidenceLinks(input.EvidenceLinks)
+	if err != nil {
+		return nil, err
+	}
+
+	description := strings.TrimSpace(derefString(input.Description))
+	if len(description) > maxDescriptionLength {
+		return nil, apperrors.NewValidationError("description",
+			fmt.Sprintf("Description must be at most %d characters", maxDescriptionLength))
+	}
+
+	tags := []string{}
+	for _, tag := range input.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	proposal := &entity.LabelProposal{
+		Kind:          input.Kind,
+		Address:       address,
+		Tags:          tags,
+		Description:   description,
+		EvidenceLinks: links,
+		Status:        entity.LabelProposalStatusPending,
+	}
+
+	switch input.Kind {
+	case entity.LabelProposalKindLabel:
+		proposal.Name = optionalString(derefString(input.Name))
+		if proposal.Name == nil {
+			return nil, apperrors.NewValidationError("name", "A label name is required")
+		}
+		proposal.Entity = optionalString(derefString(input.Entity))
+		category := entity.LabelCategoryOther
+		if input.Category != nil {
+			category = *input.Category
+		}
+		if !category.IsValid() {
+			return nil, apperrors.NewValidationError("category", fmt.Sprintf("Unknown label category %q", category))
+		}
+		proposal.Category = &category
+	case entity.LabelProposalKindTag:
+		if len(tags) == 0 {
+			return nil, apperrors.NewValidationError("tags", "At least one tag is required")
+		}
+	case entity.LabelProposalKindScamReport:
+		if description == "" {
+			return nil, apperrors.NewValidationError("description", "Describe the scam the address is involved in")
+		}
+		proposal.Name = optionalString(derefString(input.Name))
+		proposal.Entity = optionalString(derefString(input.Entity))
+		category := entity.LabelCategoryScam
+		proposal.Category = &category
+	}
+
+	return proposal, nil
+}
+
+// evidenceLinks validates and deduplicates a proposal's evidence links
+func evidenceLinks(values []string) ([]string, error) {
+	links := []string{}
+	seen := make(map[string]bool)
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || seen[value] {
+			continue
+		}
+		link, err := url.Parse(value)
+		if err != nil || (link.Scheme != "http" && link.Scheme != "https") || link.Host == "" {
+			return nil, apperrors.NewValidationError("evidenceLinks", fmt.Sprintf("%q is not an http(s) URL", value))
+		}
+		seen[value] = true
+		links = append(links, value)
+	}
+
+	if len(links) == 0 {
+		return nil, apperrors.NewValidationError("evidenceLinks", "At least one evidence link is required")
+	}
+	if len(links) > maxEvidenceLinks {
+		return nil, apperrors.NewValidationError("evidenceLinks",
+			fmt.Sprintf("At most %d evidence links are allowed", maxEvidenceLinks))
+	}
+	return links, nil
+}
+
+// communityLabel converts an approved proposal into a registry label whose
+// confidence follows the submitter's reputation score
+func communityLabel(proposal *entity.LabelProposal, reputation float64) entity.AddressLabel {
+	label := entity.AddressLabel{
+		Address:     proposal.Address,
+		Entity:      proposal.Entity,
+		Category:    entity.LabelCategoryOther,
+		Tags:        proposal.Tags,
+		Source:      communitySource,
+		SourceType:  entity.LabelSourceTypeCommunity,
+		Confidence:  communityMinConfidence + (communityMaxConfidence-communityMinConfidence)*reputation,
+		CreatedByID: proposal.SubmitterID,
+		CreatedBy:   proposal.Submitter,
+		Provenance: entity.LabelProvenance{
+			Reference:  &proposal.EvidenceLinks[0],
+			Notes:      optionalString(proposal.Description),
+			ProposalID: &proposal.ID,
+		},
+	}
+	if proposal.Category != nil {
+		label.Category = *proposal.Category
+	}
+
+	switch proposal.Kind {
+	case entity.LabelProposalKindTag:
+		label.Name = "Tags: " + strings.Join(proposal.Tags, ", ")
+	case entity.LabelProposalKindScamReport:
+		label.Name = scamReportName
+		if proposal.Name != nil {
+			label.Name = *proposal.Name
+		}
+	default:
+		label.Name = derefString(proposal.Name)
+	}
+
+	return label
+}
@@ -0,0 +1,315 @@
+package labels
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+)
+
+// maxReportedErrors caps the row errors kept on an import record
+const maxReportedErrors = 50
+
+// ParsedPack is the result of parsing a label pack
+type ParsedPack struct {
+	Labels    []entity.AddressLabel
+	TotalRows int
+	Rejected  int
+	Errors    []string
+}
+
+// rawLabel is one row of a label pack before validation
+type rawLabel struct {
+	Address    string   `json:"address"`
+	Name       string   `json:"name"`
+	Label      string   `json:"label"`
+	Entity     string   `json:"entity"`
+	Category   string   `json:"category"`
+	Tags       []string `json:"tags"`
+	Source     string   `json:"source"`
+	Confidence *float64 `json:"confidence"`
+	ValidFrom  string   `json:"valid_from"`
+	ValidUntil string   `json:"valid_until"`
+	Reference  string   `json:"reference"`
+	Notes      string   `json:"notes"`
+}
+
+// jsonPack is the object form of a JSON label pack; a bare array of labels is also accepted
+type jsonPack struct {
+	Source string     `json:"source"`
+	Labels []rawLabel `json:"labels"`
+}
+
+// Parse reads a label pack. Rows that fail validation are counted and reported
+// rather than failing the pack; source is used for rows that do not name one.
+func Parse(format entity.LabelPackFormat, r io.Reader, source string, defaultConfidence float64, maxRows int) (*ParsedPack, error) {
+	var (
+		rows []rawLabel
+		err  error
+	)
+
+	switch format {
+	case entity.LabelPackFormatCSV:
+		rows, err = parseCSV(r)
+	case entity.LabelPackFormatJSON:
+		rows, source, err = parseJSON(r, source)
+	default:
+		return nil, fmt.Errorf("unsupported label pack format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if maxRows > 0 && len(rows) > maxRows {
+		return nil, fmt.Errorf("label pack has %d rows, more than the limit of %d", len(rows), maxRows)
+	}
+
+	pack := &ParsedPack{TotalRows: len(rows)}
+	index := make(map[string]int, len(rows))
+	for i, row := range rows {
+		label, err := toLabel(row, source, defaultConfidence)
+		if err != nil {
+			pack.Rejected++
+			if len(pack.Errors) < maxReportedErrors {
+				pack.Errors = append(pack.Errors, fmt.Sprintf("row %d: %v", i+1, err))
+			}
+			continue
+		}
+
+		// A later row for the same address, source and name replaces the earlier one
+		key := label.Address + "|" + label.Source + "|" + label.Name
+		if j, ok := index[key]; ok {
+			pack.Labels[j] = label
+			continue
+		}
+		index[key] = len(pack.Labels)
+		pack.Labels = append(pack.Labels, label)
+	}
+
+	return pack, nil
+}
+
+// parseCSV parses a CSV label pack with a header row. The address and name
+// columns are required; tags are separated by ";" or "|".
+func parseCSV(r io.Reader) ([]rawLabel, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	addressCol, ok := lookupColumn(columns, "address", "wallet_address", "wallet")
+	if !ok {
+		return nil, fmt.Errorf("CSV header must contain an address column")
+	}
+	nameCol, ok := lookupColumn(columns, "name", "label")
+	if !ok {
+		return nil, fmt.Errorf("CSV header must contain a name column")
+	}
+	entityCol, _ := lookupColumn(columns, "entity", "entity_name", "owner")
+	categoryCol, _ := lookupColumn(columns, "category", "type")
+	tagsCol, _ := lookupColumn(columns, "tags")
+	sourceCol, _ := lookupColumn(columns, "source")
+	confidenceCol, _ := lookupColumn(columns, "confidence")
+	validFromCol, _ := lookupColumn(columns, "valid_from")
+	validUntilCol, _ := lookupColumn(columns, "valid_until", "expires_at")
+	referenceCol, _ := lookupColumn(columns, "reference", "url")
+	notesCol, _ := lookupColumn(columns, "notes", "remarks")
+
+	var rows []rawLabel
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV row %d: %w", line, err)
+		}
+
+		row := rawLabel{
+			Address:    field(record, addressCol),
+			Name:       field(record, nameCol),
+			Entity:     field(record, entityCol),
+			Category:   field(record, categoryCol),
+			Tags:       splitTags(field(record, tagsCol)),
+			Source:     field(record, sourceCol),
+			ValidFrom:  field(record, validFromCol),
+			ValidUntil: field(record, validUntilCol),
+			Reference:  field(record, referenceCol),
+			Notes:      field(record, notesCol),
+		}
+		if value := field(record, confidenceCol); value != "" {
+			confidence, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				// Leave it unparseable so the row is rejected with a clear error
+				confidence = -1
+			}
+			row.Confidence = &confidence
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// parseJSON parses a JSON label pack: either an array of labels or an object
+// with a labels array and an optional pack-wide source
+func parseJSON(r io.Reader, source string) ([]rawLabel, string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read JSON label pack: %w", err)
+	}
+
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var rows []rawLabel
+		if err := json.Unmarshal(data, &rows); err != nil {
+			return nil, "", fmt.Errorf("failed to parse JSON label pack: %w", err)
+		}
+		return rows, source, nil
+	}
+
+	var pack jsonPack
+	if err := json.Unmarshal(data, &pack); err != nil {
+		return nil, "", fmt.Errorf("failed to parse JSON label pack: %w", err)
+	}
+	if source == "" {
+		source = pack.Source
+	}
+	return pack.Labels, source, nil
+}
+
+// toLabel validates a row and converts it to a label
+func toLabel(row rawLabel, source string, defaultConfidence float64) (entity.AddressLabel, error) {
+	address := entity.NormalizeAddress(row.Address)
+	if !entity.IsValidAddress(address) {
+		return entity.AddressLabel{}, fmt.Errorf("invalid address %q", row.Address)
+	}
+
+	name := strings.TrimSpace(row.Name)
+	if name == "" {
+		name = strings.TrimSpace(row.Label)
+	}
+	if name == "" {
+		return entity.AddressLabel{}, fmt.Errorf("missing label name")
+	}
+
+	if s := strings.TrimSpace(row.Source); s != "" {
+		source = s
+	}
+	if source == "" {
+		return entity.AddressLabel{}, fmt.Errorf("missing source")
+	}
+
+	category := entity.LabelCategoryOther
+	if c := strings.TrimSpace(row.Category); c != "" {
+		category = entity.LabelCategory(strings.ToUpper(strings.ReplaceAll(c, " ", "_")))
+		if !category.IsValid() {
+			return entity.AddressLabel{}, fmt.Errorf("unknown category %q", row.Category)
+		}
+	}
+
+	confidence := defaultConfidence
+	if row.Confidence != nil {
+		confidence = *row.Confidence
+		if confidence > 1 && confidence <= 100 {
+			// Percentages are accepted as well as fractions
+			confidence /= 100
+		}
+		if confidence <= 0 || confidence > 1 {
+			return entity.AddressLabel{}, fmt.Errorf("confidence must be between 0 and 1")
+		}
+	}
+
+	validFrom, err := parseTime(row.ValidFrom)
+	if err != nil {
+		return entity.AddressLabel{}, fmt.Errorf("invalid valid_from: %w", err)
+	}
+	validUntil, err := parseTime(row.ValidUntil)
+	if err != nil {
+		return entity.AddressLabel{}, fmt.Errorf("invalid valid_until: %w", err)
+	}
+	if validFrom != nil && validUntil != nil && !validUntil.After(*validFrom) {
+		return entity.AddressLabel{}, fmt.Errorf("valid_until must be after valid_from")
+	}
+
+	tags := []string{}
+	for _, tag := range row.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return entity.AddressLabel{
+		Address:    address,
+		Name:       name,
+		Entity:     optionalString(row.Entity),
+		Category:   category,
+		Tags:       tags,
+		Source:     source,
+		SourceType: entity.LabelSourceTypeImport,
+		Confidence: confidence,
+		ValidFrom:  validFrom,
+		ValidUntil: validUntil,
+		Provenance: entity.LabelProvenance{
+			Reference: optionalString(row.Reference),
+			Notes:     optionalString(row.Notes),
+		},
+	}, nil
+}
+
+// parseTime accepts RFC3339 timestamps and plain dates; empty means unset
+func parseTime(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("%q is not an RFC3339 timestamp or YYYY-MM-DD date", value)
+}
+
+func splitTags(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ';' || r == '|'
+	})
+}
+
+func optionalString(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func lookupColumn(columns map[string]int, names ...string) (int, bool) {
+	for _, name := range names {
+		if i, ok := columns[name]; ok {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func field(record []string, col int) string {
+	if col < 0 || col >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[col])
+}
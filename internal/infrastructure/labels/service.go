@@ -0,0 +1,435 @@
+package labels
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/config"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+
+	"go.uber.org/zap"
+)
+
+// manualSource is the source of analyst labels that do not name one
+const manualSource = "analyst"
+
+// Service manages the address label registry: analyst labels, imported label
+// packs, moderated community proposals and the resolution of disagreeing labels
+// into one attribution per address. Attributions are propagated to the wallet graph whenever an
+// address's labels change or one of them becomes valid or expires.
+type Service struct {
+	labelRepo    repository.LabelRepository
+	proposalRepo repository.LabelProposalRepository
+	walletRepo   repository.WalletRepository
+	config       *config.LabelsConfig
+	logger       *zap.Logger
+
+	mu        sync.Mutex
+	cancel    context.CancelFunc
+	done      chan struct{}
+	lastSweep time.Time
+}
+
+// NewService creates a new label registry service
+func NewService(
+	labelRepo repository.LabelRepository,
+	proposalRepo repository.LabelProposalRepository,
+	walletRepo repository.WalletRepository,
+	cfg *config.LabelsConfig,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		labelRepo:    labelRepo,
+		proposalRepo: proposalRepo,
+		walletRepo:   walletRepo,
+		config:       cfg,
+		logger:       logger,
+	}
+}
+
+// LabelInput describes an analyst's label
+type LabelInput struct {
+	Address    string
+	Name       string
+	Entity     *string
+	Category   entity.LabelCategory
+	Tags       []string
+	Source     *string
+	Confidence *float64
+	ValidFrom  *time.Time
+	ValidUntil *time.Time
+	Reference  *string
+	Notes      *string
+}
+
+// CreateLabel adds an analyst's label and propagates the address's attribution
+func (s *Service) CreateLabel(ctx context.Context, input LabelInput, user *entity.User) (*entity.AddressLabel, error) {
+	label := &entity.AddressLabel{
+		SourceType:  entity.LabelSourceTypeManual,
+		CreatedByID: user.ID,
+		CreatedBy:   user.Email,
+	}
+	if err := s.applyInput(label, input); err != nil {
+		return nil, err
+	}
+
+	created, err := s.labelRepo.CreateLabel(ctx, label)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, apperrors.NewValidationError("name",
+			fmt.Sprintf("Source %q already has a label named %q for this address", label.Source, label.Name))
+	}
+
+	s.logger.Info("Address label created",
+		zap.String("labelID", label.ID),
+		zap.String("address", label.Address),
+		zap.String("source", label.Source),
+		zap.String("actor", user.Email))
+
+	s.propagate(ctx, label.Address)
+	return label, nil
+}
+
+// UpdateLabel replaces a label's details. Moving a label to another address
+// propagates both addresses.
+func (s *Service) UpdateLabel(ctx context.Context, id string, input LabelInput, user *entity.User) (*entity.AddressLabel, error) {
+	label, err := s.loadLabel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	previousAddress := label.Address
+
+	if err := s.applyInput(label, input); err != nil {
+		return nil, err
+	}
+	if err := s.labelRepo.UpdateLabel(ctx, label); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Address label updated",
+		zap.String("labelID", label.ID),
+		zap.String("address", label.Address),
+		zap.String("actor", user.Email))
+
+	s.propagate(ctx, label.Address)
+	if previousAddress != label.Address {
+		s.propagate(ctx, previousAddress)
+	}
+	return label, nil
+}
+
+// DeleteLabel removes a label and propagates the address's attribution
+func (s *Service) DeleteLabel(ctx context.Context, id string, user *entity.User) error {
+	label, err := s.loadLabel(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.labelRepo.DeleteLabel(ctx, id); err != nil {
+		return err
+	}
+
+	s.logger.Info("Address label deleted",
+		zap.String("labelID", id),
+		zap.String("address", label.Address),
+		zap.String("actor", user.Email))
+
+	s.propagate(ctx, label.Address)
+	return nil
+}
+
+// Labels lists labels with filters
+func (s *Service) Labels(ctx context.Context, filters *entity.LabelFilters, limit, offset int) ([]entity.AddressLabel, error) {
+	return s.labelRepo.GetLabels(ctx, filters, limit, offset)
+}
+
+// Attribution resolves the address's labels as of now
+func (s *Service) Attribution(ctx context.Context, address string) (*entity.AddressAttribution, error) {
+	labels, err := s.labelRepo.GetLabelsByAddress(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	return entity.ResolveAttribution(address, labels, time.Now()), nil
+}
+
+// Imports lists the most recent label pack imports
+func (s *Service) Imports(ctx context.Context, limit int) ([]entity.LabelImport, error) {
+	return s.labelRepo.GetLabelImports(ctx, limit)
+}
+
+// ImportFile imports a label pack from the configured label data directory.
+// fileName must resolve to a file inside that directory.
+func (s *Service) ImportFile(ctx context.Context, format entity.LabelPackFormat, fileName, source string, user *entity.User) (*entity.LabelImport, error) {
+	path, err := s.resolvePath(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read label pack: %w", err)
+	}
+
+	return s.Import(ctx, format, filepath.Base(path), source, data, user)
+}
+
+// Import stores a label pack's labels, replacing the source's earlier labels of
+// the same name for the same address, and propagates every affected address
+func (s *Service) Import(ctx context.Context, format entity.LabelPackFormat, fileName, source string, data []byte, user *entity.User) (*entity.LabelImport, error) {
+	source = strings.TrimSpace(source)
+	pack, err := Parse(format, bytes.NewReader(data), source, s.config.DefaultConfidence, s.config.MaxImportRows)
+	if err != nil {
+		return nil, apperrors.NewValidationError("file", err.Error())
+	}
+
+	sum := sha256.Sum256(data)
+	labelImport := &entity.LabelImport{
+		Source:       source,
+		Format:       format,
+		FileName:     fileName,
+		Checksum:     hex.EncodeToString(sum[:]),
+		TotalRows:    pack.TotalRows,
+		Rejected:     pack.Rejected,
+		Errors:       pack.Errors,
+		ImportedByID: user.ID,
+		ImportedBy:   user.Email,
+		ImportedAt:   time.Now(),
+	}
+	if labelImport.Errors == nil {
+		labelImport.Errors = []string{}
+	}
+	if err := s.labelRepo.CreateLabelImport(ctx, labelImport); err != nil {
+		return nil, err
+	}
+
+	addresses := make(map[string]struct{})
+	for i := range pack.Labels {
+		label := &pack.Labels[i]
+		label.Provenance.ImportID = &labelImport.ID
+		label.Provenance.FileName = &labelImport.FileName
+		label.CreatedByID = user.ID
+		label.CreatedBy = user.Email
+		addresses[label.Address] = struct{}{}
+	}
+
+	created, updated, err := s.labelRepo.UpsertLabels(ctx, pack.Labels)
+	if err != nil {
+		return nil, err
+	}
+	labelImport.Created = created
+	labelImport.Updated = updated
+	labelImport.Addresses = len(addresses)
+
+	for address := range addresses {
+		s.propagate(ctx, address)
+	}
+
+	s.logger.Info("Imported label pack",
+		zap.String("importID", labelImport.ID),
+		zap.String("source", source),
+		zap.String("fileName", fileName),
+		zap.Int("created", created),
+		zap.Int("updated", updated),
+		zap.Int("rejected", pack.Rejected),
+		zap.String("actor", user.Email))
+
+	return labelImport, nil
+}
+
+// Propagate re-resolves the addresses' labels and stores the attributions on
+// their wallets, returning how many were updated
+func (s *Service) Propagate(ctx context.Context, addresses []string) int {
+	updated := 0
+	for _, address := range addresses {
+		if s.propagate(ctx, address) {
+			updated++
+		}
+	}
+	return updated
+}
+
+// propagate stores the address's attribution on its wallet. Failures are logged
+// rather than returned: the registry is the source of truth and the next change
+// or sweep propagates again.
+func (s *Service) propagate(ctx context.Context, address string) bool {
+	attribution, err := s.Attribution(ctx, address)
+	if err == nil {
+		err = s.walletRepo.UpdateAttribution(ctx, attribution)
+	}
+	if err != nil {
+		s.logger.Warn("Failed to propagate address attribution",
+			zap.String("address", address),
+			zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// applyInput validates an analyst's input onto label
+func (s *Service) applyInput(label *entity.AddressLabel, input LabelInput) error {
+	address := entity.NormalizeAddress(input.Address)
+	if !entity.IsValidAddress(address) {
+		return apperrors.NewValidationError("address", "A valid wallet address is required")
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return apperrors.NewValidationError("name", "A label name is required")
+	}
+
+	category := input.Category
+	if category == "" {
+		category = entity.LabelCategoryOther
+	}
+	if !category.IsValid() {
+		return apperrors.NewValidationError("category", fmt.Sprintf("Unknown label category %q", input.Category))
+	}
+
+	source := manualSource
+	if input.Source != nil && strings.TrimSpace(*input.Source) != "" {
+		source = strings.TrimSpace(*input.Source)
+	}
+
+	confidence := s.config.DefaultConfidence
+	if input.Confidence != nil {
+		confidence = *input.Confidence
+	}
+	if confidence <= 0 || confidence > 1 {
+		return apperrors.NewValidationError("confidence", "Confidence must be between 0 and 1")
+	}
+
+	if input.ValidFrom != nil && input.ValidUntil != nil && !input.ValidUntil.After(*input.ValidFrom) {
+		return apperrors.NewValidationError("validUntil", "validUntil must be after validFrom")
+	}
+
+	tags := []string{}
+	for _, tag := range input.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	label.Address = address
+	label.Name = name
+	label.Entity = optionalString(derefString(input.Entity))
+	label.Category = category
+	label.Tags = tags
+	label.Source = source
+	label.Confidence = confidence
+	label.ValidFrom = input.ValidFrom
+	label.ValidUntil = input.ValidUntil
+	label.Provenance.Reference = optionalString(derefString(input.Reference))
+	label.Provenance.Notes = optionalString(derefString(input.Notes))
+	return nil
+}
+
+func (s *Service) loadLabel(ctx context.Context, id string) (*entity.AddressLabel, error) {
+	label, err := s.labelRepo.GetLabel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if label == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeLabelNotFound, "Address label not found", id)
+	}
+	return label, nil
+}
+
+// resolvePath confines imports to the label data directory
+func (s *Service) resolvePath(fileName string) (string, error) {
+	base, err := filepath.Abs(s.config.DataDir)
+	if err != nil {
+		return "", fmt.Errorf("invalid label data directory: %w", err)
+	}
+
+	path := filepath.Join(base, filepath.Clean("/"+fileName))
+	if !strings.HasPrefix(path, base+string(filepath.Separator)) {
+		return "", fmt.Errorf("label pack %q is outside the data directory", fileName)
+	}
+
+	return path, nil
+}
+
+// Start propagates labels that became valid or expired every configured
+// interval until Stop is called
+func (s *Service) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	s.lastSweep = time.Now().Add(-s.config.ValiditySweepInterval)
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.config.ValiditySweepInterval)
+		defer ticker.Stop()
+
+		for {
+			s.sweep(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	s.logger.Info("Started label validity sweep", zap.Duration("interval", s.config.ValiditySweepInterval))
+}
+
+// Stop stops the sweep and waits for one in progress to finish
+func (s *Service) Stop() {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+	s.logger.Info("Stopped label validity sweep")
+}
+
+// sweep propagates the addresses whose labels became valid or expired since the last sweep
+func (s *Service) sweep(ctx context.Context) {
+	now := time.Now()
+	addresses, err := s.labelRepo.GetAddressesWithValidityChanges(ctx, s.lastSweep, now)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Error("Label validity sweep failed", zap.Error(err))
+		}
+		return
+	}
+	s.lastSweep = now
+
+	if len(addresses) > 0 {
+		updated := s.Propagate(ctx, addresses)
+		s.logger.Info("Propagated label validity changes",
+			zap.Int("addresses", len(addresses)),
+			zap.Int("updated", updated))
+	}
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -18,6 +18,8 @@ type Config struct {
 	Level       string `json:"level"`
 	Environment string `json:"environment"`
 	Debug       bool   `json:"debug"`
+	// Output is the path logs are written to, stdout when empty
+	Output string `json:"output"`
 }
 
 // NewLogger creates a new logger instance
@@ -41,7 +43,11 @@ func NewLogger(cfg *Config) (*Logger, error) {
 	zapConfig.Level = zap.NewAtomicLevelAt(level)
 
 	// Configure output paths
-	zapConfig.OutputPaths = []string{"stdout"}
+	output := cfg.Output
+	if output == "" {
+		output = "stdout"
+	}
+	zapConfig.OutputPaths = []string{output}
 	zapConfig.ErrorOutputPaths = []string{"stderr"}
 
 	// Enable debug mode if specified
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type contextKey string
+
+const userContextKey contextKey = "user"
+
+// AuthenticationMiddleware resolves the session bearer token into a user.
+// Requests without a token continue anonymously; resolvers decide what requires a user.
+func AuthenticationMiddleware(userRepo repository.UserRepository, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID := bearerToken(c.GetHeader("Authorization"))
+		if sessionID == "" {
+			c.Next()
+			return
+		}
+
+		user, err := userRepo.GetSession(c.Request.Context(), sessionID)
+		if err != nil {
+			logger.Warn("Failed to resolve session",
+				zap.String("request_id", getRequestID(c)),
+				zap.Error(err),
+			)
+			c.Next()
+			return
+		}
+
+		if user != nil {
+			c.Set("user_id", user.ID)
+			c.Set("user_role", string(user.Role))
+			c.Request = c.Request.WithContext(WithUser(c.Request.Context(), user))
+		}
+
+		c.Next()
+	}
+}
+
+// WithUser returns a copy of ctx carrying the authenticated user
+func WithUser(ctx context.Context, user *entity.User) context.Context {
+	return context.WithValue(ctx, userContextKey, user)
+}
+
+// UserFromContext returns the authenticated user, if any
+func UserFromContext(ctx context.Context) (*entity.User, bool) {
+	user, ok := ctx.Value(userContextKey).(*entity.User)
+	return user, ok && user != nil
+}
+
+// bearerToken extracts the token from an Authorization header
+func bearerToken(header string) string {
+	const prefix = "Bearer "
+	if len(header) > len(prefix) && strings.EqualFold(header[:len(prefix)], prefix) {
+		return strings.TrimSpace(header[len(prefix):])
+	}
+	return ""
+}
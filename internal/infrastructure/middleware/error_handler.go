@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"runtime/debug"
+	"strconv"
 	"time"
 
 	"crypto-bubble-map-be/internal/infrastructure/errors"
@@ -64,7 +65,7 @@ func handleError(c *gin.Context, err error, logger *zap.Logger, monitor *monitor
 	// Track error metrics
 	if monitor != nil {
 		monitor.TrackError(appErr, c.Request.Method+" "+c.FullPath(), map[string]string{
-			"status_code": string(rune(appErr.HTTPStatus)),
+			"status_code": strconv.Itoa(appErr.HTTPStatus),
 			"method":      c.Request.Method,
 			"path":        c.FullPath(),
 		})
@@ -206,6 +207,12 @@ func getUserID(c *gin.Context) *uint {
 // TimeoutMiddleware adds request timeout handling
 func TimeoutMiddleware(timeout time.Duration, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// Websocket connections (GraphQL subscriptions) live as long as the client keeps them open
+		if c.IsWebsocket() {
+			c.Next()
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
 		defer cancel()
 
@@ -244,122 +251,3 @@ func TimeoutMiddleware(timeout time.Duration, logger *zap.Logger) gin.HandlerFun
 		}
 	}
 }
-
-// RetryableErrorMiddleware handles retryable errors
-func RetryableErrorMiddleware(maxRetries int, logger *zap.Logger) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		var lastErr error
-
-		for attempt := 0; attempt <= maxRetries; attempt++ {
-			// Reset response writer for retry
-			if attempt > 0 {
-				// Create a new response writer for retry
-				c.Writer = c.Writer
-			}
-
-			c.Next()
-
-			// Check if there are any retryable errors
-			if len(c.Errors) > 0 {
-				err := c.Errors.Last().Err
-				if errors.IsRetryable(err) && attempt < maxRetries {
-					lastErr = err
-					c.Errors = c.Errors[:len(c.Errors)-1] // Remove the error for retry
-
-					logger.Info("Retrying request",
-						zap.String("request_id", getRequestID(c)),
-						zap.Int("attempt", attempt+1),
-						zap.Int("max_retries", maxRetries),
-						zap.Error(err),
-					)
-
-					// Wait before retry (exponential backoff)
-					time.Sleep(time.Duration(attempt+1) * 100 * time.Millisecond)
-					continue
-				}
-			}
-
-			// No retryable errors or max retries reached
-			break
-		}
-
-		// If we exhausted retries, add the last error back
-		if lastErr != nil && len(c.Errors) == 0 {
-			c.Error(lastErr)
-		}
-	}
-}
-
-// CircuitBreakerMiddleware implements circuit breaker pattern
-type CircuitBreaker struct {
-	maxFailures  int
-	resetTimeout time.Duration
-	failures     int
-	lastFailTime time.Time
-	state        string // "closed", "open", "half-open"
-	logger       *zap.Logger
-}
-
-// NewCircuitBreaker creates a new circuit breaker
-func NewCircuitBreaker(maxFailures int, resetTimeout time.Duration, logger *zap.Logger) *CircuitBreaker {
-	return &CircuitBreaker{
-		maxFailures:  maxFailures,
-		resetTimeout: resetTimeout,
-		state:        "closed",
-		logger:       logger,
-	}
-}
-
-// CircuitBreakerMiddleware returns a middleware that implements circuit breaker pattern
-func (cb *CircuitBreaker) CircuitBreakerMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Check circuit breaker state
-		if cb.state == "open" {
-			if time.Since(cb.lastFailTime) > cb.resetTimeout {
-				cb.state = "half-open"
-				cb.logger.Info("Circuit breaker transitioning to half-open state")
-			} else {
-				// Circuit is open, reject request
-				appErr := errors.NewAppError(
-					errors.ErrCodeServiceUnavailable,
-					"Service temporarily unavailable",
-					"Circuit breaker is open",
-				).WithRequestID(getRequestID(c))
-
-				c.JSON(http.StatusServiceUnavailable, errors.ToErrorResponse(appErr))
-				c.Abort()
-				return
-			}
-		}
-
-		c.Next()
-
-		// Check if request failed
-		if len(c.Errors) > 0 {
-			cb.recordFailure()
-		} else if cb.state == "half-open" {
-			cb.recordSuccess()
-		}
-	}
-}
-
-// recordFailure records a failure and potentially opens the circuit
-func (cb *CircuitBreaker) recordFailure() {
-	cb.failures++
-	cb.lastFailTime = time.Now()
-
-	if cb.failures >= cb.maxFailures {
-		cb.state = "open"
-		cb.logger.Warn("Circuit breaker opened",
-			zap.Int("failures", cb.failures),
-			zap.Int("max_failures", cb.maxFailures),
-		)
-	}
-}
-
-// recordSuccess records a success and potentially closes the circuit
-func (cb *CircuitBreaker) recordSuccess() {
-	cb.failures = 0
-	cb.state = "closed"
-	cb.logger.Info("Circuit breaker closed")
-}
@@ -2,7 +2,9 @@ package middleware
 
 import (
 	"bytes"
+	"context"
 	"io"
+	"strconv"
 	"time"
 
 	"crypto-bubble-map-be/internal/infrastructure/monitoring"
@@ -20,12 +22,27 @@ func RequestLoggingMiddleware(logger *zap.Logger, monitor *monitoring.Performanc
 	})
 }
 
+const requestIDContextKey contextKey = "request_id"
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, requestID)
+}
+
+// RequestIDFromContext returns the ID that DetailedLoggingMiddleware assigned
+// to the request, if any
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey).(string)
+	return requestID
+}
+
 // DetailedLoggingMiddleware provides detailed request/response logging
 func DetailedLoggingMiddleware(logger *zap.Logger, monitor *monitoring.PerformanceMonitor) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Generate request ID
 		requestID := uuid.New().String()
 		c.Set("request_id", requestID)
+		c.Request = c.Request.WithContext(WithRequestID(c.Request.Context(), requestID))
 
 		// Start timer
 		start := time.Now()
@@ -106,11 +123,11 @@ func DetailedLoggingMiddleware(logger *zap.Logger, monitor *monitoring.Performan
 			labels := map[string]string{
 				"method":      c.Request.Method,
 				"path":        c.FullPath(),
-				"status_code": string(rune(c.Writer.Status())),
+				"status_code": strconv.Itoa(c.Writer.Status()),
 			}
 
 			// Track request duration
-			monitor.TrackDuration("http_request", labels)
+			monitor.ObserveDuration("http_request", duration, labels)
 
 			// Track request count
 			if c.Writer.Status() >= 400 {
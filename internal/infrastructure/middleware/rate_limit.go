@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"crypto-bubble-map-be/internal/infrastructure/cache"
+	"crypto-bubble-map-be/internal/infrastructure/config"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const rateLimitBudgetContextKey contextKey = "rate_limit_budget"
+
+// Rate limit response headers
+const (
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderRateLimitReset     = "X-RateLimit-Reset"
+	HeaderRateLimitCost      = "X-RateLimit-Cost"
+	HeaderRetryAfter         = "Retry-After"
+)
+
+// RateLimiter charges request costs against a per-client Redis token bucket
+type RateLimiter struct {
+	redis     *cache.RedisClient
+	capacity  int64
+	perMinute int64
+	logger    *zap.Logger
+}
+
+// NewRateLimiter creates a token bucket rate limiter. The bucket holds
+// RateLimitBurst tokens and refills at RateLimitRequestsPerMin tokens per minute.
+func NewRateLimiter(redis *cache.RedisClient, cfg *config.SecurityConfig, logger *zap.Logger) *RateLimiter {
+	capacity := int64(cfg.RateLimitBurst)
+	perMinute := int64(cfg.RateLimitRequestsPerMin)
+	if perMinute <= 0 {
+		perMinute = 60
+	}
+	if capacity <= 0 {
+		capacity = perMinute
+	}
+
+	return &RateLimiter{
+		redis:     redis,
+		capacity:  capacity,
+		perMinute: perMinute,
+		logger:    logger,
+	}
+}
+
+// Capacity returns the maximum number of tokens a single request may consume
+func (l *RateLimiter) Capacity() int64 {
+	return l.capacity
+}
+
+// Charge takes cost tokens from the bucket identified by subject
+func (l *RateLimiter) Charge(ctx context.Context, subject string, cost int64) (*cache.TokenBucketResult, error) {
+	return l.redis.TakeTokens(ctx, subject, l.capacity, l.perMinute, cost)
+}
+
+// RateLimitBudget is attached to the request context so that handlers which
+// only know their cost after parsing (GraphQL) can charge the caller's bucket.
+type RateLimitBudget struct {
+	Subject string
+	limiter *RateLimiter
+	header  http.Header
+}
+
+// Capacity returns the maximum number of tokens a single request may consume
+func (b *RateLimitBudget) Capacity() int64 {
+	return b.limiter.Capacity()
+}
+
+// Charge takes cost tokens from the caller's bucket and writes the rate limit headers.
+// Limiter failures fail open so that a Redis outage does not take down the API.
+func (b *RateLimitBudget) Charge(ctx context.Context, cost int64) (*cache.TokenBucketResult, error) {
+	if cost < 1 {
+		cost = 1
+	}
+
+	result, err := b.limiter.Charge(ctx, b.Subject, cost)
+	if err != nil {
+		b.limiter.logger.Error("Rate limit check failed",
+			zap.String("subject", b.Subject),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	setRateLimitHeaders(b.header, result, cost)
+
+	if !result.Allowed {
+		b.limiter.logger.Warn("Rate limit exceeded",
+			zap.String("subject", b.Subject),
+			zap.Int64("cost", cost),
+			zap.Duration("retry_after", result.RetryAfter),
+		)
+	}
+
+	return result, nil
+}
+
+// RateLimitBudgetFromContext returns the caller's rate limit budget, if rate limiting is enabled
+func RateLimitBudgetFromContext(ctx context.Context) (*RateLimitBudget, bool) {
+	budget, ok := ctx.Value(rateLimitBudgetContextKey).(*RateLimitBudget)
+	return budget, ok && budget != nil
+}
+
+// RateLimitMiddleware identifies the caller and attaches its budget to the request.
+// Requests to deferredPaths are charged later by the handler once their cost is known;
+// every other request costs a single token.
+func RateLimitMiddleware(limiter *RateLimiter, logger *zap.Logger, deferredPaths ...string) gin.HandlerFunc {
+	deferred := make(map[string]bool, len(deferredPaths))
+	for _, path := range deferredPaths {
+		deferred[path] = true
+	}
+
+	return func(c *gin.Context) {
+		budget := &RateLimitBudget{
+			Subject: rateLimitSubject(c),
+			limiter: limiter,
+			header:  c.Writer.Header(),
+		}
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), rateLimitBudgetContextKey, budget))
+
+		if deferred[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		result, err := budget.Charge(c.Request.Context(), 1)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		if !result.Allowed {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":      "Rate limit exceeded",
+				"code":       "RATE_LIMIT_EXCEEDED",
+				"request_id": getRequestID(c),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimitSubject picks the bucket key: authenticated user, then API key, then client IP
+func rateLimitSubject(c *gin.Context) string {
+	if userID := getUserID(c); userID != nil {
+		return fmt.Sprintf("user:%d", *userID)
+	}
+
+	if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+		sum := sha256.Sum256([]byte(apiKey))
+		return "api_key:" + hex.EncodeToString(sum[:8])
+	}
+
+	return "ip:" + c.ClientIP()
+}
+
+// setRateLimitHeaders exposes the remaining budget to the client
+func setRateLimitHeaders(header http.Header, result *cache.TokenBucketResult, cost int64) {
+	header.Set(HeaderRateLimitLimit, strconv.FormatInt(result.Capacity, 10))
+	header.Set(HeaderRateLimitRemaining, strconv.FormatInt(result.Remaining, 10))
+	header.Set(HeaderRateLimitReset, strconv.FormatInt(int64(math.Ceil(result.ResetAfter.Seconds())), 10))
+	header.Set(HeaderRateLimitCost, strconv.FormatInt(cost, 10))
+
+	if !result.Allowed {
+		header.Set(HeaderRetryAfter, strconv.FormatInt(int64(math.Ceil(result.RetryAfter.Seconds())), 10))
+	} else {
+		header.Del(HeaderRetryAfter)
+	}
+}
@@ -0,0 +1,474 @@
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"crypto-bubble-map-be/internal/infrastructure/config"
+	"crypto-bubble-map-be/internal/infrastructure/database"
+
+	"go.uber.org/zap"
+)
+
+// ErrLockTimeout is returned when another replica holds a store's migration
+// lock for longer than the lock timeout
+var ErrLockTimeout = errors.New("timed out waiting for migration lock")
+
+// errLockLost is returned when another replica took over an expired lease
+var errLockLost = errors.New("migration lock lost to another replica")
+
+// lockRetryInterval is how often a held migration lock is retried
+const lockRetryInterval = time.Second
+
+// lockLease is how long the lease based locks of MongoDB and Neo4j are held
+// without being renewed. The runner renews the lease every lockRenewInterval
+// while a migration runs, so a lock left behind by a crashed replica expires
+// after at most a lease.
+const lockLease = 10 * time.Minute
+
+// lockRenewInterval is how often the lease is renewed during a migration. It
+// leaves room for a couple of failed renewals before the lease expires.
+const lockRenewInterval = lockLease / 4
+
+// Migration describes a versioned schema change to a store
+type Migration struct {
+	Version int
+	Name    string
+	// Checksum identifies the migration's definition; an applied migration
+	// whose definition has since changed is reported as modified
+	Checksum string
+}
+
+// AppliedMigration is a migration recorded as applied in a store
+type AppliedMigration struct {
+	Version   int
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Store is a datastore with its own ordered migrations and the table,
+// collection or nodes recording which of them have been applied
+type Store interface {
+	// Name returns the name of the store, e.g. "postgresql"
+	Name() string
+	// Migrations returns the store's migrations in ascending version order
+	Migrations() []Migration
+	// Prepare creates what the store needs to record migrations and lock
+	Prepare(ctx context.Context) error
+	// Lock acquires the store's migration lock for owner, or renews it if
+	// owner already holds it. It reports false if another owner holds it.
+	Lock(ctx context.Context, owner string) (bool, error)
+	// Unlock releases the store's migration lock held by owner
+	Unlock(ctx context.Context, owner string) error
+	// Applied returns the migrations recorded as applied
+	Applied(ctx context.Context) ([]AppliedMigration, error)
+	// Up applies a migration and records it
+	Up(ctx context.Context, version int) error
+	// Down reverts a migration and removes its record
+	Down(ctx context.Context, version int) error
+}
+
+// State is the state of a migration in a store
+type State string
+
+const (
+	StatePending  State = "pending"
+	StateApplied  State = "applied"
+	StateModified State = "modified"
+	// StateUnknown is a migration recorded as applied that this build does
+	// not know, e.g. one applied by a newer release
+	StateUnknown State = "unknown"
+)
+
+// MigrationStatus is the status of a migration in a store
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	State     State      `json:"state"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// StoreStatus is the migration status of a store
+type StoreStatus struct {
+	Store      string            `json:"store"`
+	Migrations []MigrationStatus `json:"migrations"`
+}
+
+// Checksum returns the checksum of a migration's definition
+func Checksum(source string) string {
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:])
+}
+
+// Runner applies and reverts the migrations of a set of stores. Each store
+// is migrated under its own lock, so that only one replica migrates a store
+// at a time.
+type Runner struct {
+	stores      []Store
+	lockTimeout time.Duration
+	owner       string
+	logger      *zap.Logger
+}
+
+// NewRunner creates a migration runner for stores
+func NewRunner(lockTimeout time.Duration, logger *zap.Logger, stores ...Store) *Runner {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return &Runner{
+		stores:      stores,
+		lockTimeout: lockTimeout,
+		owner:       fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), time.Now().UnixNano()),
+		logger:      logger,
+	}
+}
+
+// Stores returns the names of the runner's stores
+func (r *Runner) Stores() []string {
+	names := make([]string, 0, len(r.stores))
+	for _, store := range r.stores {
+		names = append(names, store.Name())
+	}
+	return names
+}
+
+// Up applies the pending migrations of the named store, or of every store
+// if name is empty
+func (r *Runner) Up(ctx context.Context, name string) error {
+	stores, err := r.selectStores(name)
+	if err != nil {
+		return err
+	}
+
+	for _, store := range stores {
+		err := r.locked(ctx, store, func() error {
+			return r.up(ctx, store)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to migrate %s: %w", store.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Down reverts the last steps applied migrations of the named store, or of
+// every store if name is empty
+func (r *Runner) Down(ctx context.Context, name string, steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive, got %d", steps)
+	}
+
+	stores, err := r.selectStores(name)
+	if err != nil {
+		return err
+	}
+
+	for _, store := range stores {
+		err := r.locked(ctx, store, func() error {
+			return r.down(ctx, store, steps)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to revert %s migrations: %w", store.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Status returns the migration status of the named store, or of every store
+// if name is empty
+func (r *Runner) Status(ctx context.Context, name string) ([]StoreStatus, error) {
+	stores, err := r.selectStores(name)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]StoreStatus, 0, len(stores))
+	for _, store := range stores {
+		if err := store.Prepare(ctx); err != nil {
+			return nil, fmt.Errorf("failed to prepare %s: %w", store.Name(), err)
+		}
+		applied, err := store.Applied(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get applied %s migrations: %w", store.Name(), err)
+		}
+		statuses = append(statuses, StoreStatus{
+			Store:      store.Name(),
+			Migrations: migrationStatuses(store.Migrations(), applied),
+		})
+	}
+	return statuses, nil
+}
+
+// selectStores returns the named store, or every store if name is empty
+func (r *Runner) selectStores(name string) ([]Store, error) {
+	if name == "" {
+		return r.stores, nil
+	}
+	for _, store := range r.stores {
+		if store.Name() == name {
+			return []Store{store}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown store %q", name)
+}
+
+// locked prepares store and runs fn while holding its migration lock,
+// waiting up to the lock timeout for another replica to release it
+func (r *Runner) locked(ctx context.Context, store Store, fn func() error) error {
+	if err := store.Prepare(ctx); err != nil {
+		return fmt.Errorf("failed to prepare: %w", err)
+	}
+
+	deadline := time.Now().Add(r.lockTimeout)
+	for attempt := 0; ; attempt++ {
+		acquired, err := store.Lock(ctx, r.owner)
+		if err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		if acquired {
+			break
+		}
+		if time.Now().After(deadline) {
+			return ErrLockTimeout
+		}
+
+		if attempt == 0 {
+			r.logger.Info("Waiting for migration lock held by another replica", zap.String("store", store.Name()))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+
+	defer func() {
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := store.Unlock(unlockCtx, r.owner); err != nil {
+			r.logger.Warn("Failed to release migration lock", zap.String("store", store.Name()), zap.Error(err))
+		}
+	}()
+
+	return fn()
+}
+
+// up applies the pending migrations of store in version order
+func (r *Runner) up(ctx context.Context, store Store) error {
+	applied, err := store.Applied(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	if err := verify(store.Migrations(), applied); err != nil {
+		return err
+	}
+
+	done := make(map[int]bool, len(applied))
+	for _, migration := range applied {
+		done[migration.Version] = true
+	}
+
+	count := 0
+	for _, migration := range store.Migrations() {
+		if done[migration.Version] {
+			continue
+		}
+		start := time.Now()
+		err := r.renewing(ctx, store, func(ctx context.Context) error {
+			return store.Up(ctx, migration.Version)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+		count++
+
+		r.logger.Info("Applied migration",
+			zap.String("store", store.Name()),
+			zap.Int("version", migration.Version),
+			zap.String("name", migration.Name),
+			zap.Duration("duration", time.Since(start)))
+	}
+
+	if count == 0 {
+		r.logger.Info("Schema is up to date", zap.String("store", store.Name()))
+	}
+	return nil
+}
+
+// down reverts the last steps applied migrations of store
+func (r *Runner) down(ctx context.Context, store Store, steps int) error {
+	applied, err := store.Applied(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	if err := verify(store.Migrations(), applied); err != nil {
+		return err
+	}
+
+	known := make(map[int]Migration)
+	for _, migration := range store.Migrations() {
+		known[migration.Version] = migration
+	}
+
+	sort.Slice(applied, func(i, j int) bool { return applied[i].Version > applied[j].Version })
+	for i := 0; i < steps && i < len(applied); i++ {
+		migration, ok := known[applied[i].Version]
+		if !ok {
+			return fmt.Errorf("cannot revert unknown migration %d (%s)", applied[i].Version, applied[i].Name)
+		}
+		err := r.renewing(ctx, store, func(ctx context.Context) error {
+			return store.Down(ctx, migration.Version)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to revert migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+
+		r.logger.Info("Reverted migration",
+			zap.String("store", store.Name()),
+			zap.Int("version", migration.Version),
+			zap.String("name", migration.Name))
+	}
+	return nil
+}
+
+// renewing renews the migration lock before running fn and keeps renewing it
+// in the background until fn returns, so that the lease does not expire
+// during a long migration. If the lock is lost to another replica, fn's
+// context is cancelled.
+func (r *Runner) renewing(ctx context.Context, store Store, fn func(ctx context.Context) error) error {
+	if err := r.renew(ctx, store); err != nil {
+		return err
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var lost error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(lockRenewInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-runCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			err := r.renew(runCtx, store)
+			if err == nil || runCtx.Err() != nil {
+				continue
+			}
+			if errors.Is(err, errLockLost) {
+				lost = err
+				cancel()
+				return
+			}
+			// The lease outlasts a few failed renewals, so try again later
+			r.logger.Warn("Failed to renew migration lock", zap.String("store", store.Name()), zap.Error(err))
+		}
+	}()
+
+	err := fn(runCtx)
+	cancel()
+	<-done
+
+	if lost != nil {
+		return lost
+	}
+	return err
+}
+
+// renew renews the migration lock held by the runner
+func (r *Runner) renew(ctx context.Context, store Store) error {
+	acquired, err := store.Lock(ctx, r.owner)
+	if err != nil {
+		return fmt.Errorf("failed to renew migration lock: %w", err)
+	}
+	if !acquired {
+		return errLockLost
+	}
+	return nil
+}
+
+// verify checks that applied migrations have not been modified since. An
+// applied migration unknown to this build is allowed, so that replicas of
+// an older release keep starting during a rolling deploy.
+func verify(migrations []Migration, applied []AppliedMigration) error {
+	known := make(map[int]Migration, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = migration
+	}
+
+	for _, record := range applied {
+		migration, ok := known[record.Version]
+		if ok && migration.Checksum != record.Checksum {
+			return fmt.Errorf("migration %d (%s) was modified after it was applied: checksum %s, applied %s",
+				migration.Version, migration.Name, migration.Checksum, record.Checksum)
+		}
+	}
+	return nil
+}
+
+// migrationStatuses merges a store's migrations with those it has applied
+func migrationStatuses(migrations []Migration, applied []AppliedMigration) []MigrationStatus {
+	records := make(map[int]AppliedMigration, len(applied))
+	for _, record := range applied {
+		records[record.Version] = record
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name, State: StatePending}
+		if record, ok := records[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
+			status.State = StateApplied
+			if record.Checksum != migration.Checksum {
+				status.State = StateModified
+			}
+			delete(records, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, record := range records {
+		appliedAt := record.AppliedAt
+		statuses = append(statuses, MigrationStatus{
+			Version:   record.Version,
+			Name:      record.Name,
+			State:     StateUnknown,
+			AppliedAt: &appliedAt,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses
+}
+
+// NewDatastoreRunner creates a migration runner for the PostgreSQL, MongoDB
+// and Neo4j stores
+func NewDatastoreRunner(
+	cfg *config.MigrationConfig,
+	postgres *database.PostgreSQLClient,
+	mongo *database.MongoClient,
+	neo4j *database.Neo4jClient,
+	logger *zap.Logger,
+) *Runner {
+	return NewRunner(cfg.LockTimeout, logger,
+		NewPostgreSQLStore(postgres, logger),
+		NewMongoStore(mongo, logger),
+		NewNeo4jStore(neo4j, logger),
+	)
+}
@@ -0,0 +1,343 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"crypto-bubble-map-be/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	mongoMigrationsCollection = "schema_migrations"
+	mongoLockCollection       = "schema_migration_locks"
+	mongoLockID               = "migrations"
+)
+
+// mongoIndex is an index created by a MongoDB migration
+type mongoIndex struct {
+	Collection string `bson:"collection"`
+	Keys       bson.D `bson:"keys"`
+	// Name defaults to the name MongoDB generates from the keys, so that
+	// indexes created before migrations were versioned are matched
+	Name    string `bson:"name,omitempty"`
+	Unique  bool   `bson:"unique,omitempty"`
+	Sparse  bool   `bson:"sparse,omitempty"`
+	Weights bson.D `bson:"weights,omitempty"`
+	// Collation is the collation queries must use to be served by the index
+	Collation *options.Collation `bson:"collation,omitempty"`
+}
+
+// name returns the index name
+func (i mongoIndex) name() string {
+	if i.Name != "" {
+		return i.Name
+	}
+
+	parts := make([]string, 0, len(i.Keys)*2)
+	for _, key := range i.Keys {
+		parts = append(parts, key.Key, fmt.Sprint(key.Value))
+	}
+	return strings.Join(parts, "_")
+}
+
+// model returns the index model to create the index with
+func (i mongoIndex) model() mongo.IndexModel {
+	opts := options.Index().SetName(i.name())
+	if i.Unique {
+		opts.SetUnique(true)
+	}
+	if i.Sparse {
+		opts.SetSparse(true)
+	}
+	if i.Weights != nil {
+		opts.SetWeights(i.Weights)
+	}
+	if i.Collation != nil {
+		opts.SetCollation(i.Collation)
+	}
+	return mongo.IndexModel{Keys: i.Keys, Options: opts}
+}
+
+// mongoMigration is a MongoDB migration creating indexes. Down drops them.
+type mongoMigration struct {
+	version int
+	name    string
+	indexes []mongoIndex
+}
+
+// checksum returns the checksum of the migration's index definitions
+func (m mongoMigration) checksum() string {
+	source, err := bson.MarshalExtJSON(bson.D{{Key: "indexes", Value: m.indexes}}, true, false)
+	if err != nil {
+		// Index definitions are static; failing to encode them is a bug
+		panic(fmt.Sprintf("failed to encode migration %d: %v", m.version, err))
+	}
+	return Checksum(string(source))
+}
+
+// mongoMigrations are the MongoDB migrations in version order
+var mongoMigrations = []mongoMigration{
+	{
+		version: 1,
+		name:    "transaction indexes",
+		indexes: []mongoIndex{
+			{Collection: "transactions", Keys: bson.D{{Key: "hash", Value: 1}}, Unique: true},
+			{Collection: "transactions", Keys: bson.D{{Key: "from", Value: 1}}},
+			{Collection: "transactions", Keys: bson.D{{Key: "to", Value: 1}}},
+			{Collection: "transactions", Keys: bson.D{{Key: "crawled_at", Value: -1}}},
+			{Collection: "transactions", Keys: bson.D{{Key: "block_number", Value: 1}, {Key: "transaction_index", Value: 1}}},
+			{Collection: "transactions", Keys: bson.D{{Key: "from", Value: 1}, {Key: "to", Value: 1}, {Key: "crawled_at", Value: -1}}},
+		},
+	},
+	{
+		version: 2,
+		name:    "compliance indexes",
+		indexes: []mongoIndex{
+			{Collection: "sanctioned_addresses", Keys: bson.D{{Key: "list_version", Value: 1}, {Key: "address", Value: 1}}, Unique: true},
+			{Collection: "sanctioned_addresses", Keys: bson.D{{Key: "address", Value: 1}}},
+			{Collection: "sanctions_list_versions", Keys: bson.D{{Key: "id", Value: 1}}, Unique: true},
+			{Collection: "sanctions_list_versions", Keys: bson.D{{Key: "source", Value: 1}, {Key: "imported_at", Value: -1}}},
+			{Collection: "screening_results", Keys: bson.D{{Key: "job_id", Value: 1}, {Key: "index", Value: 1}}},
+			{Collection: "compliance_reports", Keys: bson.D{{Key: "id", Value: 1}}, Unique: true},
+			{Collection: "compliance_reports", Keys: bson.D{{Key: "wallet_address", Value: 1}, {Key: "generated_at", Value: -1}}},
+			{Collection: "compliance_report_history", Keys: bson.D{{Key: "report_id", Value: 1}, {Key: "sequence", Value: 1}}, Unique: true},
+		},
+	},
+	{
+		version: 3,
+		name:    "security alert and case indexes",
+		indexes: []mongoIndex{
+			{Collection: "security_alerts", Keys: bson.D{{Key: "dedupe_key", Value: 1}}, Unique: true, Sparse: true},
+			{Collection: "detection_checkpoints", Keys: bson.D{{Key: "name", Value: 1}}, Unique: true},
+			{Collection: "security_cases", Keys: bson.D{{Key: "id", Value: 1}}, Unique: true},
+			{Collection: "security_cases", Keys: bson.D{{Key: "assignee_id", Value: 1}, {Key: "status", Value: 1}, {Key: "resolution_due_at", Value: 1}}},
+			{Collection: "security_cases", Keys: bson.D{{Key: "alert_ids", Value: 1}}},
+			{Collection: "case_comments", Keys: bson.D{{Key: "case_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		},
+	},
+	{
+		version: 4,
+		name:    "mev and wallet classification indexes",
+		indexes: []mongoIndex{
+			{Collection: "mev_activities", Keys: bson.D{{Key: "dedupe_key", Value: 1}}, Unique: true},
+			{Collection: "mev_activities", Keys: bson.D{{Key: "bot_address", Value: 1}, {Key: "timestamp", Value: -1}}},
+			{Collection: "mev_activities", Keys: bson.D{{Key: "victims.address", Value: 1}, {Key: "timestamp", Value: -1}}},
+			{Collection: "wallet_classifications", Keys: bson.D{{Key: "address", Value: 1}}, Unique: true},
+			{Collection: "wallet_classifications", Keys: bson.D{{Key: "wallet_type", Value: 1}, {Key: "confidence", Value: -1}}},
+		},
+	},
+	{
+		version: 5,
+		name:    "address label indexes",
+		indexes: []mongoIndex{
+			{Collection: "address_labels", Keys: bson.D{{Key: "id", Value: 1}}, Unique: true},
+			{Collection: "address_labels", Keys: bson.D{{Key: "address", Value: 1}, {Key: "source", Value: 1}, {Key: "name", Value: 1}}, Unique: true},
+			{Collection: "address_labels", Keys: bson.D{{Key: "source", Value: 1}, {Key: "updated_at", Value: -1}}},
+			{Collection: "address_labels", Keys: bson.D{{Key: "category", Value: 1}, {Key: "updated_at", Value: -1}}},
+			{Collection: "address_labels", Keys: bson.D{{Key: "valid_from", Value: 1}}, Sparse: true},
+			{Collection: "address_labels", Keys: bson.D{{Key: "valid_until", Value: 1}}, Sparse: true},
+			{
+				Collection: "address_labels",
+				Keys:       bson.D{{Key: "name", Value: "text"}, {Key: "entity", Value: "text"}, {Key: "tags", Value: "text"}},
+				Name:       "address_labels_text",
+				Weights:    bson.D{{Key: "name", Value: 3}, {Key: "entity", Value: 2}, {Key: "tags", Value: 1}},
+			},
+			{Collection: "label_imports", Keys: bson.D{{Key: "imported_at", Value: -1}}},
+			{Collection: "label_proposals", Keys: bson.D{{Key: "id", Value: 1}}, Unique: true},
+			{Collection: "label_proposals", Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+			{Collection: "label_proposals", Keys: bson.D{{Key: "submitter_id", Value: 1}, {Key: "status", Value: 1}}},
+			{Collection: "label_proposals", Keys: bson.D{{Key: "address", Value: 1}, {Key: "kind", Value: 1}, {Key: "status", Value: 1}}},
+			{Collection: "submitter_reputation", Keys: bson.D{{Key: "user_id", Value: 1}}, Unique: true},
+		},
+	},
+	{
+		version: 6,
+		name:    "token transfer indexes",
+		indexes: []mongoIndex{
+			{Collection: "token_transfers", Keys: bson.D{{Key: "token_address", Value: 1}}},
+			{Collection: "token_transfers", Keys: bson.D{{Key: "token_symbol", Value: 1}}},
+			{Collection: "token_transfers", Keys: bson.D{{Key: "token_name", Value: 1}}},
+		},
+	},
+	{
+		// Transactions are stored with addresses as crawled, often checksummed,
+		// while wallets are looked up by their normalized address
+		version: 7,
+		name:    "case-insensitive transaction address indexes",
+		indexes: []mongoIndex{
+			{Collection: "transactions", Keys: bson.D{{Key: "from", Value: 1}}, Name: "from_1_ci", Collation: database.AddressCollation},
+			{Collection: "transactions", Keys: bson.D{{Key: "to", Value: 1}}, Name: "to_1_ci", Collation: database.AddressCollation},
+		},
+	},
+}
+
+// mongoMigrationRecord is a migration recorded in schema_migrations
+type mongoMigrationRecord struct {
+	Version   int       `bson:"_id"`
+	Name      string    `bson:"name"`
+	Checksum  string    `bson:"checksum"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+// MongoStore migrates MongoDB. Applied migrations are recorded in the
+// schema_migrations collection and migrations are serialized by a lease held
+// in the schema_migration_locks collection.
+type MongoStore struct {
+	db     *mongo.Database
+	logger *zap.Logger
+}
+
+// NewMongoStore creates a migration store for MongoDB
+func NewMongoStore(client *database.MongoClient, logger *zap.Logger) *MongoStore {
+	return &MongoStore{
+		db:     client.GetDatabase(),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the store
+func (s *MongoStore) Name() string {
+	return "mongodb"
+}
+
+// Migrations returns the MongoDB migrations
+func (s *MongoStore) Migrations() []Migration {
+	migrations := make([]Migration, 0, len(mongoMigrations))
+	for _, migration := range mongoMigrations {
+		migrations = append(migrations, Migration{
+			Version:  migration.version,
+			Name:     migration.name,
+			Checksum: migration.checksum(),
+		})
+	}
+	return migrations
+}
+
+// Prepare does nothing: collections are created on first write and the
+// records and lock are keyed by _id
+func (s *MongoStore) Prepare(ctx context.Context) error {
+	return nil
+}
+
+// Lock acquires or renews the lease. The lock document is upserted only if
+// it is free, expired or already owned by owner; otherwise the upsert
+// collides with the existing document.
+func (s *MongoStore) Lock(ctx context.Context, owner string) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": mongoLockID,
+		"$or": bson.A{
+			bson.M{"owner": owner},
+			bson.M{"expires_at": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"owner":       owner,
+		"acquired_at": now,
+		"expires_at":  now.Add(lockLease),
+	}}
+
+	_, err := s.db.Collection(mongoLockCollection).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Unlock releases the lease held by owner
+func (s *MongoStore) Unlock(ctx context.Context, owner string) error {
+	_, err := s.db.Collection(mongoLockCollection).DeleteOne(ctx, bson.M{"_id": mongoLockID, "owner": owner})
+	return err
+}
+
+// Applied returns the migrations recorded in schema_migrations
+func (s *MongoStore) Applied(ctx context.Context) ([]AppliedMigration, error) {
+	cursor, err := s.db.Collection(mongoMigrationsCollection).Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	var records []mongoMigrationRecord
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make([]AppliedMigration, 0, len(records))
+	for _, record := range records {
+		applied = append(applied, AppliedMigration(record))
+	}
+	return applied, nil
+}
+
+// Up creates a migration's indexes and records it. Creating an existing
+// index with the same definition is a no-op, so a migration interrupted
+// before it was recorded is safely re-run.
+func (s *MongoStore) Up(ctx context.Context, version int) error {
+	migration, err := findMongoMigration(version)
+	if err != nil {
+		return err
+	}
+
+	for _, index := range migration.indexes {
+		if _, err := s.db.Collection(index.Collection).Indexes().CreateOne(ctx, index.model()); err != nil {
+			return fmt.Errorf("failed to create index %s on %s: %w", index.name(), index.Collection, err)
+		}
+	}
+
+	_, err = s.db.Collection(mongoMigrationsCollection).InsertOne(ctx, mongoMigrationRecord{
+		Version:   migration.version,
+		Name:      migration.name,
+		Checksum:  migration.checksum(),
+		AppliedAt: time.Now(),
+	})
+	return err
+}
+
+// Down drops a migration's indexes and removes its record
+func (s *MongoStore) Down(ctx context.Context, version int) error {
+	migration, err := findMongoMigration(version)
+	if err != nil {
+		return err
+	}
+
+	for _, index := range migration.indexes {
+		_, err := s.db.Collection(index.Collection).Indexes().DropOne(ctx, index.name())
+		if err != nil && !isMongoIndexNotFound(err) {
+			return fmt.Errorf("failed to drop index %s on %s: %w", index.name(), index.Collection, err)
+		}
+	}
+
+	_, err = s.db.Collection(mongoMigrationsCollection).DeleteOne(ctx, bson.M{"_id": migration.version})
+	return err
+}
+
+// isMongoIndexNotFound reports whether err is MongoDB's error for dropping an
+// index, or an index of a collection, that does not exist
+func isMongoIndexNotFound(err error) bool {
+	var commandErr mongo.CommandError
+	if errors.As(err, &commandErr) {
+		return commandErr.Name == "IndexNotFound" || commandErr.Name == "NamespaceNotFound"
+	}
+	return false
+}
+
+// findMongoMigration returns the MongoDB migration with version
+func findMongoMigration(version int) (mongoMigration, error) {
+	for _, migration := range mongoMigrations {
+		if migration.version == version {
+			return migration, nil
+		}
+	}
+	return mongoMigration{}, fmt.Errorf("unknown migration %d", version)
+}
@@ -0,0 +1,271 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"crypto-bubble-map-be/internal/infrastructure/database"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+)
+
+// neo4jMigration is a Neo4j migration of schema statements. Neo4j does not
+// mix schema and data changes in a transaction, so each statement runs in
+// its own; statements use IF NOT EXISTS and IF EXISTS so that a migration
+// interrupted before it was recorded is safely re-run.
+type neo4jMigration struct {
+	version int
+	name    string
+	up      []string
+	down    []string
+}
+
+// checksum returns the checksum of the migration's statements
+func (m neo4jMigration) checksum() string {
+	return Checksum(strings.Join(m.up, ";\n") + "\n// down\n" + strings.Join(m.down, ";\n"))
+}
+
+// neo4jMigrations are the Neo4j migrations in version order
+var neo4jMigrations = []neo4jMigration{
+	{
+		version: 1,
+		name:    "wallet, network and transaction constraints and indexes",
+		up: []string{
+			`CREATE CONSTRAINT wallet_address_unique IF NOT EXISTS FOR (w:Wallet) REQUIRE w.address IS UNIQUE`,
+			`CREATE CONSTRAINT network_id_unique IF NOT EXISTS FOR (n:Network) REQUIRE n.id IS UNIQUE`,
+			`CREATE INDEX wallet_risk_score IF NOT EXISTS FOR (w:Wallet) ON (w.risk_score)`,
+			`CREATE INDEX wallet_balance IF NOT EXISTS FOR (w:Wallet) ON (w.balance)`,
+			`CREATE INDEX wallet_type IF NOT EXISTS FOR (w:Wallet) ON (w.type)`,
+			`CREATE INDEX wallet_network IF NOT EXISTS FOR (w:Wallet) ON (w.network_id)`,
+			`CREATE INDEX wallet_last_activity IF NOT EXISTS FOR (w:Wallet) ON (w.last_activity)`,
+			`CREATE INDEX transaction_timestamp IF NOT EXISTS FOR (t:Transaction) ON (t.timestamp)`,
+			`CREATE INDEX transaction_value IF NOT EXISTS FOR (t:Transaction) ON (t.value_usd)`,
+			`CREATE INDEX transaction_risk IF NOT EXISTS FOR (t:Transaction) ON (t.risk_score)`,
+			`CREATE INDEX wallet_cluster_id IF NOT EXISTS FOR (c:Cluster) ON (c.id)`,
+			`CREATE INDEX network_stats_id IF NOT EXISTS FOR (s:NetworkStats) ON (s.network_id)`,
+		},
+		down: []string{
+			`DROP INDEX network_stats_id IF EXISTS`,
+			`DROP INDEX wallet_cluster_id IF EXISTS`,
+			`DROP INDEX transaction_risk IF EXISTS`,
+			`DROP INDEX transaction_value IF EXISTS`,
+			`DROP INDEX transaction_timestamp IF EXISTS`,
+			`DROP INDEX wallet_last_activity IF EXISTS`,
+			`DROP INDEX wallet_network IF EXISTS`,
+			`DROP INDEX wallet_type IF EXISTS`,
+			`DROP INDEX wallet_balance IF EXISTS`,
+			`DROP INDEX wallet_risk_score IF EXISTS`,
+			`DROP CONSTRAINT network_id_unique IF EXISTS`,
+			`DROP CONSTRAINT wallet_address_unique IF EXISTS`,
+		},
+	},
+	{
+		version: 2,
+		name:    "wallet search indexes",
+		up: []string{
+			`CREATE INDEX wallet_ens_name IF NOT EXISTS FOR (w:Wallet) ON (w.ens_name)`,
+			fmt.Sprintf(`CREATE FULLTEXT INDEX %s IF NOT EXISTS FOR (w:Wallet) ON EACH [w.label, w.label_entity, w.ens_name, w.tags_text]`, database.WalletSearchIndex),
+		},
+		down: []string{
+			fmt.Sprintf(`DROP INDEX %s IF EXISTS`, database.WalletSearchIndex),
+			`DROP INDEX wallet_ens_name IF EXISTS`,
+		},
+	},
+	walletAddressesMigration,
+}
+
+// Neo4jStore migrates Neo4j. Applied migrations are recorded as
+// SchemaMigration nodes and migrations are serialized by a lease held on a
+// MigrationLock node.
+type Neo4jStore struct {
+	client *database.Neo4jClient
+	logger *zap.Logger
+}
+
+// NewNeo4jStore creates a migration store for Neo4j
+func NewNeo4jStore(client *database.Neo4jClient, logger *zap.Logger) *Neo4jStore {
+	return &Neo4jStore{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Name returns the name of the store
+func (s *Neo4jStore) Name() string {
+	return "neo4j"
+}
+
+// Migrations returns the Neo4j migrations
+func (s *Neo4jStore) Migrations() []Migration {
+	migrations := make([]Migration, 0, len(neo4jMigrations))
+	for _, migration := range neo4jMigrations {
+		migrations = append(migrations, Migration{
+			Version:  migration.version,
+			Name:     migration.name,
+			Checksum: migration.checksum(),
+		})
+	}
+	return migrations
+}
+
+// Prepare creates the constraints keeping migration records and the lock
+// unique
+func (s *Neo4jStore) Prepare(ctx context.Context) error {
+	statements := []string{
+		`CREATE CONSTRAINT schema_migration_version IF NOT EXISTS FOR (m:SchemaMigration) REQUIRE m.version IS UNIQUE`,
+		`CREATE CONSTRAINT migration_lock_name IF NOT EXISTS FOR (l:MigrationLock) REQUIRE l.name IS UNIQUE`,
+	}
+	for _, statement := range statements {
+		if err := s.run(ctx, statement, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lock acquires or renews the lease. MERGE locks the node, so concurrent
+// replicas are serialized and only one sees the lock free or expired.
+func (s *Neo4jStore) Lock(ctx context.Context, owner string) (bool, error) {
+	query := `
+		MERGE (l:MigrationLock {name: 'migrations'})
+		WITH l
+		WHERE l.owner IS NULL OR l.owner = $owner OR l.expires_at < datetime()
+		SET l.owner = $owner,
+			l.acquired_at = datetime(),
+			l.expires_at = datetime() + duration({seconds: $lease})
+		RETURN l.owner AS owner
+	`
+
+	result, err := s.client.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		result, err := tx.Run(ctx, query, map[string]interface{}{
+			"owner": owner,
+			"lease": int64(lockLease / time.Second),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		records, err := result.Collect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return len(records) > 0, nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	acquired, _ := result.(bool)
+	return acquired, nil
+}
+
+// Unlock releases the lease held by owner
+func (s *Neo4jStore) Unlock(ctx context.Context, owner string) error {
+	return s.run(ctx, `MATCH (l:MigrationLock {name: 'migrations', owner: $owner}) DELETE l`, map[string]interface{}{
+		"owner": owner,
+	})
+}
+
+// Applied returns the migrations recorded as SchemaMigration nodes
+func (s *Neo4jStore) Applied(ctx context.Context) ([]AppliedMigration, error) {
+	query := `
+		MATCH (m:SchemaMigration)
+		RETURN m.version AS version, m.name AS name, m.checksum AS checksum, m.applied_at AS applied_at
+		ORDER BY m.version
+	`
+
+	result, err := s.client.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		result, err := tx.Run(ctx, query, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		records, err := result.Collect(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		applied := make([]AppliedMigration, 0, len(records))
+		for _, record := range records {
+			version, _ := record.Get("version")
+			name, _ := record.Get("name")
+			checksum, _ := record.Get("checksum")
+			appliedAt, _ := record.Get("applied_at")
+
+			migration := AppliedMigration{}
+			if v, ok := version.(int64); ok {
+				migration.Version = int(v)
+			}
+			migration.Name, _ = name.(string)
+			migration.Checksum, _ = checksum.(string)
+			migration.AppliedAt, _ = appliedAt.(time.Time)
+			applied = append(applied, migration)
+		}
+		return applied, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.([]AppliedMigration), nil
+}
+
+// Up runs a migration's statements and records it
+func (s *Neo4jStore) Up(ctx context.Context, version int) error {
+	migration, err := findNeo4jMigration(version)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range migration.up {
+		if err := s.run(ctx, statement, nil); err != nil {
+			return fmt.Errorf("failed to run %q: %w", statement, err)
+		}
+	}
+
+	return s.run(ctx, `CREATE (m:SchemaMigration {version: $version, name: $name, checksum: $checksum, applied_at: datetime()})`, map[string]interface{}{
+		"version":  int64(migration.version),
+		"name":     migration.name,
+		"checksum": migration.checksum(),
+	})
+}
+
+// Down runs a migration's down statements and removes its record
+func (s *Neo4jStore) Down(ctx context.Context, version int) error {
+	migration, err := findNeo4jMigration(version)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range migration.down {
+		if err := s.run(ctx, statement, nil); err != nil {
+			return fmt.Errorf("failed to run %q: %w", statement, err)
+		}
+	}
+
+	return s.run(ctx, `MATCH (m:SchemaMigration {version: $version}) DELETE m`, map[string]interface{}{
+		"version": int64(migration.version),
+	})
+}
+
+// run runs a statement in its own write transaction
+func (s *Neo4jStore) run(ctx context.Context, statement string, params map[string]interface{}) error {
+	_, err := s.client.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		_, err := tx.Run(ctx, statement, params)
+		return nil, err
+	})
+	return err
+}
+
+// findNeo4jMigration returns the Neo4j migration with version
+func findNeo4jMigration(version int) (neo4jMigration, error) {
+	for _, migration := range neo4jMigrations {
+		if migration.version == version {
+			return migration, nil
+		}
+	}
+	return neo4jMigration{}, fmt.Errorf("unknown migration %d", version)
+}
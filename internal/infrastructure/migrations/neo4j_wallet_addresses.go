@@ -0,0 +1,28 @@
+package migrations
+
+// walletAddressesMigration stores EVM wallet addresses lowercased, as
+// entity.NormalizeAddress normalizes them, so that every lookup is a single
+// equality on the wallet_address_unique index.
+//
+// Wallets whose addresses differ only by case are the same wallet and would
+// violate the constraint once lowercased, so they are first merged into one
+// node, keeping the relationships of all of them and the properties of the
+// already lowercased node if there is one. Merging needs the APOC plugin. The
+// original casing is not kept, so there is nothing to revert.
+var walletAddressesMigration = neo4jMigration{
+	version: 3,
+	name:    "lowercase EVM wallet addresses",
+	up: []string{
+		`MATCH (w:Wallet)
+		WHERE w.address STARTS WITH '0x' OR w.address STARTS WITH '0X'
+		WITH toLower(w.address) AS address, w
+		ORDER BY w.address = toLower(w.address) DESC
+		WITH address, collect(w) AS wallets
+		WHERE size(wallets) > 1
+		CALL apoc.refactor.mergeNodes(wallets, {properties: 'discard', mergeRels: true}) YIELD node
+		RETURN count(node)`,
+		`MATCH (w:Wallet)
+		WHERE (w.address STARTS WITH '0x' OR w.address STARTS WITH '0X') AND w.address <> toLower(w.address)
+		SET w.address = toLower(w.address)`,
+	},
+}
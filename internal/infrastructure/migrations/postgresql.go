@@ -0,0 +1,427 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"crypto-bubble-map-be/internal/infrastructure/database"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// postgresLockKey is the key of the advisory lock serializing migrations
+const postgresLockKey int64 = 4_915_327_083
+
+// postgresMigration is a PostgreSQL migration of SQL statements. The
+// statements run in the transaction that records the migration, so a failed
+// migration leaves no trace.
+type postgresMigration struct {
+	version int
+	name    string
+	up      []string
+	down    []string
+}
+
+// checksum returns the checksum of the migration's statements
+func (m postgresMigration) checksum() string {
+	return Checksum(strings.Join(m.up, ";\n") + "\n-- down\n" + strings.Join(m.down, ";\n"))
+}
+
+// updatedAtTables are the tables whose updated_at column is maintained by a
+// trigger
+var updatedAtTables = []string{"users", "user_sessions", "watched_wallet_tags", "watched_wallets", "wallet_alerts"}
+
+// postgresMigrations are the PostgreSQL migrations in version order. The GORM
+// models do not change the schema: a schema change is a new migration, and the
+// models are updated to match it.
+var postgresMigrations = []postgresMigration{
+	{
+		// The tables as GORM auto-migration created them before migrations
+		// were versioned, so that databases created that way are baselined
+		// without changes
+		version: 1,
+		name:    "baseline",
+		up: []string{
+			`CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`,
+			`CREATE TABLE IF NOT EXISTS users (
+				id BIGSERIAL PRIMARY KEY,
+				email TEXT NOT NULL,
+				username TEXT,
+				password_hash TEXT NOT NULL,
+				first_name TEXT,
+				last_name TEXT,
+				role TEXT DEFAULT 'USER',
+				is_active BOOLEAN DEFAULT true,
+				email_verified BOOLEAN DEFAULT false,
+				email_verified_at TIMESTAMPTZ,
+				last_login_at TIMESTAMPTZ,
+				ai_daily_token_limit BIGINT,
+				created_at TIMESTAMPTZ,
+				updated_at TIMESTAMPTZ,
+				deleted_at TIMESTAMPTZ
+			)`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email)`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (username)`,
+			`CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at)`,
+			`CREATE TABLE IF NOT EXISTS user_sessions (
+				id BIGSERIAL PRIMARY KEY,
+				user_id BIGINT NOT NULL,
+				session_id TEXT NOT NULL,
+				expires_at TIMESTAMPTZ NOT NULL,
+				created_at TIMESTAMPTZ,
+				updated_at TIMESTAMPTZ
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_user_sessions_user_id ON user_sessions (user_id)`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_user_sessions_session_id ON user_sessions (session_id)`,
+			`CREATE INDEX IF NOT EXISTS idx_user_sessions_expires_at ON user_sessions (expires_at)`,
+			`CREATE TABLE IF NOT EXISTS watched_wallets (
+				id BIGSERIAL PRIMARY KEY,
+				user_id BIGINT NOT NULL,
+				address TEXT NOT NULL,
+				label TEXT,
+				added_at TIMESTAMPTZ,
+				last_activity TIMESTAMPTZ,
+				balance TEXT,
+				transaction_count BIGINT,
+				risk_score DECIMAL,
+				alerts_enabled BOOLEAN DEFAULT true,
+				balance_change_threshold DECIMAL,
+				transaction_volume_threshold TEXT,
+				risk_score_increase_threshold DECIMAL,
+				notes TEXT,
+				last_checked TIMESTAMPTZ,
+				created_at TIMESTAMPTZ,
+				updated_at TIMESTAMPTZ,
+				deleted_at TIMESTAMPTZ
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_watched_wallets_user_id ON watched_wallets (user_id)`,
+			`CREATE INDEX IF NOT EXISTS idx_watched_wallets_address ON watched_wallets (address)`,
+			`CREATE INDEX IF NOT EXISTS idx_watched_wallets_deleted_at ON watched_wallets (deleted_at)`,
+			`CREATE TABLE IF NOT EXISTS watched_wallet_tags (
+				id BIGSERIAL PRIMARY KEY,
+				name TEXT NOT NULL,
+				color TEXT,
+				created_at TIMESTAMPTZ,
+				updated_at TIMESTAMPTZ,
+				deleted_at TIMESTAMPTZ
+			)`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_watched_wallet_tags_name ON watched_wallet_tags (name)`,
+			`CREATE INDEX IF NOT EXISTS idx_watched_wallet_tags_deleted_at ON watched_wallet_tags (deleted_at)`,
+			`CREATE TABLE IF NOT EXISTS watched_wallet_tag_associations (
+				id BIGSERIAL PRIMARY KEY,
+				watched_wallet_id BIGINT NOT NULL,
+				tag_id BIGINT NOT NULL,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (watched_wallet_id, tag_id)
+			)`,
+			`CREATE TABLE IF NOT EXISTS wallet_alerts (
+				id BIGSERIAL PRIMARY KEY,
+				wallet_id BIGINT NOT NULL,
+				type TEXT NOT NULL,
+				severity TEXT NOT NULL,
+				message TEXT NOT NULL,
+				details TEXT,
+				timestamp TIMESTAMPTZ,
+				acknowledged BOOLEAN DEFAULT false,
+				acknowledged_at TIMESTAMPTZ,
+				acknowledged_by BIGINT,
+				created_at TIMESTAMPTZ,
+				updated_at TIMESTAMPTZ,
+				deleted_at TIMESTAMPTZ
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_wallet_alerts_wallet_id ON wallet_alerts (wallet_id)`,
+			`CREATE INDEX IF NOT EXISTS idx_wallet_alerts_deleted_at ON wallet_alerts (deleted_at)`,
+			`CREATE TABLE IF NOT EXISTS ai_conversations (
+				id BIGSERIAL PRIMARY KEY,
+				user_id BIGINT NOT NULL,
+				title TEXT NOT NULL,
+				wallet_address TEXT,
+				tokens_used BIGINT NOT NULL DEFAULT 0,
+				message_count BIGINT NOT NULL DEFAULT 0,
+				created_at TIMESTAMPTZ,
+				updated_at TIMESTAMPTZ,
+				deleted_at TIMESTAMPTZ
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_ai_conversations_user_id ON ai_conversations (user_id)`,
+			`CREATE INDEX IF NOT EXISTS idx_ai_conversations_wallet_address ON ai_conversations (wallet_address)`,
+			`CREATE INDEX IF NOT EXISTS idx_ai_conversations_updated_at ON ai_conversations (updated_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_ai_conversations_deleted_at ON ai_conversations (deleted_at)`,
+			`CREATE TABLE IF NOT EXISTS ai_messages (
+				id BIGSERIAL PRIMARY KEY,
+				conversation_id BIGINT NOT NULL,
+				role TEXT NOT NULL,
+				content TEXT NOT NULL,
+				sources TEXT,
+				related_questions TEXT,
+				action_items TEXT,
+				confidence DECIMAL,
+				model TEXT,
+				tokens_used BIGINT NOT NULL DEFAULT 0,
+				created_at TIMESTAMPTZ
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_ai_messages_conversation_id ON ai_messages (conversation_id)`,
+			`CREATE INDEX IF NOT EXISTS idx_ai_messages_created_at ON ai_messages (created_at)`,
+			`CREATE TABLE IF NOT EXISTS ai_usage (
+				id BIGSERIAL PRIMARY KEY,
+				user_id BIGINT NOT NULL,
+				role TEXT NOT NULL,
+				conversation_id BIGINT,
+				model TEXT,
+				tokens_used BIGINT NOT NULL DEFAULT 0,
+				cached BOOLEAN NOT NULL DEFAULT false,
+				created_at TIMESTAMPTZ
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_ai_usage_user_created ON ai_usage (user_id, created_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_ai_usage_role ON ai_usage (role)`,
+			`CREATE INDEX IF NOT EXISTS idx_ai_usage_conversation_id ON ai_usage (conversation_id)`,
+		},
+		// The uuid-ossp extension is kept, as other schemas may use it
+		down: []string{
+			`DROP TABLE IF EXISTS ai_usage`,
+			`DROP TABLE IF EXISTS ai_messages`,
+			`DROP TABLE IF EXISTS ai_conversations`,
+			`DROP TABLE IF EXISTS wallet_alerts`,
+			`DROP TABLE IF EXISTS watched_wallet_tag_associations`,
+			`DROP TABLE IF EXISTS watched_wallet_tags`,
+			`DROP TABLE IF EXISTS watched_wallets`,
+			`DROP TABLE IF EXISTS user_sessions`,
+			`DROP TABLE IF EXISTS users`,
+		},
+	},
+	{
+		// The referential integrity, triggers and default tags of the SQL
+		// schema that preceded the GORM models. Foreign keys GORM created
+		// without ON DELETE CASCADE are replaced.
+		version: 2,
+		name:    "foreign keys, updated_at triggers and default tags",
+		up: append(append([]string{
+			`ALTER TABLE watched_wallets DROP CONSTRAINT IF EXISTS fk_users_watched_wallets`,
+			`ALTER TABLE wallet_alerts DROP CONSTRAINT IF EXISTS fk_watched_wallets_alert_history`,
+			`ALTER TABLE wallet_alerts DROP CONSTRAINT IF EXISTS fk_wallet_alerts_watched_wallet`,
+			`ALTER TABLE user_sessions ADD CONSTRAINT fk_user_sessions_user_id
+				FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE`,
+			`ALTER TABLE watched_wallets ADD CONSTRAINT fk_watched_wallets_user_id
+				FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE`,
+			`ALTER TABLE watched_wallet_tag_associations ADD CONSTRAINT fk_watched_wallet_tag_associations_watched_wallet_id
+				FOREIGN KEY (watched_wallet_id) REFERENCES watched_wallets (id) ON DELETE CASCADE`,
+			`ALTER TABLE watched_wallet_tag_associations ADD CONSTRAINT fk_watched_wallet_tag_associations_tag_id
+				FOREIGN KEY (tag_id) REFERENCES watched_wallet_tags (id) ON DELETE CASCADE`,
+			`ALTER TABLE wallet_alerts ADD CONSTRAINT fk_wallet_alerts_wallet_id
+				FOREIGN KEY (wallet_id) REFERENCES watched_wallets (id) ON DELETE CASCADE`,
+			`ALTER TABLE wallet_alerts ADD CONSTRAINT fk_wallet_alerts_acknowledged_by
+				FOREIGN KEY (acknowledged_by) REFERENCES users (id)`,
+			`CREATE OR REPLACE FUNCTION update_updated_at_column()
+			RETURNS TRIGGER AS $$
+			BEGIN
+				NEW.updated_at = CURRENT_TIMESTAMP;
+				RETURN NEW;
+			END;
+			$$ LANGUAGE plpgsql`,
+		}, updatedAtTriggers("CREATE")...),
+			`ALTER TABLE watched_wallet_tags ADD COLUMN IF NOT EXISTS description TEXT`,
+			`INSERT INTO watched_wallet_tags (name, color, description, created_at, updated_at) VALUES
+				('High Risk', '#EF4444', 'Wallets with high risk scores', NOW(), NOW()),
+				('Exchange', '#3B82F6', 'Exchange wallets', NOW(), NOW()),
+				('DeFi', '#10B981', 'DeFi protocol wallets', NOW(), NOW()),
+				('Whale', '#8B5CF6', 'High-value wallets', NOW(), NOW()),
+				('Suspicious', '#F59E0B', 'Potentially suspicious activity', NOW(), NOW()),
+				('Whitelist', '#06B6D4', 'Trusted wallets', NOW(), NOW()),
+				('Bridge', '#EC4899', 'Cross-chain bridge wallets', NOW(), NOW()),
+				('Contract', '#6B7280', 'Smart contract addresses', NOW(), NOW()),
+				('Miner', '#F97316', 'Mining pool wallets', NOW(), NOW()),
+				('Personal', '#84CC16', 'Personal wallet addresses', NOW(), NOW())
+			ON CONFLICT (name) DO NOTHING`,
+			`COMMENT ON TABLE users IS 'User accounts and authentication'`,
+			`COMMENT ON TABLE user_sessions IS 'User session management'`,
+			`COMMENT ON TABLE watched_wallets IS 'User-watched wallet addresses'`,
+			`COMMENT ON TABLE watched_wallet_tags IS 'Tags for categorizing watched wallets'`,
+			`COMMENT ON TABLE watched_wallet_tag_associations IS 'Many-to-many relationship between wallets and tags'`,
+			`COMMENT ON TABLE wallet_alerts IS 'Alerts generated for watched wallets'`,
+			`COMMENT ON COLUMN users.role IS 'User role: USER, ADMIN, MODERATOR, ANALYST'`,
+		),
+		// The default tags are kept, as wallets may have been tagged with them
+		down: append(updatedAtTriggers("DROP"),
+			`DROP FUNCTION IF EXISTS update_updated_at_column()`,
+			`ALTER TABLE watched_wallet_tags DROP COLUMN IF EXISTS description`,
+			`ALTER TABLE wallet_alerts DROP CONSTRAINT IF EXISTS fk_wallet_alerts_acknowledged_by`,
+			`ALTER TABLE wallet_alerts DROP CONSTRAINT IF EXISTS fk_wallet_alerts_wallet_id`,
+			`ALTER TABLE watched_wallet_tag_associations DROP CONSTRAINT IF EXISTS fk_watched_wallet_tag_associations_tag_id`,
+			`ALTER TABLE watched_wallet_tag_associations DROP CONSTRAINT IF EXISTS fk_watched_wallet_tag_associations_watched_wallet_id`,
+			`ALTER TABLE watched_wallets DROP CONSTRAINT IF EXISTS fk_watched_wallets_user_id`,
+			`ALTER TABLE user_sessions DROP CONSTRAINT IF EXISTS fk_user_sessions_user_id`,
+		),
+	},
+}
+
+// updatedAtTriggers returns the statements creating or dropping the triggers
+// maintaining updated_at. Existing triggers are dropped before they are
+// created, so that a database that already has them is migrated.
+func updatedAtTriggers(action string) []string {
+	var statements []string
+	for _, table := range updatedAtTables {
+		statements = append(statements, fmt.Sprintf(`DROP TRIGGER IF EXISTS update_%s_updated_at ON %s`, table, table))
+		if action == "CREATE" {
+			statements = append(statements, fmt.Sprintf(
+				`CREATE TRIGGER update_%s_updated_at BEFORE UPDATE ON %s FOR EACH ROW EXECUTE FUNCTION update_updated_at_column()`,
+				table, table))
+		}
+	}
+	return statements
+}
+
+// PostgreSQLStore migrates PostgreSQL. Applied migrations are recorded in the
+// schema_migrations table and migrations are serialized by an advisory lock.
+type PostgreSQLStore struct {
+	db     *gorm.DB
+	logger *zap.Logger
+
+	// conn is the session holding the advisory lock, which is released when
+	// the session ends
+	conn *sql.Conn
+}
+
+// NewPostgreSQLStore creates a migration store for PostgreSQL
+func NewPostgreSQLStore(client *database.PostgreSQLClient, logger *zap.Logger) *PostgreSQLStore {
+	return &PostgreSQLStore{
+		db:     client.GetDB(),
+		logger: logger,
+	}
+}
+
+// Name returns the name of the store
+func (s *PostgreSQLStore) Name() string {
+	return "postgresql"
+}
+
+// Migrations returns the PostgreSQL migrations
+func (s *PostgreSQLStore) Migrations() []Migration {
+	migrations := make([]Migration, 0, len(postgresMigrations))
+	for _, migration := range postgresMigrations {
+		migrations = append(migrations, Migration{
+			Version:  migration.version,
+			Name:     migration.name,
+			Checksum: migration.checksum(),
+		})
+	}
+	return migrations
+}
+
+// Prepare creates the schema_migrations table
+func (s *PostgreSQLStore) Prepare(ctx context.Context) error {
+	return s.db.WithContext(ctx).Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		checksum TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`).Error
+}
+
+// Lock acquires the advisory lock on a dedicated session. The lock is held
+// until Unlock or until the session is lost, so it needs no renewal.
+func (s *PostgreSQLStore) Lock(ctx context.Context, owner string) (bool, error) {
+	if s.conn != nil {
+		return true, nil
+	}
+
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", postgresLockKey).Scan(&acquired); err != nil {
+		conn.Close()
+		return false, err
+	}
+	if !acquired {
+		conn.Close()
+		return false, nil
+	}
+
+	s.conn = conn
+	return true, nil
+}
+
+// Unlock releases the advisory lock
+func (s *PostgreSQLStore) Unlock(ctx context.Context, owner string) error {
+	if s.conn == nil {
+		return nil
+	}
+	defer func() {
+		s.conn.Close()
+		s.conn = nil
+	}()
+
+	_, err := s.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", postgresLockKey)
+	return err
+}
+
+// Applied returns the migrations recorded in schema_migrations
+func (s *PostgreSQLStore) Applied(ctx context.Context) ([]AppliedMigration, error) {
+	var applied []AppliedMigration
+	err := s.db.WithContext(ctx).
+		Raw("SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version").
+		Scan(&applied).Error
+	if err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// Up applies a migration and records it in the same transaction
+func (s *PostgreSQLStore) Up(ctx context.Context, version int) error {
+	migration, err := findPostgresMigration(version)
+	if err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := execStatements(tx, migration.up); err != nil {
+			return err
+		}
+		return tx.Exec("INSERT INTO schema_migrations (version, name, checksum) VALUES (?, ?, ?)",
+			migration.version, migration.name, migration.checksum()).Error
+	})
+}
+
+// Down reverts a migration and removes its record in the same transaction
+func (s *PostgreSQLStore) Down(ctx context.Context, version int) error {
+	migration, err := findPostgresMigration(version)
+	if err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := execStatements(tx, migration.down); err != nil {
+			return err
+		}
+		return tx.Exec("DELETE FROM schema_migrations WHERE version = ?", migration.version).Error
+	})
+}
+
+// execStatements runs statements in order
+func execStatements(tx *gorm.DB, statements []string) error {
+	for _, statement := range statements {
+		if err := tx.Exec(statement).Error; err != nil {
+			return fmt.Errorf("failed to run %q: %w", statement, err)
+		}
+	}
+	return nil
+}
+
+// findPostgresMigration returns the PostgreSQL migration with version
+func findPostgresMigration(version int) (postgresMigration, error) {
+	for _, migration := range postgresMigrations {
+		if migration.version == version {
+			return migration, nil
+		}
+	}
+	return postgresMigration{}, fmt.Errorf("unknown migration %d", version)
+}
@@ -3,6 +3,10 @@ package monitoring
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,6 +25,10 @@ const (
 	MetricTypeSummary   MetricType = "summary"
 )
 
+// DefaultBuckets are the histogram bucket upper bounds used for durations in
+// seconds, from 5ms to 10s
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
 // Metric represents a single metric
 type Metric struct {
 	Name        string                 `json:"name"`
@@ -31,6 +39,19 @@ type Metric struct {
 	Description string                 `json:"description,omitempty"`
 	Unit        string                 `json:"unit,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+
+	// Histogram observations. Value holds their average; Buckets are
+	// cumulative, as Prometheus exposes them, without the +Inf bucket which
+	// always equals Count.
+	Count   uint64   `json:"count,omitempty"`
+	Sum     float64  `json:"sum,omitempty"`
+	Buckets []Bucket `json:"buckets,omitempty"`
+}
+
+// Bucket counts the histogram observations less than or equal to UpperBound
+type Bucket struct {
+	UpperBound float64 `json:"le"`
+	Count      uint64  `json:"count"`
 }
 
 // MetricsCollector collects and manages application metrics
@@ -69,6 +90,27 @@ func (mc *MetricsCollector) Counter(name string, labels map[string]string, descr
 	}
 }
 
+// Add increases a counter metric by value
+func (mc *MetricsCollector) Add(name string, value float64, labels map[string]string, description string) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	key := mc.buildKey(name, labels)
+	if metric, exists := mc.metrics[key]; exists {
+		metric.Value += value
+		metric.Timestamp = time.Now()
+	} else {
+		mc.metrics[key] = &Metric{
+			Name:        name,
+			Type:        MetricTypeCounter,
+			Value:       value,
+			Labels:      labels,
+			Timestamp:   time.Now(),
+			Description: description,
+		}
+	}
+}
+
 // Gauge sets a gauge metric value
 func (mc *MetricsCollector) Gauge(name string, value float64, labels map[string]string, description string) {
 	mc.mu.Lock()
@@ -85,37 +127,40 @@ func (mc *MetricsCollector) Gauge(name string, value float64, labels map[string]
 	}
 }
 
-// Histogram records a histogram metric
+// Histogram records an observation in a histogram metric with DefaultBuckets
 func (mc *MetricsCollector) Histogram(name string, value float64, labels map[string]string, description string) {
+	mc.HistogramWithBuckets(name, value, DefaultBuckets, labels, description)
+}
+
+// HistogramWithBuckets records an observation in a histogram metric. The
+// bucket upper bounds must be sorted and are fixed by the first observation.
+func (mc *MetricsCollector) HistogramWithBuckets(name string, value float64, buckets []float64, labels map[string]string, description string) {
 	mc.mu.Lock()
 	defer mc.mu.Unlock()
 
 	key := mc.buildKey(name, labels)
-	if metric, exists := mc.metrics[key]; exists {
-		// Simple histogram implementation - in production, use proper buckets
-		if metric.Metadata == nil {
-			metric.Metadata = make(map[string]interface{})
-		}
-
-		count, _ := metric.Metadata["count"].(float64)
-		sum, _ := metric.Metadata["sum"].(float64)
-
-		metric.Metadata["count"] = count + 1
-		metric.Metadata["sum"] = sum + value
-		metric.Value = (sum + value) / (count + 1) // Average
-		metric.Timestamp = time.Now()
-	} else {
-		mc.metrics[key] = &Metric{
+	metric, exists := mc.metrics[key]
+	if !exists {
+		metric = &Metric{
 			Name:        name,
 			Type:        MetricTypeHistogram,
-			Value:       value,
 			Labels:      labels,
-			Timestamp:   time.Now(),
 			Description: description,
-			Metadata: map[string]interface{}{
-				"count": 1.0,
-				"sum":   value,
-			},
+			Buckets:     make([]Bucket, len(buckets)),
+		}
+		for i, upperBound := range buckets {
+			metric.Buckets[i].UpperBound = upperBound
+		}
+		mc.metrics[key] = metric
+	}
+
+	metric.Count++
+	metric.Sum += value
+	metric.Value = metric.Sum / float64(metric.Count)
+	metric.Timestamp = time.Now()
+	for i := range metric.Buckets {
+		if value <= metric.Buckets[i].UpperBound {
+			metric.Buckets[i].Count++
 		}
 	}
 }
@@ -128,8 +173,7 @@ func (mc *MetricsCollector) GetMetrics() map[string]*Metric {
 	// Return a copy to avoid race conditions
 	result := make(map[string]*Metric)
 	for k, v := range mc.metrics {
-		metricCopy := *v
-		result[k] = &metricCopy
+		result[k] = copyMetric(v)
 	}
 	return result
 }
@@ -142,12 +186,20 @@ func (mc *MetricsCollector) GetMetric(name string, labels map[string]string) (*M
 	key := mc.buildKey(name, labels)
 	metric, exists := mc.metrics[key]
 	if exists {
-		metricCopy := *metric
-		return &metricCopy, true
+		return copyMetric(metric), true
 	}
 	return nil, false
 }
 
+// copyMetric copies a metric so that it can be read without the lock
+func copyMetric(metric *Metric) *Metric {
+	metricCopy := *metric
+	if metric.Buckets != nil {
+		metricCopy.Buckets = append([]Bucket(nil), metric.Buckets...)
+	}
+	return &metricCopy
+}
+
 // Reset clears all metrics
 func (mc *MetricsCollector) Reset() {
 	mc.mu.Lock()
@@ -157,11 +209,16 @@ func (mc *MetricsCollector) Reset() {
 
 // buildKey creates a unique key for a metric
 func (mc *MetricsCollector) buildKey(name string, labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	// Sort so the same labels always map to the same metric
+	sort.Strings(names)
+
 	key := name
-	if labels != nil {
-		for k, v := range labels {
-			key += ":" + k + "=" + v
-		}
+	for _, k := range names {
+		key += ":" + k + "=" + labels[k]
 	}
 	return key
 }
@@ -180,20 +237,25 @@ func NewPerformanceMonitor(collector *MetricsCollector, logger *zap.Logger) *Per
 	}
 }
 
-// TrackDuration tracks the duration of an operation
+// TrackDuration tracks the duration of an operation; call the returned
+// function when the operation completes
 func (pm *PerformanceMonitor) TrackDuration(operation string, labels map[string]string) func() {
 	start := time.Now()
 	return func() {
-		duration := time.Since(start)
-		pm.collector.Histogram(
-			"operation_duration_seconds",
-			duration.Seconds(),
-			mergeLabels(labels, map[string]string{"operation": operation}),
-			"Duration of operations in seconds",
-		)
+		pm.ObserveDuration(operation, time.Since(start), labels)
 	}
 }
 
+// ObserveDuration records the duration of a completed operation
+func (pm *PerformanceMonitor) ObserveDuration(operation string, duration time.Duration, labels map[string]string) {
+	pm.collector.Histogram(
+		"operation_duration_seconds",
+		duration.Seconds(),
+		mergeLabels(labels, map[string]string{"operation": operation}),
+		"Duration of operations in seconds",
+	)
+}
+
 // TrackError tracks error occurrences
 func (pm *PerformanceMonitor) TrackError(err error, operation string, labels map[string]string) {
 	if err == nil {
@@ -267,7 +329,7 @@ func (pm *PerformanceMonitor) TrackAPICall(service, endpoint string, statusCode
 	labels := map[string]string{
 		"service":     service,
 		"endpoint":    endpoint,
-		"status_code": string(rune(statusCode)),
+		"status_code": strconv.Itoa(statusCode),
 	}
 
 	// Track duration
@@ -311,6 +373,109 @@ func (pm *PerformanceMonitor) TrackCacheOperation(operation string, hit bool, du
 		labels,
 		"Total number of cache operations",
 	)
+
+	var hits, total float64
+	for _, outcome := range []string{"true", "false"} {
+		if metric, ok := pm.collector.GetMetric("cache_operations_total", map[string]string{"operation": operation, "hit": outcome}); ok {
+			total += metric.Value
+			if outcome == "true" {
+				hits = metric.Value
+			}
+		}
+	}
+	pm.collector.Gauge(
+		"cache_hit_ratio",
+		hits/total,
+		map[string]string{"operation": operation},
+		"Fraction of cache operations that were hits since startup",
+	)
+}
+
+// TrackGraphQLOperation tracks the latency of a GraphQL query or mutation.
+// Operations are labelled by their first root field rather than the
+// client-chosen operation name, which would be unbounded.
+func (pm *PerformanceMonitor) TrackGraphQLOperation(operationType, rootField string, duration time.Duration, errorCount int) {
+	labels := map[string]string{
+		"type":       operationType,
+		"root_field": rootField,
+	}
+
+	pm.collector.Histogram(
+		"graphql_operation_duration_seconds",
+		duration.Seconds(),
+		labels,
+		"Duration of GraphQL operations in seconds",
+	)
+
+	if errorCount > 0 {
+		pm.collector.Counter(
+			"graphql_operation_errors_total",
+			labels,
+			"Total number of GraphQL operations that returned errors",
+		)
+	}
+}
+
+// TrackGraphQLField tracks the latency of a GraphQL field resolver
+func (pm *PerformanceMonitor) TrackGraphQLField(object, field string, duration time.Duration, err error) {
+	labels := map[string]string{
+		"object": object,
+		"field":  field,
+	}
+
+	pm.collector.Histogram(
+		"graphql_field_duration_seconds",
+		duration.Seconds(),
+		labels,
+		"Duration of GraphQL field resolvers in seconds",
+	)
+
+	if err != nil {
+		pm.collector.Counter(
+			"graphql_field_errors_total",
+			labels,
+			"Total number of GraphQL field resolver errors",
+		)
+	}
+}
+
+// TrackCircuitBreakerState records the state of a dependency's circuit
+// breaker: 0 when closed, 1 when half-open and 2 when open
+func (pm *PerformanceMonitor) TrackCircuitBreakerState(dependency string, value float64) {
+	pm.collector.Gauge(
+		"circuit_breaker_state",
+		value,
+		map[string]string{"dependency": dependency},
+		"Circuit breaker state per dependency (0 closed, 1 half-open, 2 open)",
+	)
+}
+
+// TrackCircuitBreakerTransition records a dependency's circuit breaker
+// moving to state
+func (pm *PerformanceMonitor) TrackCircuitBreakerTransition(dependency, state string) {
+	pm.collector.Counter(
+		"circuit_breaker_transitions_total",
+		map[string]string{"dependency": dependency, "to": state},
+		"Total number of circuit breaker state transitions",
+	)
+}
+
+// TrackCircuitBreakerRejection records a call refused by an open breaker
+func (pm *PerformanceMonitor) TrackCircuitBreakerRejection(dependency string) {
+	pm.collector.Counter(
+		"circuit_breaker_rejections_total",
+		map[string]string{"dependency": dependency},
+		"Total number of calls rejected by an open circuit breaker",
+	)
+}
+
+// TrackRetry records a failed call to dependency being retried
+func (pm *PerformanceMonitor) TrackRetry(dependency string) {
+	pm.collector.Counter(
+		"dependency_retries_total",
+		map[string]string{"dependency": dependency},
+		"Total number of retried dependency calls",
+	)
 }
 
 // SystemMetrics represents system-level metrics
@@ -375,9 +540,10 @@ func (sm *SystemMetrics) TrackWalletAnalysis(networkID string, walletType string
 		"Total wallet analyses performed",
 	)
 
-	sm.collector.Histogram(
+	sm.collector.HistogramWithBuckets(
 		"wallet_risk_score",
 		riskScore,
+		[]float64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100},
 		labels,
 		"Distribution of wallet risk scores",
 	)
@@ -408,40 +574,109 @@ func NewMetricsExporter(collector *MetricsCollector, logger *zap.Logger) *Metric
 	}
 }
 
-// ExportPrometheus exports metrics in Prometheus format
+// ExportPrometheus exports metrics in the Prometheus text exposition format.
+// Each metric family is written once with its series sorted by labels, so the
+// output is stable between scrapes.
 func (me *MetricsExporter) ExportPrometheus() string {
-	metrics := me.collector.GetMetrics()
-	var output string
+	families := make(map[string][]*Metric)
+	for _, metric := range me.collector.GetMetrics() {
+		families[metric.Name] = append(families[metric.Name], metric)
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-	for _, metric := range metrics {
-		// Add help comment
-		if metric.Description != "" {
-			output += "# HELP " + metric.Name + " " + metric.Description + "\n"
+	var b strings.Builder
+	for _, name := range names {
+		series := families[name]
+		sort.Slice(series, func(i, j int) bool {
+			return formatLabels(series[i].Labels, "", "") < formatLabels(series[j].Labels, "", "")
+		})
+
+		first := series[0]
+		if first.Description != "" {
+			fmt.Fprintf(&b, "# HELP %s %s\n", name, escapeHelp(first.Description))
 		}
+		fmt.Fprintf(&b, "# TYPE %s %s\n", name, first.Type)
+
+		for _, metric := range series {
+			if metric.Type != MetricTypeHistogram {
+				fmt.Fprintf(&b, "%s%s %s\n", name, formatLabels(metric.Labels, "", ""), formatValue(metric.Value))
+				continue
+			}
 
-		// Add type comment
-		output += "# TYPE " + metric.Name + " " + string(metric.Type) + "\n"
-
-		// Add metric line
-		metricLine := metric.Name
-		if len(metric.Labels) > 0 {
-			metricLine += "{"
-			first := true
-			for k, v := range metric.Labels {
-				if !first {
-					metricLine += ","
-				}
-				metricLine += k + `="` + v + `"`
-				first = false
+			for _, bucket := range metric.Buckets {
+				fmt.Fprintf(&b, "%s_bucket%s %d\n", name,
+					formatLabels(metric.Labels, "le", formatValue(bucket.UpperBound)), bucket.Count)
 			}
-			metricLine += "}"
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", name, formatLabels(metric.Labels, "le", "+Inf"), metric.Count)
+			fmt.Fprintf(&b, "%s_sum%s %s\n", name, formatLabels(metric.Labels, "", ""), formatValue(metric.Sum))
+			fmt.Fprintf(&b, "%s_count%s %d\n", name, formatLabels(metric.Labels, "", ""), metric.Count)
 		}
-		metricLine += " " + fmt.Sprintf("%.6f", metric.Value)
-		metricLine += " " + fmt.Sprintf("%d", metric.Timestamp.UnixMilli())
-		output += metricLine + "\n"
 	}
 
-	return output
+	return b.String()
+}
+
+// formatLabels writes labels sorted by name, followed by the extra label when
+// one is given, e.g. the "le" label of a histogram bucket
+func formatLabels(labels map[string]string, extraName, extraValue string) string {
+	if len(labels) == 0 && extraName == "" {
+		return ""
+	}
+
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, k := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `%s="%s"`, k, escapeLabelValue(labels[k]))
+	}
+	if extraName != "" {
+		if len(names) > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `%s="%s"`, extraName, escapeLabelValue(extraValue))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var (
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+// formatValue formats a sample value, spelling infinities and NaN the way
+// Prometheus expects
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
 }
 
 // ExportJSON exports metrics in JSON format
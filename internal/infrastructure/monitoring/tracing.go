@@ -0,0 +1,99 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"crypto-bubble-map-be/internal/infrastructure/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// TracerName names the tracer the application's own spans are created with
+const TracerName = "crypto-bubble-map-be"
+
+// Tracer returns the application tracer. Spans it creates are dropped unless
+// tracing has been enabled with NewTracing.
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// Tracing owns the global OpenTelemetry tracer provider
+type Tracing struct {
+	provider *sdktrace.TracerProvider
+	logger   *zap.Logger
+}
+
+// NewTracing installs the global W3C trace context propagator and, when
+// tracing is enabled, a tracer provider exporting spans over OTLP/HTTP. With
+// tracing disabled every span is a no-op.
+func NewTracing(cfg *config.MonitoringConfig, environment string, logger *zap.Logger) (*Tracing, error) {
+	// Incoming trace context is propagated either way, so that a disabled
+	// replica does not break traces that pass through it
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	t := &Tracing{logger: logger}
+	if !cfg.EnableTracing {
+		return t, nil
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(cfg.OTLPEndpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.TracingServiceName),
+		semconv.DeploymentEnvironment(environment),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	t.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TracingSampleRatio))),
+	)
+	otel.SetTracerProvider(t.provider)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warn("OpenTelemetry error", zap.Error(err))
+	}))
+
+	logger.Info("Tracing enabled",
+		zap.String("endpoint", cfg.OTLPEndpoint),
+		zap.String("service", cfg.TracingServiceName),
+		zap.Float64("sampleRatio", cfg.TracingSampleRatio))
+	return t, nil
+}
+
+// Enabled reports whether spans are being recorded and exported
+func (t *Tracing) Enabled() bool {
+	return t.provider != nil
+}
+
+// Shutdown flushes buffered spans and stops the exporter
+func (t *Tracing) Shutdown(ctx context.Context) error {
+	if t.provider == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := t.provider.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to shut down tracer provider: %w", err)
+	}
+	return nil
+}
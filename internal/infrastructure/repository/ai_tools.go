@@ -0,0 +1,417 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+)
+
+// Limits on how much data a single tool call can put into the model context
+const (
+	aiToolDefaultLimit     = 10
+	aiToolMaxTransactions  = 50
+	aiToolMaxAlerts        = 25
+	aiToolMaxFlowAccounts  = 25
+	aiToolMaxFlowTransfers = 20
+)
+
+// Tool describes a function the model may call
+type Tool struct {
+	Type     string       `json:"type"`
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction is the name, purpose and JSON schema of a callable function
+type ToolFunction struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Parameters  map[string]interface{} `json:"parameters"`
+}
+
+// ToolCall is a function call requested by the model
+type ToolCall struct {
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction holds the called function name and its JSON-encoded arguments
+type ToolCallFunction struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// aiToolResult is the outcome of one tool call: the JSON handed back to the model
+// and the records it was built from
+type aiToolResult struct {
+	Content string
+	Sources []string
+	Failed  bool
+}
+
+// aiTools runs the assistant's function calls against our own repositories so
+// answers are grounded in stored data rather than the model's memory
+type aiTools struct {
+	walletRepo      repository.WalletRepository
+	transactionRepo repository.TransactionRepository
+	securityRepo    repository.SecurityRepository
+}
+
+func addressParam(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "description": description}
+}
+
+// definitions returns the tools offered to the model
+func (t *aiTools) definitions() []Tool {
+	return []Tool{
+		{Type: "function", Function: ToolFunction{
+			Name:        "get_wallet",
+			Description: "Look up a wallet's profile: type, label, tags, balance, transaction count, first/last seen and associated exchanges and protocols.",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"address": addressParam("Wallet address (0x-prefixed)"),
+				},
+				"required": []string{"address"},
+			},
+		}},
+		{Type: "function", Function: ToolFunction{
+			Name:        "get_risk_score",
+			Description: "Get a wallet's risk score (0-100), risk level, per-category risk factors and flags.",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"address": addressParam("Wallet address (0x-prefixed)"),
+				},
+				"required": []string{"address"},
+			},
+		}},
+		{Type: "function", Function: ToolFunction{
+			Name:        "get_pairwise_transactions",
+			Description: "List the most recent transactions between two wallets with a summary of their volume.",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"wallet_a": addressParam("First wallet address"),
+					"wallet_b": addressParam("Second wallet address"),
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": fmt.Sprintf("Maximum transactions to return (default %d, max %d)", aiToolDefaultLimit, aiToolMaxTransactions),
+					},
+				},
+				"required": []string{"wallet_a", "wallet_b"},
+			},
+		}},
+		{Type: "function", Function: ToolFunction{
+			Name:        "get_money_flow",
+			Description: "Summarize where a wallet's funds come from and go to: top inbound and outbound counterparties, totals and recent transfers.",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"address": addressParam("Wallet address (0x-prefixed)"),
+					"flow_type": map[string]interface{}{
+						"type": "string",
+						"enum": []string{string(entity.MoneyFlowTypeInbound), string(entity.MoneyFlowTypeOutbound), string(entity.MoneyFlowTypeBoth)},
+					},
+					"top_n": map[string]interface{}{
+						"type":        "integer",
+						"description": fmt.Sprintf("Counterparties to return per direction (default %d, max %d)", aiToolDefaultLimit, aiToolMaxFlowAccounts),
+					},
+				},
+				"required": []string{"address"},
+			},
+		}},
+		{Type: "function", Function: ToolFunction{
+			Name:        "get_security_alerts",
+			Description: "List security alerts, optionally for one wallet and filtered by severity or status.",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"address": addressParam("Only alerts for this wallet"),
+					"severity": map[string]interface{}{
+						"type": "string",
+						"enum": []string{string(entity.AlertSeverityLow), string(entity.AlertSeverityMedium), string(entity.AlertSeverityHigh), string(entity.AlertSeverityCritical)},
+					},
+					"status": map[string]interface{}{
+						"type": "string",
+						"enum": []string{string(entity.AlertStatusActive), string(entity.AlertStatusInvestigating), string(entity.AlertStatusResolved), string(entity.AlertStatusFalsePositive)},
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": fmt.Sprintf("Maximum alerts to return (default %d, max %d)", aiToolDefaultLimit, aiToolMaxAlerts),
+					},
+				},
+			},
+		}},
+	}
+}
+
+// execute runs one tool call. Failures are reported to the model as an error
+// payload so it can recover instead of aborting the conversation.
+func (t *aiTools) execute(ctx context.Context, call ToolCall) aiToolResult {
+	var args struct {
+		Address  string `json:"address"`
+		WalletA  string `json:"wallet_a"`
+		WalletB  string `json:"wallet_b"`
+		FlowType string `json:"flow_type"`
+		TopN     int    `json:"top_n"`
+		Severity string `json:"severity"`
+		Status   string `json:"status"`
+		Limit    int    `json:"limit"`
+	}
+	if strings.TrimSpace(call.Function.Arguments) != "" {
+		if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+			return toolError(fmt.Errorf("invalid arguments: %w", err))
+		}
+	}
+
+	var (
+		payload interface{}
+		sources []string
+		err     error
+	)
+	switch call.Function.Name {
+	case "get_wallet":
+		payload, sources, err = t.getWallet(ctx, args.Address)
+	case "get_risk_score":
+		payload, sources, err = t.getRiskScore(ctx, args.Address)
+	case "get_pairwise_transactions":
+		payload, sources, err = t.getPairwiseTransactions(ctx, args.WalletA, args.WalletB, args.Limit)
+	case "get_money_flow":
+		payload, sources, err = t.getMoneyFlow(ctx, args.Address, args.FlowType, args.TopN)
+	case "get_security_alerts":
+		payload, sources, err = t.getSecurityAlerts(ctx, args.Address, args.Severity, args.Status, args.Limit)
+	default:
+		err = fmt.Errorf("unknown tool: %s", call.Function.Name)
+	}
+	if err != nil {
+		return toolError(err)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return toolError(fmt.Errorf("failed to encode result: %w", err))
+	}
+	return aiToolResult{Content: string(data), Sources: sources}
+}
+
+func (t *aiTools) getWallet(ctx context.Context, address string) (interface{}, []string, error) {
+	address, err := toolAddress("address", address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wallet, err := t.walletRepo.GetWallet(ctx, address)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get wallet: %w", err)
+	}
+	if wallet == nil {
+		return map[string]interface{}{"address": address, "found": false}, nil, nil
+	}
+
+	return map[string]interface{}{
+		"found":                true,
+		"address":              wallet.Address,
+		"label":                wallet.Label,
+		"wallet_type":          wallet.WalletType,
+		"tags":                 wallet.Tags,
+		"balance":              wallet.Balance,
+		"transaction_count":    wallet.TransactionCount,
+		"total_sent":           wallet.TotalSent,
+		"total_received":       wallet.TotalReceived,
+		"first_seen":           formatToolTime(wallet.FirstSeen),
+		"last_seen":            formatToolTime(wallet.LastSeen),
+		"is_contract":          wallet.IsContract,
+		"associated_exchanges": wallet.AssociatedExchanges,
+		"associated_protocols": wallet.AssociatedProtocols,
+		"risk_level":           wallet.RiskLevel,
+	}, []string{fmt.Sprintf("wallet:%s", wallet.Address)}, nil
+}
+
+func (t *aiTools) getRiskScore(ctx context.Context, address string) (interface{}, []string, error) {
+	address, err := toolAddress("address", address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	score, err := t.walletRepo.GetRiskScore(ctx, address)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get risk score: %w", err)
+	}
+	if score == nil {
+		return map[string]interface{}{"address": address, "found": false}, nil, nil
+	}
+
+	return map[string]interface{}{
+		"found":        true,
+		"address":      score.Address,
+		"total_score":  score.TotalScore,
+		"risk_level":   score.RiskLevel,
+		"factors":      score.Factors,
+		"flags":        score.Flags,
+		"last_updated": formatToolTime(score.LastUpdated),
+	}, []string{fmt.Sprintf("risk_score:%s@%s", score.Address, formatToolTime(score.LastUpdated))}, nil
+}
+
+func (t *aiTools) getPairwiseTransactions(ctx context.Context, walletA, walletB string, limit int) (interface{}, []string, error) {
+	walletA, err := toolAddress("wallet_a", walletA)
+	if err != nil {
+		return nil, nil, err
+	}
+	walletB, err = toolAddress("wallet_b", walletB)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := t.transactionRepo.GetPairwiseTransactions(ctx, walletA, walletB, int64(clampLimit(limit, aiToolMaxTransactions)), 0, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	transactions := make([]map[string]interface{}, 0, len(result.Transactions))
+	sources := make([]string, 0, len(result.Transactions))
+	for _, tx := range result.Transactions {
+		transactions = append(transactions, map[string]interface{}{
+			"hash":         tx.Hash,
+			"from":         tx.From,
+			"to":           tx.To,
+			"value":        tx.Value,
+			"timestamp":    formatToolTime(tx.Timestamp),
+			"block_number": tx.BlockNumber,
+		})
+		sources = append(sources, fmt.Sprintf("transaction:%s", tx.Hash))
+	}
+
+	return map[string]interface{}{
+		"summary":      result.Summary,
+		"total_count":  result.TotalCount,
+		"transactions": transactions,
+	}, sources, nil
+}
+
+func (t *aiTools) getMoneyFlow(ctx context.Context, address, flowType string, topN int) (interface{}, []string, error) {
+	address, err := toolAddress("address", address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filters := &entity.MoneyFlowFilters{
+		FlowType:     entity.MoneyFlowTypeBoth,
+		TransferType: entity.TransferTypeBoth,
+		TopN:         clampLimit(topN, aiToolMaxFlowAccounts),
+	}
+	switch entity.MoneyFlowType(strings.ToUpper(flowType)) {
+	case entity.MoneyFlowTypeInbound:
+		filters.FlowType = entity.MoneyFlowTypeInbound
+	case entity.MoneyFlowTypeOutbound:
+		filters.FlowType = entity.MoneyFlowTypeOutbound
+	}
+
+	flow, err := t.transactionRepo.GetMoneyFlowData(ctx, address, filters)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get money flow: %w", err)
+	}
+
+	// The Sankey layout and full transfer list are for rendering; the model only
+	// needs the counterparties, totals and the latest transfers
+	transfers := flow.Transactions
+	if len(transfers) > aiToolMaxFlowTransfers {
+		transfers = transfers[:aiToolMaxFlowTransfers]
+	}
+	sources := []string{fmt.Sprintf("money_flow:%s", address)}
+	for _, tx := range transfers {
+		sources = append(sources, fmt.Sprintf("transaction:%s", tx.Hash))
+	}
+
+	return map[string]interface{}{
+		"center_account":    flow.CenterAccount,
+		"inbound_accounts":  flow.InboundAccounts,
+		"outbound_accounts": flow.OutboundAccounts,
+		"summary":           flow.Summary,
+		"recent_transfers":  transfers,
+	}, sources, nil
+}
+
+func (t *aiTools) getSecurityAlerts(ctx context.Context, address, severity, status string, limit int) (interface{}, []string, error) {
+	filters := &entity.SecurityAlertFilters{}
+	if address != "" {
+		normalized, err := toolAddress("address", address)
+		if err != nil {
+			return nil, nil, err
+		}
+		filters.WalletAddress = &normalized
+	}
+	if severity != "" {
+		s := entity.AlertSeverity(strings.ToUpper(severity))
+		filters.Severity = &s
+	}
+	if status != "" {
+		s := entity.AlertStatus(strings.ToUpper(status))
+		filters.Status = &s
+	}
+
+	result, err := t.securityRepo.GetSecurityAlerts(ctx, filters, clampLimit(limit, aiToolMaxAlerts), 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get security alerts: %w", err)
+	}
+
+	alerts := make([]map[string]interface{}, 0, len(result.Alerts))
+	sources := make([]string, 0, len(result.Alerts))
+	for _, alert := range result.Alerts {
+		alerts = append(alerts, map[string]interface{}{
+			"id":                   alert.ID,
+			"type":                 alert.Type,
+			"severity":             alert.Severity,
+			"status":               alert.Status,
+			"title":                alert.Title,
+			"description":          alert.Description,
+			"wallet_address":       alert.WalletAddress,
+			"confidence":           alert.Confidence,
+			"action_required":      alert.ActionRequired,
+			"related_transactions": alert.RelatedTransactions,
+			"timestamp":            formatToolTime(alert.Timestamp),
+		})
+		sources = append(sources, fmt.Sprintf("security_alert:%s", alert.ID))
+	}
+
+	return map[string]interface{}{
+		"total":    result.Total,
+		"has_more": result.HasMore,
+		"alerts":   alerts,
+	}, sources, nil
+}
+
+func toolError(err error) aiToolResult {
+	data, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return aiToolResult{Content: string(data), Failed: true}
+}
+
+func toolAddress(field, address string) (string, error) {
+	address = entity.NormalizeAddress(address)
+	if !entity.IsValidAddress(address) {
+		return "", fmt.Errorf("%s must be a valid wallet address", field)
+	}
+	return address, nil
+}
+
+func clampLimit(limit, max int) int {
+	if limit <= 0 {
+		return aiToolDefaultLimit
+	}
+	if limit > max {
+		return max
+	}
+	return limit
+}
+
+func formatToolTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
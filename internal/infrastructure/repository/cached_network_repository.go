@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/cache"
+	"crypto-bubble-map-be/internal/infrastructure/config"
+
+	"go.uber.org/zap"
+)
+
+// CachedNetworkRepository decorates a NetworkRepository with cache-aside reads.
+// Network statistics aggregate every wallet, so they expire by TTL rather than
+// being invalidated per address. While the datastores behind them are down,
+// the last values read are served marked stale.
+type CachedNetworkRepository struct {
+	repository.NetworkRepository
+	cache  *cache.Aside
+	ttl    *config.TTLConfig
+	logger *zap.Logger
+}
+
+// NewCachedNetworkRepository creates a caching network repository around inner
+func NewCachedNetworkRepository(inner repository.NetworkRepository, aside *cache.Aside, ttl *config.TTLConfig, logger *zap.Logger) repository.NetworkRepository {
+	return &CachedNetworkRepository{
+		NetworkRepository: inner,
+		cache:             aside,
+		ttl:               ttl,
+		logger:            logger,
+	}
+}
+
+// GetNetworks retrieves all networks. The list is cached under the networks
+// namespace like every other read, so that it can be flushed with it.
+func (r *CachedNetworkRepository) GetNetworks(ctx context.Context) ([]entity.NetworkInfo, error) {
+	var networks []entity.NetworkInfo
+	stale, err := r.cache.LoadOrStale(ctx, "networks:all", r.ttl.NetworkStats, r.ttl.NetworkStale, &networks, func(ctx context.Context) ([]string, error) {
+		var err error
+		networks, err = r.NetworkRepository.GetNetworks(ctx)
+		return nil, err
+	})
+	if stale {
+		for i := range networks {
+			networks[i].Stale = true
+		}
+	}
+	return networks, err
+}
+
+// GetNetwork retrieves a network
+func (r *CachedNetworkRepository) GetNetwork(ctx context.Context, networkID string) (*entity.NetworkInfo, error) {
+	var network *entity.NetworkInfo
+	stale, err := r.cache.LoadOrStale(ctx, "network:"+networkID, r.ttl.NetworkStats, r.ttl.NetworkStale, &network, func(ctx context.Context) ([]string, error) {
+		var err error
+		network, err = r.NetworkRepository.GetNetwork(ctx, networkID)
+		return nil, err
+	})
+	if stale && network != nil {
+		network.Stale = true
+	}
+	return network, err
+}
+
+// GetNetworkStats retrieves a network's statistics
+func (r *CachedNetworkRepository) GetNetworkStats(ctx context.Context, networkID string) (*entity.NetworkStats, error) {
+	var stats *entity.NetworkStats
+	stale, err := r.cache.LoadOrStale(ctx, "network_stats:"+networkID, r.ttl.NetworkStats, r.ttl.NetworkStale, &stats, func(ctx context.Context) ([]string, error) {
+		var err error
+		stats, err = r.NetworkRepository.GetNetworkStats(ctx, networkID)
+		return nil, err
+	})
+	if stale && stats != nil {
+		stats.Stale = true
+	}
+	return stats, err
+}
+
+// GetNetworkRankings retrieves network rankings
+func (r *CachedNetworkRepository) GetNetworkRankings(ctx context.Context, limit int) ([]entity.NetworkRanking, error) {
+	var rankings []entity.NetworkRanking
+	stale, err := r.cache.LoadOrStale(ctx, fmt.Sprintf("network_rankings:%d", limit), r.ttl.NetworkStats, r.ttl.NetworkStale, &rankings, func(ctx context.Context) ([]string, error) {
+		var err error
+		rankings, err = r.NetworkRepository.GetNetworkRankings(ctx, limit)
+		return nil, err
+	})
+	if stale {
+		for i := range rankings {
+			rankings[i].Network.Stale = true
+		}
+	}
+	return rankings, err
+}
+
+// GetDashboardStats retrieves dashboard statistics
+func (r *CachedNetworkRepository) GetDashboardStats(ctx context.Context, networkID *string) (*entity.DashboardStats, error) {
+	key := "dashboard_stats:all"
+	if networkID != nil {
+		key = "dashboard_stats:" + *networkID
+	}
+
+	var stats *entity.DashboardStats
+	stale, err := r.cache.LoadOrStale(ctx, key, r.ttl.DashboardStats, r.ttl.NetworkStale, &stats, func(ctx context.Context) ([]string, error) {
+		var err error
+		stats, err = r.NetworkRepository.GetDashboardStats(ctx, networkID)
+		return nil, err
+	})
+	if stale && stats != nil {
+		stats.Stale = true
+	}
+	return stats, err
+}
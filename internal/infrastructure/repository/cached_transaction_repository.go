@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/cache"
+	"crypto-bubble-map-be/internal/infrastructure/config"
+
+	"go.uber.org/zap"
+)
+
+// CachedTransactionRepository decorates a TransactionRepository with
+// cache-aside reads of the wallet views. Crawler and detector reads such as
+// GetTransactionsAfter need fresh data and go straight to the database.
+type CachedTransactionRepository struct {
+	repository.TransactionRepository
+	cache  *cache.Aside
+	ttl    *config.TTLConfig
+	logger *zap.Logger
+}
+
+// NewCachedTransactionRepository creates a caching transaction repository around inner
+func NewCachedTransactionRepository(inner repository.TransactionRepository, aside *cache.Aside, ttl *config.TTLConfig, logger *zap.Logger) repository.TransactionRepository {
+	return &CachedTransactionRepository{
+		TransactionRepository: inner,
+		cache:                 aside,
+		ttl:                   ttl,
+		logger:                logger,
+	}
+}
+
+// GetTransaction retrieves a transaction by hash
+func (r *CachedTransactionRepository) GetTransaction(ctx context.Context, hash string) (*entity.Transaction, error) {
+	var tx *entity.Transaction
+	err := r.cache.Load(ctx, "transaction:"+hash, r.ttl.TransactionData, &tx, func(ctx context.Context) ([]string, error) {
+		var err error
+		tx, err = r.TransactionRepository.GetTransaction(ctx, hash)
+		return nil, err
+	})
+	return tx, err
+}
+
+// GetTransactionsByWallet retrieves a page of a wallet's transactions
+func (r *CachedTransactionRepository) GetTransactionsByWallet(ctx context.Context, walletAddress string, limit, offset int64) ([]entity.Transaction, error) {
+	key := fmt.Sprintf("wallet_transactions:%s:%d:%d", walletAddress, limit, offset)
+
+	var transactions []entity.Transaction
+	err := r.cache.Load(ctx, key, r.ttl.TransactionData, &transactions, func(ctx context.Context) ([]string, error) {
+		var err error
+		transactions, err = r.TransactionRepository.GetTransactionsByWallet(ctx, walletAddress, limit, offset)
+		return []string{walletAddress}, err
+	})
+	return transactions, err
+}
+
+// GetPairwiseTransactions retrieves the transactions between two wallets
+func (r *CachedTransactionRepository) GetPairwiseTransactions(ctx context.Context, walletA, walletB string, limit, offset int64, filters *entity.TransactionFilters) (*entity.PairwiseTransactionResult, error) {
+	key := fmt.Sprintf("pairwise_transactions:%s:%s:%s", walletA, walletB, cacheKeyHash(limit, offset, filters))
+
+	var result *entity.PairwiseTransactionResult
+	err := r.cache.Load(ctx, key, r.ttl.TransactionData, &result, func(ctx context.Context) ([]string, error) {
+		var err error
+		result, err = r.TransactionRepository.GetPairwiseTransactions(ctx, walletA, walletB, limit, offset, filters)
+		return []string{walletA, walletB}, err
+	})
+	return result, err
+}
+
+// GetMoneyFlowData retrieves a wallet's money flow
+func (r *CachedTransactionRepository) GetMoneyFlowData(ctx context.Context, walletAddress string, filters *entity.MoneyFlowFilters) (*entity.MoneyFlowData, error) {
+	key := fmt.Sprintf("money_flow:%s:%s", walletAddress, cacheKeyHash(filters))
+
+	var flow *entity.MoneyFlowData
+	err := r.cache.Load(ctx, key, r.ttl.TransactionData, &flow, func(ctx context.Context) ([]string, error) {
+		var err error
+		flow, err = r.TransactionRepository.GetMoneyFlowData(ctx, walletAddress, filters)
+		return []string{walletAddress}, err
+	})
+	return flow, err
+}
@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/cache"
+	"crypto-bubble-map-be/internal/infrastructure/config"
+
+	"go.uber.org/zap"
+)
+
+// CachedWalletRepository decorates a WalletRepository with cache-aside reads.
+// Cached reads are tagged with every address they include, and writes through
+// the repository (graph updates, classification, attribution and risk
+// overrides) invalidate the reads that include the addresses they touch.
+type CachedWalletRepository struct {
+	repository.WalletRepository
+	cache  *cache.Aside
+	ttl    *config.TTLConfig
+	logger *zap.Logger
+}
+
+// NewCachedWalletRepository creates a caching wallet repository around inner
+func NewCachedWalletRepository(inner repository.WalletRepository, aside *cache.Aside, ttl *config.TTLConfig, logger *zap.Logger) repository.WalletRepository {
+	return &CachedWalletRepository{
+		WalletRepository: inner,
+		cache:            aside,
+		ttl:              ttl,
+		logger:           logger,
+	}
+}
+
+// GetWalletNetwork retrieves a wallet network, tagged with every wallet in it.
+// While Neo4j is unavailable the last cached network is served marked stale.
+func (r *CachedWalletRepository) GetWalletNetwork(ctx context.Context, input *entity.WalletNetworkInput) (*entity.WalletNetwork, error) {
+	address := input.Address
+	key := fmt.Sprintf("wallet_network:%s:%d:%s", address, input.Depth, cacheKeyHash(input))
+
+	var network *entity.WalletNetwork
+	stale, err := r.cache.LoadOrStale(ctx, key, r.ttl.WalletNetwork, r.ttl.NetworkStale, &network, func(ctx context.Context) ([]string, error) {
+		var err error
+		network, err = r.WalletRepository.GetWalletNetwork(ctx, input)
+		if err != nil || network == nil {
+			return []string{address}, err
+		}
+
+		addresses := []string{address}
+		for _, node := range network.Nodes {
+			addresses = append(addresses, node.Address)
+		}
+		return addresses, nil
+	})
+	if stale && network != nil {
+		network.Stale = true
+	}
+	return network, err
+}
+
+// GetWallet retrieves a wallet
+func (r *CachedWalletRepository) GetWallet(ctx context.Context, address string) (*entity.Wallet, error) {
+	var wallet *entity.Wallet
+	err := r.cache.Load(ctx, "wallet:"+address, r.ttl.WalletData, &wallet, func(ctx context.Context) ([]string, error) {
+		var err error
+		wallet, err = r.WalletRepository.GetWallet(ctx, address)
+		return []string{address}, err
+	})
+	return wallet, err
+}
+
+// GetWalletsByAddresses retrieves wallets, loading only the uncached ones
+func (r *CachedWalletRepository) GetWalletsByAddresses(ctx context.Context, addresses []string) ([]entity.Wallet, error) {
+	if r.ttl.WalletData <= 0 || len(addresses) == 0 {
+		return r.WalletRepository.GetWalletsByAddresses(ctx, addresses)
+	}
+
+	requested := distinctStrings(addresses...)
+	keys := make([]string, len(requested))
+	for i, address := range requested {
+		keys[i] = "wallet:" + address
+	}
+	cached := make([]*entity.Wallet, len(requested))
+	found := r.cache.GetEach(ctx, keys, func(i int) interface{} { return &cached[i] })
+
+	var missing []string
+	for i, address := range requested {
+		if !found[i] {
+			missing = append(missing, address)
+		}
+	}
+
+	loaded := map[string]*entity.Wallet{}
+	if len(missing) > 0 {
+		wallets, err := r.WalletRepository.GetWalletsByAddresses(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		for i := range wallets {
+			loaded[wallets[i].Address] = &wallets[i]
+		}
+		// Wallets that were not found are cached as nil like GetWallet does
+		for _, address := range missing {
+			r.cache.Store(ctx, "wallet:"+address, r.ttl.WalletData, loaded[address], address)
+		}
+	}
+
+	wallets := make([]entity.Wallet, 0, len(requested))
+	for i, address := range requested {
+		wallet := cached[i]
+		if !found[i] {
+			wallet = loaded[address]
+		}
+		if wallet != nil {
+			wallets = append(wallets, *wallet)
+		}
+	}
+	return wallets, nil
+}
+
+// GetWalletRankings retrieves wallet rankings, tagged with every ranked wallet
+func (r *CachedWalletRepository) GetWalletRankings(ctx context.Context, category entity.RankingCategory, networkID *string, limit, offset int) (*entity.WalletRankingResult, error) {
+	network := ""
+	if networkID != nil {
+		network = *networkID
+	}
+	key := fmt.Sprintf("wallet_rankings:%s:%s:%d:%d", category, network, limit, offset)
+
+	var rankings *entity.WalletRankingResult
+	err := r.cache.Load(ctx, key, r.ttl.WalletRankings, &rankings, func(ctx context.Context) ([]string, error) {
+		var err error
+		rankings, err = r.WalletRepository.GetWalletRankings(ctx, category, networkID, limit, offset)
+		if err != nil || rankings == nil {
+			return nil, err
+		}
+
+		addresses := make([]string, 0, len(rankings.Rankings))
+		for _, ranking := range rankings.Rankings {
+			addresses = append(addresses, ranking.Wallet.Address)
+		}
+		return addresses, nil
+	})
+	return rankings, err
+}
+
+// GetRiskScore retrieves a wallet's risk score
+func (r *CachedWalletRepository) GetRiskScore(ctx context.Context, address string) (*entity.RiskScore, error) {
+	var score *entity.RiskScore
+	err := r.cache.Load(ctx, "risk_score:"+address, r.ttl.RiskScores, &score, func(ctx context.Context) ([]string, error) {
+		var err error
+		score, err = r.WalletRepository.GetRiskScore(ctx, address)
+		return []string{address}, err
+	})
+	return score, err
+}
+
+// GetRiskScores retrieves risk scores, loading only the uncached ones
+func (r *CachedWalletRepository) GetRiskScores(ctx context.Context, addresses []string) ([]entity.RiskScore, error) {
+	if r.ttl.RiskScores <= 0 || len(addresses) == 0 {
+		return r.WalletRepository.GetRiskScores(ctx, addresses)
+	}
+
+	requested := distinctStrings(addresses...)
+	keys := make([]string, len(requested))
+	for i, address := range requested {
+		keys[i] = "risk_score:" + address
+	}
+	cached := make([]*entity.RiskScore, len(requested))
+	found := r.cache.GetEach(ctx, keys, func(i int) interface{} { return &cached[i] })
+
+	var missing []string
+	for i, address := range requested {
+		if !found[i] {
+			missing = append(missing, address)
+		}
+	}
+
+	loaded := map[string]*entity.RiskScore{}
+	if len(missing) > 0 {
+		scores, err := r.WalletRepository.GetRiskScores(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		for i := range scores {
+			address := scores[i].Address
+			loaded[address] = &scores[i]
+			r.cache.Store(ctx, "risk_score:"+address, r.ttl.RiskScores, &scores[i], address)
+		}
+	}
+
+	scores := make([]entity.RiskScore, 0, len(requested))
+	for i, address := range requested {
+		score := cached[i]
+		if !found[i] {
+			score = loaded[address]
+		}
+		if score != nil {
+			scores = append(scores, *score)
+		}
+	}
+	return scores, nil
+}
+
+// GetWalletStats retrieves wallet statistics
+func (r *CachedWalletRepository) GetWalletStats(ctx context.Context, address string) (*entity.WalletStats, error) {
+	var stats *entity.WalletStats
+	err := r.cache.Load(ctx, "wallet_stats:"+address, r.ttl.WalletData, &stats, func(ctx context.Context) ([]string, error) {
+		var err error
+		stats, err = r.WalletRepository.GetWalletStats(ctx, address)
+		return []string{address}, err
+	})
+	return stats, err
+}
+
+// UpdateRiskScore applies a risk override and invalidates the wallet's cached reads
+func (r *CachedWalletRepository) UpdateRiskScore(ctx context.Context, address string, manualFlags []string, whitelistStatus *bool) (*entity.RiskScore, error) {
+	score, err := r.WalletRepository.UpdateRiskScore(ctx, address, manualFlags, whitelistStatus)
+	r.invalidate(ctx, address)
+	return score, err
+}
+
+// UpdateWalletType sets wallet types and invalidates the wallets' cached reads
+func (r *CachedWalletRepository) UpdateWalletType(ctx context.Context, addresses []string, walletType entity.WalletType) error {
+	err := r.WalletRepository.UpdateWalletType(ctx, addresses, walletType)
+	r.invalidate(ctx, addresses...)
+	return err
+}
+
+// UpdateClassification stores a classifier result and invalidates the wallet's cached reads
+func (r *CachedWalletRepository) UpdateClassification(ctx context.Context, classification *entity.WalletClassification) error {
+	err := r.WalletRepository.UpdateClassification(ctx, classification)
+	r.invalidate(ctx, classification.Address)
+	return err
+}
+
+// UpdateAttribution stores an attribution and invalidates the wallet's cached reads
+func (r *CachedWalletRepository) UpdateAttribution(ctx context.Context, attribution *entity.AddressAttribution) error {
+	err := r.WalletRepository.UpdateAttribution(ctx, attribution)
+	r.invalidate(ctx, attribution.Address)
+	return err
+}
+
+// UpdateScamReports stores scam reports and invalidates the wallet's cached reads
+func (r *CachedWalletRepository) UpdateScamReports(ctx context.Context, address string, reports int) error {
+	err := r.WalletRepository.UpdateScamReports(ctx, address, reports)
+	r.invalidate(ctx, address)
+	return err
+}
+
+// invalidate drops the cached reads that include the addresses. It runs even
+// when the write failed, since a failed write may have been partly applied.
+func (r *CachedWalletRepository) invalidate(ctx context.Context, addresses ...string) {
+	if err := r.cache.InvalidateAddresses(context.WithoutCancel(ctx), addresses...); err != nil {
+		r.logger.Warn("Failed to invalidate cached wallet reads",
+			zap.Int("addresses", len(addresses)),
+			zap.Error(err))
+	}
+}
+
+// cacheKeyHash condenses the parameters of a read into a cache key component
+func cacheKeyHash(params ...interface{}) string {
+	data, err := json.Marshal(params)
+	if err != nil {
+		data = []byte(fmt.Sprint(params...))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
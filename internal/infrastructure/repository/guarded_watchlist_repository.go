@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+
+	"go.uber.org/zap"
+)
+
+// GuardedWatchListRepository decorates a WatchListRepository so that watch
+// list features are disabled while PostgreSQL is unavailable: calls fail
+// fast with a service unavailable error instead of waiting on connections.
+type GuardedWatchListRepository struct {
+	repository.WatchListRepository
+	available func() bool
+	logger    *zap.Logger
+}
+
+// NewGuardedWatchListRepository creates a watch list repository around inner
+// that is disabled whenever available reports false
+func NewGuardedWatchListRepository(inner repository.WatchListRepository, available func() bool, logger *zap.Logger) repository.WatchListRepository {
+	return &GuardedWatchListRepository{
+		WatchListRepository: inner,
+		available:           available,
+		logger:              logger,
+	}
+}
+
+// check returns the error reported while watch lists are disabled
+func (r *GuardedWatchListRepository) check() error {
+	if r.available() {
+		return nil
+	}
+	return apperrors.NewAppError(apperrors.ErrCodeServiceUnavailable,
+		"Watch lists are temporarily unavailable", "PostgreSQL is unavailable").
+		WithMetadata("capability", "watch_lists")
+}
+
+// GetWatchedWallets retrieves a user's watched wallets
+func (r *GuardedWatchListRepository) GetWatchedWallets(ctx context.Context, userID uint) ([]entity.WatchedWallet, error) {
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+	return r.WatchListRepository.GetWatchedWallets(ctx, userID)
+}
+
+// GetWatchedWallet retrieves a watched wallet
+func (r *GuardedWatchListRepository) GetWatchedWallet(ctx context.Context, userID uint, walletID uint) (*entity.WatchedWallet, error) {
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+	return r.WatchListRepository.GetWatchedWallet(ctx, userID, walletID)
+}
+
+// GetWatchedWalletByAddress retrieves a watched wallet by address
+func (r *GuardedWatchListRepository) GetWatchedWalletByAddress(ctx context.Context, userID uint, address string) (*entity.WatchedWallet, error) {
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+	return r.WatchListRepository.GetWatchedWalletByAddress(ctx, userID, address)
+}
+
+// AddWatchedWallet adds a wallet to a user's watch list
+func (r *GuardedWatchListRepository) AddWatchedWallet(ctx context.Context, wallet *entity.WatchedWallet) error {
+	if err := r.check(); err != nil {
+		return err
+	}
+	return r.WatchListRepository.AddWatchedWallet(ctx, wallet)
+}
+
+// UpdateWatchedWallet updates a watched wallet
+func (r *GuardedWatchListRepository) UpdateWatchedWallet(ctx context.Context, wallet *entity.WatchedWallet) error {
+	if err := r.check(); err != nil {
+		return err
+	}
+	return r.WatchListRepository.UpdateWatchedWallet(ctx, wallet)
+}
+
+// RemoveWatchedWallet removes a wallet from a user's watch list
+func (r *GuardedWatchListRepository) RemoveWatchedWallet(ctx context.Context, userID uint, walletID uint) error {
+	if err := r.check(); err != nil {
+		return err
+	}
+	return r.WatchListRepository.RemoveWatchedWallet(ctx, userID, walletID)
+}
+
+// GetAllWatchedAddresses retrieves every watched address
+func (r *GuardedWatchListRepository) GetAllWatchedAddresses(ctx context.Context) ([]string, error) {
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+	return r.WatchListRepository.GetAllWatchedAddresses(ctx)
+}
+
+// GetWatchListStats retrieves a user's watch list statistics
+func (r *GuardedWatchListRepository) GetWatchListStats(ctx context.Context, userID uint) (*entity.WatchListStats, error) {
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+	return r.WatchListRepository.GetWatchListStats(ctx, userID)
+}
+
+// CreateWalletAlert creates a wallet alert
+func (r *GuardedWatchListRepository) CreateWalletAlert(ctx context.Context, alert *entity.WalletAlert) error {
+	if err := r.check(); err != nil {
+		return err
+	}
+	return r.WatchListRepository.CreateWalletAlert(ctx, alert)
+}
+
+// GetWalletAlerts retrieves a user's wallet alerts
+func (r *GuardedWatchListRepository) GetWalletAlerts(ctx context.Context, userID uint, filters map[string]interface{}) ([]entity.WalletAlert, error) {
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+	return r.WatchListRepository.GetWalletAlerts(ctx, userID, filters)
+}
+
+// AcknowledgeWalletAlert acknowledges a wallet alert
+func (r *GuardedWatchListRepository) AcknowledgeWalletAlert(ctx context.Context, userID uint, alertID uint) error {
+	if err := r.check(); err != nil {
+		return err
+	}
+	return r.WatchListRepository.AcknowledgeWalletAlert(ctx, userID, alertID)
+}
+
+// GetOrCreateTag retrieves or creates a watch list tag
+func (r *GuardedWatchListRepository) GetOrCreateTag(ctx context.Context, name string) (*entity.WatchedWalletTag, error) {
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+	return r.WatchListRepository.GetOrCreateTag(ctx, name)
+}
+
+// GetAllTags retrieves every watch list tag
+func (r *GuardedWatchListRepository) GetAllTags(ctx context.Context) ([]entity.WatchedWalletTag, error) {
+	if err := r.check(); err != nil {
+		return nil, err
+	}
+	return r.WatchListRepository.GetAllTags(ctx)
+}
@@ -52,6 +52,27 @@ func (r *MockAIRepository) AskAI(ctx context.Context, question string, aiContext
 	return response, nil
 }
 
+// ChatAI answers like AskAI, ignoring the history, and streams the answer word by word
+func (r *MockAIRepository) ChatAI(ctx context.Context, req *entity.AIChatRequest, onDelta func(delta string)) (*entity.AIResponse, error) {
+	response, err := r.AskAI(ctx, req.Question, req.Context, req.WalletAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if onDelta != nil {
+		for _, word := range strings.SplitAfter(response.Answer, " ") {
+			onDelta(word)
+		}
+	}
+
+	return response, nil
+}
+
+// DraftReportNarrative writes a narrative from the report's findings with a fixed template
+func (r *MockAIRepository) DraftReportNarrative(ctx context.Context, report *entity.ComplianceReport, evidence []entity.Transaction) (*entity.AIResponse, error) {
+	return templateNarrative(report), nil
+}
+
 // GetAvailableModels returns available AI models
 func (r *MockAIRepository) GetAvailableModels(ctx context.Context) ([]string, error) {
 	return []string{
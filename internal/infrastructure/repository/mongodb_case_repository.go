@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	securityCasesCollection = "security_cases"
+	caseCommentsCollection  = "case_comments"
+)
+
+// MongoCaseRepository implements CaseRepository using MongoDB
+type MongoCaseRepository struct {
+	mongo  *database.MongoClient
+	logger *zap.Logger
+}
+
+// NewMongoCaseRepository creates a new MongoDB security case repository
+func NewMongoCaseRepository(mongo *database.MongoClient, logger *zap.Logger) repository.CaseRepository {
+	return &MongoCaseRepository{
+		mongo:  mongo,
+		logger: logger,
+	}
+}
+
+// CreateCase creates a new security case
+func (r *MongoCaseRepository) CreateCase(ctx context.Context, securityCase *entity.SecurityCase) error {
+	if securityCase.ID == "" {
+		securityCase.ID = primitive.NewObjectID().Hex()
+	}
+	if securityCase.CreatedAt.IsZero() {
+		securityCase.CreatedAt = time.Now()
+	}
+	securityCase.UpdatedAt = securityCase.CreatedAt
+	securityCase.Version = 1
+
+	if _, err := r.mongo.GetCollection(securityCasesCollection).InsertOne(ctx, securityCase); err != nil {
+		r.logger.Error("Failed to create security case",
+			zap.String("caseID", securityCase.ID),
+			zap.Error(err))
+		return fmt.Errorf("failed to create security case: %w", err)
+	}
+
+	return nil
+}
+
+// GetCase retrieves a security case by ID
+func (r *MongoCaseRepository) GetCase(ctx context.Context, caseID string) (*entity.SecurityCase, error) {
+	var securityCase entity.SecurityCase
+	err := r.mongo.GetCollection(securityCasesCollection).FindOne(ctx, bson.M{"id": caseID}).Decode(&securityCase)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get security case",
+			zap.String("caseID", caseID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to get security case: %w", err)
+	}
+
+	return &securityCase, nil
+}
+
+// GetCases retrieves security cases with filters, soonest resolution deadline first
+func (r *MongoCaseRepository) GetCases(ctx context.Context, filters *entity.CaseFilters, limit, offset int) ([]entity.SecurityCase, error) {
+	filter := bson.M{}
+	if filters != nil {
+		if len(filters.Statuses) > 0 {
+			filter["status"] = bson.M{"$in": filters.Statuses}
+		}
+		if filters.AssigneeID != nil {
+			filter["assignee_id"] = *filters.AssigneeID
+		} else if filters.Unassigned {
+			filter["assignee_id"] = bson.M{"$exists": false}
+		}
+		if filters.Severity != nil {
+			filter["severity"] = *filters.Severity
+		}
+		if filters.WalletAddress != nil {
+			filter["wallet_addresses"] = entity.NormalizeAddress(*filters.WalletAddress)
+		}
+	}
+
+	findOptions := options.Find().
+		SetLimit(int64(limit)).
+		SetSkip(int64(offset)).
+		SetSort(bson.D{{Key: "resolution_due_at", Value: 1}, {Key: "created_at", Value: 1}})
+
+	cursor, err := r.mongo.GetCollection(securityCasesCollection).Find(ctx, filter, findOptions)
+	if err != nil {
+		r.logger.Error("Failed to find security cases", zap.Error(err))
+		return nil, fmt.Errorf("failed to find security cases: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	cases := []entity.SecurityCase{}
+	if err := cursor.All(ctx, &cases); err != nil {
+		return nil, fmt.Errorf("failed to decode security cases: %w", err)
+	}
+
+	return cases, nil
+}
+
+// UpdateCase saves a security case. It only succeeds if nobody else has updated
+// the case since expectedVersion was read; on success the case's version is
+// advanced. The comment count is maintained by AddComment and left untouched.
+func (r *MongoCaseRepository) UpdateCase(ctx context.Context, securityCase *entity.SecurityCase, expectedVersion int) (bool, error) {
+	updated := *securityCase
+	updated.Version = expectedVersion + 1
+	updated.UpdatedAt = time.Now()
+
+	raw, err := bson.Marshal(&updated)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode security case: %w", err)
+	}
+	var fields bson.M
+	if err := bson.Unmarshal(raw, &fields); err != nil {
+		return false, fmt.Errorf("failed to encode security case: %w", err)
+	}
+	delete(fields, "comment_count")
+
+	filter := bson.M{"id": securityCase.ID, "version": expectedVersion}
+	result, err := r.mongo.GetCollection(securityCasesCollection).UpdateOne(ctx, filter, bson.M{"$set": fields})
+	if err != nil {
+		r.logger.Error("Failed to update security case",
+			zap.String("caseID", securityCase.ID),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to update security case: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return false, nil
+	}
+
+	*securityCase = updated
+	return true, nil
+}
+
+// AddComment stores a comment and increments the case's comment count
+func (r *MongoCaseRepository) AddComment(ctx context.Context, comment *entity.CaseComment) error {
+	if comment.ID == "" {
+		comment.ID = primitive.NewObjectID().Hex()
+	}
+	if comment.CreatedAt.IsZero() {
+		comment.CreatedAt = time.Now()
+	}
+
+	if _, err := r.mongo.GetCollection(caseCommentsCollection).InsertOne(ctx, comment); err != nil {
+		r.logger.Error("Failed to add case comment",
+			zap.String("caseID", comment.CaseID),
+			zap.Error(err))
+		return fmt.Errorf("failed to add case comment: %w", err)
+	}
+
+	update := bson.M{
+		"$inc": bson.M{"comment_count": 1},
+		"$set": bson.M{"updated_at": comment.CreatedAt},
+	}
+	if _, err := r.mongo.GetCollection(securityCasesCollection).UpdateOne(ctx, bson.M{"id": comment.CaseID}, update); err != nil {
+		r.logger.Warn("Failed to update case comment count",
+			zap.String("caseID", comment.CaseID),
+			zap.Error(err))
+	}
+
+	return nil
+}
+
+// GetComments retrieves a case's comments in the order they were written
+func (r *MongoCaseRepository) GetComments(ctx context.Context, caseID string) ([]entity.CaseComment, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := r.mongo.GetCollection(caseCommentsCollection).Find(ctx, bson.M{"case_id": caseID}, findOptions)
+	if err != nil {
+		r.logger.Error("Failed to find case comments",
+			zap.String("caseID", caseID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to find case comments: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	comments := []entity.CaseComment{}
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, fmt.Errorf("failed to decode case comments: %w", err)
+	}
+
+	return comments, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const walletClassificationsCollection = "wallet_classifications"
+
+// MongoClassificationRepository implements ClassificationRepository using MongoDB
+type MongoClassificationRepository struct {
+	mongo  *database.MongoClient
+	logger *zap.Logger
+}
+
+// NewMongoClassificationRepository creates a new MongoDB wallet classification repository
+func NewMongoClassificationRepository(mongo *database.MongoClient, logger *zap.Logger) repository.ClassificationRepository {
+	return &MongoClassificationRepository{
+		mongo:  mongo,
+		logger: logger,
+	}
+}
+
+// SaveClassification replaces the wallet's classification
+func (r *MongoClassificationRepository) SaveClassification(ctx context.Context, classification *entity.WalletClassification) error {
+	classification.Address = entity.NormalizeAddress(classification.Address)
+
+	filter := bson.M{"address": classification.Address}
+	_, err := r.mongo.GetCollection(walletClassificationsCollection).ReplaceOne(ctx, filter, classification, options.Replace().SetUpsert(true))
+	if err != nil {
+		r.logger.Error("Failed to save wallet classification",
+			zap.String("address", classification.Address),
+			zap.Error(err))
+		return fmt.Errorf("failed to save wallet classification: %w", err)
+	}
+
+	return nil
+}
+
+// GetClassification retrieves a wallet's classification
+func (r *MongoClassificationRepository) GetClassification(ctx context.Context, address string) (*entity.WalletClassification, error) {
+	var classification entity.WalletClassification
+	filter := bson.M{"address": entity.NormalizeAddress(address)}
+	err := r.mongo.GetCollection(walletClassificationsCollection).FindOne(ctx, filter).Decode(&classification)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get wallet classification",
+			zap.String("address", address),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to get wallet classification: %w", err)
+	}
+
+	return &classification, nil
+}
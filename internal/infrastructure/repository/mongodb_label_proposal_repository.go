@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	labelProposalsCollection      = "label_proposals"
+	submitterReputationCollection = "submitter_reputation"
+)
+
+// MongoLabelProposalRepository implements LabelProposalRepository using MongoDB
+type MongoLabelProposalRepository struct {
+	mongo  *database.MongoClient
+	logger *zap.Logger
+}
+
+// NewMongoLabelProposalRepository creates a new MongoDB label proposal repository
+func NewMongoLabelProposalRepository(mongo *database.MongoClient, logger *zap.Logger) repository.LabelProposalRepository {
+	return &MongoLabelProposalRepository{
+		mongo:  mongo,
+		logger: logger,
+	}
+}
+
+// CreateProposal stores a new proposal
+func (r *MongoLabelProposalRepository) CreateProposal(ctx context.Context, proposal *entity.LabelProposal) error {
+	proposal.Address = entity.NormalizeAddress(proposal.Address)
+	if proposal.ID == "" {
+		proposal.ID = primitive.NewObjectID().Hex()
+	}
+	if proposal.CreatedAt.IsZero() {
+		proposal.CreatedAt = time.Now()
+	}
+	proposal.UpdatedAt = proposal.CreatedAt
+
+	if _, err := r.mongo.GetCollection(labelProposalsCollection).InsertOne(ctx, proposal); err != nil {
+		r.logger.Error("Failed to create label proposal",
+			zap.String("address", proposal.Address),
+			zap.Uint("submitterID", proposal.SubmitterID),
+			zap.Error(err))
+		return fmt.Errorf("failed to create label proposal: %w", err)
+	}
+
+	return nil
+}
+
+// GetProposal retrieves a proposal by ID
+func (r *MongoLabelProposalRepository) GetProposal(ctx context.Context, id string) (*entity.LabelProposal, error) {
+	var proposal entity.LabelProposal
+	err := r.mongo.GetCollection(labelProposalsCollection).FindOne(ctx, bson.M{"id": id}).Decode(&proposal)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get label proposal",
+			zap.String("proposalID", id),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to get label proposal: %w", err)
+	}
+
+	return &proposal, nil
+}
+
+// GetProposals lists proposals with filters, oldest first
+func (r *MongoLabelProposalRepository) GetProposals(ctx context.Context, filters *entity.LabelProposalFilters, limit, offset int) ([]entity.LabelProposal, error) {
+	findOptions := options.Find().
+		SetLimit(int64(limit)).
+		SetSkip(int64(offset)).
+		SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := r.mongo.GetCollection(labelProposalsCollection).Find(ctx, proposalFilter(filters), findOptions)
+	if err != nil {
+		r.logger.Error("Failed to find label proposals", zap.Error(err))
+		return nil, fmt.Errorf("failed to find label proposals: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	proposals := []entity.LabelProposal{}
+	if err := cursor.All(ctx, &proposals); err != nil {
+		return nil, fmt.Errorf("failed to decode label proposals: %w", err)
+	}
+
+	return proposals, nil
+}
+
+// CountProposals counts proposals matching the filters
+func (r *MongoLabelProposalRepository) CountProposals(ctx context.Context, filters *entity.LabelProposalFilters) (int, error) {
+	count, err := r.mongo.GetCollection(labelProposalsCollection).CountDocuments(ctx, proposalFilter(filters))
+	if err != nil {
+		r.logger.Error("Failed to count label proposals", zap.Error(err))
+		return 0, fmt.Errorf("failed to count label proposals: %w", err)
+	}
+	return int(count), nil
+}
+
+func proposalFilter(filters *entity.LabelProposalFilters) bson.M {
+	filter := bson.M{}
+	if filters == nil {
+		return filter
+	}
+	if filters.Status != nil {
+		filter["status"] = *filters.Status
+	}
+	if filters.Kind != nil {
+		filter["kind"] = *filters.Kind
+	}
+	if filters.Address != nil {
+		filter["address"] = entity.NormalizeAddress(*filters.Address)
+	}
+	if filters.SubmitterID != nil {
+		filter["submitter_id"] = *filters.SubmitterID
+	}
+	return filter
+}
+
+// ReviewProposal stores a moderation decision if the proposal is still pending,
+// so two moderators cannot both decide the same proposal
+func (r *MongoLabelProposalRepository) ReviewProposal(ctx context.Context, proposal *entity.LabelProposal) (bool, error) {
+	proposal.UpdatedAt = time.Now()
+	filter := bson.M{"id": proposal.ID, "status": entity.LabelProposalStatusPending}
+	update := bson.M{"$set": bson.M{
+		"status":      proposal.Status,
+		"reviewer_id": proposal.ReviewerID,
+		"reviewer":    proposal.Reviewer,
+		"review_note": proposal.ReviewNote,
+		"reviewed_at": proposal.ReviewedAt,
+		"updated_at":  proposal.UpdatedAt,
+	}}
+
+	result, err := r.mongo.GetCollection(labelProposalsCollection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		r.logger.Error("Failed to review label proposal",
+			zap.String("proposalID", proposal.ID),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to review label proposal: %w", err)
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+// ReopenProposal returns a reviewed proposal to pending if it still has the
+// status and reviewer stored by ReviewProposal
+func (r *MongoLabelProposalRepository) ReopenProposal(ctx context.Context, proposal *entity.LabelProposal) (bool, error) {
+	filter := bson.M{"id": proposal.ID, "status": proposal.Status, "reviewer_id": proposal.ReviewerID}
+	update := bson.M{
+		"$set":   bson.M{"status": entity.LabelProposalStatusPending, "updated_at": time.Now()},
+		"$unset": bson.M{"reviewer_id": "", "reviewer": "", "review_note": "", "reviewed_at": ""},
+	}
+
+	result, err := r.mongo.GetCollection(labelProposalsCollection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		r.logger.Error("Failed to reopen label proposal",
+			zap.String("proposalID", proposal.ID),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to reopen label proposal: %w", err)
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+// CountApprovedScamReporters returns how many distinct users had a scam report
+// for the address approved
+func (r *MongoLabelProposalRepository) CountApprovedScamReporters(ctx context.Context, address string) (int, error) {
+	filter := bson.M{
+		"address": entity.NormalizeAddress(address),
+		"kind":    entity.LabelProposalKindScamReport,
+		"status":  entity.LabelProposalStatusApproved,
+	}
+
+	reporters, err := r.mongo.GetCollection(labelProposalsCollection).Distinct(ctx, "submitter_id", filter)
+	if err != nil {
+		r.logger.Error("Failed to count scam reporters",
+			zap.String("address", address),
+			zap.Error(err))
+		return 0, fmt.Errorf("failed to count scam reporters: %w", err)
+	}
+
+	return len(reporters), nil
+}
+
+// GetReputation retrieves a submitter's reputation; users who never submitted
+// a proposal have an empty record
+func (r *MongoLabelProposalRepository) GetReputation(ctx context.Context, userID uint) (*entity.SubmitterReputation, error) {
+	var reputation entity.SubmitterReputation
+	err := r.mongo.GetCollection(submitterReputationCollection).FindOne(ctx, bson.M{"user_id": userID}).Decode(&reputation)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return &entity.SubmitterReputation{UserID: userID}, nil
+		}
+		r.logger.Error("Failed to get submitter reputation",
+			zap.Uint("userID", userID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to get submitter reputation: %w", err)
+	}
+
+	return &reputation, nil
+}
+
+// IncrementReputation adds to a submitter's counters and returns the updated record
+func (r *MongoLabelProposalRepository) IncrementReputation(ctx context.Context, userID uint, submitted, approved, rejected int) (*entity.SubmitterReputation, error) {
+	update := bson.M{
+		"$inc": bson.M{
+			"submitted": submitted,
+			"approved":  approved,
+			"rejected":  rejected,
+		},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+	updateOptions := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+
+	var reputation entity.SubmitterReputation
+	err := r.mongo.GetCollection(submitterReputationCollection).
+		FindOneAndUpdate(ctx, bson.M{"user_id": userID}, update, updateOptions).
+		Decode(&reputation)
+	if err != nil {
+		r.logger.Error("Failed to update submitter reputation",
+			zap.Uint("userID", userID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to update submitter reputation: %w", err)
+	}
+
+	return &reputation, nil
+}
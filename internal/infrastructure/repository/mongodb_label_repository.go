@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	addressLabelsCollection = "address_labels"
+	labelImportsCollection  = "label_imports"
+)
+
+// MongoLabelRepository implements LabelRepository using MongoDB
+type MongoLabelRepository struct {
+	mongo  *database.MongoClient
+	logger *zap.Logger
+}
+
+// NewMongoLabelRepository creates a new MongoDB address label repository
+func NewMongoLabelRepository(mongo *database.MongoClient, logger *zap.Logger) repository.LabelRepository {
+	return &MongoLabelRepository{
+		mongo:  mongo,
+		logger: logger,
+	}
+}
+
+// CreateLabel creates the label unless its source already has a label of the
+// same name for the address
+func (r *MongoLabelRepository) CreateLabel(ctx context.Context, label *entity.AddressLabel) (bool, error) {
+	label.Address = entity.NormalizeAddress(label.Address)
+	if label.ID == "" {
+		label.ID = primitive.NewObjectID().Hex()
+	}
+	if label.CreatedAt.IsZero() {
+		label.CreatedAt = time.Now()
+	}
+	label.UpdatedAt = label.CreatedAt
+
+	_, err := r.mongo.GetCollection(addressLabelsCollection).InsertOne(ctx, label)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		r.logger.Error("Failed to create address label",
+			zap.String("address", label.Address),
+			zap.String("source", label.Source),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to create address label: %w", err)
+	}
+
+	return true, nil
+}
+
+// UpdateLabel replaces a label
+func (r *MongoLabelRepository) UpdateLabel(ctx context.Context, label *entity.AddressLabel) error {
+	label.Address = entity.NormalizeAddress(label.Address)
+	label.UpdatedAt = time.Now()
+
+	_, err := r.mongo.GetCollection(addressLabelsCollection).ReplaceOne(ctx, bson.M{"id": label.ID}, label)
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("source %q already has a label named %q for %s", label.Source, label.Name, label.Address)
+	}
+	if err != nil {
+		r.logger.Error("Failed to update address label",
+			zap.String("labelID", label.ID),
+			zap.Error(err))
+		return fmt.Errorf("failed to update address label: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteLabel deletes a label, reporting whether it existed
+func (r *MongoLabelRepository) DeleteLabel(ctx context.Context, id string) (bool, error) {
+	result, err := r.mongo.GetCollection(addressLabelsCollection).DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		r.logger.Error("Failed to delete address label",
+			zap.String("labelID", id),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to delete address label: %w", err)
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+// GetLabel retrieves a label by ID
+func (r *MongoLabelRepository) GetLabel(ctx context.Context, id string) (*entity.AddressLabel, error) {
+	var label entity.AddressLabel
+	err := r.mongo.GetCollection(addressLabelsCollection).FindOne(ctx, bson.M{"id": id}).Decode(&label)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get address label",
+			zap.String("labelID", id),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to get address label: %w", err)
+	}
+
+	return &label, nil
+}
+
+// GetLabels retrieves labels with filters, most recently updated first
+func (r *MongoLabelRepository) GetLabels(ctx context.Context, filters *entity.LabelFilters, limit, offset int) ([]entity.AddressLabel, error) {
+	filter := bson.M{}
+	if filters != nil {
+		if filters.Address != nil {
+			filter["address"] = entity.NormalizeAddress(*filters.Address)
+		}
+		if filters.Source != nil {
+			filter["source"] = *filters.Source
+		}
+		if filters.Category != nil {
+			filter["category"] = *filters.Category
+		}
+		if filters.Search != nil && *filters.Search != "" {
+			pattern := primitive.Regex{Pattern: regexp.QuoteMeta(*filters.Search), Options: "i"}
+			filter["$or"] = []bson.M{
+				{"name": pattern},
+				{"entity": pattern},
+			}
+		}
+		if !filters.IncludeExpired {
+			filter["$and"] = validAt(time.Now())
+		}
+	}
+
+	findOptions := options.Find().
+		SetLimit(int64(limit)).
+		SetSkip(int64(offset)).
+		SetSort(bson.D{{Key: "updated_at", Value: -1}})
+
+	return r.findLabels(ctx, filter, findOptions)
+}
+
+// GetLabelsByAddress returns every label for the address, expired or not
+func (r *MongoLabelRepository) GetLabelsByAddress(ctx context.Context, address string) ([]entity.AddressLabel, error) {
+	filter := bson.M{"address": entity.NormalizeAddress(address)}
+	return r.findLabels(ctx, filter, options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}}))
+}
+
+func (r *MongoLabelRepository) findLabels(ctx context.Context, filter bson.M, findOptions *options.FindOptions) ([]entity.AddressLabel, error) {
+	cursor, err := r.mongo.GetCollection(addressLabelsCollection).Find(ctx, filter, findOptions)
+	if err != nil {
+		r.logger.Error("Failed to find address labels", zap.Error(err))
+		return nil, fmt.Errorf("failed to find address labels: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	labels := []entity.AddressLabel{}
+	if err := cursor.All(ctx, &labels); err != nil {
+		return nil, fmt.Errorf("failed to decode address labels: %w", err)
+	}
+
+	return labels, nil
+}
+
+// UpsertLabels creates or replaces labels by address, source and name. A
+// replaced label keeps its ID and creation details.
+func (r *MongoLabelRepository) UpsertLabels(ctx context.Context, labels []entity.AddressLabel) (created, updated int, err error) {
+	if len(labels) == 0 {
+		return 0, 0, nil
+	}
+
+	now := time.Now()
+	models := make([]mongo.WriteModel, 0, len(labels))
+	for _, label := range labels {
+		label.Address = entity.NormalizeAddress(label.Address)
+		filter := bson.M{"address": label.Address, "source": label.Source, "name": label.Name}
+		update := bson.M{
+			"$set": bson.M{
+				"entity":      label.Entity,
+				"category":    label.Category,
+				"tags":        label.Tags,
+				"source_type": label.SourceType,
+				"confidence":  label.Confidence,
+				"valid_from":  label.ValidFrom,
+				"valid_until": label.ValidUntil,
+				"provenance":  label.Provenance,
+				"updated_at":  now,
+			},
+			"$setOnInsert": bson.M{
+				"id":            primitive.NewObjectID().Hex(),
+				"created_by_id": label.CreatedByID,
+				"created_by":    label.CreatedBy,
+				"created_at":    now,
+			},
+		}
+		models = append(models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true))
+	}
+
+	result, err := r.mongo.GetCollection(addressLabelsCollection).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		r.logger.Error("Failed to upsert address labels",
+			zap.Int("labels", len(labels)),
+			zap.Error(err))
+		return 0, 0, fmt.Errorf("failed to upsert address labels: %w", err)
+	}
+
+	return int(result.UpsertedCount), int(result.MatchedCount), nil
+}
+
+// validAt is the filter clause for labels valid at now
+func validAt(now time.Time) []bson.M {
+	return []bson.M{
+		{"$or": []bson.M{{"valid_from": bson.M{"$exists": false}}, {"valid_from": bson.M{"$lte": now}}}},
+		{"$or": []bson.M{{"valid_until": bson.M{"$exists": false}}, {"valid_until": bson.M{"$gt": now}}}},
+	}
+}
+
+// SearchLabels finds currently valid labels with the text index over name,
+// entity and tags. Scores are relative to the best match, which scores labelSearchScoreScale.
+func (r *MongoLabelRepository) SearchLabels(ctx context.Context, query string, limit int) ([]entity.LabelMatch, error) {
+	filter := bson.M{
+		"$text": bson.M{"$search": query},
+		"$and":  validAt(time.Now()),
+	}
+	findOptions := options.Find().
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"search_score": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.D{{Key: "search_score", Value: bson.M{"$meta": "textScore"}}})
+
+	cursor, err := r.mongo.GetCollection(addressLabelsCollection).Find(ctx, filter, findOptions)
+	if err != nil {
+		r.logger.Error("Failed to search address labels", zap.Error(err))
+		return nil, fmt.Errorf("failed to search address labels: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var hits []struct {
+		entity.AddressLabel `bson:",inline"`
+		SearchScore         float64 `bson:"search_score"`
+	}
+	if err := cursor.All(ctx, &hits); err != nil {
+		return nil, fmt.Errorf("failed to decode address labels: %w", err)
+	}
+
+	matches := make([]entity.LabelMatch, 0, len(hits))
+	for _, hit := range hits {
+		score := labelSearchScoreScale
+		if best := hits[0].SearchScore; best > 0 {
+			score *= hit.SearchScore / best
+		}
+		matches = append(matches, entity.LabelMatch{Label: hit.AddressLabel, RelevanceScore: score})
+	}
+
+	return matches, nil
+}
+
+// labelSearchScoreScale caps label text matches below exact address and hash matches
+const labelSearchScoreScale = 0.8
+
+// GetAddressesWithValidityChanges returns addresses with a label that became
+// valid or expired in (from, to]
+func (r *MongoLabelRepository) GetAddressesWithValidityChanges(ctx context.Context, from, to time.Time) ([]string, error) {
+	window := bson.M{"$gt": from, "$lte": to}
+	filter := bson.M{"$or": []bson.M{
+		{"valid_from": window},
+		{"valid_until": window},
+	}}
+
+	values, err := r.mongo.GetCollection(addressLabelsCollection).Distinct(ctx, "address", filter)
+	if err != nil {
+		r.logger.Error("Failed to find label validity changes", zap.Error(err))
+		return nil, fmt.Errorf("failed to find label validity changes: %w", err)
+	}
+
+	addresses := make([]string, 0, len(values))
+	for _, value := range values {
+		if address, ok := value.(string); ok {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses, nil
+}
+
+// CreateLabelImport records a label pack import
+func (r *MongoLabelRepository) CreateLabelImport(ctx context.Context, labelImport *entity.LabelImport) error {
+	if labelImport.ID == "" {
+		labelImport.ID = primitive.NewObjectID().Hex()
+	}
+	if labelImport.ImportedAt.IsZero() {
+		labelImport.ImportedAt = time.Now()
+	}
+
+	if _, err := r.mongo.GetCollection(labelImportsCollection).InsertOne(ctx, labelImport); err != nil {
+		r.logger.Error("Failed to record label import",
+			zap.String("source", labelImport.Source),
+			zap.Error(err))
+		return fmt.Errorf("failed to record label import: %w", err)
+	}
+
+	return nil
+}
+
+// GetLabelImports retrieves the most recent label pack imports
+func (r *MongoLabelRepository) GetLabelImports(ctx context.Context, limit int) ([]entity.LabelImport, error) {
+	findOptions := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "imported_at", Value: -1}})
+
+	cursor, err := r.mongo.GetCollection(labelImportsCollection).Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		r.logger.Error("Failed to find label imports", zap.Error(err))
+		return nil, fmt.Errorf("failed to find label imports: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	imports := []entity.LabelImport{}
+	if err := cursor.All(ctx, &imports); err != nil {
+		return nil, fmt.Errorf("failed to decode label imports: %w", err)
+	}
+
+	return imports, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const mevActivitiesCollection = "mev_activities"
+
+// MongoMEVRepository implements MEVRepository using MongoDB
+type MongoMEVRepository struct {
+	mongo  *database.MongoClient
+	logger *zap.Logger
+}
+
+// NewMongoMEVRepository creates a new MongoDB MEV activity repository
+func NewMongoMEVRepository(mongo *database.MongoClient, logger *zap.Logger) repository.MEVRepository {
+	return &MongoMEVRepository{
+		mongo:  mongo,
+		logger: logger,
+	}
+}
+
+// SaveMEVActivity records an activity unless one with the same dedupe key exists
+func (r *MongoMEVRepository) SaveMEVActivity(ctx context.Context, activity *entity.MEVActivity) (bool, error) {
+	if activity.DedupeKey == "" {
+		return false, fmt.Errorf("MEV activity has no dedupe key")
+	}
+	if activity.ID == "" {
+		activity.ID = primitive.NewObjectID().Hex()
+	}
+	if activity.DetectedAt.IsZero() {
+		activity.DetectedAt = time.Now()
+	}
+
+	filter := bson.M{"dedupe_key": activity.DedupeKey}
+	update := bson.M{"$setOnInsert": activity}
+	result, err := r.mongo.GetCollection(mevActivitiesCollection).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		r.logger.Error("Failed to save MEV activity",
+			zap.String("dedupeKey", activity.DedupeKey),
+			zap.String("type", string(activity.Type)),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to save MEV activity: %w", err)
+	}
+
+	return result.UpsertedCount > 0, nil
+}
+
+// GetMEVActivities retrieves MEV activities with filters, most recent first
+func (r *MongoMEVRepository) GetMEVActivities(ctx context.Context, filters *entity.MEVFilters, limit, offset int) ([]entity.MEVActivity, error) {
+	filter := bson.M{}
+	if filters != nil {
+		if filters.WalletAddress != nil {
+			address := entity.NormalizeAddress(*filters.WalletAddress)
+			filter["$or"] = []bson.M{
+				{"bot_address": address},
+				{"bot_contract": address},
+				{"victims.address": address},
+			}
+		}
+		if filters.Type != nil {
+			filter["type"] = *filters.Type
+		}
+	}
+
+	findOptions := options.Find().
+		SetLimit(int64(limit)).
+		SetSkip(int64(offset)).
+		SetSort(bson.D{{Key: "timestamp", Value: -1}})
+
+	cursor, err := r.mongo.GetCollection(mevActivitiesCollection).Find(ctx, filter, findOptions)
+	if err != nil {
+		r.logger.Error("Failed to find MEV activities", zap.Error(err))
+		return nil, fmt.Errorf("failed to find MEV activities: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	activities := []entity.MEVActivity{}
+	if err := cursor.All(ctx, &activities); err != nil {
+		return nil, fmt.Errorf("failed to decode MEV activities: %w", err)
+	}
+
+	return activities, nil
+}
@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	sanctionsVersionsCollection   = "sanctions_list_versions"
+	sanctionedAddressesCollection = "sanctioned_addresses"
+)
+
+// MongoSanctionsRepository implements SanctionsRepository using MongoDB for the
+// versioned lists and Neo4j for hop-based exposure screening
+type MongoSanctionsRepository struct {
+	mongo  *database.MongoClient
+	neo4j  *database.Neo4jClient
+	logger *zap.Logger
+}
+
+// NewMongoSanctionsRepository creates a new sanctions repository
+func NewMongoSanctionsRepository(mongo *database.MongoClient, neo4j *database.Neo4jClient, logger *zap.Logger) repository.SanctionsRepository {
+	return &MongoSanctionsRepository{
+		mongo:  mongo,
+		neo4j:  neo4j,
+		logger: logger,
+	}
+}
+
+// CreateListVersion stores a new list version with its entries and makes it the
+// active version for its source
+func (r *MongoSanctionsRepository) CreateListVersion(ctx context.Context, version *entity.SanctionsListVersion, entries []entity.SanctionedAddress) error {
+	if version.ID == "" {
+		version.ID = primitive.NewObjectID().Hex()
+	}
+	if version.ImportedAt.IsZero() {
+		version.ImportedAt = time.Now()
+	}
+	version.EntryCount = len(entries)
+	version.Active = false
+
+	if len(entries) > 0 {
+		docs := make([]interface{}, 0, len(entries))
+		for i := range entries {
+			entries[i].ListVersion = version.ID
+			entries[i].Source = version.Source
+			docs = append(docs, entries[i])
+		}
+
+		_, err := r.mongo.GetCollection(sanctionedAddressesCollection).
+			InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+		if err != nil {
+			r.logger.Error("Failed to insert sanctioned addresses",
+				zap.String("versionID", version.ID),
+				zap.Int("count", len(entries)),
+				zap.Error(err))
+			return fmt.Errorf("failed to insert sanctioned addresses: %w", err)
+		}
+	}
+
+	versions := r.mongo.GetCollection(sanctionsVersionsCollection)
+	if _, err := versions.InsertOne(ctx, version); err != nil {
+		r.logger.Error("Failed to insert sanctions list version",
+			zap.String("versionID", version.ID),
+			zap.Error(err))
+		return fmt.Errorf("failed to insert sanctions list version: %w", err)
+	}
+
+	// Flag the designated wallets in the graph before activating so that exposure
+	// screening never runs against a version the graph does not reflect yet
+	addresses := make([]string, 0, len(entries))
+	for _, e := range entries {
+		addresses = append(addresses, e.Address)
+	}
+	flagged, err := r.neo4j.SetSanctionedWallets(ctx, string(version.Source), addresses)
+	if err != nil {
+		return fmt.Errorf("failed to flag sanctioned wallets in graph: %w", err)
+	}
+
+	_, err = versions.UpdateMany(ctx,
+		bson.M{"source": version.Source, "active": true, "id": bson.M{"$ne": version.ID}},
+		bson.M{"$set": bson.M{"active": false}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to deactivate previous sanctions list versions: %w", err)
+	}
+
+	if _, err := versions.UpdateOne(ctx, bson.M{"id": version.ID}, bson.M{"$set": bson.M{"active": true}}); err != nil {
+		return fmt.Errorf("failed to activate sanctions list version: %w", err)
+	}
+	version.Active = true
+
+	r.logger.Info("Created sanctions list version",
+		zap.String("versionID", version.ID),
+		zap.String("source", string(version.Source)),
+		zap.Int("entries", version.EntryCount),
+		zap.Int64("flaggedWallets", flagged))
+
+	return nil
+}
+
+// GetListVersion retrieves a sanctions list version by ID
+func (r *MongoSanctionsRepository) GetListVersion(ctx context.Context, versionID string) (*entity.SanctionsListVersion, error) {
+	return r.findVersion(ctx, bson.M{"id": versionID})
+}
+
+// GetActiveListVersion retrieves the active version for a source
+func (r *MongoSanctionsRepository) GetActiveListVersion(ctx context.Context, source entity.SanctionsSource) (*entity.SanctionsListVersion, error) {
+	return r.findVersion(ctx, bson.M{"source": source, "active": true})
+}
+
+// GetListVersions retrieves the import history, newest first
+func (r *MongoSanctionsRepository) GetListVersions(ctx context.Context, source *entity.SanctionsSource, limit int) ([]entity.SanctionsListVersion, error) {
+	filter := bson.M{}
+	if source != nil {
+		filter["source"] = *source
+	}
+
+	findOptions := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "imported_at", Value: -1}})
+
+	cursor, err := r.mongo.GetCollection(sanctionsVersionsCollection).Find(ctx, filter, findOptions)
+	if err != nil {
+		r.logger.Error("Failed to find sanctions list versions", zap.Error(err))
+		return nil, fmt.Errorf("failed to find sanctions list versions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var versions []entity.SanctionsListVersion
+	if err := cursor.All(ctx, &versions); err != nil {
+		return nil, fmt.Errorf("failed to decode sanctions list versions: %w", err)
+	}
+
+	return versions, nil
+}
+
+// GetListEntries retrieves all addresses designated by a list version
+func (r *MongoSanctionsRepository) GetListEntries(ctx context.Context, versionID string) ([]entity.SanctionedAddress, error) {
+	cursor, err := r.mongo.GetCollection(sanctionedAddressesCollection).Find(ctx, bson.M{"list_version": versionID})
+	if err != nil {
+		r.logger.Error("Failed to find sanctions list entries",
+			zap.String("versionID", versionID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to find sanctions list entries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []entity.SanctionedAddress
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode sanctions list entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// FindSanctionedAddresses returns the entries of active list versions matching any of addresses
+func (r *MongoSanctionsRepository) FindSanctionedAddresses(ctx context.Context, addresses []string) ([]entity.SanctionedAddress, error) {
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+
+	activeIDs, err := r.activeVersionIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(activeIDs) == 0 {
+		return nil, nil
+	}
+
+	normalized := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		normalized = append(normalized, entity.NormalizeAddress(address))
+	}
+
+	filter := bson.M{
+		"list_version": bson.M{"$in": activeIDs},
+		"address":      bson.M{"$in": normalized},
+	}
+
+	cursor, err := r.mongo.GetCollection(sanctionedAddressesCollection).Find(ctx, filter)
+	if err != nil {
+		r.logger.Error("Failed to find sanctioned addresses",
+			zap.Int("addresses", len(addresses)),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to find sanctioned addresses: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var matches []entity.SanctionedAddress
+	if err := cursor.All(ctx, &matches); err != nil {
+		return nil, fmt.Errorf("failed to decode sanctioned addresses: %w", err)
+	}
+
+	return matches, nil
+}
+
+// FindExposures finds sanctioned wallets within maxHops of address in the transaction graph
+func (r *MongoSanctionsRepository) FindExposures(ctx context.Context, address string, maxHops int) ([]entity.SanctionsExposure, error) {
+	if maxHops < 1 {
+		return nil, nil
+	}
+
+	records, err := r.neo4j.FindSanctionedWithinHops(ctx, entity.NormalizeAddress(address), maxHops)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sanctions exposures: %w", err)
+	}
+
+	exposures := make([]entity.SanctionsExposure, 0, len(records))
+	for _, record := range records {
+		exposures = append(exposures, entity.SanctionsExposure{
+			SanctionedAddress: getStringValue(record, "sanctioned_address"),
+			Hops:              getIntValue(record, "hops"),
+		})
+	}
+
+	return exposures, nil
+}
+
+// findVersion retrieves a single version matching filter
+func (r *MongoSanctionsRepository) findVersion(ctx context.Context, filter bson.M) (*entity.SanctionsListVersion, error) {
+	var version entity.SanctionsListVersion
+	findOptions := options.FindOne().SetSort(bson.D{{Key: "imported_at", Value: -1}})
+
+	err := r.mongo.GetCollection(sanctionsVersionsCollection).FindOne(ctx, filter, findOptions).Decode(&version)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get sanctions list version", zap.Error(err))
+		return nil, fmt.Errorf("failed to get sanctions list version: %w", err)
+	}
+
+	return &version, nil
+}
+
+// activeVersionIDs returns the IDs of the active version of every source
+func (r *MongoSanctionsRepository) activeVersionIDs(ctx context.Context) ([]string, error) {
+	cursor, err := r.mongo.GetCollection(sanctionsVersionsCollection).
+		Find(ctx, bson.M{"active": true}, options.Find().SetProjection(bson.M{"id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find active sanctions list versions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var ids []string
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID string `bson:"id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			continue
+		}
+		ids = append(ids, doc.ID)
+	}
+
+	return ids, cursor.Err()
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	screeningJobsCollection    = "screening_jobs"
+	screeningResultsCollection = "screening_results"
+)
+
+// MongoScreeningRepository implements ScreeningRepository using MongoDB
+type MongoScreeningRepository struct {
+	mongo  *database.MongoClient
+	logger *zap.Logger
+}
+
+// NewMongoScreeningRepository creates a new MongoDB screening repository
+func NewMongoScreeningRepository(mongo *database.MongoClient, logger *zap.Logger) repository.ScreeningRepository {
+	return &MongoScreeningRepository{
+		mongo:  mongo,
+		logger: logger,
+	}
+}
+
+// CreateJob creates a new screening job
+func (r *MongoScreeningRepository) CreateJob(ctx context.Context, job *entity.ScreeningJob) error {
+	if job.ID == "" {
+		job.ID = primitive.NewObjectID().Hex()
+	}
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = time.Now()
+	}
+	if job.Status == "" {
+		job.Status = entity.ScreeningJobStatusPending
+	}
+
+	if _, err := r.mongo.GetCollection(screeningJobsCollection).InsertOne(ctx, job); err != nil {
+		r.logger.Error("Failed to create screening job",
+			zap.String("jobID", job.ID),
+			zap.Error(err))
+		return fmt.Errorf("failed to create screening job: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateJob updates an existing screening job
+func (r *MongoScreeningRepository) UpdateJob(ctx context.Context, job *entity.ScreeningJob) error {
+	result, err := r.mongo.GetCollection(screeningJobsCollection).
+		UpdateOne(ctx, bson.M{"id": job.ID}, bson.M{"$set": job})
+	if err != nil {
+		r.logger.Error("Failed to update screening job",
+			zap.String("jobID", job.ID),
+			zap.Error(err))
+		return fmt.Errorf("failed to update screening job: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("screening job not found")
+	}
+
+	return nil
+}
+
+// GetJob retrieves a screening job by ID
+func (r *MongoScreeningRepository) GetJob(ctx context.Context, jobID string) (*entity.ScreeningJob, error) {
+	var job entity.ScreeningJob
+	err := r.mongo.GetCollection(screeningJobsCollection).FindOne(ctx, bson.M{"id": jobID}).Decode(&job)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get screening job",
+			zap.String("jobID", jobID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to get screening job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// FailUnfinishedJobs marks jobs created before createdBefore that are still
+// PENDING or RUNNING as FAILED with message
+func (r *MongoScreeningRepository) FailUnfinishedJobs(ctx context.Context, createdBefore time.Time, message string) (int64, error) {
+	filter := bson.M{
+		"status": bson.M{"$in": []entity.ScreeningJobStatus{
+			entity.ScreeningJobStatusPending,
+			entity.ScreeningJobStatusRunning,
+		}},
+		"created_at": bson.M{"$lt": createdBefore},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":       entity.ScreeningJobStatusFailed,
+		"error":        message,
+		"completed_at": time.Now(),
+	}}
+
+	result, err := r.mongo.GetCollection(screeningJobsCollection).UpdateMany(ctx, filter, update)
+	if err != nil {
+		r.logger.Error("Failed to fail unfinished screening jobs", zap.Error(err))
+		return 0, fmt.Errorf("failed to fail unfinished screening jobs: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// SaveResults stores a batch of per-address results
+func (r *MongoScreeningRepository) SaveResults(ctx context.Context, results []entity.AddressScreeningResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(results))
+	for _, result := range results {
+		docs = append(docs, result)
+	}
+
+	if _, err := r.mongo.GetCollection(screeningResultsCollection).InsertMany(ctx, docs); err != nil {
+		r.logger.Error("Failed to save screening results",
+			zap.String("jobID", results[0].JobID),
+			zap.Int("count", len(results)),
+			zap.Error(err))
+		return fmt.Errorf("failed to save screening results: %w", err)
+	}
+
+	return nil
+}
+
+// GetResults retrieves a page of results in input order
+func (r *MongoScreeningRepository) GetResults(ctx context.Context, jobID string, limit, offset int) ([]entity.AddressScreeningResult, error) {
+	findOptions := options.Find().
+		SetLimit(int64(limit)).
+		SetSkip(int64(offset)).
+		SetSort(bson.D{{Key: "index", Value: 1}})
+
+	cursor, err := r.mongo.GetCollection(screeningResultsCollection).Find(ctx, bson.M{"job_id": jobID}, findOptions)
+	if err != nil {
+		r.logger.Error("Failed to find screening results",
+			zap.String("jobID", jobID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to find screening results: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []entity.AddressScreeningResult
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode screening results: %w", err)
+	}
+
+	return results, nil
+}
+
+// IterateResults streams every result of a job in input order
+func (r *MongoScreeningRepository) IterateResults(ctx context.Context, jobID string, fn func(entity.AddressScreeningResult) error) error {
+	findOptions := options.Find().SetSort(bson.D{{Key: "index", Value: 1}})
+
+	cursor, err := r.mongo.GetCollection(screeningResultsCollection).Find(ctx, bson.M{"job_id": jobID}, findOptions)
+	if err != nil {
+		r.logger.Error("Failed to find screening results",
+			zap.String("jobID", jobID),
+			zap.Error(err))
+		return fmt.Errorf("failed to find screening results: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var result entity.AddressScreeningResult
+		if err := cursor.Decode(&result); err != nil {
+			return fmt.Errorf("failed to decode screening result: %w", err)
+		}
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
@@ -265,6 +267,50 @@ func (r *MongoSecurityRepository) ResolveSecurityAlert(ctx context.Context, aler
 	return nil
 }
 
+// CreateSecurityAlertIfNew inserts the alert only if no alert with its dedupe key
+// exists, in a single upsert so concurrent detections cannot both insert it
+func (r *MongoSecurityRepository) CreateSecurityAlertIfNew(ctx context.Context, alert *entity.SecurityAlert) (bool, error) {
+	if alert.DedupeKey == "" {
+		return false, fmt.Errorf("security alert has no dedupe key")
+	}
+	collection := r.mongo.GetCollection("security_alerts")
+
+	if alert.ID == "" {
+		alert.ID = primitive.NewObjectID().Hex()
+	}
+	now := time.Now()
+	alert.CreatedAt = now
+	alert.UpdatedAt = now
+	if alert.Status == "" {
+		alert.Status = entity.AlertStatusActive
+	}
+
+	filter := bson.M{"dedupe_key": alert.DedupeKey}
+	update := bson.M{"$setOnInsert": alert}
+	result, err := collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		r.logger.Error("Failed to create security alert",
+			zap.String("dedupeKey", alert.DedupeKey),
+			zap.String("type", string(alert.Type)),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to create security alert: %w", err)
+	}
+	if result.UpsertedCount == 0 {
+		return false, nil
+	}
+
+	r.logger.Info("Created security alert",
+		zap.String("alertID", alert.ID),
+		zap.String("type", string(alert.Type)),
+		zap.String("walletAddress", alert.WalletAddress),
+		zap.String("dedupeKey", alert.DedupeKey))
+
+	return true, nil
+}
+
 // GetComplianceReports retrieves compliance reports with filters
 func (r *MongoSecurityRepository) GetComplianceReports(ctx context.Context, filters map[string]interface{}) ([]entity.ComplianceReport, error) {
 	collection := r.mongo.GetCollection("compliance_reports")
@@ -411,85 +457,117 @@ func (r *MongoSecurityRepository) UpdateComplianceReport(ctx context.Context, re
 	return nil
 }
 
-// GenerateComplianceReport generates a new compliance report for a wallet
-func (r *MongoSecurityRepository) GenerateComplianceReport(ctx context.Context, walletAddress string, reportType entity.ComplianceReportType, timeRange entity.TimeRange) (*entity.ComplianceReport, error) {
-	// Create a new compliance report
-	report := &entity.ComplianceReport{
-		ID:            primitive.NewObjectID().Hex(),
-		WalletAddress: walletAddress,
-		ReportType:    reportType,
-		GeneratedAt:   time.Now(),
-		GeneratedBy:   "system", // This could be passed as parameter
-		TimeRange:     timeRange,
-		Status:        entity.ComplianceReportStatusDraft,
-		Summary: entity.ComplianceSummary{
-			TotalTransactions:    0,
-			TotalVolume:          "0",
-			TotalVolumeUSD:       0,
-			HighRiskTransactions: 0,
-			SuspiciousPatterns:   0,
-			RegulatoryViolations: 0,
-			OverallRiskScore:     0,
-			ComplianceScore:      100, // Start with perfect score
-		},
-		Findings:        []entity.ComplianceFinding{},
-		Recommendations: []string{},
-		RiskAssessment: entity.ComplianceRiskAssessment{
-			OverallRisk:        entity.RiskLevelLow,
-			GeographicRisk:     entity.RiskLevelLow,
-			TransactionRisk:    entity.RiskLevelLow,
-			CounterpartyRisk:   entity.RiskLevelLow,
-			ProductRisk:        entity.RiskLevelLow,
-			RiskFactors:        []string{},
-			MitigatingFactors:  []string{},
-			RecommendedActions: []string{},
-			NextReviewDate:     time.Now().AddDate(0, 3, 0), // 3 months from now
-		},
-		RegulatoryFlags: []entity.RegulatoryFlag{},
-		Metadata:        make(map[string]interface{}),
-	}
-
-	// TODO: Implement actual analysis logic here
-	// This would involve:
-	// 1. Fetching transaction data for the wallet in the time range
-	// 2. Analyzing patterns and risks
-	// 3. Checking against regulatory databases
-	// 4. Generating findings and recommendations
-	// For now, we'll create a basic report
-
-	// Add some basic analysis based on report type
-	switch reportType {
-	case entity.ComplianceReportTypeAML:
-		report.Recommendations = append(report.Recommendations,
-			"Continue monitoring for unusual transaction patterns",
-			"Verify source of funds for large transactions",
-			"Maintain transaction records for regulatory compliance")
-	case entity.ComplianceReportTypeKYC:
-		report.Recommendations = append(report.Recommendations,
-			"Verify customer identity documentation",
-			"Update customer information regularly",
-			"Monitor for changes in transaction behavior")
-	case entity.ComplianceReportTypeSAR:
-		report.Recommendations = append(report.Recommendations,
-			"File suspicious activity report if patterns persist",
-			"Enhanced monitoring recommended",
-			"Consider customer due diligence review")
-	case entity.ComplianceReportTypeRiskAssessment:
-		report.Recommendations = append(report.Recommendations,
-			"Regular risk assessment updates recommended",
-			"Monitor for changes in risk profile",
-			"Implement appropriate risk controls")
-	}
-
-	// Save the report
-	if err := r.CreateComplianceReport(ctx, report); err != nil {
-		return nil, fmt.Errorf("failed to save generated compliance report: %w", err)
-	}
-
-	r.logger.Info("Generated compliance report",
-		zap.String("reportID", report.ID),
-		zap.String("walletAddress", walletAddress),
-		zap.String("reportType", string(reportType)))
+// TransitionComplianceReport moves a report from one status to another. It only
+// succeeds if the report is still in the expected status, so concurrent
+// transitions cannot both win; the boolean reports whether it was applied.
+func (r *MongoSecurityRepository) TransitionComplianceReport(ctx context.Context, reportID string, from, to entity.ComplianceReportStatus) (bool, error) {
+	collection := r.mongo.GetCollection("compliance_reports")
+
+	filter := bson.M{"id": reportID, "status": from}
+	update := bson.M{"$set": bson.M{"status": to}}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		r.logger.Error("Failed to transition compliance report",
+			zap.String("reportID", reportID),
+			zap.String("from", string(from)),
+			zap.String("to", string(to)),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to transition compliance report: %w", err)
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+// UpdateComplianceReportNarrative replaces a report's narrative and metadata. Like
+// TransitionComplianceReport it only applies while the report is in the expected
+// status and still has the expected narrative, where nil expects none; the
+// boolean reports whether it was applied.
+func (r *MongoSecurityRepository) UpdateComplianceReportNarrative(ctx context.Context, reportID string, status entity.ComplianceReportStatus, expected, narrative *entity.ComplianceNarrative, metadata map[string]interface{}) (bool, error) {
+	collection := r.mongo.GetCollection("compliance_reports")
 
-	return report, nil
+	// A null narrative also matches reports without the field
+	filter := bson.M{"id": reportID, "status": status, "narrative": expected}
+	update := bson.M{"$set": bson.M{"narrative": narrative, "metadata": metadata}}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		r.logger.Error("Failed to update compliance report narrative",
+			zap.String("reportID", reportID),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to update compliance report narrative: %w", err)
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+// AppendComplianceReportEvent adds an entry to a report's history. Entries are never
+// updated or deleted; the unique (report_id, sequence) index rejects forks of the chain.
+func (r *MongoSecurityRepository) AppendComplianceReportEvent(ctx context.Context, event *entity.ComplianceReportEvent) error {
+	collection := r.mongo.GetCollection("compliance_report_history")
+
+	if event.ID == "" {
+		event.ID = primitive.NewObjectID().Hex()
+	}
+
+	if _, err := collection.InsertOne(ctx, event); err != nil {
+		r.logger.Error("Failed to append compliance report event",
+			zap.String("reportID", event.ReportID),
+			zap.Int("sequence", event.Sequence),
+			zap.Error(err))
+		return fmt.Errorf("failed to append compliance report event: %w", err)
+	}
+
+	return nil
+}
+
+// GetComplianceReportHistory retrieves a report's history in sequence order
+func (r *MongoSecurityRepository) GetComplianceReportHistory(ctx context.Context, reportID string) ([]entity.ComplianceReportEvent, error) {
+	collection := r.mongo.GetCollection("compliance_report_history")
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "sequence", Value: 1}})
+
+	cursor, err := collection.Find(ctx, bson.M{"report_id": reportID}, findOptions)
+	if err != nil {
+		r.logger.Error("Failed to find compliance report history",
+			zap.String("reportID", reportID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to find compliance report history: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var events []entity.ComplianceReportEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, fmt.Errorf("failed to decode compliance report history: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetDetectionCheckpoint retrieves a detection runner's checkpoint
+func (r *MongoSecurityRepository) GetDetectionCheckpoint(ctx context.Context, name string) (*entity.DetectionCheckpoint, error) {
+	var checkpoint entity.DetectionCheckpoint
+	err := r.mongo.GetCollection("detection_checkpoints").FindOne(ctx, bson.M{"name": name}).Decode(&checkpoint)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get detection checkpoint: %w", err)
+	}
+
+	return &checkpoint, nil
+}
+
+// SaveDetectionCheckpoint creates or replaces a detection runner's checkpoint
+func (r *MongoSecurityRepository) SaveDetectionCheckpoint(ctx context.Context, checkpoint *entity.DetectionCheckpoint) error {
+	_, err := r.mongo.GetCollection("detection_checkpoints").ReplaceOne(ctx,
+		bson.M{"name": checkpoint.Name}, checkpoint, options.Replace().SetUpsert(true))
+	if err != nil {
+		r.logger.Error("Failed to save detection checkpoint",
+			zap.String("name", checkpoint.Name),
+			zap.Error(err))
+		return fmt.Errorf("failed to save detection checkpoint: %w", err)
+	}
+
+	return nil
 }
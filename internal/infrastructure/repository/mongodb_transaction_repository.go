@@ -3,13 +3,18 @@ package repository
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"crypto-bubble-map-be/internal/domain/entity"
 	"crypto-bubble-map-be/internal/domain/repository"
 	"crypto-bubble-map-be/internal/infrastructure/database"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
 
@@ -43,6 +48,32 @@ func (r *MongoTransactionRepository) GetTransactionsByWallet(ctx context.Context
 	return transactions, nil
 }
 
+// GetTransactionsInRange retrieves a wallet's transactions within a time range, newest first
+func (r *MongoTransactionRepository) GetTransactionsInRange(ctx context.Context, walletAddress string, timeRange entity.TimeRange, limit int64) ([]entity.Transaction, error) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{"from": walletAddress},
+			{"to": walletAddress},
+		},
+		"crawled_at": bson.M{
+			"$gte": timeRange.Start,
+			"$lte": timeRange.End,
+		},
+	}
+
+	data, err := r.mongo.GetTransactions(ctx, filter, limit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions in range: %w", err)
+	}
+
+	transactions := make([]entity.Transaction, 0, len(data))
+	for _, record := range data {
+		transactions = append(transactions, r.convertToTransaction(record))
+	}
+
+	return transactions, nil
+}
+
 // GetPairwiseTransactions retrieves transactions between two specific wallets
 func (r *MongoTransactionRepository) GetPairwiseTransactions(ctx context.Context, walletA, walletB string, limit, offset int64, filters *entity.TransactionFilters) (*entity.PairwiseTransactionResult, error) {
 	data, err := r.mongo.GetPairwiseTransactions(ctx, walletA, walletB, limit, offset)
@@ -108,13 +139,82 @@ func (r *MongoTransactionRepository) GetTransaction(ctx context.Context, hash st
 	}
 
 	if len(data) == 0 {
-		return nil, fmt.Errorf("transaction not found")
+		return nil, apperrors.NewAppError(apperrors.ErrCodeTransactionNotFound, "Transaction not found", hash)
 	}
 
 	tx := r.convertToTransaction(data[0])
 	return &tx, nil
 }
 
+// GetTransactionsAfter retrieves transactions crawled after cursor, oldest first
+func (r *MongoTransactionRepository) GetTransactionsAfter(ctx context.Context, cursor entity.TransactionCursor, limit int64) ([]entity.Transaction, error) {
+	filter := bson.M{
+		"$or": []bson.M{
+			{"crawled_at": bson.M{"$gt": cursor.CrawledAt}},
+			{"crawled_at": cursor.CrawledAt, "_id": bson.M{"$gt": cursor.ID}},
+		},
+	}
+	findOptions := options.Find().
+		SetLimit(limit).
+		SetSort(bson.D{{Key: "crawled_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	mongoCursor, err := r.mongo.GetCollection("transactions").Find(ctx, filter, findOptions)
+	if err != nil {
+		r.logger.Error("Failed to find transactions after cursor", zap.Error(err))
+		return nil, fmt.Errorf("failed to get transactions after cursor: %w", err)
+	}
+	defer mongoCursor.Close(ctx)
+
+	var data []bson.M
+	if err := mongoCursor.All(ctx, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode transactions: %w", err)
+	}
+
+	transactions := make([]entity.Transaction, 0, len(data))
+	for _, record := range data {
+		tx := r.convertToTransaction(record)
+		if id, ok := record["_id"].(primitive.ObjectID); ok {
+			tx.ID = id
+		}
+		transactions = append(transactions, tx)
+	}
+
+	return transactions, nil
+}
+
+// GetTransactionsInBlocks retrieves every transaction in the given blocks, ordered
+// by block and position within the block
+func (r *MongoTransactionRepository) GetTransactionsInBlocks(ctx context.Context, blockNumbers []string) ([]entity.Transaction, error) {
+	if len(blockNumbers) == 0 {
+		return nil, nil
+	}
+
+	filter := bson.M{"block_number": bson.M{"$in": blockNumbers}}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "block_number", Value: 1}, {Key: "transaction_index", Value: 1}})
+
+	mongoCursor, err := r.mongo.GetCollection("transactions").Find(ctx, filter, findOptions)
+	if err != nil {
+		r.logger.Error("Failed to find transactions in blocks",
+			zap.Int("blocks", len(blockNumbers)),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to get transactions in blocks: %w", err)
+	}
+	defer mongoCursor.Close(ctx)
+
+	var data []bson.M
+	if err := mongoCursor.All(ctx, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode transactions: %w", err)
+	}
+
+	transactions := make([]entity.Transaction, 0, len(data))
+	for _, record := range data {
+		transactions = append(transactions, r.convertToTransaction(record))
+	}
+
+	return transactions, nil
+}
+
 // GetMoneyFlowData retrieves money flow analysis data
 func (r *MongoTransactionRepository) GetMoneyFlowData(ctx context.Context, walletAddress string, filters *entity.MoneyFlowFilters) (*entity.MoneyFlowData, error) {
 	flowType := "BOTH"
@@ -190,22 +290,144 @@ func (r *MongoTransactionRepository) GetMoneyFlowData(ctx context.Context, walle
 	return result, nil
 }
 
-// SearchTransactions searches transactions by hash or address
+// SearchTransactions finds transactions by hash, hash or address prefix, address
+// or block number, depending on what the query looks like. Prefixes are
+// matched with anchored, case-sensitive patterns so the hash, from and to
+// indexes serve every lookup.
 func (r *MongoTransactionRepository) SearchTransactions(ctx context.Context, query string, limit int64) ([]entity.Transaction, error) {
-	data, err := r.mongo.SearchTransactions(ctx, query, limit)
+	query = strings.TrimSpace(query)
+	normalized := entity.NormalizeAddress(query)
+	sort := bson.D{{Key: "crawled_at", Value: -1}}
+
+	var filter bson.M
+	switch entity.DetectSearchKind(query) {
+	case entity.SearchQueryKindTransactionHash:
+		filter = bson.M{"hash": bson.M{"$in": distinctStrings(normalized, query)}}
+	case entity.SearchQueryKindAddress:
+		addresses := distinctStrings(normalized, query)
+		filter = bson.M{"$or": []bson.M{
+			{"from": bson.M{"$in": addresses}},
+			{"to": bson.M{"$in": addresses}},
+		}}
+	case entity.SearchQueryKindHexPrefix:
+		prefix := primitive.Regex{Pattern: "^" + regexp.QuoteMeta(normalized)}
+		filter = bson.M{"$or": []bson.M{
+			{"hash": prefix},
+			{"from": prefix},
+			{"to": prefix},
+		}}
+	case entity.SearchQueryKindBlockNumber:
+		filter = bson.M{"block_number": query}
+		sort = bson.D{{Key: "transaction_index", Value: 1}}
+	default:
+		return []entity.Transaction{}, nil
+	}
+
+	findOptions := options.Find().
+		SetLimit(limit).
+		SetSort(sort)
+
+	mongoCursor, err := r.mongo.GetCollection("transactions").Find(ctx, filter, findOptions)
 	if err != nil {
+		r.logger.Error("Failed to search transactions",
+			zap.String("query", query),
+			zap.Error(err))
 		return nil, fmt.Errorf("failed to search transactions: %w", err)
 	}
+	defer mongoCursor.Close(ctx)
 
-	var transactions []entity.Transaction
+	var data []bson.M
+	if err := mongoCursor.All(ctx, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode transactions: %w", err)
+	}
+
+	transactions := make([]entity.Transaction, 0, len(data))
 	for _, record := range data {
-		tx := r.convertToTransaction(record)
-		transactions = append(transactions, tx)
+		transactions = append(transactions, r.convertToTransaction(record))
 	}
 
 	return transactions, nil
 }
 
+// SearchTokens finds tokens by contract address, or by symbol or name prefix.
+// Symbols are matched upper-cased and names as typed, both anchored so the
+// token transfer indexes serve the lookup.
+func (r *MongoTransactionRepository) SearchTokens(ctx context.Context, query string, limit int64) ([]entity.TokenMatch, error) {
+	query = strings.TrimSpace(query)
+
+	var match bson.M
+	switch entity.DetectSearchKind(query) {
+	case entity.SearchQueryKindAddress:
+		match = bson.M{"token_address": bson.M{"$in": distinctStrings(entity.NormalizeAddress(query), query)}}
+	case entity.SearchQueryKindText, entity.SearchQueryKindENSName:
+		match = bson.M{"$or": []bson.M{
+			{"token_symbol": primitive.Regex{Pattern: "^" + regexp.QuoteMeta(strings.ToUpper(query))}},
+			{"token_name": primitive.Regex{Pattern: "^" + regexp.QuoteMeta(query)}},
+		}}
+	default:
+		return []entity.TokenMatch{}, nil
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{
+			"$group": bson.M{
+				"_id":               "$token_address",
+				"symbol":            bson.M{"$first": "$token_symbol"},
+				"name":              bson.M{"$first": "$token_name"},
+				"transaction_count": bson.M{"$sum": 1},
+			},
+		},
+		{"$sort": bson.M{"transaction_count": -1}},
+		{"$limit": limit},
+	}
+
+	cursor, err := r.mongo.GetCollection("token_transfers").Aggregate(ctx, pipeline)
+	if err != nil {
+		r.logger.Error("Failed to search tokens",
+			zap.String("query", query),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to search tokens: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var data []bson.M
+	if err := cursor.All(ctx, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode tokens: %w", err)
+	}
+
+	tokens := make([]entity.TokenMatch, 0, len(data))
+	for _, record := range data {
+		token := entity.TokenMatch{
+			Address:          getStringValue(record, "_id"),
+			Symbol:           getStringValue(record, "symbol"),
+			Name:             getStringValue(record, "name"),
+			TransactionCount: getInt64Value(record, "transaction_count"),
+		}
+		token.RelevanceScore, token.MatchedField = tokenRelevance(token, query)
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+// tokenRelevance scores a token against the query: an exact contract address or
+// symbol scores highest, then symbol and name prefixes by how much of the
+// symbol or name the query covers
+func tokenRelevance(token entity.TokenMatch, query string) (float64, string) {
+	switch {
+	case strings.EqualFold(token.Address, query):
+		return 1, "address"
+	case strings.EqualFold(token.Symbol, query):
+		return 0.95, "symbol"
+	case token.Symbol != "" && strings.HasPrefix(token.Symbol, strings.ToUpper(query)):
+		return 0.6 + 0.3*float64(len(query))/float64(len(token.Symbol)), "symbol"
+	case token.Name != "":
+		return 0.5 + 0.3*float64(len(query))/float64(len(token.Name)), "name"
+	}
+	return 0.5, "name"
+}
+
 // GetTransactionStats retrieves transaction statistics
 func (r *MongoTransactionRepository) GetTransactionStats(ctx context.Context, timeRange *entity.TimeRange) (map[string]interface{}, error) {
 	var mongoTimeRange *database.TimeRange
@@ -275,12 +497,17 @@ func (r *MongoTransactionRepository) convertToTransaction(record bson.M) entity.
 		To:          getStringPointer(record, "to"),
 		Value:       getStringValue(record, "value"),
 		Timestamp:   getTimeValue(record, "crawled_at"),
+		CrawledAt:   getTimeValue(record, "crawled_at"),
 		BlockNumber: getStringValue(record, "block_number"),
 		GasUsed:     uint64(getInt64Value(record, "gas_used")),
 		GasPrice:    getStringValue(record, "gas_price"),
 		Network:     getStringValue(record, "network"),
+		Data:        getStringValue(record, "data"),
 		TxStatus:    entity.TransactionStatusSuccess, // Default
 		RiskLevel:   entity.RiskLevelLow,             // Default
+
+		TransactionIndex: uint(getInt64Value(record, "transaction_index")),
+		Logs:             getTransactionLogs(record),
 	}
 
 	// Calculate gas fee
@@ -298,6 +525,28 @@ func (r *MongoTransactionRepository) convertToTransaction(record bson.M) entity.
 	return tx
 }
 
+// getTransactionLogs extracts a transaction's receipt logs
+func getTransactionLogs(record bson.M) []entity.TransactionLog {
+	raw, ok := record["logs"].(primitive.A)
+	if !ok {
+		return nil
+	}
+
+	logs := make([]entity.TransactionLog, 0, len(raw))
+	for _, item := range raw {
+		entry, ok := item.(bson.M)
+		if !ok {
+			continue
+		}
+		logs = append(logs, entity.TransactionLog{
+			Address: getStringValue(entry, "address"),
+			Topics:  getStringSliceValue(entry, "topics"),
+			Data:    getStringValue(entry, "data"),
+		})
+	}
+	return logs
+}
+
 func (r *MongoTransactionRepository) convertToPairwiseTransaction(record bson.M, walletA, walletB string) entity.PairwiseTransaction {
 	from := getStringValue(record, "from")
 	to := getStringValue(record, "to")
@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"crypto-bubble-map-be/internal/domain/entity"
 	"crypto-bubble-map-be/internal/domain/repository"
 	"crypto-bubble-map-be/internal/infrastructure/database"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap"
 )
 
@@ -29,7 +32,8 @@ func NewNeo4jWalletRepository(neo4j *database.Neo4jClient, logger *zap.Logger) r
 
 // GetWalletNetwork retrieves wallet network data
 func (r *Neo4jWalletRepository) GetWalletNetwork(ctx context.Context, input *entity.WalletNetworkInput) (*entity.WalletNetwork, error) {
-	data, err := r.neo4j.GetWalletNetwork(ctx, input.Address, input.Depth)
+	address := entity.NormalizeAddress(input.Address)
+	data, err := r.neo4j.GetWalletNetwork(ctx, address, input.Depth)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get wallet network: %w", err)
 	}
@@ -39,7 +43,7 @@ func (r *Neo4jWalletRepository) GetWalletNetwork(ctx context.Context, input *ent
 		Nodes: []entity.Wallet{},
 		Links: []entity.WalletConnection{},
 		Metadata: entity.NetworkMetadata{
-			CenterWallet: input.Address,
+			CenterWallet: address,
 			MaxDepth:     input.Depth,
 			GeneratedAt:  time.Now(),
 		},
@@ -102,18 +106,26 @@ func (r *Neo4jWalletRepository) GetWalletNetwork(ctx context.Context, input *ent
 
 // GetWallet retrieves a single wallet by address
 func (r *Neo4jWalletRepository) GetWallet(ctx context.Context, address string) (*entity.Wallet, error) {
+	address = entity.NormalizeAddress(address)
 	data, err := r.neo4j.GetWalletInfo(ctx, address)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get wallet info: %w", err)
 	}
+	if data == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeWalletNotFound, "Wallet not found", address)
+	}
 
 	wallet := &entity.Wallet{
 		ID:                  address,
 		Address:             address,
+		Label:               getStringPointer(data, "label"),
 		WalletType:          entity.WalletType(getStringValue(data, "wallet_type")),
 		RiskLevel:           entity.RiskLevel(getStringValue(data, "risk_level")),
 		TransactionCount:    getInt64Value(data, "transaction_count"),
 		Balance:             getStringPointer(data, "balance"),
+		IsContract:          getBoolValue(data, "is_contract"),
+		LastClassified:      getTimeValue(data, "last_classified"),
+		ConnectionCount:     getIntValue(data, "connection_count"),
 		FirstSeen:           getTimeValue(data, "first_seen"),
 		LastSeen:            getTimeValue(data, "last_seen"),
 		Tags:                getStringSliceValue(data, "tags"),
@@ -178,41 +190,213 @@ func (r *Neo4jWalletRepository) GetWalletRankings(ctx context.Context, category
 	return result, nil
 }
 
-// SearchWallets searches for wallets
+// SearchWallets searches wallets by address, address prefix, ENS name or label
+// text, depending on what the query looks like. Every lookup is served by an
+// index; full-text scores are scaled below exact matches.
 func (r *Neo4jWalletRepository) SearchWallets(ctx context.Context, query string, limit int) ([]entity.WalletSearchResult, error) {
-	data, err := r.neo4j.SearchWallets(ctx, query, limit)
+	query = strings.TrimSpace(query)
+	normalized := entity.NormalizeAddress(query)
+
+	kind := entity.DetectSearchKind(query)
+
+	var (
+		data         []map[string]interface{}
+		matchedField string
+		err          error
+	)
+	switch kind {
+	case entity.SearchQueryKindAddress:
+		data, err = r.neo4j.FindWalletsByAddress(ctx, distinctStrings(normalized, query))
+		matchedField = "address"
+	case entity.SearchQueryKindHexPrefix:
+		if len(normalized) >= addressLength {
+			return []entity.WalletSearchResult{}, nil
+		}
+		data, err = r.neo4j.SearchWalletsByAddressPrefix(ctx, distinctStrings(normalized, query), limit)
+		// Longer prefixes are more specific, so they rank closer to an exact match
+		for _, record := range data {
+			record["score"] = 0.5 + 0.4*float64(len(normalized))/float64(addressLength)
+		}
+		matchedField = "address"
+	case entity.SearchQueryKindENSName:
+		data, err = r.neo4j.FindWalletsByENSName(ctx, strings.ToLower(query))
+		matchedField = "ens_name"
+	case entity.SearchQueryKindText:
+	default:
+		// Transaction hashes and block numbers never match a wallet
+		return []entity.WalletSearchResult{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to search wallets: %w", err)
+	}
+
+	results := make([]entity.WalletSearchResult, 0, len(data))
+	seen := make(map[string]bool)
+	for _, record := range data {
+		result := toWalletSearchResult(record)
+		result.MatchedField = matchedField
+		seen[result.Address] = true
+		results = append(results, result)
+	}
+
+	// Names and free text also go through the full-text index over labels, ENS
+	// names and tags
+	if (kind == entity.SearchQueryKindText || kind == entity.SearchQueryKindENSName) && len(results) < limit {
+		matches, err := r.searchWalletsFullText(ctx, query, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			if !seen[match.Address] && len(results) < limit {
+				seen[match.Address] = true
+				results = append(results, match)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// fullTextScoreScale caps full-text relevance below exact matches
+const fullTextScoreScale = 0.85
+
+// addressLength is the length of an EVM address including the 0x prefix
+const addressLength = 42
+
+// searchWalletsFullText queries the wallet full-text index, scaling the index's
+// scores relative to the best hit
+func (r *Neo4jWalletRepository) searchWalletsFullText(ctx context.Context, query string, limit int) ([]entity.WalletSearchResult, error) {
+	luceneQuery := fullTextQuery(query)
+	if luceneQuery == "" {
+		return []entity.WalletSearchResult{}, nil
+	}
+
+	data, err := r.neo4j.SearchWalletsFullText(ctx, luceneQuery, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search wallets: %w", err)
 	}
 
-	var results []entity.WalletSearchResult
+	results := make([]entity.WalletSearchResult, 0, len(data))
+	var best float64
+	for _, record := range data {
+		if score := getFloat64Value(record, "score"); score > best {
+			best = score
+		}
+	}
 	for _, record := range data {
-		result := entity.WalletSearchResult{
-			Address:          getStringValue(record, "address"),
-			Label:            getStringPointer(record, "label"),
-			Tags:             getStringSliceValue(record, "tags"),
-			TransactionCount: getInt64Value(record, "transaction_count"),
-			Balance:          getStringPointer(record, "balance"),
-			RelevanceScore:   getFloat64Value(record, "confidence_score"),
+		result := toWalletSearchResult(record)
+		if best > 0 {
+			result.RelevanceScore = fullTextScoreScale * result.RelevanceScore / best
 		}
+		result.MatchedField = fullTextMatchedField(record, query)
 		results = append(results, result)
 	}
 
 	return results, nil
 }
 
+func toWalletSearchResult(record map[string]interface{}) entity.WalletSearchResult {
+	result := entity.WalletSearchResult{
+		Address:          getStringValue(record, "address"),
+		Label:            getStringPointer(record, "label"),
+		ENSName:          getStringPointer(record, "ens_name"),
+		Tags:             getStringSliceValue(record, "tags"),
+		WalletType:       entity.WalletType(getStringValue(record, "wallet_type")),
+		RiskLevel:        entity.RiskLevel(getStringValue(record, "risk_level")),
+		TransactionCount: getInt64Value(record, "transaction_count"),
+		Balance:          getStringPointer(record, "balance"),
+		RelevanceScore:   getFloat64Value(record, "score"),
+		LabelEntity:      getStringPointer(record, "label_entity"),
+		LabelSource:      getStringPointer(record, "label_source"),
+	}
+	if category := getStringValue(record, "label_category"); category != "" {
+		labelCategory := entity.LabelCategory(category)
+		result.LabelCategory = &labelCategory
+	}
+	if confidence, ok := record["label_confidence"].(float64); ok {
+		result.LabelConfidence = &confidence
+	}
+	return result
+}
+
+// fullTextQuery builds a Lucene query requiring every term of the user's query,
+// each as a whole word or a word prefix. Lucene syntax in the query is escaped.
+func fullTextQuery(query string) string {
+	const maxTerms = 8
+
+	var clauses []string
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if len(clauses) == maxTerms {
+			break
+		}
+		escaped := luceneEscaper.Replace(term)
+		clauses = append(clauses, fmt.Sprintf("+(%s %s*)", escaped, escaped))
+	}
+	return strings.Join(clauses, " ")
+}
+
+var luceneEscaper = strings.NewReplacer(
+	`\`, `\\`, `+`, `\+`, `-`, `\-`, `!`, `\!`, `(`, `\(`, `)`, `\)`, `:`, `\:`,
+	`^`, `\^`, `[`, `\[`, `]`, `\]`, `"`, `\"`, `{`, `\{`, `}`, `\}`, `~`, `\~`,
+	`*`, `\*`, `?`, `\?`, `|`, `\|`, `&`, `\&`, `/`, `\/`,
+)
+
+// fullTextMatchedField names the indexed property that best explains a full-text hit
+func fullTextMatchedField(record map[string]interface{}, query string) string {
+	terms := strings.Fields(strings.ToLower(query))
+	for _, field := range []string{"label", "label_entity", "ens_name"} {
+		value := strings.ToLower(getStringValue(record, field))
+		if value == "" {
+			continue
+		}
+		for _, term := range terms {
+			if strings.Contains(value, term) {
+				return field
+			}
+		}
+	}
+	return "tags"
+}
+
+func distinctStrings(values ...string) []string {
+	result := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
 // GetRiskScore retrieves risk score for a wallet
 func (r *Neo4jWalletRepository) GetRiskScore(ctx context.Context, address string) (*entity.RiskScore, error) {
 	// This would typically come from a separate risk scoring service
-	// For now, return a placeholder
-	return &entity.RiskScore{
+	// For now, return a placeholder raised by approved community scam reports
+	score := &entity.RiskScore{
 		Address:     address,
 		TotalScore:  50,
 		RiskLevel:   entity.RiskLevelMedium,
 		Factors:     entity.RiskFactors{},
 		Flags:       []string{},
 		LastUpdated: time.Now(),
-	}, nil
+	}
+
+	reports, err := r.neo4j.GetWalletScamReports(ctx, entity.NormalizeAddress(address))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk score: %w", err)
+	}
+	if reports > 0 {
+		score.Factors.Scam = entity.ScamReportRiskFactor(reports)
+		score.Flags = append(score.Flags, "COMMUNITY_SCAM_REPORT")
+		if score.Factors.Scam > score.TotalScore {
+			score.TotalScore = score.Factors.Scam
+			score.RiskLevel = entity.ScoreToRiskLevel(score.TotalScore)
+		}
+	}
+
+	return score, nil
 }
 
 // GetRiskScores retrieves risk scores for multiple wallets
@@ -236,12 +420,79 @@ func (r *Neo4jWalletRepository) UpdateRiskScore(ctx context.Context, address str
 	return r.GetRiskScore(ctx, address)
 }
 
+// UpdateWalletType sets the type of the known wallets among addresses
+func (r *Neo4jWalletRepository) UpdateWalletType(ctx context.Context, addresses []string, walletType entity.WalletType) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	normalized := make([]string, len(addresses))
+	for i, address := range addresses {
+		normalized[i] = entity.NormalizeAddress(address)
+	}
+	updated, err := r.neo4j.SetWalletType(ctx, normalized, string(walletType))
+	if err != nil {
+		return fmt.Errorf("failed to update wallet type: %w", err)
+	}
+
+	r.logger.Debug("Updated wallet type",
+		zap.String("walletType", string(walletType)),
+		zap.Int("addresses", len(addresses)),
+		zap.Int64("updated", updated))
+	return nil
+}
+
+// UpdateClassification stores a classifier result on the wallet
+func (r *Neo4jWalletRepository) UpdateClassification(ctx context.Context, classification *entity.WalletClassification) error {
+	err := r.neo4j.SetWalletClassification(ctx, entity.NormalizeAddress(classification.Address), string(classification.WalletType),
+		classification.Confidence, classification.ClassifiedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update wallet classification: %w", err)
+	}
+	return nil
+}
+
+// UpdateAttribution stores an address's resolved labels on the wallet
+func (r *Neo4jWalletRepository) UpdateAttribution(ctx context.Context, attribution *entity.AddressAttribution) error {
+	var (
+		label, labelEntity *string
+		category, source   string
+		confidence         float64
+	)
+	if winner := attribution.Label; winner != nil {
+		label = &winner.Name
+		labelEntity = winner.Entity
+		category = string(winner.Category)
+		source = winner.Source
+		confidence = winner.Confidence
+	}
+
+	err := r.neo4j.SetWalletAttribution(ctx, entity.NormalizeAddress(attribution.Address), label, labelEntity, category, source, confidence,
+		attribution.Tags, attribution.AssociatedExchanges, attribution.AssociatedProtocols)
+	if err != nil {
+		return fmt.Errorf("failed to update wallet attribution: %w", err)
+	}
+	return nil
+}
+
+// UpdateScamReports stores the number of approved community scam reports on the wallet
+func (r *Neo4jWalletRepository) UpdateScamReports(ctx context.Context, address string, reports int) error {
+	if err := r.neo4j.SetWalletScamReports(ctx, entity.NormalizeAddress(address), reports); err != nil {
+		return fmt.Errorf("failed to update wallet scam reports: %w", err)
+	}
+	return nil
+}
+
 // GetWalletStats retrieves wallet statistics
 func (r *Neo4jWalletRepository) GetWalletStats(ctx context.Context, address string) (*entity.WalletStats, error) {
+	address = entity.NormalizeAddress(address)
 	data, err := r.neo4j.GetWalletInfo(ctx, address)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get wallet stats: %w", err)
 	}
+	if data == nil {
+		return nil, apperrors.NewAppError(apperrors.ErrCodeWalletNotFound, "Wallet not found", address)
+	}
 
 	stats := &entity.WalletStats{
 		Address:          address,
@@ -274,6 +525,8 @@ func getInt64Value(record map[string]interface{}, key string) int64 {
 		switch v := val.(type) {
 		case int64:
 			return v
+		case int32:
+			return int64(v)
 		case int:
 			return int64(v)
 		case float64:
@@ -291,6 +544,15 @@ func getIntValue(record map[string]interface{}, key string) int {
 	return int(getInt64Value(record, key))
 }
 
+func getBoolValue(record map[string]interface{}, key string) bool {
+	if val, ok := record[key]; ok && val != nil {
+		if b, ok := val.(bool); ok {
+			return b
+		}
+	}
+	return false
+}
+
 func getFloat64Value(record map[string]interface{}, key string) float64 {
 	if val, ok := record[key]; ok && val != nil {
 		switch v := val.(type) {
@@ -314,6 +576,9 @@ func getTimeValue(record map[string]interface{}, key string) time.Time {
 		if t, ok := val.(time.Time); ok {
 			return t
 		}
+		if dt, ok := val.(primitive.DateTime); ok {
+			return dt.Time()
+		}
 		if str, ok := val.(string); ok {
 			if t, err := time.Parse(time.RFC3339, str); err == nil {
 				return t
@@ -325,6 +590,10 @@ func getTimeValue(record map[string]interface{}, key string) time.Time {
 
 func getStringSliceValue(record map[string]interface{}, key string) []string {
 	if val, ok := record[key]; ok && val != nil {
+		// MongoDB documents decode arrays as primitive.A
+		if slice, ok := val.(primitive.A); ok {
+			val = []interface{}(slice)
+		}
 		if slice, ok := val.([]interface{}); ok {
 			var result []string
 			for _, item := range slice {
@@ -8,6 +8,7 @@ import (
 	"crypto-bubble-map-be/internal/domain/entity"
 	"crypto-bubble-map-be/internal/domain/repository"
 	"crypto-bubble-map-be/internal/infrastructure/database"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
 	"crypto-bubble-map-be/internal/infrastructure/external"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -134,7 +135,7 @@ func (r *NetworkRepository) GetNetwork(ctx context.Context, networkID string) (*
 		}
 	}
 
-	return nil, fmt.Errorf("network not found: %s", networkID)
+	return nil, apperrors.NewAppError(apperrors.ErrCodeNetworkNotSupported, "Network not supported", networkID)
 }
 
 // GetNetworkStats retrieves statistics for a specific network
@@ -568,7 +569,7 @@ func (r *NetworkRepository) GetDashboardStats(ctx context.Context, networkID *st
 		activity := entity.NetworkActivity{
 			Timestamp:        date,
 			TransactionCount: getRandomInt64(10000, 100000),
-			Volume:           fmt.Sprintf("%.0f", getRandomFloat64(1000000, 10000000)),
+			Volume:           fmt.Sprintf("%.0f", *getRandomFloat64(1000000, 10000000)),
 			UniqueWallets:    getRandomInt64(1000, 10000),
 		}
 		networkActivity = append(networkActivity, activity)
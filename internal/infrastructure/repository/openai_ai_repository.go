@@ -13,39 +13,77 @@ import (
 	"crypto-bubble-map-be/internal/domain/entity"
 	"crypto-bubble-map-be/internal/domain/repository"
 	"crypto-bubble-map-be/internal/infrastructure/config"
+	"crypto-bubble-map-be/internal/infrastructure/resilience"
 
 	"go.uber.org/zap"
 )
 
-// OpenAIRepository implements AIRepository using OpenAI API
+// OpenAIRepository implements AIRepository using the OpenAI chat completions API.
+// Questions are answered with function calling against our own repositories so
+// the answer and its sources come from stored records.
 type OpenAIRepository struct {
 	config     *config.ExternalConfig
 	logger     *zap.Logger
 	httpClient *http.Client
+	// streamClient has no overall timeout; streamed answers are bounded by the
+	// caller's context instead
+	streamClient *http.Client
+	tools        *aiTools
 }
 
-// NewOpenAIRepository creates a new OpenAI AI repository
-func NewOpenAIRepository(cfg *config.ExternalConfig, logger *zap.Logger) repository.AIRepository {
+// NewOpenAIRepository creates a new OpenAI AI repository. Completions and
+// streams share the OpenAI circuit breaker from breakers; a stream is only
+// retried until its response headers arrive.
+func NewOpenAIRepository(
+	cfg *config.ExternalConfig,
+	walletRepo repository.WalletRepository,
+	transactionRepo repository.TransactionRepository,
+	securityRepo repository.SecurityRepository,
+	breakers *resilience.Registry,
+	logger *zap.Logger,
+) repository.AIRepository {
+	transport := resilience.NewTransport(nil, breakers.Breaker(resilience.DependencyOpenAI, resilience.IsHTTPFailure))
 	return &OpenAIRepository{
 		config: cfg,
 		logger: logger,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: transport,
+		},
+		streamClient: &http.Client{Transport: transport},
+		tools: &aiTools{
+			walletRepo:      walletRepo,
+			transactionRepo: transactionRepo,
+			securityRepo:    securityRepo,
 		},
 	}
 }
 
+// defaultOpenAIBaseURL is the hosted API, which cannot be used without a key
+const defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
 // OpenAI API structures
 type OpenAIRequest struct {
 	Model       string    `json:"model"`
 	Messages    []Message `json:"messages"`
 	MaxTokens   int       `json:"max_tokens,omitempty"`
 	Temperature float64   `json:"temperature,omitempty"`
+	Tools       []Tool    `json:"tools,omitempty"`
+	ToolChoice  string    `json:"tool_choice,omitempty"`
+
+	Stream        bool           `json:"stream,omitempty"`
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
+}
+
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
 }
 
 type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
 }
 
 type OpenAIResponse struct {
@@ -69,63 +107,178 @@ type Usage struct {
 	TotalTokens      int `json:"total_tokens"`
 }
 
-// AskAI sends a question to OpenAI and returns a structured response
+// followUpMarker separates the answer text from the JSON follow-up block so the
+// answer can be streamed as it is written and the block parsed afterwards
+const followUpMarker = "<<<FOLLOW_UP>>>"
+
+// assistantFollowUp is the JSON block the model writes after its answer
+type assistantFollowUp struct {
+	RelatedQuestions []string `json:"related_questions"`
+	ActionItems      []string `json:"action_items"`
+}
+
+// assistantReply is the model's answer split into its parts
+type assistantReply struct {
+	Answer string
+	assistantFollowUp
+}
+
+// AskAI answers a single question with no conversation history
 func (r *OpenAIRepository) AskAI(ctx context.Context, question string, aiContext *entity.AIContext, walletAddress *string) (*entity.AIResponse, error) {
-	if r.config.OpenAIAPIKey == "" {
+	return r.ChatAI(ctx, &entity.AIChatRequest{
+		Question:      question,
+		Context:       aiContext,
+		WalletAddress: walletAddress,
+	}, nil)
+}
+
+// ChatAI answers a question after the conversation history, letting the model call
+// tools that read wallet data for up to OpenAIMaxToolRounds rounds before it must
+// answer. When onDelta is set the answer is streamed to it as it is generated.
+func (r *OpenAIRepository) ChatAI(ctx context.Context, chat *entity.AIChatRequest, onDelta func(delta string)) (*entity.AIResponse, error) {
+	if r.config.OpenAIAPIKey == "" && strings.TrimRight(r.config.OpenAIBaseURL, "/") == defaultOpenAIBaseURL {
 		r.logger.Warn("OpenAI API key not configured, falling back to mock response")
-		return r.generateMockResponse(question, walletAddress), nil
+		response := r.generateMockResponse(chat.Question, chat.WalletAddress)
+		if onDelta != nil {
+			onDelta(response.Answer)
+		}
+		return response, nil
 	}
 
-	// Build system prompt based on context
-	systemPrompt := r.buildSystemPrompt(aiContext, walletAddress)
+	maxRounds := r.config.OpenAIMaxToolRounds
+	if maxRounds <= 0 {
+		maxRounds = 5
+	}
 
-	// Create OpenAI request
-	openAIReq := OpenAIRequest{
-		Model: r.config.OpenAIModel,
-		Messages: []Message{
-			{
-				Role:    "system",
-				Content: systemPrompt,
-			},
-			{
-				Role:    "user",
-				Content: question,
-			},
+	messages := []Message{
+		{
+			Role:    "system",
+			Content: r.buildSystemPrompt(chat.Context, chat.WalletAddress),
 		},
-		MaxTokens:   1000,
-		Temperature: 0.7,
 	}
+	for _, m := range chat.History {
+		role := "user"
+		if m.Role == entity.AIMessageRoleAssistant {
+			role = "assistant"
+		}
+		messages = append(messages, Message{Role: role, Content: m.Content})
+	}
+	messages = append(messages, Message{Role: "user", Content: chat.Question})
+
+	var (
+		reply      *OpenAIResponse
+		tokensUsed int
+		sources    []string
+		toolsUsed  = make(map[string]bool)
+		seen       = make(map[string]bool)
+		failures   int
+		exhausted  bool
+	)
+
+	for round := 0; ; round++ {
+		exhausted = round >= maxRounds
+		req := OpenAIRequest{
+			Model:       r.config.OpenAIModel,
+			Messages:    messages,
+			MaxTokens:   1000,
+			Temperature: 0.2,
+			Tools:       r.tools.definitions(),
+		}
+		if exhausted {
+			// Out of tool rounds: the model has to answer from what it has gathered
+			req.ToolChoice = "none"
+		}
 
-	// Make API call
-	openAIResp, err := r.callOpenAI(ctx, openAIReq)
-	if err != nil {
-		r.logger.Error("Failed to call OpenAI API", zap.Error(err))
-		// Fall back to mock response on error
-		return r.generateMockResponse(question, walletAddress), nil
+		var (
+			resp *OpenAIResponse
+			err  error
+		)
+		if onDelta != nil {
+			resp, err = r.callOpenAIStream(ctx, req, onDelta)
+		} else {
+			resp, err = r.callOpenAI(ctx, req)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			r.logger.Error("Failed to call OpenAI API", zap.Error(err))
+			if onDelta != nil {
+				// Part of an answer may already have been streamed; a canned
+				// response would contradict it
+				return nil, fmt.Errorf("failed to generate answer: %w", err)
+			}
+			// Fall back to mock response on error
+			return r.generateMockResponse(chat.Question, chat.WalletAddress), nil
+		}
+		if len(resp.Choices) == 0 {
+			return nil, fmt.Errorf("no response from OpenAI")
+		}
+		tokensUsed += resp.Usage.TotalTokens
+		reply = resp
+
+		message := resp.Choices[0].Message
+		if len(message.ToolCalls) == 0 || exhausted {
+			break
+		}
+
+		messages = append(messages, message)
+		for _, call := range message.ToolCalls {
+			result := r.tools.execute(ctx, call)
+			if result.Failed {
+				failures++
+				r.logger.Warn("AI tool call failed",
+					zap.String("tool", call.Function.Name),
+					zap.String("arguments", call.Function.Arguments),
+					zap.String("result", result.Content))
+			} else {
+				toolsUsed[call.Function.Name] = true
+			}
+			for _, source := range result.Sources {
+				if !seen[source] {
+					seen[source] = true
+					sources = append(sources, source)
+				}
+			}
+			messages = append(messages, Message{
+				Role:       "tool",
+				ToolCallID: call.ID,
+				Content:    result.Content,
+			})
+		}
 	}
 
-	// Parse response
-	if len(openAIResp.Choices) == 0 {
-		return nil, fmt.Errorf("no response from OpenAI")
+	content := reply.Choices[0].Message.Content
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("OpenAI returned no answer")
 	}
+	parsed := parseAssistantReply(content)
 
-	answer := openAIResp.Choices[0].Message.Content
+	if sources == nil {
+		sources = []string{}
+	}
+	if tokensUsed == 0 {
+		// Streaming servers that ignore stream_options do not report usage
+		tokensUsed = estimateTokens(messages) + estimateTokens([]Message{reply.Choices[0].Message})
+	}
 
-	// Generate additional response components
 	response := &entity.AIResponse{
-		Answer:           answer,
-		Confidence:       r.calculateConfidence(question, answer),
-		Sources:          r.generateSources(question, walletAddress),
-		RelatedQuestions: r.generateRelatedQuestions(question),
-		ActionItems:      r.generateActionItems(question, walletAddress),
+		Answer:           parsed.Answer,
+		Confidence:       groundedConfidence(len(toolsUsed), failures, exhausted),
+		Sources:          sources,
+		RelatedQuestions: parsed.RelatedQuestions,
+		ActionItems:      parsed.ActionItems,
 		GeneratedAt:      time.Now(),
-		Model:            openAIResp.Model,
-		TokensUsed:       openAIResp.Usage.TotalTokens,
+		Model:            reply.Model,
+		TokensUsed:       tokensUsed,
 	}
 
 	r.logger.Info("Generated AI response",
-		zap.String("model", openAIResp.Model),
-		zap.Int("tokens", openAIResp.Usage.TotalTokens))
+		zap.String("model", reply.Model),
+		zap.Int("tokens", tokensUsed),
+		zap.Int("historyMessages", len(chat.History)),
+		zap.Int("toolsUsed", len(toolsUsed)),
+		zap.Int("sources", len(sources)))
 
 	return response, nil
 }
@@ -222,7 +375,9 @@ func (r *OpenAIRepository) callOpenAI(ctx context.Context, req OpenAIRequest) (*
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+r.config.OpenAIAPIKey)
+	if r.config.OpenAIAPIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+r.config.OpenAIAPIKey)
+	}
 
 	resp, err := r.httpClient.Do(httpReq)
 	if err != nil {
@@ -262,7 +417,16 @@ Always provide:
 4. Compliance considerations
 5. Specific recommendations
 
-Be concise but thorough. Focus on practical, actionable information.`
+Be concise but thorough. Focus on practical, actionable information.
+
+Use the provided tools to look up wallets, risk scores, transactions, money flows and security alerts.
+Only state facts about specific wallets or transactions that a tool returned; if the data is not
+available, say so instead of guessing. Cite transaction hashes and alert IDs you rely on.
+
+Write your answer as plain text. After the answer, write a line containing only ` + followUpMarker + `
+followed by a JSON object: {"related_questions": ["..."], "action_items": ["..."]}
+where related_questions are follow-up questions the user may ask and action_items are concrete
+next steps supported by the data you retrieved.`
 
 	if aiContext != nil {
 		if aiContext.AnalysisType != "" {
@@ -285,40 +449,46 @@ Be concise but thorough. Focus on practical, actionable information.`
 
 // Helper methods for generating response components
 
-func (r *OpenAIRepository) calculateConfidence(question, answer string) float64 {
-	// Simple confidence calculation based on question and answer characteristics
-	confidence := 0.7 // Base confidence
-
-	question = strings.ToLower(question)
-	answer = strings.ToLower(answer)
+// parseAssistantReply splits the model's reply into the answer and the follow-up
+// block. Models that omit the block (or wrap it in a code fence) still produce a
+// usable answer.
+func parseAssistantReply(content string) assistantReply {
+	answer, followUp, _ := strings.Cut(content, followUpMarker)
+	reply := assistantReply{Answer: strings.TrimSpace(answer)}
 
-	// Higher confidence for specific questions
-	if strings.Contains(question, "risk") || strings.Contains(question, "transaction") {
-		confidence += 0.1
+	followUp = strings.TrimSpace(followUp)
+	followUp = strings.TrimPrefix(followUp, "```json")
+	followUp = strings.TrimPrefix(followUp, "```")
+	followUp = strings.TrimSuffix(followUp, "```")
+	if followUp != "" {
+		_ = json.Unmarshal([]byte(strings.TrimSpace(followUp)), &reply.assistantFollowUp)
 	}
 
-	if strings.Contains(question, "compliance") || strings.Contains(question, "wallet") {
-		confidence += 0.1
+	if reply.RelatedQuestions == nil {
+		reply.RelatedQuestions = []string{}
 	}
-
-	// Lower confidence for vague questions
-	if len(question) < 20 {
-		confidence -= 0.1
+	if reply.ActionItems == nil {
+		reply.ActionItems = []string{}
 	}
+	return reply
+}
 
-	// Adjust based on answer quality
-	if len(answer) > 100 && !strings.Contains(answer, "i don't know") {
-		confidence += 0.05
+// groundedConfidence rates an answer by how much of it is backed by tool data:
+// ungrounded answers start low, each kind of record consulted raises confidence,
+// and failed lookups or an exhausted tool budget lower it
+func groundedConfidence(toolsUsed, failures int, exhausted bool) float64 {
+	confidence := 0.4 + 0.1*float64(toolsUsed)
+	confidence -= 0.05 * float64(failures)
+	if exhausted {
+		confidence -= 0.1
 	}
 
-	// Ensure confidence is within bounds
-	if confidence > 1.0 {
-		confidence = 1.0
+	if confidence > 0.95 {
+		confidence = 0.95
 	}
-	if confidence < 0.0 {
-		confidence = 0.0
+	if confidence < 0.1 {
+		confidence = 0.1
 	}
-
 	return confidence
 }
 
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+
+	"go.uber.org/zap"
+)
+
+// narrativeSystemPrompt instructs the model to draft a report narrative from the
+// supplied material only
+const narrativeSystemPrompt = `You draft the narrative section of cryptocurrency compliance reports, such as Suspicious Activity Reports, for a compliance analyst to review.
+
+Rules:
+- Use only the facts in the report material supplied by the user. Do not speculate about identities, motives or facts that are not in the material.
+- Cite the finding every statement is based on with its ID in square brackets, e.g. [F-001]. Every paragraph must cite at least one finding. Never cite an ID that is not in the material.
+- Cover who (the wallet and counterparties), what (the activity), when (dates), where (network), why it is suspicious and how it was carried out.
+- Write in the third person and the past tense, in plain paragraphs without headings, bullet points or markdown.
+- Transaction values in the material are in wei unless stated otherwise.`
+
+// narrativeReport is the report material sent to the model
+type narrativeReport struct {
+	ReportID        string                          `json:"report_id"`
+	ReportType      entity.ComplianceReportType     `json:"report_type"`
+	WalletAddress   string                          `json:"wallet_address"`
+	Period          map[string]string               `json:"period"`
+	Summary         entity.ComplianceSummary        `json:"summary"`
+	RiskAssessment  entity.ComplianceRiskAssessment `json:"risk_assessment"`
+	Findings        []narrativeFinding              `json:"findings"`
+	RegulatoryFlags []entity.RegulatoryFlag         `json:"regulatory_flags"`
+	Transactions    []map[string]interface{}        `json:"evidence_transactions"`
+}
+
+// narrativeFinding is a finding as sent to the model, without its free-form metadata
+type narrativeFinding struct {
+	ID                  string                       `json:"id"`
+	Type                entity.ComplianceFindingType `json:"type"`
+	Severity            entity.AlertSeverity         `json:"severity"`
+	Title               string                       `json:"title"`
+	Description         string                       `json:"description"`
+	Evidence            []string                     `json:"evidence"`
+	RelatedTransactions []string                     `json:"related_transactions"`
+	RegulatoryReference *string                      `json:"regulatory_reference,omitempty"`
+}
+
+// DraftReportNarrative asks the model for a narrative grounded in the report's
+// findings. Unlike questions, failures are returned rather than answered with
+// canned text, since a narrative may be filed with a regulator.
+func (r *OpenAIRepository) DraftReportNarrative(ctx context.Context, report *entity.ComplianceReport, evidence []entity.Transaction) (*entity.AIResponse, error) {
+	if r.config.OpenAIAPIKey == "" && strings.TrimRight(r.config.OpenAIBaseURL, "/") == defaultOpenAIBaseURL {
+		r.logger.Warn("OpenAI API key not configured, drafting narrative from template")
+		return templateNarrative(report), nil
+	}
+
+	material, err := json.Marshal(buildNarrativeReport(report, evidence))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode report material: %w", err)
+	}
+
+	messages := []Message{
+		{Role: "system", Content: narrativeSystemPrompt},
+		{Role: "user", Content: "Draft the narrative for this report.\n\n" + string(material)},
+	}
+	resp, err := r.callOpenAI(ctx, OpenAIRequest{
+		Model:       r.config.OpenAIModel,
+		Messages:    messages,
+		MaxTokens:   1500,
+		Temperature: 0.2,
+	})
+	if err != nil {
+		r.logger.Error("Failed to draft report narrative",
+			zap.String("reportID", report.ID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to draft narrative: %w", err)
+	}
+	if len(resp.Choices) == 0 || strings.TrimSpace(resp.Choices[0].Message.Content) == "" {
+		return nil, fmt.Errorf("OpenAI returned no narrative")
+	}
+
+	tokensUsed := resp.Usage.TotalTokens
+	if tokensUsed == 0 {
+		tokensUsed = estimateTokens(messages) + estimateTokens([]Message{resp.Choices[0].Message})
+	}
+
+	r.logger.Info("Drafted report narrative",
+		zap.String("reportID", report.ID),
+		zap.String("model", resp.Model),
+		zap.Int("tokens", tokensUsed))
+
+	return &entity.AIResponse{
+		Answer:           strings.TrimSpace(resp.Choices[0].Message.Content),
+		Confidence:       0.7,
+		Sources:          narrativeSources(report),
+		RelatedQuestions: []string{},
+		ActionItems:      []string{},
+		GeneratedAt:      time.Now(),
+		Model:            resp.Model,
+		TokensUsed:       tokensUsed,
+	}, nil
+}
+
+func buildNarrativeReport(report *entity.ComplianceReport, evidence []entity.Transaction) narrativeReport {
+	material := narrativeReport{
+		ReportID:      report.ID,
+		ReportType:    report.ReportType,
+		WalletAddress: report.WalletAddress,
+		Period: map[string]string{
+			"start": formatToolTime(report.TimeRange.Start),
+			"end":   formatToolTime(report.TimeRange.End),
+		},
+		Summary:         report.Summary,
+		RiskAssessment:  report.RiskAssessment,
+		Findings:        make([]narrativeFinding, 0, len(report.Findings)),
+		RegulatoryFlags: report.RegulatoryFlags,
+		Transactions:    make([]map[string]interface{}, 0, len(evidence)),
+	}
+
+	for _, finding := range report.Findings {
+		material.Findings = append(material.Findings, narrativeFinding{
+			ID:                  finding.ID,
+			Type:                finding.Type,
+			Severity:            finding.Severity,
+			Title:               finding.Title,
+			Description:         finding.Description,
+			Evidence:            finding.Evidence,
+			RelatedTransactions: finding.RelatedTransactions,
+			RegulatoryReference: finding.RegulatoryReference,
+		})
+	}
+	for _, tx := range evidence {
+		material.Transactions = append(material.Transactions, map[string]interface{}{
+			"hash":      tx.Hash,
+			"from":      tx.From,
+			"to":        tx.To,
+			"value":     tx.Value,
+			"timestamp": formatToolTime(tx.Timestamp),
+			"network":   tx.Network,
+		})
+	}
+
+	return material
+}
+
+// templateNarrative writes a plain narrative from the findings when no model is configured
+func templateNarrative(report *entity.ComplianceReport) *entity.AIResponse {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Between %s and %s, wallet %s was reviewed for %s reporting. ",
+		report.TimeRange.Start.UTC().Format("2006-01-02"),
+		report.TimeRange.End.UTC().Format("2006-01-02"),
+		report.WalletAddress, report.ReportType)
+	fmt.Fprintf(&b, "The review covered %d transactions with a total volume of %.2f USD and produced %d findings.",
+		report.Summary.TotalTransactions, report.Summary.TotalVolumeUSD, len(report.Findings))
+
+	for _, finding := range report.Findings {
+		fmt.Fprintf(&b, "\n\n%s: %s [%s]", finding.Title, finding.Description, finding.ID)
+	}
+	if len(report.Findings) > 0 {
+		fmt.Fprintf(&b, "\n\nThe overall risk of the activity was assessed as %s.", report.RiskAssessment.OverallRisk)
+	}
+
+	answer := b.String()
+	return &entity.AIResponse{
+		Answer:           answer,
+		Confidence:       0.5,
+		Sources:          narrativeSources(report),
+		RelatedQuestions: []string{},
+		ActionItems:      []string{},
+		GeneratedAt:      time.Now(),
+		Model:            "crypto-bubble-map-ai-v1.0",
+		TokensUsed:       entity.EstimateTokens(answer),
+	}
+}
+
+// narrativeSources lists the report and its findings as the narrative's sources
+func narrativeSources(report *entity.ComplianceReport) []string {
+	sources := []string{fmt.Sprintf("compliance_report:%s", report.ID)}
+	for _, finding := range report.Findings {
+		sources = append(sources, fmt.Sprintf("finding:%s", finding.ID))
+	}
+	return sources
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+)
+
+// openAIStreamChunk is one server-sent event of a streamed chat completion
+type openAIStreamChunk struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Index        int         `json:"index"`
+		Delta        streamDelta `json:"delta"`
+		FinishReason *string     `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *Usage `json:"usage"`
+}
+
+// streamDelta is the incremental part of the assistant message in a chunk
+type streamDelta struct {
+	Role      string                `json:"role"`
+	Content   string                `json:"content"`
+	ToolCalls []streamToolCallDelta `json:"tool_calls"`
+}
+
+// streamToolCallDelta is a fragment of a tool call; fragments with the same
+// index are concatenated
+type streamToolCallDelta struct {
+	Index    int              `json:"index"`
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function ToolCallFunction `json:"function"`
+}
+
+// callOpenAIStream makes a streaming API call, passing answer text to onDelta as
+// it arrives, and returns the assembled response
+func (r *OpenAIRepository) callOpenAIStream(ctx context.Context, req OpenAIRequest, onDelta func(string)) (*OpenAIResponse, error) {
+	req.Stream = true
+	req.StreamOptions = &StreamOptions{IncludeUsage: true}
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", r.config.OpenAIBaseURL+"/chat/completions", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+	if r.config.OpenAIAPIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+r.config.OpenAIAPIKey)
+	}
+
+	resp, err := r.streamClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("OpenAI API error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	result := &OpenAIResponse{}
+	var (
+		content      strings.Builder
+		toolCalls    = make(map[int]*ToolCall)
+		finishReason string
+		answer       = newAnswerStream(onDelta)
+	)
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("failed to decode stream chunk: %w", err)
+		}
+		if chunk.ID != "" {
+			result.ID = chunk.ID
+		}
+		if chunk.Model != "" {
+			result.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			result.Usage = *chunk.Usage
+		}
+
+		for _, choice := range chunk.Choices {
+			if choice.FinishReason != nil {
+				finishReason = *choice.FinishReason
+			}
+			if choice.Delta.Content != "" {
+				content.WriteString(choice.Delta.Content)
+				answer.write(choice.Delta.Content)
+			}
+			for _, fragment := range choice.Delta.ToolCalls {
+				call, ok := toolCalls[fragment.Index]
+				if !ok {
+					call = &ToolCall{Type: "function"}
+					toolCalls[fragment.Index] = call
+				}
+				if fragment.ID != "" {
+					call.ID = fragment.ID
+				}
+				if fragment.Type != "" {
+					call.Type = fragment.Type
+				}
+				call.Function.Name += fragment.Function.Name
+				call.Function.Arguments += fragment.Function.Arguments
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read stream: %w", err)
+	}
+	answer.flush()
+
+	message := Message{Role: "assistant", Content: content.String()}
+	indexes := make([]int, 0, len(toolCalls))
+	for i := range toolCalls {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	for _, i := range indexes {
+		message.ToolCalls = append(message.ToolCalls, *toolCalls[i])
+	}
+
+	result.Choices = []Choice{{Message: message, FinishReason: finishReason}}
+	return result, nil
+}
+
+// answerStream forwards answer text to a callback, stopping at the follow-up
+// marker. Text that could be the start of the marker is held back until the
+// next write shows whether it is.
+type answerStream struct {
+	emit    func(string)
+	pending string
+	done    bool
+}
+
+func newAnswerStream(emit func(string)) *answerStream {
+	return &answerStream{emit: emit}
+}
+
+func (a *answerStream) write(text string) {
+	if a.done || a.emit == nil {
+		return
+	}
+	a.pending += text
+
+	if i := strings.Index(a.pending, followUpMarker); i >= 0 {
+		a.send(a.pending[:i])
+		a.pending = ""
+		a.done = true
+		return
+	}
+
+	safe := len(a.pending) - (len(followUpMarker) - 1)
+	for safe > 0 && !utf8.RuneStart(a.pending[safe]) {
+		safe--
+	}
+	if safe > 0 {
+		a.send(a.pending[:safe])
+		a.pending = a.pending[safe:]
+	}
+}
+
+func (a *answerStream) flush() {
+	if !a.done && a.emit != nil {
+		a.send(a.pending)
+	}
+	a.pending = ""
+}
+
+func (a *answerStream) send(text string) {
+	if text != "" {
+		a.emit(text)
+	}
+}
+
+// estimateTokens approximates the token count of messages for servers that do
+// not report usage
+func estimateTokens(messages []Message) int {
+	total := 0
+	for _, m := range messages {
+		total += entity.EstimateTokens(m.Content) + 4
+		for _, call := range m.ToolCalls {
+			total += entity.EstimateTokens(call.Function.Name + call.Function.Arguments)
+		}
+	}
+	return total
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/database"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// PostgreSQLAIConversationRepository implements AIConversationRepository using PostgreSQL
+type PostgreSQLAIConversationRepository struct {
+	db     *database.PostgreSQLClient
+	logger *zap.Logger
+}
+
+// NewPostgreSQLAIConversationRepository creates a new PostgreSQL AI conversation repository
+func NewPostgreSQLAIConversationRepository(db *database.PostgreSQLClient, logger *zap.Logger) repository.AIConversationRepository {
+	return &PostgreSQLAIConversationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateConversation stores a new conversation
+func (r *PostgreSQLAIConversationRepository) CreateConversation(ctx context.Context, conversation *entity.AIConversation) error {
+	if err := r.db.GetDB().WithContext(ctx).Create(conversation).Error; err != nil {
+		r.logger.Error("Failed to create AI conversation",
+			zap.Uint("userID", conversation.UserID),
+			zap.Error(err))
+		return fmt.Errorf("failed to create conversation: %w", err)
+	}
+	return nil
+}
+
+// GetConversation retrieves one of a user's conversations
+func (r *PostgreSQLAIConversationRepository) GetConversation(ctx context.Context, userID, conversationID uint) (*entity.AIConversation, error) {
+	var conversation entity.AIConversation
+
+	err := r.db.GetDB().WithContext(ctx).
+		Where("id = ? AND user_id = ?", conversationID, userID).
+		First(&conversation).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get AI conversation",
+			zap.Uint("userID", userID),
+			zap.Uint("conversationID", conversationID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to get conversation: %w", err)
+	}
+
+	return &conversation, nil
+}
+
+// GetConversations lists a user's conversations, most recently active first
+func (r *PostgreSQLAIConversationRepository) GetConversations(ctx context.Context, userID uint, limit, offset int) ([]entity.AIConversation, error) {
+	var conversations []entity.AIConversation
+
+	err := r.db.GetDB().WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("updated_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&conversations).Error
+
+	if err != nil {
+		r.logger.Error("Failed to get AI conversations",
+			zap.Uint("userID", userID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to get conversations: %w", err)
+	}
+
+	return conversations, nil
+}
+
+// DeleteConversation soft-deletes one of a user's conversations
+func (r *PostgreSQLAIConversationRepository) DeleteConversation(ctx context.Context, userID, conversationID uint) error {
+	result := r.db.GetDB().WithContext(ctx).
+		Where("id = ? AND user_id = ?", conversationID, userID).
+		Delete(&entity.AIConversation{})
+
+	if result.Error != nil {
+		r.logger.Error("Failed to delete AI conversation",
+			zap.Uint("userID", userID),
+			zap.Uint("conversationID", conversationID),
+			zap.Error(result.Error))
+		return fmt.Errorf("failed to delete conversation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("conversation not found")
+	}
+
+	return nil
+}
+
+// AddMessage appends a message and updates the conversation's counters
+func (r *PostgreSQLAIConversationRepository) AddMessage(ctx context.Context, message *entity.AIMessage) error {
+	err := r.db.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(message).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.AIConversation{}).
+			Where("id = ?", message.ConversationID).
+			Updates(map[string]interface{}{
+				"message_count": gorm.Expr("message_count + 1"),
+				"tokens_used":   gorm.Expr("tokens_used + ?", message.TokensUsed),
+				"updated_at":    message.CreatedAt,
+			}).Error
+	})
+
+	if err != nil {
+		r.logger.Error("Failed to add AI message",
+			zap.Uint("conversationID", message.ConversationID),
+			zap.Error(err))
+		return fmt.Errorf("failed to add message: %w", err)
+	}
+	return nil
+}
+
+// GetMessages lists a conversation's messages in the order they were written
+func (r *PostgreSQLAIConversationRepository) GetMessages(ctx context.Context, conversationID uint, limit, offset int) ([]entity.AIMessage, error) {
+	var messages []entity.AIMessage
+
+	err := r.db.GetDB().WithContext(ctx).
+		Where("conversation_id = ?", conversationID).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&messages).Error
+
+	if err != nil {
+		r.logger.Error("Failed to get AI messages",
+			zap.Uint("conversationID", conversationID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// GetRecentMessages returns the latest limit messages in the order they were written
+func (r *PostgreSQLAIConversationRepository) GetRecentMessages(ctx context.Context, conversationID uint, limit int) ([]entity.AIMessage, error) {
+	var messages []entity.AIMessage
+
+	err := r.db.GetDB().WithContext(ctx).
+		Where("conversation_id = ?", conversationID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&messages).Error
+
+	if err != nil {
+		r.logger.Error("Failed to get recent AI messages",
+			zap.Uint("conversationID", conversationID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to get recent messages: %w", err)
+	}
+
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, nil
+}
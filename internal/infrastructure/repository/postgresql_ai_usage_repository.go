@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/database"
+
+	"go.uber.org/zap"
+)
+
+// PostgreSQLAIUsageRepository implements AIUsageRepository using PostgreSQL
+type PostgreSQLAIUsageRepository struct {
+	db     *database.PostgreSQLClient
+	logger *zap.Logger
+}
+
+// NewPostgreSQLAIUsageRepository creates a new PostgreSQL AI usage repository
+func NewPostgreSQLAIUsageRepository(db *database.PostgreSQLClient, logger *zap.Logger) repository.AIUsageRepository {
+	return &PostgreSQLAIUsageRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// RecordUsage stores the usage of one AI request
+func (r *PostgreSQLAIUsageRepository) RecordUsage(ctx context.Context, usage *entity.AIUsage) error {
+	if err := r.db.GetDB().WithContext(ctx).Create(usage).Error; err != nil {
+		r.logger.Error("Failed to record AI usage",
+			zap.Uint("userID", usage.UserID),
+			zap.Int("tokens", usage.TokensUsed),
+			zap.Error(err))
+		return fmt.Errorf("failed to record AI usage: %w", err)
+	}
+	return nil
+}
+
+// GetTokensUsedSince returns the tokens a user has consumed since the given time
+func (r *PostgreSQLAIUsageRepository) GetTokensUsedSince(ctx context.Context, userID uint, since time.Time) (int, error) {
+	var total int64
+
+	err := r.db.GetDB().WithContext(ctx).
+		Model(&entity.AIUsage{}).
+		Select("COALESCE(SUM(tokens_used), 0)").
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Scan(&total).Error
+
+	if err != nil {
+		r.logger.Error("Failed to get AI token usage",
+			zap.Uint("userID", userID),
+			zap.Error(err))
+		return 0, fmt.Errorf("failed to get AI token usage: %w", err)
+	}
+
+	return int(total), nil
+}
+
+// GetDailyUsage returns a user's usage per UTC day since the given time, most recent first
+func (r *PostgreSQLAIUsageRepository) GetDailyUsage(ctx context.Context, userID uint, since time.Time) ([]entity.AIDailyUsage, error) {
+	var rows []struct {
+		Day            time.Time
+		TokensUsed     int
+		Requests       int
+		CachedRequests int
+	}
+
+	err := r.db.GetDB().WithContext(ctx).
+		Model(&entity.AIUsage{}).
+		Select(`date_trunc('day', created_at AT TIME ZONE 'UTC') AS day,
+			COALESCE(SUM(tokens_used), 0) AS tokens_used,
+			COUNT(*) AS requests,
+			COUNT(*) FILTER (WHERE cached) AS cached_requests`).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Group("day").
+		Order("day DESC").
+		Scan(&rows).Error
+
+	if err != nil {
+		r.logger.Error("Failed to get daily AI usage",
+			zap.Uint("userID", userID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to get daily AI usage: %w", err)
+	}
+
+	usage := make([]entity.AIDailyUsage, len(rows))
+	for i, row := range rows {
+		usage[i] = entity.AIDailyUsage{
+			Date:           time.Date(row.Day.Year(), row.Day.Month(), row.Day.Day(), 0, 0, 0, 0, time.UTC),
+			TokensUsed:     row.TokensUsed,
+			Requests:       row.Requests,
+			CachedRequests: row.CachedRequests,
+		}
+	}
+	return usage, nil
+}
@@ -54,6 +54,23 @@ func (r *PostgreSQLWatchListRepository) GetWatchedWallets(ctx context.Context, u
 	return wallets, nil
 }
 
+// GetAllWatchedAddresses retrieves the distinct addresses watched by any user
+func (r *PostgreSQLWatchListRepository) GetAllWatchedAddresses(ctx context.Context) ([]string, error) {
+	var addresses []string
+
+	err := r.db.GetDB().WithContext(ctx).
+		Model(&entity.WatchedWallet{}).
+		Distinct("address").
+		Pluck("address", &addresses).Error
+
+	if err != nil {
+		r.logger.Error("Failed to get watched addresses", zap.Error(err))
+		return nil, fmt.Errorf("failed to get watched addresses: %w", err)
+	}
+
+	return addresses, nil
+}
+
 // GetWatchedWallet retrieves a specific watched wallet by ID
 func (r *PostgreSQLWatchListRepository) GetWatchedWallet(ctx context.Context, userID uint, walletID uint) (*entity.WatchedWallet, error) {
 	var wallet entity.WatchedWallet
@@ -0,0 +1,256 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"crypto-bubble-map-be/internal/infrastructure/config"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+	"crypto-bubble-map-be/internal/infrastructure/monitoring"
+
+	"go.uber.org/zap"
+)
+
+// ErrCircuitOpen is the cause of the error returned for calls rejected by an
+// open circuit breaker
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// State is the state of a circuit breaker
+type State int
+
+const (
+	// StateClosed lets every call through
+	StateClosed State = iota
+	// StateHalfOpen lets trial calls through after the open timeout; the first
+	// outcome recorded closes or reopens the breaker
+	StateHalfOpen
+	// StateOpen rejects every call until the open timeout has passed
+	StateOpen
+)
+
+// String returns the state's name
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half_open"
+	case StateOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// FailureFunc reports whether an error means the dependency itself is
+// failing, as opposed to e.g. a missing document or an invalid query. Only
+// such errors count towards opening the breaker and are retried.
+type FailureFunc func(err error) bool
+
+// Breaker is a circuit breaker guarding a single dependency. It opens after
+// a number of consecutive failures, so that callers fail fast instead of
+// queueing behind a dependency that is down. A nil Breaker lets every call
+// through without retries.
+type Breaker struct {
+	name        string
+	threshold   int
+	openTimeout time.Duration
+	retry       RetryPolicy
+	isFailure   FailureFunc
+	monitor     *monitoring.PerformanceMonitor
+	logger      *zap.Logger
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	openedAt  time.Time
+	lastError string
+}
+
+// Snapshot describes a breaker's current state
+type Snapshot struct {
+	Name                string     `json:"name"`
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+// NewBreaker creates a closed breaker for the named dependency
+func NewBreaker(name string, cfg *config.ResilienceConfig, isFailure FailureFunc, monitor *monitoring.PerformanceMonitor, logger *zap.Logger) *Breaker {
+	b := &Breaker{
+		name:        name,
+		threshold:   cfg.BreakerFailureThreshold,
+		openTimeout: cfg.BreakerOpenTimeout,
+		retry:       NewRetryPolicy(cfg),
+		isFailure:   isFailure,
+		monitor:     monitor,
+		logger:      logger,
+	}
+	if b.threshold <= 0 {
+		b.threshold = 1
+	}
+	if monitor != nil {
+		monitor.TrackCircuitBreakerState(name, float64(StateClosed))
+	}
+	return b
+}
+
+// Name returns the name of the guarded dependency
+func (b *Breaker) Name() string {
+	if b == nil {
+		return ""
+	}
+	return b.name
+}
+
+// Allow returns an error if the breaker is open, and otherwise lets the call
+// through. An open breaker becomes half-open once its open timeout has passed.
+func (b *Breaker) Allow() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen {
+		if time.Since(b.openedAt) < b.openTimeout {
+			if b.monitor != nil {
+				b.monitor.TrackCircuitBreakerRejection(b.name)
+			}
+			return apperrors.NewAppError(apperrors.ErrCodeServiceUnavailable,
+				fmt.Sprintf("%s is unavailable", b.name), ErrCircuitOpen.Error()).
+				WithMetadata("dependency", b.name).
+				WithCause(ErrCircuitOpen)
+		}
+		b.transition(StateHalfOpen)
+	}
+	return nil
+}
+
+// Record records the outcome of a call let through by Allow and reports
+// whether err counted as a failure of the dependency
+func (b *Breaker) Record(err error) bool {
+	if b == nil {
+		return err != nil
+	}
+
+	failed := err != nil && b.isFailure(err)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		b.failures = 0
+		if b.state == StateHalfOpen {
+			b.transition(StateClosed)
+		}
+		return false
+	}
+
+	b.failures++
+	b.lastError = err.Error()
+	switch {
+	case b.state == StateHalfOpen:
+		b.transition(StateOpen)
+	case b.state == StateClosed && b.failures >= b.threshold:
+		b.transition(StateOpen)
+	}
+	return true
+}
+
+// Execute calls fn, retrying it with exponential backoff while it fails with
+// errors that count as dependency failures. Every attempt is subject to the
+// breaker, so retries stop as soon as it opens.
+func (b *Breaker) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	if b == nil {
+		return fn(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := b.Allow(); err != nil {
+			return err
+		}
+
+		err := fn(ctx)
+		if !b.Record(err) || attempt >= b.retry.MaxAttempts {
+			return err
+		}
+
+		if b.monitor != nil {
+			b.monitor.TrackRetry(b.name)
+		}
+		b.logger.Debug("Retrying dependency call",
+			zap.String("dependency", b.name),
+			zap.Int("attempt", attempt),
+			zap.Error(err))
+
+		if waitErr := b.retry.Wait(ctx, attempt); waitErr != nil {
+			return err
+		}
+	}
+}
+
+// State returns the breaker's current state
+func (b *Breaker) State() State {
+	if b == nil {
+		return StateClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Snapshot returns the breaker's current state for health reporting
+func (b *Breaker) Snapshot() Snapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	snapshot := Snapshot{
+		Name:                b.name,
+		State:               b.state.String(),
+		ConsecutiveFailures: b.failures,
+		LastError:           b.lastError,
+	}
+	if b.state != StateClosed {
+		openedAt := b.openedAt
+		snapshot.OpenedAt = &openedAt
+	}
+	return snapshot
+}
+
+// transition moves the breaker to state; b.mu must be held
+func (b *Breaker) transition(state State) {
+	if b.state == state {
+		return
+	}
+
+	from := b.state
+	b.state = state
+	switch state {
+	case StateOpen:
+		b.openedAt = time.Now()
+		b.logger.Warn("Circuit breaker opened",
+			zap.String("dependency", b.name),
+			zap.String("from", from.String()),
+			zap.Int("consecutiveFailures", b.failures),
+			zap.Duration("openTimeout", b.openTimeout),
+			zap.String("lastError", b.lastError))
+	case StateClosed:
+		b.failures = 0
+		b.lastError = ""
+		b.logger.Info("Circuit breaker closed", zap.String("dependency", b.name))
+	case StateHalfOpen:
+		b.logger.Info("Circuit breaker half-open, probing dependency", zap.String("dependency", b.name))
+	}
+
+	if b.monitor != nil {
+		b.monitor.TrackCircuitBreakerState(b.name, float64(state))
+		b.monitor.TrackCircuitBreakerTransition(b.name, state.String())
+	}
+}
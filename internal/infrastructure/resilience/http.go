@@ -0,0 +1,122 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// StatusError reports an HTTP response whose status means the remote API is
+// failing or throttling us
+type StatusError struct {
+	StatusCode int
+}
+
+// Error implements error
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status %d", e.StatusCode)
+}
+
+// IsHTTPFailure is the FailureFunc of HTTP APIs: transport errors and
+// StatusErrors count as failures, requests cancelled by the caller do not
+func IsHTTPFailure(err error) bool {
+	return err != nil && !errors.Is(err, context.Canceled)
+}
+
+// failingStatus reports whether a response status counts as a failure of
+// the remote API
+func failingStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// Transport is an http.RoundTripper that guards an HTTP API with a circuit
+// breaker and retries requests failing with transport errors, 429 or 5xx.
+// Once every attempt has failed, the last response is returned as is, so
+// callers keep handling error statuses themselves. Requests whose body
+// cannot be replayed are not retried.
+type Transport struct {
+	Base    http.RoundTripper
+	Breaker *Breaker
+}
+
+// NewTransport guards base, or http.DefaultTransport if nil, with breaker
+func NewTransport(base http.RoundTripper, breaker *Breaker) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Base: base, Breaker: breaker}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return t.roundTripOnce(req)
+	}
+
+	var (
+		resp    *http.Response
+		attempt int
+	)
+	err := t.Breaker.Execute(req.Context(), func(ctx context.Context) error {
+		attempt++
+		if resp != nil {
+			// Retrying: release the connection held by the failed response
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			resp = nil
+		}
+
+		attemptReq := req
+		if attempt > 1 {
+			attemptReq = req.Clone(ctx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return fmt.Errorf("failed to replay request body: %w", err)
+				}
+				attemptReq.Body = body
+			}
+		}
+
+		r, err := t.Base.RoundTrip(attemptReq)
+		if err != nil {
+			return err
+		}
+		resp = r
+		if failingStatus(r.StatusCode) {
+			return &StatusError{StatusCode: r.StatusCode}
+		}
+		return nil
+	})
+
+	var statusErr *StatusError
+	switch {
+	case err == nil, errors.As(err, &statusErr) && resp != nil:
+		return resp, nil
+	default:
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return nil, err
+	}
+}
+
+// roundTripOnce sends a request that cannot be retried through the breaker
+func (t *Transport) roundTripOnce(req *http.Request) (*http.Response, error) {
+	if err := t.Breaker.Allow(); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	switch {
+	case err != nil:
+		t.Breaker.Record(err)
+	case failingStatus(resp.StatusCode):
+		t.Breaker.Record(&StatusError{StatusCode: resp.StatusCode})
+	default:
+		t.Breaker.Record(nil)
+	}
+	return resp, err
+}
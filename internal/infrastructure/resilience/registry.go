@@ -0,0 +1,94 @@
+package resilience
+
+import (
+	"sort"
+	"sync"
+
+	"crypto-bubble-map-be/internal/infrastructure/config"
+	"crypto-bubble-map-be/internal/infrastructure/monitoring"
+
+	"go.uber.org/zap"
+)
+
+// Names of the guarded dependencies
+const (
+	DependencyNeo4j      = "neo4j"
+	DependencyMongoDB    = "mongodb"
+	DependencyPostgreSQL = "postgresql"
+	DependencyRedis      = "redis"
+	DependencyCoinGecko  = "coingecko"
+	DependencyEtherscan  = "etherscan"
+	DependencyOpenAI     = "openai"
+)
+
+// Registry holds one circuit breaker per dependency, so that one failing
+// dependency does not reject calls to the others. A nil Registry hands out
+// nil breakers, which let every call through.
+type Registry struct {
+	config  *config.ResilienceConfig
+	monitor *monitoring.PerformanceMonitor
+	logger  *zap.Logger
+
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+}
+
+// NewRegistry creates an empty breaker registry
+func NewRegistry(cfg *config.ResilienceConfig, monitor *monitoring.PerformanceMonitor, logger *zap.Logger) *Registry {
+	return &Registry{
+		config:   cfg,
+		monitor:  monitor,
+		logger:   logger,
+		breakers: make(map[string]*Breaker),
+	}
+}
+
+// Breaker returns the named dependency's breaker, creating it with isFailure
+// on first use
+func (r *Registry) Breaker(name string, isFailure FailureFunc) *Breaker {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if b, ok := r.breakers[name]; ok {
+		return b
+	}
+	b := NewBreaker(name, r.config, isFailure, r.monitor, r.logger)
+	r.breakers[name] = b
+	return b
+}
+
+// RetryPolicy returns the retry policy shared by all dependencies, for
+// clients whose driver performs the retries itself
+func (r *Registry) RetryPolicy() RetryPolicy {
+	if r == nil {
+		return RetryPolicy{MaxAttempts: 1}
+	}
+	return NewRetryPolicy(r.config)
+}
+
+// Snapshots returns the state of every breaker, sorted by dependency
+func (r *Registry) Snapshots() []Snapshot {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	breakers := make([]*Breaker, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		breakers = append(breakers, b)
+	}
+	r.mu.Unlock()
+
+	snapshots := make([]Snapshot, 0, len(breakers))
+	for _, b := range breakers {
+		snapshots = append(snapshots, b.Snapshot())
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+	return snapshots
+}
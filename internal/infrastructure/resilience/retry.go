@@ -0,0 +1,68 @@
+package resilience
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"crypto-bubble-map-be/internal/infrastructure/config"
+)
+
+// RetryPolicy bounds the retries of a failing dependency call. The backoff
+// doubles after every attempt, up to MaxBackoff, with jitter so that callers
+// failing together do not retry together.
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt; 1 disables retries
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// NewRetryPolicy creates the retry policy configured for all dependencies
+func NewRetryPolicy(cfg *config.ResilienceConfig) RetryPolicy {
+	policy := RetryPolicy{
+		MaxAttempts:    cfg.RetryMaxAttempts,
+		InitialBackoff: cfg.RetryInitialBackoff,
+		MaxBackoff:     cfg.RetryMaxBackoff,
+	}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+	if policy.MaxBackoff < policy.InitialBackoff {
+		policy.MaxBackoff = policy.InitialBackoff
+	}
+	return policy
+}
+
+// Backoff returns the delay before the attempt following attempt, which
+// counts from 1: between half and all of InitialBackoff*2^(attempt-1),
+// capped at MaxBackoff
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempt && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)+1))
+}
+
+// Wait sleeps for the backoff after attempt, returning early with the
+// context's error if it is done first
+func (p RetryPolicy) Wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(p.Backoff(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
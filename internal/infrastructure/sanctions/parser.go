@@ -0,0 +1,276 @@
+package sanctions
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+)
+
+// digitalCurrencyIDPrefix is the OFAC idType prefix for blockchain addresses,
+// e.g. "Digital Currency Address - ETH"
+const digitalCurrencyIDPrefix = "Digital Currency Address - "
+
+// ofacRemarksAddress matches addresses embedded in the Remarks column of sdn.csv
+var ofacRemarksAddress = regexp.MustCompile(`Digital Currency Address - ([A-Za-z0-9]+)\s+([A-Za-z0-9]+)`)
+
+// ParsedList is the result of parsing a sanctions list file
+type ParsedList struct {
+	Entries     []entity.SanctionedAddress
+	PublishedAt *time.Time
+	SkippedRows int
+}
+
+// Parse reads a sanctions list in the given format
+func Parse(format entity.SanctionsListFormat, r io.Reader) (*ParsedList, error) {
+	var (
+		list *ParsedList
+		err  error
+	)
+
+	switch format {
+	case entity.SanctionsListFormatOFACXML:
+		list, err = parseOFACXML(r)
+	case entity.SanctionsListFormatOFACCSV:
+		list, err = parseOFACCSV(r)
+	case entity.SanctionsListFormatGenericCSV:
+		list, err = parseGenericCSV(r)
+	default:
+		return nil, fmt.Errorf("unsupported sanctions list format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list.Entries = dedupeEntries(list.Entries)
+	return list, nil
+}
+
+// ofacSDNList mirrors the parts of the OFAC sdn.xml schema we use
+type ofacSDNList struct {
+	XMLName     xml.Name `xml:"sdnList"`
+	PublishInfo struct {
+		PublishDate string `xml:"Publish_Date"`
+	} `xml:"publshInformation"`
+	Entries []struct {
+		UID       string   `xml:"uid"`
+		FirstName string   `xml:"firstName"`
+		LastName  string   `xml:"lastName"`
+		Programs  []string `xml:"programList>program"`
+		IDs       []struct {
+			IDType   string `xml:"idType"`
+			IDNumber string `xml:"idNumber"`
+		} `xml:"idList>id"`
+	} `xml:"sdnEntry"`
+}
+
+// parseOFACXML parses the OFAC SDN list in its XML format
+func parseOFACXML(r io.Reader) (*ParsedList, error) {
+	var doc ofacSDNList
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode OFAC SDN XML: %w", err)
+	}
+
+	list := &ParsedList{}
+	if t, err := time.Parse("01/02/2006", strings.TrimSpace(doc.PublishInfo.PublishDate)); err == nil {
+		list.PublishedAt = &t
+	}
+
+	for _, e := range doc.Entries {
+		name := strings.TrimSpace(strings.TrimSpace(e.FirstName) + " " + strings.TrimSpace(e.LastName))
+		for _, id := range e.IDs {
+			if !strings.HasPrefix(id.IDType, digitalCurrencyIDPrefix) {
+				continue
+			}
+			address := strings.TrimSpace(id.IDNumber)
+			if address == "" {
+				list.SkippedRows++
+				continue
+			}
+			list.Entries = append(list.Entries, entity.SanctionedAddress{
+				Address:    entity.NormalizeAddress(address),
+				Currency:   strings.TrimSpace(strings.TrimPrefix(id.IDType, digitalCurrencyIDPrefix)),
+				EntityName: name,
+				EntityID:   e.UID,
+				Programs:   e.Programs,
+			})
+		}
+	}
+
+	return list, nil
+}
+
+// parseOFACCSV parses the OFAC sdn.csv file. It has no header; addresses are
+// embedded in the Remarks column (index 11) and programs in column 3.
+func parseOFACCSV(r io.Reader) (*ParsedList, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	list := &ParsedList{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read OFAC SDN CSV: %w", err)
+		}
+
+		if len(record) < 12 {
+			list.SkippedRows++
+			continue
+		}
+
+		remarks := ofacNull(record[11])
+		matches := ofacRemarksAddress.FindAllStringSubmatch(remarks, -1)
+		if len(matches) == 0 {
+			continue
+		}
+
+		programs := splitPrograms(ofacNull(record[3]))
+		for _, m := range matches {
+			list.Entries = append(list.Entries, entity.SanctionedAddress{
+				Address:    entity.NormalizeAddress(m[2]),
+				Currency:   m[1],
+				EntityName: ofacNull(record[1]),
+				EntityID:   strings.TrimSpace(record[0]),
+				Programs:   programs,
+			})
+		}
+	}
+
+	return list, nil
+}
+
+// parseGenericCSV parses a CSV file with a header row. Only the address column
+// is required; currency, name, id, programs and remarks are optional.
+func parseGenericCSV(r io.Reader) (*ParsedList, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	addressCol, ok := lookupColumn(columns, "address", "wallet_address", "wallet")
+	if !ok {
+		return nil, fmt.Errorf("CSV header must contain an address column")
+	}
+	currencyCol, _ := lookupColumn(columns, "currency", "chain", "network")
+	nameCol, _ := lookupColumn(columns, "name", "entity_name", "entity")
+	idCol, _ := lookupColumn(columns, "id", "entity_id", "uid")
+	programsCol, _ := lookupColumn(columns, "programs", "program", "list")
+	remarksCol, _ := lookupColumn(columns, "remarks", "notes")
+
+	list := &ParsedList{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV row: %w", err)
+		}
+
+		address := field(record, addressCol)
+		if address == "" {
+			list.SkippedRows++
+			continue
+		}
+
+		list.Entries = append(list.Entries, entity.SanctionedAddress{
+			Address:    entity.NormalizeAddress(address),
+			Currency:   strings.ToUpper(field(record, currencyCol)),
+			EntityName: field(record, nameCol),
+			EntityID:   field(record, idCol),
+			Programs:   splitPrograms(field(record, programsCol)),
+			Remarks:    field(record, remarksCol),
+		})
+	}
+
+	return list, nil
+}
+
+// dedupeEntries merges entries for the same address, unioning their programs
+func dedupeEntries(entries []entity.SanctionedAddress) []entity.SanctionedAddress {
+	index := make(map[string]int, len(entries))
+	result := make([]entity.SanctionedAddress, 0, len(entries))
+
+	for _, e := range entries {
+		if i, ok := index[e.Address]; ok {
+			result[i].Programs = unionStrings(result[i].Programs, e.Programs)
+			continue
+		}
+		index[e.Address] = len(result)
+		result = append(result, e)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Address < result[j].Address })
+	return result
+}
+
+// splitPrograms splits program lists such as "CYBER2] [DPRK3" or "CYBER2;DPRK3"
+func splitPrograms(value string) []string {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == '[' || r == ']' || r == ';' || r == '|' || r == ','
+	})
+
+	programs := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			programs = append(programs, f)
+		}
+	}
+	return programs
+}
+
+func unionStrings(a, b []string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	out := make([]string, 0, len(a)+len(b))
+	for _, s := range append(append([]string{}, a...), b...) {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// ofacNull converts the OFAC "-0-" null marker to an empty string
+func ofacNull(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "-0-" {
+		return ""
+	}
+	return value
+}
+
+func lookupColumn(columns map[string]int, names ...string) (int, bool) {
+	for _, name := range names {
+		if i, ok := columns[name]; ok {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func field(record []string, col int) string {
+	if col < 0 || col >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[col])
+}
@@ -0,0 +1,402 @@
+package sanctions
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/config"
+
+	"go.uber.org/zap"
+)
+
+// exposureConcurrency bounds the number of concurrent graph exposure queries
+const exposureConcurrency = 8
+
+// Service imports sanctions lists and screens addresses against them
+type Service struct {
+	sanctionsRepo repository.SanctionsRepository
+	securityRepo  repository.SecurityRepository
+	watchListRepo repository.WatchListRepository
+	config        *config.ComplianceConfig
+	logger        *zap.Logger
+}
+
+// NewService creates a new sanctions service
+func NewService(
+	sanctionsRepo repository.SanctionsRepository,
+	securityRepo repository.SecurityRepository,
+	watchListRepo repository.WatchListRepository,
+	cfg *config.ComplianceConfig,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		sanctionsRepo: sanctionsRepo,
+		securityRepo:  securityRepo,
+		watchListRepo: watchListRepo,
+		config:        cfg,
+		logger:        logger,
+	}
+}
+
+// ImportFile imports a list from the configured sanctions data directory.
+// fileName must resolve to a file inside that directory.
+func (s *Service) ImportFile(ctx context.Context, format entity.SanctionsListFormat, fileName, importedBy string) (*entity.SanctionsImportResult, error) {
+	path, err := s.resolvePath(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sanctions list: %w", err)
+	}
+
+	return s.Import(ctx, format, filepath.Base(path), data, importedBy)
+}
+
+// Import parses and stores a new version of a sanctions list, then re-screens
+// every watched wallet so that newly designated exposure raises alerts.
+// Re-importing a file identical to the active version is a no-op.
+func (s *Service) Import(ctx context.Context, format entity.SanctionsListFormat, fileName string, data []byte, importedBy string) (*entity.SanctionsImportResult, error) {
+	source := format.Source()
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	previous, err := s.sanctionsRepo.GetActiveListVersion(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil && previous.Checksum == checksum {
+		s.logger.Info("Sanctions list unchanged, skipping import",
+			zap.String("source", string(source)),
+			zap.String("versionID", previous.ID))
+		return &entity.SanctionsImportResult{Version: previous, UnchangedChecksum: true}, nil
+	}
+
+	parsed, err := Parse(format, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	version := &entity.SanctionsListVersion{
+		Source:      source,
+		Format:      format,
+		FileName:    fileName,
+		Checksum:    checksum,
+		PublishedAt: parsed.PublishedAt,
+		ImportedAt:  time.Now(),
+		ImportedBy:  importedBy,
+		SkippedRows: parsed.SkippedRows,
+	}
+
+	if previous != nil {
+		previousEntries, err := s.sanctionsRepo.GetListEntries(ctx, previous.ID)
+		if err != nil {
+			return nil, err
+		}
+		version.PreviousVersion = &previous.ID
+		version.AddedCount, version.RemovedCount = diffEntries(previousEntries, parsed.Entries)
+	} else {
+		version.AddedCount = len(parsed.Entries)
+	}
+
+	if err := s.sanctionsRepo.CreateListVersion(ctx, version, parsed.Entries); err != nil {
+		return nil, err
+	}
+
+	result := &entity.SanctionsImportResult{Version: version}
+
+	screened, alerts, err := s.ScreenWatchedWallets(ctx, s.config.ScreeningHops)
+	if err != nil {
+		// The import itself succeeded; report screening failures without failing it
+		s.logger.Error("Failed to re-screen watched wallets after import",
+			zap.String("versionID", version.ID),
+			zap.Error(err))
+	}
+	result.ScreenedWallets = screened
+	result.AlertsRaised = alerts
+
+	s.logger.Info("Imported sanctions list",
+		zap.String("versionID", version.ID),
+		zap.String("source", string(source)),
+		zap.Int("entries", version.EntryCount),
+		zap.Int("added", version.AddedCount),
+		zap.Int("removed", version.RemovedCount),
+		zap.Int("alerts", alerts))
+
+	return result, nil
+}
+
+// Screen checks an address against the active lists directly and, when maxHops
+// is positive, for exposure through the transaction graph. A hit raises a
+// SANCTIONS security alert unless an equivalent alert is already open.
+func (s *Service) Screen(ctx context.Context, address string, maxHops int) (*entity.SanctionsScreeningResult, error) {
+	results, err := s.ScreenBatch(ctx, []string{address}, maxHops, true)
+	if err != nil {
+		return nil, err
+	}
+	return results[entity.NormalizeAddress(address)], nil
+}
+
+// ScreenBatch screens many addresses with a single list lookup. Exposure queries
+// run with bounded concurrency. Results are keyed by normalized address.
+func (s *Service) ScreenBatch(ctx context.Context, addresses []string, maxHops int, raiseAlerts bool) (map[string]*entity.SanctionsScreeningResult, error) {
+	maxHops = s.clampHops(maxHops)
+
+	normalized := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		normalized = append(normalized, entity.NormalizeAddress(address))
+	}
+
+	matches, err := s.sanctionsRepo.FindSanctionedAddresses(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+	matchesByAddress := make(map[string][]entity.SanctionedAddress, len(matches))
+	for _, m := range matches {
+		matchesByAddress[m.Address] = append(matchesByAddress[m.Address], m)
+	}
+
+	listVersions, err := s.activeListVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	results := make(map[string]*entity.SanctionsScreeningResult, len(normalized))
+	for _, address := range normalized {
+		results[address] = &entity.SanctionsScreeningResult{
+			Address:      address,
+			DirectMatch:  len(matchesByAddress[address]) > 0,
+			Matches:      matchesByAddress[address],
+			MaxHops:      maxHops,
+			ListVersions: listVersions,
+			ScreenedAt:   now,
+		}
+	}
+
+	if maxHops > 0 {
+		if err := s.findExposures(ctx, results, maxHops); err != nil {
+			return nil, err
+		}
+	}
+
+	if raiseAlerts {
+		for _, result := range results {
+			if !result.IsHit() {
+				continue
+			}
+			alertID, err := s.raiseAlert(ctx, result)
+			if err != nil {
+				s.logger.Warn("Failed to raise sanctions alert",
+					zap.String("address", result.Address),
+					zap.Error(err))
+				continue
+			}
+			if alertID != "" {
+				result.AlertIDs = append(result.AlertIDs, alertID)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// ScreenWatchedWallets screens every watched address and returns how many were
+// screened and how many new alerts were raised
+func (s *Service) ScreenWatchedWallets(ctx context.Context, maxHops int) (int, int, error) {
+	addresses, err := s.watchListRepo.GetAllWatchedAddresses(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	results, err := s.ScreenBatch(ctx, addresses, maxHops, true)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	alerts := 0
+	for _, result := range results {
+		alerts += len(result.AlertIDs)
+	}
+
+	return len(addresses), alerts, nil
+}
+
+// GetListVersions returns the import history, newest first
+func (s *Service) GetListVersions(ctx context.Context, source *entity.SanctionsSource, limit int) ([]entity.SanctionsListVersion, error) {
+	return s.sanctionsRepo.GetListVersions(ctx, source, limit)
+}
+
+// activeListVersions returns the IDs of the active version of every source
+func (s *Service) activeListVersions(ctx context.Context) ([]string, error) {
+	var versions []string
+	for _, source := range []entity.SanctionsSource{entity.SanctionsSourceOFACSDN, entity.SanctionsSourceGenericCSV} {
+		version, err := s.sanctionsRepo.GetActiveListVersion(ctx, source)
+		if err != nil {
+			return nil, err
+		}
+		if version != nil {
+			versions = append(versions, version.ID)
+		}
+	}
+	return versions, nil
+}
+
+// findExposures fills in graph exposures for every result
+func (s *Service) findExposures(ctx context.Context, results map[string]*entity.SanctionsScreeningResult, maxHops int) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, exposureConcurrency)
+	)
+
+	for _, result := range results {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(result *entity.SanctionsScreeningResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			exposures, err := s.sanctionsRepo.FindExposures(ctx, result.Address, maxHops)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				return
+			}
+			result.Exposures = exposures
+		}(result)
+	}
+
+	wg.Wait()
+	return firstErr
+}
+
+// raiseAlert creates a SANCTIONS alert for a hit. It returns an empty ID when an
+// alert with the same dedupe key already exists; the unique dedupe key index makes
+// this hold for concurrent screenings too.
+func (s *Service) raiseAlert(ctx context.Context, result *entity.SanctionsScreeningResult) (string, error) {
+	alert := buildAlert(result)
+	created, err := s.securityRepo.CreateSecurityAlertIfNew(ctx, alert)
+	if err != nil || !created {
+		return "", err
+	}
+	return alert.ID, nil
+}
+
+// buildAlert turns a screening hit into a security alert
+func buildAlert(result *entity.SanctionsScreeningResult) *entity.SecurityAlert {
+	alert := &entity.SecurityAlert{
+		Type:           entity.AlertTypeSanctions,
+		WalletAddress:  result.Address,
+		Timestamp:      result.ScreenedAt,
+		Status:         entity.AlertStatusActive,
+		ActionRequired: true,
+		Metadata: map[string]interface{}{
+			"list_versions": result.ListVersions,
+			"max_hops":      result.MaxHops,
+		},
+	}
+
+	if result.DirectMatch {
+		match := result.Matches[0]
+		alert.Severity = entity.AlertSeverityCritical
+		alert.Confidence = 100
+		alert.Title = "Sanctioned address match"
+		alert.Description = fmt.Sprintf("%s is designated on %s (%s) for %s",
+			result.Address, match.Source, strings.Join(match.Programs, ", "), match.EntityName)
+		alert.DedupeKey = fmt.Sprintf("sanctions:direct:%s", result.Address)
+		alert.Metadata["entity_name"] = match.EntityName
+		alert.Metadata["entity_id"] = match.EntityID
+		alert.Metadata["programs"] = match.Programs
+		alert.Metadata["source"] = string(match.Source)
+		return alert
+	}
+
+	closest := result.ClosestExposure()
+	switch closest.Hops {
+	case 1:
+		alert.Severity = entity.AlertSeverityHigh
+		alert.Confidence = 90
+	case 2:
+		alert.Severity = entity.AlertSeverityMedium
+		alert.Confidence = 70
+	default:
+		alert.Severity = entity.AlertSeverityLow
+		alert.Confidence = 50
+		alert.ActionRequired = false
+	}
+	alert.Title = "Exposure to sanctioned address"
+	alert.Description = fmt.Sprintf("%s is %d hop(s) from sanctioned address %s (%d sanctioned addresses within %d hops)",
+		result.Address, closest.Hops, closest.SanctionedAddress, len(result.Exposures), result.MaxHops)
+	alert.DedupeKey = fmt.Sprintf("sanctions:exposure:%s:%s:%d", result.Address, closest.SanctionedAddress, closest.Hops)
+	alert.Metadata["sanctioned_address"] = closest.SanctionedAddress
+	alert.Metadata["hops"] = closest.Hops
+	alert.Metadata["exposure_count"] = len(result.Exposures)
+
+	return alert
+}
+
+// clampHops applies the configured default and maximum screening depth
+func (s *Service) clampHops(maxHops int) int {
+	if maxHops < 0 {
+		maxHops = s.config.ScreeningHops
+	}
+	if s.config.MaxScreeningHops > 0 && maxHops > s.config.MaxScreeningHops {
+		maxHops = s.config.MaxScreeningHops
+	}
+	return maxHops
+}
+
+// resolvePath confines imports to the sanctions data directory
+func (s *Service) resolvePath(fileName string) (string, error) {
+	base, err := filepath.Abs(s.config.SanctionsDataDir)
+	if err != nil {
+		return "", fmt.Errorf("invalid sanctions data directory: %w", err)
+	}
+
+	path := filepath.Join(base, filepath.Clean("/"+fileName))
+	if !strings.HasPrefix(path, base+string(filepath.Separator)) {
+		return "", fmt.Errorf("sanctions list %q is outside the data directory", fileName)
+	}
+
+	return path, nil
+}
+
+// diffEntries counts addresses added and removed between two list versions
+func diffEntries(previous, current []entity.SanctionedAddress) (added, removed int) {
+	before := make(map[string]bool, len(previous))
+	for _, e := range previous {
+		before[e.Address] = true
+	}
+
+	after := make(map[string]bool, len(current))
+	for _, e := range current {
+		after[e.Address] = true
+		if !before[e.Address] {
+			added++
+		}
+	}
+
+	for address := range before {
+		if !after[address] {
+			removed++
+		}
+	}
+
+	return added, removed
+}
@@ -0,0 +1,157 @@
+package screening
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+)
+
+// RoutePrefix is where the REST screening endpoints are mounted
+const RoutePrefix = "/screening"
+
+// ResultsPath returns the download path for a job's results in the given format
+func ResultsPath(jobID, format string) string {
+	return fmt.Sprintf("%s/jobs/%s/results?format=%s", RoutePrefix, jobID, format)
+}
+
+// csvHeader is the column layout of screening result exports
+var csvHeader = []string{
+	"index", "address", "valid", "risk_score", "risk_level", "sanctioned",
+	"sanctions_programs", "sanctioned_entity", "direct_exposure", "indirect_exposure",
+	"closest_exposure_hops", "labels", "alert_ids", "error",
+}
+
+// WriteCSV streams a job's results as CSV in input order
+func (s *Service) WriteCSV(ctx context.Context, jobID string, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	err := s.screeningRepo.IterateResults(ctx, jobID, func(r entity.AddressScreeningResult) error {
+		return writer.Write([]string{
+			strconv.Itoa(r.Index),
+			r.Address,
+			strconv.FormatBool(r.Valid),
+			optionalInt(r.RiskScore),
+			string(r.RiskLevel),
+			strconv.FormatBool(r.Sanctioned),
+			strings.Join(r.SanctionsPrograms, ";"),
+			optionalString(r.SanctionedEntity),
+			strconv.Itoa(r.DirectExposure),
+			strconv.Itoa(r.IndirectExposure),
+			optionalInt(r.ClosestExposureHops),
+			strings.Join(r.Labels, ";"),
+			strings.Join(r.AlertIDs, ";"),
+			optionalString(r.Error),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON streams a job's results as a JSON array in input order
+func (s *Service) WriteJSON(ctx context.Context, jobID string, w io.Writer) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	first := true
+	err := s.screeningRepo.IterateResults(ctx, jobID, func(r entity.AddressScreeningResult) error {
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		data, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("failed to encode screening result: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]")
+	return err
+}
+
+// ParseAddressCSV reads addresses from an uploaded CSV file. If the first row
+// has an address column header that column is used, otherwise the first column.
+func ParseAddressCSV(r io.Reader) ([]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var (
+		addresses []string
+		column    = 0
+		first     = true
+	)
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV row: %w", err)
+		}
+
+		if first {
+			first = false
+			if col, ok := headerColumn(record); ok {
+				column = col
+				continue
+			}
+		}
+
+		if column >= len(record) {
+			continue
+		}
+		if address := strings.TrimSpace(record[column]); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+
+	return addresses, nil
+}
+
+// headerColumn finds the address column in a header row
+func headerColumn(record []string) (int, bool) {
+	for i, name := range record {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "address", "wallet_address", "wallet":
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func optionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func optionalString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
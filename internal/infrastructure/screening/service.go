@@ -0,0 +1,376 @@
+package screening
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/config"
+	"crypto-bubble-map-be/internal/infrastructure/sanctions"
+
+	"go.uber.org/zap"
+)
+
+// asyncJobTimeout bounds how long a background screening job may run
+const asyncJobTimeout = 2 * time.Hour
+
+// errStopped fails background jobs submitted while the service is stopping
+var errStopped = errors.New("screening service is shutting down")
+
+// Service runs bulk address screening jobs. Small batches are screened inline;
+// larger ones run in the background and are polled through the job.
+type Service struct {
+	walletRepo    repository.WalletRepository
+	screeningRepo repository.ScreeningRepository
+	sanctions     *sanctions.Service
+	config        *config.ComplianceConfig
+	logger        *zap.Logger
+
+	created time.Time
+	ctx     context.Context
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	stopped bool
+	jobs    sync.WaitGroup
+}
+
+// NewService creates a new bulk screening service
+func NewService(
+	walletRepo repository.WalletRepository,
+	screeningRepo repository.ScreeningRepository,
+	sanctionsService *sanctions.Service,
+	cfg *config.ComplianceConfig,
+	logger *zap.Logger,
+) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Service{
+		walletRepo:    walletRepo,
+		screeningRepo: screeningRepo,
+		sanctions:     sanctionsService,
+		config:        cfg,
+		logger:        logger,
+		created:       time.Now(),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// Start fails the background jobs left PENDING or RUNNING by a previous
+// process. Background jobs only run in the process that accepted them, so
+// any created before this service cannot make progress again.
+func (s *Service) Start() {
+	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+	defer cancel()
+
+	failed, err := s.screeningRepo.FailUnfinishedJobs(ctx, s.created, "screening job was interrupted by a server restart")
+	if err != nil {
+		s.logger.Error("Failed to fail interrupted screening jobs", zap.Error(err))
+		return
+	}
+	if failed > 0 {
+		s.logger.Warn("Failed screening jobs interrupted by a restart", zap.Int64("jobs", failed))
+	}
+}
+
+// Stop cancels the background jobs and waits for them to record their failure
+func (s *Service) Stop() {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return
+	}
+	s.stopped = true
+	s.mu.Unlock()
+
+	s.cancel()
+	s.jobs.Wait()
+	s.logger.Info("Stopped bulk screening jobs")
+}
+
+// Submit creates a screening job for addresses. Jobs up to BulkScreeningSyncLimit
+// addresses complete before Submit returns; larger jobs are returned PENDING.
+func (s *Service) Submit(ctx context.Context, addresses []string, options entity.ScreeningOptions, requestedBy uint, source string) (*entity.ScreeningJob, error) {
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("no addresses to screen")
+	}
+	if s.config.BulkScreeningMaxAddresses > 0 && len(addresses) > s.config.BulkScreeningMaxAddresses {
+		return nil, fmt.Errorf("too many addresses: %d exceeds the limit of %d", len(addresses), s.config.BulkScreeningMaxAddresses)
+	}
+
+	if !options.IncludeExposure {
+		options.Hops = 0
+	} else if options.Hops <= 0 {
+		options.Hops = s.config.ScreeningHops
+	}
+
+	job := &entity.ScreeningJob{
+		Status:         entity.ScreeningJobStatusPending,
+		RequestedBy:    requestedBy,
+		Source:         source,
+		Options:        options,
+		TotalAddresses: len(addresses),
+	}
+	if err := s.screeningRepo.CreateJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	if len(addresses) <= s.config.BulkScreeningSyncLimit {
+		s.run(ctx, job, addresses)
+		return job, nil
+	}
+
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		s.fail(job, errStopped)
+		return nil, errStopped
+	}
+	s.jobs.Add(1)
+	s.mu.Unlock()
+
+	go func() {
+		defer s.jobs.Done()
+		bgCtx, cancel := context.WithTimeout(s.ctx, asyncJobTimeout)
+		defer cancel()
+		s.run(bgCtx, job, addresses)
+	}()
+
+	s.logger.Info("Queued bulk screening job",
+		zap.String("jobID", job.ID),
+		zap.Int("addresses", len(addresses)))
+
+	return job, nil
+}
+
+// GetJob retrieves a screening job
+func (s *Service) GetJob(ctx context.Context, jobID string) (*entity.ScreeningJob, error) {
+	return s.screeningRepo.GetJob(ctx, jobID)
+}
+
+// GetResults retrieves a page of a job's results in input order
+func (s *Service) GetResults(ctx context.Context, jobID string, limit, offset int) ([]entity.AddressScreeningResult, error) {
+	return s.screeningRepo.GetResults(ctx, jobID, limit, offset)
+}
+
+// run screens the job's addresses batch by batch, persisting results and progress
+func (s *Service) run(ctx context.Context, job *entity.ScreeningJob, addresses []string) {
+	started := time.Now()
+	job.Status = entity.ScreeningJobStatusRunning
+	job.StartedAt = &started
+	if err := s.screeningRepo.UpdateJob(ctx, job); err != nil {
+		s.logger.Warn("Failed to mark screening job running", zap.String("jobID", job.ID), zap.Error(err))
+	}
+
+	batchSize := s.config.BulkScreeningBatchSize
+	if batchSize <= 0 {
+		batchSize = 200
+	}
+
+	for offset := 0; offset < len(addresses); offset += batchSize {
+		end := offset + batchSize
+		if end > len(addresses) {
+			end = len(addresses)
+		}
+
+		results, err := s.screenBatch(ctx, job, offset, addresses[offset:end])
+		if err == nil {
+			err = s.screeningRepo.SaveResults(ctx, results)
+		}
+		if err != nil {
+			s.fail(job, err)
+			return
+		}
+
+		for _, r := range results {
+			if !r.Valid {
+				job.InvalidCount++
+			}
+			if r.Sanctioned {
+				job.SanctionsHits++
+			}
+			if r.DirectExposure > 0 || r.IndirectExposure > 0 {
+				job.ExposureHits++
+			}
+		}
+		job.Processed = end
+
+		if err := s.screeningRepo.UpdateJob(ctx, job); err != nil {
+			s.logger.Warn("Failed to update screening job progress", zap.String("jobID", job.ID), zap.Error(err))
+		}
+	}
+
+	completed := time.Now()
+	job.Status = entity.ScreeningJobStatusCompleted
+	job.CompletedAt = &completed
+	if err := s.screeningRepo.UpdateJob(ctx, job); err != nil {
+		s.logger.Error("Failed to complete screening job", zap.String("jobID", job.ID), zap.Error(err))
+	}
+
+	s.logger.Info("Completed bulk screening job",
+		zap.String("jobID", job.ID),
+		zap.Int("addresses", job.TotalAddresses),
+		zap.Int("sanctionsHits", job.SanctionsHits),
+		zap.Int("exposureHits", job.ExposureHits),
+		zap.Duration("duration", completed.Sub(started)))
+}
+
+// fail marks a job failed. It uses a fresh context so that a cancelled job
+// context does not prevent the failure from being recorded.
+func (s *Service) fail(job *entity.ScreeningJob, cause error) {
+	s.logger.Error("Bulk screening job failed", zap.String("jobID", job.ID), zap.Error(cause))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	message := cause.Error()
+	completed := time.Now()
+	job.Status = entity.ScreeningJobStatusFailed
+	job.Error = &message
+	job.CompletedAt = &completed
+	if err := s.screeningRepo.UpdateJob(ctx, job); err != nil {
+		s.logger.Error("Failed to record screening job failure", zap.String("jobID", job.ID), zap.Error(err))
+	}
+}
+
+// screenBatch combines risk scores, sanctions hits, exposure and labels for one batch
+func (s *Service) screenBatch(ctx context.Context, job *entity.ScreeningJob, offset int, batch []string) ([]entity.AddressScreeningResult, error) {
+	results := make([]entity.AddressScreeningResult, len(batch))
+	valid := make([]string, 0, len(batch))
+
+	for i, raw := range batch {
+		address := entity.NormalizeAddress(raw)
+		results[i] = entity.AddressScreeningResult{
+			JobID:             job.ID,
+			Index:             offset + i,
+			Address:           address,
+			RiskLevel:         entity.RiskLevelUnknown,
+			SanctionsPrograms: []string{},
+			Labels:            []string{},
+			AlertIDs:          []string{},
+		}
+		if !entity.IsValidAddress(address) {
+			message := "invalid address"
+			results[i].Error = &message
+			continue
+		}
+		results[i].Valid = true
+		valid = append(valid, address)
+	}
+
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	scores, err := s.walletRepo.GetRiskScores(ctx, valid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk scores: %w", err)
+	}
+	scoresByAddress := make(map[string]entity.RiskScore, len(scores))
+	for _, score := range scores {
+		scoresByAddress[entity.NormalizeAddress(score.Address)] = score
+	}
+
+	hits, err := s.sanctions.ScreenBatch(ctx, valid, job.Options.Hops, job.Options.RaiseAlerts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to screen sanctions: %w", err)
+	}
+
+	labels := map[string][]string{}
+	if job.Options.IncludeLabels {
+		labels, err = s.labels(ctx, valid)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range results {
+		r := &results[i]
+		if !r.Valid {
+			continue
+		}
+
+		if score, ok := scoresByAddress[r.Address]; ok {
+			total := score.TotalScore
+			r.RiskScore = &total
+			r.RiskLevel = score.RiskLevel
+		}
+
+		if hit, ok := hits[r.Address]; ok {
+			applySanctionsHit(r, hit)
+		}
+
+		if l, ok := labels[r.Address]; ok {
+			r.Labels = l
+		}
+	}
+
+	return results, nil
+}
+
+// labels collects the known labels, tags and wallet type of each address
+func (s *Service) labels(ctx context.Context, addresses []string) (map[string][]string, error) {
+	wallets, err := s.walletRepo.GetWalletsByAddresses(ctx, addresses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet labels: %w", err)
+	}
+
+	labels := make(map[string][]string, len(wallets))
+	for _, w := range wallets {
+		var l []string
+		if w.Label != nil && *w.Label != "" {
+			l = append(l, *w.Label)
+		}
+		if w.WalletType != "" {
+			l = append(l, string(w.WalletType))
+		}
+		l = append(l, w.Tags...)
+		labels[entity.NormalizeAddress(w.Address)] = l
+	}
+
+	return labels, nil
+}
+
+// applySanctionsHit copies a sanctions screening result into a bulk result.
+// A sanctioned address is always reported as critical risk.
+func applySanctionsHit(r *entity.AddressScreeningResult, hit *entity.SanctionsScreeningResult) {
+	if hit.DirectMatch {
+		r.Sanctioned = true
+		r.RiskLevel = entity.RiskLevelCritical
+		score := 100
+		r.RiskScore = &score
+
+		seen := map[string]bool{}
+		for _, m := range hit.Matches {
+			for _, p := range m.Programs {
+				if !seen[p] {
+					seen[p] = true
+					r.SanctionsPrograms = append(r.SanctionsPrograms, p)
+				}
+			}
+			if r.SanctionedEntity == nil && m.EntityName != "" {
+				name := m.EntityName
+				r.SanctionedEntity = &name
+			}
+		}
+	}
+
+	for _, e := range hit.Exposures {
+		if e.Hops == 1 {
+			r.DirectExposure++
+		} else {
+			r.IndirectExposure++
+		}
+	}
+	if closest := hit.ClosestExposure(); closest != nil {
+		hops := closest.Hops
+		r.ClosestExposureHops = &hops
+	}
+
+	if len(hit.AlertIDs) > 0 {
+		r.AlertIDs = append(r.AlertIDs, hit.AlertIDs...)
+	}
+}
@@ -0,0 +1,368 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/domain/repository"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultLimit is the number of results returned when no limit is given
+	DefaultLimit = 20
+	// MaxLimit caps the number of results of one search
+	MaxLimit = 100
+
+	maxQueryLength = 200
+)
+
+// typeOrder breaks relevance ties between result types
+var typeOrder = map[entity.SearchResultType]int{
+	entity.SearchResultTypeWallet:      0,
+	entity.SearchResultTypeTransaction: 1,
+	entity.SearchResultTypeBlock:       2,
+	entity.SearchResultTypeToken:       3,
+	entity.SearchResultTypeLabel:       4,
+}
+
+// Service searches wallets, transactions, blocks, tokens and registry labels
+// with one query. The query's kind decides which lookups run; each lookup is
+// served by an index, and the results are merged into one ranking by relevance.
+type Service struct {
+	walletRepo      repository.WalletRepository
+	transactionRepo repository.TransactionRepository
+	labelRepo       repository.LabelRepository
+	logger          *zap.Logger
+}
+
+// NewService creates a new unified search service
+func NewService(
+	walletRepo repository.WalletRepository,
+	transactionRepo repository.TransactionRepository,
+	labelRepo repository.LabelRepository,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		walletRepo:      walletRepo,
+		transactionRepo: transactionRepo,
+		labelRepo:       labelRepo,
+		logger:          logger,
+	}
+}
+
+// Search runs the lookups for the query's kind and returns the best limit
+// results of the requested types; no types means every type. A lookup that
+// fails is logged and left out, so the search only fails when every lookup does.
+func (s *Service) Search(ctx context.Context, query string, types []entity.SearchResultType, limit int) (*entity.SearchResponse, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, apperrors.NewValidationError("query", "A search query is required")
+	}
+	if len(query) > maxQueryLength {
+		return nil, apperrors.NewValidationError("query", fmt.Sprintf("Search queries are limited to %d characters", maxQueryLength))
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	kind := entity.DetectSearchKind(query)
+	wanted := func(resultType entity.SearchResultType) bool {
+		if len(types) == 0 {
+			return true
+		}
+		for _, t := range types {
+			if t == resultType {
+				return true
+			}
+		}
+		return false
+	}
+
+	var lookups []func(context.Context) ([]entity.SearchResult, error)
+	if wanted(entity.SearchResultTypeWallet) {
+		lookups = append(lookups, func(ctx context.Context) ([]entity.SearchResult, error) {
+			return s.searchWallets(ctx, query, limit)
+		})
+	}
+	if wanted(entity.SearchResultTypeTransaction) || (wanted(entity.SearchResultTypeBlock) && kind == entity.SearchQueryKindBlockNumber) {
+		lookups = append(lookups, func(ctx context.Context) ([]entity.SearchResult, error) {
+			return s.searchTransactions(ctx, query, kind, limit, wanted(entity.SearchResultTypeTransaction), wanted(entity.SearchResultTypeBlock))
+		})
+	}
+	if wanted(entity.SearchResultTypeToken) {
+		lookups = append(lookups, func(ctx context.Context) ([]entity.SearchResult, error) {
+			return s.searchTokens(ctx, query, limit)
+		})
+	}
+	if wanted(entity.SearchResultTypeLabel) {
+		lookups = append(lookups, func(ctx context.Context) ([]entity.SearchResult, error) {
+			return s.searchLabels(ctx, query, kind, limit)
+		})
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		results  = []entity.SearchResult{}
+		failures int
+		lastErr  error
+	)
+	for _, lookup := range lookups {
+		wg.Add(1)
+		go func(lookup func(context.Context) ([]entity.SearchResult, error)) {
+			defer wg.Done()
+			found, err := lookup(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failures++
+				lastErr = err
+				s.logger.Warn("Search lookup failed",
+					zap.String("query", query),
+					zap.String("kind", string(kind)),
+					zap.Error(err))
+				return
+			}
+			results = append(results, found...)
+		}(lookup)
+	}
+	wg.Wait()
+
+	if len(lookups) > 0 && failures == len(lookups) {
+		return nil, fmt.Errorf("failed to search: %w", lastErr)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.RelevanceScore != b.RelevanceScore {
+			return a.RelevanceScore > b.RelevanceScore
+		}
+		if typeOrder[a.Type] != typeOrder[b.Type] {
+			return typeOrder[a.Type] < typeOrder[b.Type]
+		}
+		return a.ID < b.ID
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return &entity.SearchResponse{
+		Query:   query,
+		Kind:    kind,
+		Results: results,
+	}, nil
+}
+
+func (s *Service) searchWallets(ctx context.Context, query string, limit int) ([]entity.SearchResult, error) {
+	wallets, err := s.walletRepo.SearchWallets(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]entity.SearchResult, 0, len(wallets))
+	for i := range wallets {
+		wallet := &wallets[i]
+		result := entity.SearchResult{
+			Type:           entity.SearchResultTypeWallet,
+			ID:             wallet.Address,
+			Title:          wallet.Address,
+			RelevanceScore: wallet.RelevanceScore,
+			MatchedField:   wallet.MatchedField,
+			Address:        &wallet.Address,
+			LabelCategory:  wallet.LabelCategory,
+		}
+		switch {
+		case wallet.Label != nil:
+			result.Title = *wallet.Label
+			result.Subtitle = &wallet.Address
+		case wallet.ENSName != nil:
+			result.Title = *wallet.ENSName
+			result.Subtitle = &wallet.Address
+		}
+		if wallet.WalletType != "" {
+			result.WalletType = &wallet.WalletType
+		}
+		if wallet.RiskLevel != "" {
+			result.RiskLevel = &wallet.RiskLevel
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// searchTransactions returns the transactions found for the query and, for a
+// block number, the block itself
+func (s *Service) searchTransactions(ctx context.Context, query string, kind entity.SearchQueryKind, limit int, includeTransactions, includeBlock bool) ([]entity.SearchResult, error) {
+	transactions, err := s.transactionRepo.SearchTransactions(ctx, query, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	var results []entity.SearchResult
+	if includeBlock && kind == entity.SearchQueryKindBlockNumber && len(transactions) > 0 {
+		subtitle := fmt.Sprintf("%d transactions", len(transactions))
+		if len(transactions) == limit {
+			subtitle = fmt.Sprintf("%d+ transactions", limit)
+		}
+		block := query
+		results = append(results, entity.SearchResult{
+			Type:           entity.SearchResultTypeBlock,
+			ID:             query,
+			Title:          "Block " + query,
+			Subtitle:       &subtitle,
+			RelevanceScore: 1,
+			MatchedField:   "block_number",
+			BlockNumber:    &block,
+			Timestamp:      &transactions[0].Timestamp,
+		})
+	}
+	if !includeTransactions {
+		return results, nil
+	}
+
+	normalized := entity.NormalizeAddress(query)
+	for i := range transactions {
+		tx := &transactions[i]
+		score, matchedField := transactionRelevance(tx, normalized, kind, i, len(transactions))
+		subtitle := tx.From
+		if tx.To != nil {
+			subtitle = tx.From + " → " + *tx.To
+		}
+		results = append(results, entity.SearchResult{
+			Type:            entity.SearchResultTypeTransaction,
+			ID:              tx.Hash,
+			Title:           tx.Hash,
+			Subtitle:        &subtitle,
+			RelevanceScore:  score,
+			MatchedField:    matchedField,
+			TransactionHash: &tx.Hash,
+			BlockNumber:     &tx.BlockNumber,
+			Timestamp:       &tx.Timestamp,
+		})
+	}
+	return results, nil
+}
+
+// transactionRelevance scores a transaction found for the query. An exact hash
+// is a perfect match; a hash prefix ranks by how much of the hash it covers.
+// Transactions of an address, block or address prefix rank below the wallet or
+// block itself, most recent (or earliest in the block) first.
+func transactionRelevance(tx *entity.Transaction, query string, kind entity.SearchQueryKind, position, total int) (float64, string) {
+	// decay spreads a list's results over 0.1 below its base score in list order
+	decay := 0.1 * float64(position) / float64(total)
+
+	switch kind {
+	case entity.SearchQueryKindTransactionHash:
+		return 1, "hash"
+	case entity.SearchQueryKindHexPrefix:
+		if strings.HasPrefix(strings.ToLower(tx.Hash), query) {
+			return 0.5 + 0.4*float64(len(query))/float64(len(tx.Hash)), "hash"
+		}
+		if strings.HasPrefix(strings.ToLower(tx.From), query) {
+			return 0.4 - decay, "from"
+		}
+		return 0.4 - decay, "to"
+	case entity.SearchQueryKindBlockNumber:
+		return 0.7 - decay, "block_number"
+	default:
+		if strings.EqualFold(tx.From, query) {
+			return 0.6 - decay, "from"
+		}
+		return 0.6 - decay, "to"
+	}
+}
+
+func (s *Service) searchTokens(ctx context.Context, query string, limit int) ([]entity.SearchResult, error) {
+	tokens, err := s.transactionRepo.SearchTokens(ctx, query, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]entity.SearchResult, 0, len(tokens))
+	for i := range tokens {
+		token := &tokens[i]
+		title := token.Symbol
+		if title == "" {
+			title = token.Name
+		}
+		if title == "" {
+			title = token.Address
+		}
+		subtitle := fmt.Sprintf("%s · %d transfers", token.Address, token.TransactionCount)
+		if token.Name != "" && token.Name != title {
+			subtitle = token.Name + " · " + subtitle
+		}
+		results = append(results, entity.SearchResult{
+			Type:           entity.SearchResultTypeToken,
+			ID:             token.Address,
+			Title:          title,
+			Subtitle:       &subtitle,
+			RelevanceScore: token.RelevanceScore,
+			MatchedField:   token.MatchedField,
+			Address:        &token.Address,
+		})
+	}
+	return results, nil
+}
+
+// searchLabels returns the registry labels of an address, or the labels whose
+// text matches the query
+func (s *Service) searchLabels(ctx context.Context, query string, kind entity.SearchQueryKind, limit int) ([]entity.SearchResult, error) {
+	var matches []entity.LabelMatch
+	switch kind {
+	case entity.SearchQueryKindAddress:
+		labels, err := s.labelRepo.GetLabelsByAddress(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		attribution := entity.ResolveAttribution(query, labels, time.Now())
+		for _, label := range attribution.Labels {
+			// An address's labels rank just below the wallet itself
+			matches = append(matches, entity.LabelMatch{Label: label, RelevanceScore: 0.9 * label.Confidence})
+		}
+	case entity.SearchQueryKindText, entity.SearchQueryKindENSName:
+		var err error
+		matches, err = s.labelRepo.SearchLabels(ctx, query, limit)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+
+	results := make([]entity.SearchResult, 0, len(matches))
+	for i := range matches {
+		label := &matches[i].Label
+		subtitle := label.Address + " · " + label.Source
+		if label.Entity != nil {
+			subtitle = *label.Entity + " · " + subtitle
+		}
+		matchedField := "name"
+		if kind == entity.SearchQueryKindAddress {
+			matchedField = "address"
+		}
+		results = append(results, entity.SearchResult{
+			Type:           entity.SearchResultTypeLabel,
+			ID:             label.ID,
+			Title:          label.Name,
+			Subtitle:       &subtitle,
+			RelevanceScore: matches[i].RelevanceScore,
+			MatchedField:   matchedField,
+			Address:        &label.Address,
+			LabelCategory:  &label.Category,
+		})
+	}
+	return results, nil
+}
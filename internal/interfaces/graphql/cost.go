@@ -0,0 +1,204 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"crypto-bubble-map-be/graph/generated"
+	"crypto-bubble-map-be/internal/domain/entity"
+	"crypto-bubble-map-be/internal/infrastructure/config"
+	"crypto-bubble-map-be/internal/infrastructure/middleware"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	operationCostExtension = "OperationCost"
+
+	errCodeComplexityLimit = "COMPLEXITY_LIMIT_EXCEEDED"
+	errCodeDepthLimit      = "DEPTH_LIMIT_EXCEEDED"
+	errCodeRateLimit       = "RATE_LIMIT_EXCEEDED"
+
+	defaultSearchLimit = 20
+)
+
+// OperationCostStats is reported under the "OperationCost" stats extension
+type OperationCostStats struct {
+	Complexity      int
+	ComplexityLimit int
+	Depth           int
+	DepthLimit      int
+	Cost            int64
+}
+
+// OperationCost enforces complexity and depth limits on every operation and
+// charges the caller's rate limit budget in proportion to the operation's complexity.
+type OperationCost struct {
+	config *config.GraphQLConfig
+	es     graphql.ExecutableSchema
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = &OperationCost{}
+
+// NewOperationCost creates the operation cost extension
+func NewOperationCost(cfg *config.GraphQLConfig) *OperationCost {
+	return &OperationCost{config: cfg}
+}
+
+// ExtensionName implements graphql.HandlerExtension
+func (o OperationCost) ExtensionName() string {
+	return operationCostExtension
+}
+
+// Validate implements graphql.HandlerExtension
+func (o *OperationCost) Validate(schema graphql.ExecutableSchema) error {
+	if o.config == nil {
+		return errors.New("OperationCost config can not be nil")
+	}
+	o.es = schema
+	return nil
+}
+
+// MutateOperationContext implements graphql.OperationContextMutator
+func (o OperationCost) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	op := opCtx.Doc.Operations.ForName(opCtx.OperationName)
+	if op == nil {
+		return nil
+	}
+
+	stats := &OperationCostStats{
+		Complexity:      complexity.Calculate(ctx, o.es, op, opCtx.Variables),
+		ComplexityLimit: o.config.ComplexityLimit,
+		Depth:           selectionSetDepth(op.SelectionSet, map[string]bool{}),
+		DepthLimit:      o.config.DepthLimit,
+	}
+	stats.Cost = o.tokenCost(stats.Complexity)
+	opCtx.Stats.SetExtension(operationCostExtension, stats)
+
+	if stats.DepthLimit > 0 && stats.Depth > stats.DepthLimit {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", stats.Depth, stats.DepthLimit)
+		errcode.Set(err, errCodeDepthLimit)
+		return err
+	}
+
+	if stats.ComplexityLimit > 0 && stats.Complexity > stats.ComplexityLimit {
+		err := gqlerror.Errorf("operation has complexity %d, which exceeds the limit of %d", stats.Complexity, stats.ComplexityLimit)
+		errcode.Set(err, errCodeComplexityLimit)
+		return err
+	}
+
+	budget, ok := middleware.RateLimitBudgetFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	// An operation within the complexity limit must be affordable with a full
+	// bucket, whatever the cost unit and burst are configured to
+	if capacity := budget.Capacity(); stats.Cost > capacity {
+		stats.Cost = capacity
+	}
+
+	result, err := budget.Charge(ctx, stats.Cost)
+	if err != nil {
+		// Fail open when the limiter backend is unavailable
+		return nil
+	}
+
+	if !result.Allowed {
+		err := gqlerror.Errorf("rate limit exceeded: operation costs %d tokens, %d remaining", stats.Cost, result.Remaining)
+		errcode.Set(err, errCodeRateLimit)
+		err.Extensions["retryAfterSeconds"] = int64(result.RetryAfter.Seconds() + 0.999)
+		return err
+	}
+
+	return nil
+}
+
+// tokenCost converts complexity points into rate limit tokens
+func (o OperationCost) tokenCost(complexity int) int64 {
+	unit := o.config.CostUnit
+	if unit <= 0 {
+		return 1
+	}
+
+	cost := int64((complexity + unit - 1) / unit)
+	if cost < 1 {
+		cost = 1
+	}
+	return cost
+}
+
+// GetOperationCostStats returns the cost stats computed for the current operation
+func GetOperationCostStats(ctx context.Context) *OperationCostStats {
+	opCtx := graphql.GetOperationContext(ctx)
+	if opCtx == nil {
+		return nil
+	}
+
+	s, _ := opCtx.Stats.GetExtension(operationCostExtension).(*OperationCostStats)
+	return s
+}
+
+// selectionSetDepth returns the maximum field nesting of a selection set.
+// Fragments are expanded in place and introspection fields are ignored.
+func selectionSetDepth(selectionSet ast.SelectionSet, visiting map[string]bool) int {
+	maxDepth := 0
+	for _, selection := range selectionSet {
+		depth := 0
+		switch sel := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(sel.Name, "__") {
+				continue
+			}
+			depth = 1 + selectionSetDepth(sel.SelectionSet, visiting)
+		case *ast.InlineFragment:
+			depth = selectionSetDepth(sel.SelectionSet, visiting)
+		case *ast.FragmentSpread:
+			if sel.Definition == nil || visiting[sel.Name] {
+				continue
+			}
+			visiting[sel.Name] = true
+			depth = selectionSetDepth(sel.Definition.SelectionSet, visiting)
+			delete(visiting, sel.Name)
+		}
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+	}
+	return maxDepth
+}
+
+// configureComplexity weights fields whose cost grows with their arguments
+func configureComplexity(c *generated.ComplexityRoot) {
+	c.Query.WalletNetwork = func(childComplexity int, input entity.WalletNetworkInput) int {
+		depth := input.Depth
+		if depth < 1 {
+			depth = 1
+		}
+		// Every additional hop expands another ring of the network
+		return 5 * depth * (childComplexity + 1)
+	}
+
+	c.Query.SearchWallets = func(childComplexity int, query string, limit *int) int {
+		n := defaultSearchLimit
+		if limit != nil && *limit > 0 {
+			n = *limit
+		}
+		return n * (childComplexity + 1)
+	}
+	c.Query.Search = func(childComplexity int, query string, types []entity.SearchResultType, limit *int) int {
+		n := defaultSearchLimit
+		if limit != nil && *limit > 0 {
+			n = *limit
+		}
+		return n * (childComplexity + 1)
+	}
+}
@@ -0,0 +1,153 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+	"crypto-bubble-map-be/internal/infrastructure/middleware"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// ErrorPresenter turns resolver errors into GraphQL errors whose extensions
+// carry the AppError code, whether the request may be retried and the request
+// ID. Errors that are not AppErrors are classified from their cause, so that
+// clients can tell a missing wallet from an unavailable database. In
+// production, server errors are reported without their details.
+type ErrorPresenter struct {
+	production bool
+	collector  *apperrors.ErrorCollector
+	logger     *zap.Logger
+}
+
+// NewErrorPresenter creates an error presenter feeding collector, when given
+func NewErrorPresenter(production bool, collector *apperrors.ErrorCollector, logger *zap.Logger) *ErrorPresenter {
+	return &ErrorPresenter{
+		production: production,
+		collector:  collector,
+		logger:     logger,
+	}
+}
+
+// Present implements graphql.ErrorPresenterFunc
+func (p *ErrorPresenter) Present(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = map[string]interface{}{}
+	}
+	requestID := middleware.RequestIDFromContext(ctx)
+	if requestID != "" {
+		gqlErr.Extensions["requestId"] = requestID
+	}
+
+	// Parse, validation and operation cost errors already carry a code
+	if code, ok := gqlErr.Extensions["code"].(string); ok {
+		if _, isAppErr := apperrors.AsAppError(err); !isAppErr {
+			gqlErr.Extensions["retryable"] = code == errCodeRateLimit
+			return gqlErr
+		}
+	}
+
+	appErr := classifyError(err)
+	if requestID != "" && appErr.RequestID == "" {
+		appErr.RequestID = requestID
+	}
+	operation := operationForError(ctx)
+	if p.collector != nil {
+		p.collector.Collect(appErr, "graphql", operation)
+	}
+
+	serverError := appErr.HTTPStatus >= 500
+	if serverError {
+		p.logger.Error("GraphQL operation failed",
+			zap.String("request_id", requestID),
+			zap.String("operation", operation),
+			zap.String("path", gqlErr.Path.String()),
+			zap.String("code", string(appErr.Code)),
+			zap.Error(err))
+	}
+
+	gqlErr.Extensions["code"] = string(appErr.Code)
+	gqlErr.Extensions["retryable"] = apperrors.IsRetryable(appErr)
+	switch {
+	case !p.production:
+		// Full error chain, including driver messages, for development
+		gqlErr.Message = err.Error()
+		if appErr.Details != "" && appErr.Details != gqlErr.Message {
+			gqlErr.Extensions["details"] = appErr.Details
+		}
+	case serverError:
+		gqlErr.Message = appErr.Message
+	default:
+		// Client errors explain what to fix, e.g. which field failed validation
+		gqlErr.Message = appErr.Message
+		if appErr.Details != "" {
+			gqlErr.Message += ": " + appErr.Details
+		}
+	}
+	if !serverError || !p.production {
+		if field, ok := appErr.Metadata["field"].(string); ok {
+			gqlErr.Extensions["field"] = field
+		}
+	}
+	return gqlErr
+}
+
+// Recover implements graphql.RecoverFunc. The panic is logged with its stack
+// and reported to the client as an internal error.
+func (p *ErrorPresenter) Recover(ctx context.Context, recovered interface{}) error {
+	p.logger.Error("Panic in GraphQL resolver",
+		zap.String("request_id", middleware.RequestIDFromContext(ctx)),
+		zap.Any("panic", recovered),
+		zap.ByteString("stack", debug.Stack()))
+
+	return apperrors.NewInternalError("resolver panicked", fmt.Errorf("panic: %v", recovered))
+}
+
+// classifyError returns the AppError in err's chain or, failing that, one
+// describing its cause
+func classifyError(err error) *apperrors.AppError {
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		return appErr
+	}
+
+	var (
+		connectivityErr *neo4j.ConnectivityError
+		retryLimitErr   *neo4j.TransactionExecutionLimit
+		neo4jErr        *neo4j.Neo4jError
+	)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return apperrors.NewAppError(apperrors.ErrCodeDatabaseTimeout, "Request timeout", err.Error()).WithCause(err)
+	case errors.Is(err, context.Canceled):
+		return apperrors.NewAppError(apperrors.ErrCodeServiceUnavailable, "Request cancelled", err.Error()).WithCause(err)
+	case errors.As(err, &connectivityErr), errors.As(err, &retryLimitErr), mongo.IsNetworkError(err):
+		return apperrors.NewAppError(apperrors.ErrCodeDatabaseConnection, "Database unavailable", err.Error()).WithCause(err)
+	case mongo.IsTimeout(err):
+		return apperrors.NewAppError(apperrors.ErrCodeDatabaseTimeout, "Database timeout", err.Error()).WithCause(err)
+	case errors.As(err, &neo4jErr):
+		return apperrors.NewDatabaseError("run graph query", err)
+	default:
+		return apperrors.NewInternalError(err.Error(), err)
+	}
+}
+
+// operationForError names the operation an error belongs to by its first root
+// field, which is bounded by the schema
+func operationForError(ctx context.Context) string {
+	if !graphql.HasOperationContext(ctx) {
+		return "unknown"
+	}
+	oc := graphql.GetOperationContext(ctx)
+	if oc.Operation == nil {
+		return "unknown"
+	}
+	return rootFieldName(oc.Operation)
+}
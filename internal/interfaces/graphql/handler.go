@@ -1,41 +1,165 @@
 package graphql
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"crypto-bubble-map-be/graph"
 	"crypto-bubble-map-be/graph/generated"
+	"crypto-bubble-map-be/internal/domain/repository"
+	"crypto-bubble-map-be/internal/infrastructure/config"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
 	"crypto-bubble-map-be/internal/infrastructure/logger"
+	"crypto-bubble-map-be/internal/infrastructure/middleware"
+	"crypto-bubble-map-be/internal/infrastructure/monitoring"
 
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.uber.org/zap"
 )
 
 // Handler represents the GraphQL handler
 type Handler struct {
-	resolver *graph.Resolver
-	logger   *logger.Logger
+	resolver     *graph.Resolver
+	userRepo     repository.UserRepository
+	config       *config.GraphQLConfig
+	serverConfig *config.ServerConfig
+	appConfig    *config.AppConfig
+	monitor      *monitoring.PerformanceMonitor
+	errors       *apperrors.ErrorCollector
+	logger       *logger.Logger
 }
 
 // NewHandler creates a new GraphQL handler
-func NewHandler(resolver *graph.Resolver, logger *logger.Logger) *Handler {
+func NewHandler(
+	resolver *graph.Resolver,
+	userRepo repository.UserRepository,
+	cfg *config.GraphQLConfig,
+	serverCfg *config.ServerConfig,
+	appCfg *config.AppConfig,
+	monitor *monitoring.PerformanceMonitor,
+	errorCollector *apperrors.ErrorCollector,
+	logger *logger.Logger,
+) *Handler {
 	return &Handler{
-		resolver: resolver,
-		logger:   logger,
+		resolver:     resolver,
+		userRepo:     userRepo,
+		config:       cfg,
+		serverConfig: serverCfg,
+		appConfig:    appCfg,
+		monitor:      monitor,
+		errors:       errorCollector,
+		logger:       logger,
 	}
 }
 
 // GraphQLHandler returns a Gin handler for GraphQL requests
 func (h *Handler) GraphQLHandler() gin.HandlerFunc {
-	// Create GraphQL server
-	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{
+	schemaConfig := generated.Config{
 		Resolvers: h.resolver,
-	}))
+	}
+	configureComplexity(&schemaConfig.Complexity)
+
+	// Create GraphQL server; subscriptions are served over websockets
+	srv := handler.New(generated.NewExecutableSchema(schemaConfig))
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: h.checkOrigin,
+		},
+		InitFunc: h.websocketInit,
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+
+	// Map AppError codes to error extensions and hide internal details in production
+	presenter := NewErrorPresenter(h.appConfig.Environment == "production", h.errors, h.logger.Logger)
+	srv.SetErrorPresenter(presenter.Present)
+	srv.SetRecoverFunc(presenter.Recover)
+
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{
+		Cache: lru.New[string](100),
+	})
+
+	// Enforce complexity and depth limits and charge the caller's rate limit budget
+	srv.Use(NewOperationCost(h.config))
+
+	// Operation spans, with resolver field spans when GraphQL tracing is enabled
+	srv.Use(NewTracing(h.config.EnableTracing))
+
+	// Operation and resolver latency histograms
+	if h.monitor != nil {
+		srv.Use(NewMetrics(h.monitor))
+	}
 
 	return gin.WrapH(srv)
 }
 
+// websocketInit authenticates a websocket connection. Browsers cannot set headers
+// on the upgrade request, so the session token may instead be sent as
+// "Authorization" in the connection_init payload.
+func (h *Handler) websocketInit(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+	if _, ok := middleware.UserFromContext(ctx); ok {
+		return ctx, nil, nil
+	}
+
+	token := strings.TrimSpace(payload.Authorization())
+	if len(token) > 7 && strings.EqualFold(token[:7], "Bearer ") {
+		token = strings.TrimSpace(token[7:])
+	}
+	if token == "" {
+		// Anonymous connection; resolvers decide what requires a user
+		return ctx, nil, nil
+	}
+
+	user, err := h.userRepo.GetSession(ctx, token)
+	if err != nil {
+		h.logger.Warn("Failed to resolve websocket session", zap.Error(err))
+		return ctx, nil, errors.New("failed to resolve session")
+	}
+	if user == nil {
+		return ctx, nil, errors.New("invalid or expired session")
+	}
+
+	return middleware.WithUser(ctx, user), nil, nil
+}
+
+// checkOrigin accepts websocket upgrades from the configured CORS origins or,
+// with CORS disabled, from the same host
+func (h *Handler) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	if h.serverConfig != nil && h.serverConfig.EnableCORS {
+		for _, allowed := range h.serverConfig.CORSAllowedOrigins {
+			if allowed == "*" || allowed == origin {
+				return true
+			}
+		}
+		return false
+	}
+
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
 // PlaygroundHandler returns a Gin handler for GraphQL playground
 func (h *Handler) PlaygroundHandler() gin.HandlerFunc {
 	playgroundHandler := playground.Handler("GraphQL Playground", "/graphql")
@@ -0,0 +1,87 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"crypto-bubble-map-be/internal/infrastructure/monitoring"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const metricsExtension = "Metrics"
+
+// Metrics records the latency of every query and mutation and of every
+// resolver field
+type Metrics struct {
+	monitor *monitoring.PerformanceMonitor
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = Metrics{}
+
+// NewMetrics creates the metrics extension
+func NewMetrics(monitor *monitoring.PerformanceMonitor) Metrics {
+	return Metrics{monitor: monitor}
+}
+
+// ExtensionName implements graphql.HandlerExtension
+func (m Metrics) ExtensionName() string {
+	return metricsExtension
+}
+
+// Validate implements graphql.HandlerExtension
+func (m Metrics) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptResponse times queries and mutations. Subscriptions are long
+// lived, so only their field resolvers are timed.
+func (m Metrics) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+	oc := graphql.GetOperationContext(ctx)
+	if oc.Operation == nil || oc.Operation.Operation == ast.Subscription {
+		return next(ctx)
+	}
+
+	start := time.Now()
+	resp := next(ctx)
+
+	errorCount := 0
+	if resp != nil {
+		errorCount = len(resp.Errors)
+	}
+	m.monitor.TrackGraphQLOperation(string(oc.Operation.Operation), rootFieldName(oc.Operation), time.Since(start), errorCount)
+	return resp
+}
+
+// InterceptField times resolver calls. Fields read from an already loaded
+// object are not timed.
+func (m Metrics) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	start := time.Now()
+	result, err := next(ctx)
+	m.monitor.TrackGraphQLField(fc.Object, fc.Field.Name, time.Since(start), err)
+	return result, err
+}
+
+// rootFieldName returns the first root field of an operation, which unlike
+// the operation name is bounded by the schema
+func rootFieldName(operation *ast.OperationDefinition) string {
+	for _, selection := range operation.SelectionSet {
+		if field, ok := selection.(*ast.Field); ok {
+			return field.Name
+		}
+	}
+	return "unknown"
+}
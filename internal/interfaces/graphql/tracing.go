@@ -0,0 +1,105 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"crypto-bubble-map-be/internal/infrastructure/monitoring"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracingExtension = "Tracing"
+
+// Tracing records a span per GraphQL operation and, when fields is set, a
+// child span per resolver field. Spans are no-ops unless tracing is enabled.
+type Tracing struct {
+	fields bool
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = Tracing{}
+
+// NewTracing creates the tracing extension
+func NewTracing(fields bool) Tracing {
+	return Tracing{fields: fields}
+}
+
+// ExtensionName implements graphql.HandlerExtension
+func (t Tracing) ExtensionName() string {
+	return tracingExtension
+}
+
+// Validate implements graphql.HandlerExtension
+func (t Tracing) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptResponse wraps a query or mutation in an operation span.
+// Subscriptions are long lived, so only their field spans are recorded.
+func (t Tracing) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+	oc := graphql.GetOperationContext(ctx)
+	if oc.Operation == nil || oc.Operation.Operation == ast.Subscription {
+		return next(ctx)
+	}
+
+	name := operationName(oc)
+	ctx, span := monitoring.Tracer().Start(ctx, fmt.Sprintf("graphql.%s %s", oc.Operation.Operation, name),
+		trace.WithAttributes(
+			attribute.String("graphql.operation.type", string(oc.Operation.Operation)),
+			attribute.String("graphql.operation.name", name),
+			attribute.String("graphql.document", oc.RawQuery),
+		))
+	defer span.End()
+
+	resp := next(ctx)
+	if resp != nil && len(resp.Errors) > 0 {
+		span.SetAttributes(attribute.Int("graphql.errors", len(resp.Errors)))
+		span.SetStatus(codes.Error, resp.Errors.Error())
+	}
+	return resp
+}
+
+// InterceptField wraps each resolver call in a field span. Fields read from
+// an already loaded object are not traced.
+func (t Tracing) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if !t.fields || fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	ctx, span := monitoring.Tracer().Start(ctx, fmt.Sprintf("graphql.resolve %s.%s", fc.Object, fc.Field.Name),
+		trace.WithAttributes(
+			attribute.String("graphql.field.path", fc.Path().String()),
+			attribute.String("graphql.field.type", fc.Field.Definition.Type.String()),
+		))
+	defer span.End()
+
+	result, err := next(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return result, err
+}
+
+// operationName names anonymous operations after their first root field
+func operationName(oc *graphql.OperationContext) string {
+	if oc.OperationName != "" {
+		return oc.OperationName
+	}
+	if oc.Operation.Name != "" {
+		return oc.Operation.Name
+	}
+	return rootFieldName(oc.Operation)
+}
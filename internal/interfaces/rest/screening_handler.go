@@ -0,0 +1,188 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"crypto-bubble-map-be/internal/domain/entity"
+	apperrors "crypto-bubble-map-be/internal/infrastructure/errors"
+	"crypto-bubble-map-be/internal/infrastructure/middleware"
+	"crypto-bubble-map-be/internal/infrastructure/screening"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxUploadBytes bounds the size of an uploaded address file
+const maxUploadBytes = 20 << 20
+
+// ScreeningHandler exposes bulk screening jobs over REST for CSV upload and download
+type ScreeningHandler struct {
+	service *screening.Service
+	logger  *zap.Logger
+}
+
+// NewScreeningHandler creates a new screening handler
+func NewScreeningHandler(service *screening.Service, logger *zap.Logger) *ScreeningHandler {
+	return &ScreeningHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterRoutes registers the screening endpoints on a router group
+func (h *ScreeningHandler) RegisterRoutes(group *gin.RouterGroup) {
+	group.POST("/jobs", h.UploadJob)
+	group.GET("/jobs/:id", h.GetJob)
+	group.GET("/jobs/:id/results", h.DownloadResults)
+}
+
+// UploadJob creates a screening job from a multipart CSV upload in the "file" field.
+// Optional form fields: hops, includeExposure, includeLabels, raiseAlerts.
+func (h *ScreeningHandler) UploadJob(c *gin.Context) {
+	user, ok := h.requireAnalyst(c)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadBytes)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		abortWithError(c, http.StatusBadRequest, apperrors.ErrCodeInvalidInput, "A CSV file is required in the 'file' field")
+		return
+	}
+	defer file.Close()
+
+	addresses, err := screening.ParseAddressCSV(file)
+	if err != nil {
+		abortWithError(c, http.StatusBadRequest, apperrors.ErrCodeInvalidInput, err.Error())
+		return
+	}
+
+	options := entity.ScreeningOptions{
+		Hops:            formInt(c, "hops", 0),
+		IncludeExposure: formBool(c, "includeExposure", true),
+		IncludeLabels:   formBool(c, "includeLabels", true),
+		RaiseAlerts:     formBool(c, "raiseAlerts", false),
+	}
+
+	job, err := h.service.Submit(c.Request.Context(), addresses, options, user.ID, header.Filename)
+	if err != nil {
+		h.logger.Warn("Failed to submit screening upload",
+			zap.String("file", header.Filename),
+			zap.Error(err))
+		abortWithError(c, http.StatusBadRequest, apperrors.ErrCodeInvalidInput, err.Error())
+		return
+	}
+
+	status := http.StatusAccepted
+	if job.IsFinished() {
+		status = http.StatusOK
+	}
+	c.JSON(status, job)
+}
+
+// GetJob returns the status and progress of a screening job
+func (h *ScreeningHandler) GetJob(c *gin.Context) {
+	job, ok := h.loadJob(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// DownloadResults streams a job's results as CSV (default) or JSON (?format=json)
+func (h *ScreeningHandler) DownloadResults(c *gin.Context) {
+	job, ok := h.loadJob(c)
+	if !ok {
+		return
+	}
+
+	if job.Status != entity.ScreeningJobStatusCompleted {
+		abortWithError(c, http.StatusConflict, "SCREENING_JOB_NOT_COMPLETED",
+			fmt.Sprintf("Screening job is %s", job.Status))
+		return
+	}
+
+	var err error
+	switch c.DefaultQuery("format", "csv") {
+	case "csv":
+		c.Header("Content-Type", "text/csv")
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="screening-%s.csv"`, job.ID))
+		c.Status(http.StatusOK)
+		err = h.service.WriteCSV(c.Request.Context(), job.ID, c.Writer)
+	case "json":
+		c.Header("Content-Type", "application/json")
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="screening-%s.json"`, job.ID))
+		c.Status(http.StatusOK)
+		err = h.service.WriteJSON(c.Request.Context(), job.ID, c.Writer)
+	default:
+		abortWithError(c, http.StatusBadRequest, apperrors.ErrCodeInvalidInput, "format must be csv or json")
+		return
+	}
+
+	if err != nil {
+		// Headers are already sent; all we can do is log and stop writing
+		h.logger.Error("Failed to stream screening results",
+			zap.String("jobID", job.ID),
+			zap.Error(err))
+	}
+}
+
+// loadJob fetches the job named in the path and checks the caller may read it
+func (h *ScreeningHandler) loadJob(c *gin.Context) (*entity.ScreeningJob, bool) {
+	user, ok := h.requireAnalyst(c)
+	if !ok {
+		return nil, false
+	}
+
+	job, err := h.service.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		abortWithError(c, http.StatusInternalServerError, apperrors.ErrCodeInternalServer, "Failed to load screening job")
+		return nil, false
+	}
+	if job == nil || (job.RequestedBy != user.ID && !user.IsAdmin()) {
+		abortWithError(c, http.StatusNotFound, "SCREENING_JOB_NOT_FOUND", "Screening job not found")
+		return nil, false
+	}
+
+	return job, true
+}
+
+// requireAnalyst aborts the request unless the caller holds at least the analyst role
+func (h *ScreeningHandler) requireAnalyst(c *gin.Context) (*entity.User, bool) {
+	user, ok := middleware.UserFromContext(c.Request.Context())
+	if !ok {
+		abortWithError(c, http.StatusUnauthorized, apperrors.ErrCodeAuthTokenInvalid, "Authentication required")
+		return nil, false
+	}
+	if !user.IsAnalyst() {
+		abortWithError(c, http.StatusForbidden, apperrors.ErrCodeAuthPermissionDenied, "Analyst role required")
+		return nil, false
+	}
+	return user, true
+}
+
+func abortWithError(c *gin.Context, status int, code apperrors.ErrorCode, message string) {
+	c.AbortWithStatusJSON(status, gin.H{
+		"error":      message,
+		"code":       code,
+		"request_id": c.GetString("request_id"),
+	})
+}
+
+func formInt(c *gin.Context, name string, fallback int) int {
+	if v, err := strconv.Atoi(c.PostForm(name)); err == nil {
+		return v
+	}
+	return fallback
+}
+
+func formBool(c *gin.Context, name string, fallback bool) bool {
+	if v, err := strconv.ParseBool(c.PostForm(name)); err == nil {
+		return v
+	}
+	return fallback
+}
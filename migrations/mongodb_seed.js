@@ -4,127 +4,8 @@
 // Switch to the database
 use crypto_bubble_map;
 
-// Create collections with validation schemas
-
-// Security alerts collection
-db.createCollection("security_alerts", {
-  validator: {
-    $jsonSchema: {
-      bsonType: "object",
-      required: ["id", "type", "severity", "wallet_address", "timestamp"],
-      properties: {
-        id: { bsonType: "string" },
-        type: { 
-          bsonType: "string",
-          enum: ["suspicious_transaction", "high_risk_counterparty", "unusual_pattern", "compliance_violation", "blacklist_match"]
-        },
-        severity: {
-          bsonType: "string", 
-          enum: ["low", "medium", "high", "critical"]
-        },
-        status: {
-          bsonType: "string",
-          enum: ["active", "investigating", "resolved", "false_positive"]
-        },
-        wallet_address: { bsonType: "string" },
-        network_id: { bsonType: "string" },
-        title: { bsonType: "string" },
-        description: { bsonType: "string" },
-        confidence: { bsonType: "number", minimum: 0, maximum: 1 },
-        action_required: { bsonType: "bool" },
-        metadata: { bsonType: "object" },
-        timestamp: { bsonType: "date" },
-        acknowledged_at: { bsonType: "date" },
-        resolved_at: { bsonType: "date" }
-      }
-    }
-  }
-});
-
-// Compliance reports collection
-db.createCollection("compliance_reports", {
-  validator: {
-    $jsonSchema: {
-      bsonType: "object",
-      required: ["id", "wallet_address", "report_type", "generated_at"],
-      properties: {
-        id: { bsonType: "string" },
-        wallet_address: { bsonType: "string" },
-        network_id: { bsonType: "string" },
-        report_type: {
-          bsonType: "string",
-          enum: ["aml", "kyc", "sar", "risk_assessment"]
-        },
-        status: {
-          bsonType: "string",
-          enum: ["draft", "pending", "completed", "approved", "rejected"]
-        },
-        generated_at: { bsonType: "date" },
-        generated_by: { bsonType: "string" },
-        time_range: {
-          bsonType: "object",
-          properties: {
-            start: { bsonType: "date" },
-            end: { bsonType: "date" }
-          }
-        }
-      }
-    }
-  }
-});
-
-// Transactions collection
-db.createCollection("transactions", {
-  validator: {
-    $jsonSchema: {
-      bsonType: "object",
-      required: ["hash", "from_address", "to_address", "network_id", "timestamp"],
-      properties: {
-        hash: { bsonType: "string" },
-        from_address: { bsonType: "string" },
-        to_address: { bsonType: "string" },
-        network_id: { bsonType: "string" },
-        value: { bsonType: "string" },
-        value_usd: { bsonType: "number" },
-        gas_used: { bsonType: "number" },
-        gas_price: { bsonType: "string" },
-        status: { bsonType: "string" },
-        block_number: { bsonType: "number" },
-        timestamp: { bsonType: "date" },
-        risk_score: { bsonType: "number", minimum: 0, maximum: 100 },
-        is_flagged: { bsonType: "bool" }
-      }
-    }
-  }
-});
-
-// Create indexes for performance
-db.security_alerts.createIndex({ "wallet_address": 1 });
-db.security_alerts.createIndex({ "type": 1 });
-db.security_alerts.createIndex({ "severity": 1 });
-db.security_alerts.createIndex({ "status": 1 });
-db.security_alerts.createIndex({ "timestamp": -1 });
-db.security_alerts.createIndex({ "network_id": 1 });
-
-db.compliance_reports.createIndex({ "wallet_address": 1 });
-db.compliance_reports.createIndex({ "report_type": 1 });
-db.compliance_reports.createIndex({ "status": 1 });
-db.compliance_reports.createIndex({ "generated_at": -1 });
-db.compliance_reports.createIndex({ "network_id": 1 });
-
-db.transactions.createIndex({ "hash": 1 }, { unique: true });
-db.transactions.createIndex({ "from_address": 1 });
-db.transactions.createIndex({ "to_address": 1 });
-db.transactions.createIndex({ "network_id": 1 });
-db.transactions.createIndex({ "timestamp": -1 });
-db.transactions.createIndex({ "block_number": -1 });
-db.transactions.createIndex({ "is_flagged": 1 });
-db.transactions.createIndex({ "risk_score": -1 });
-
-// Compound indexes
-db.transactions.createIndex({ "from_address": 1, "timestamp": -1 });
-db.transactions.createIndex({ "to_address": 1, "timestamp": -1 });
-db.transactions.createIndex({ "network_id": 1, "timestamp": -1 });
+// Indexes are created by the MongoDB migrations: run `go run ./cmd/migrate up`
+// first. Collections are created on first insert.
 
 // Insert sample security alerts
 db.security_alerts.insertMany([
@@ -133,7 +14,7 @@ db.security_alerts.insertMany([
     type: "suspicious_transaction",
     severity: "high",
     status: "active",
-    wallet_address: "0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b1",
+    wallet_address: "0x742d35cc6634c0532925a3b8d4c9db96c4b4d8b1",
     network_id: "ethereum",
     title: "Large Unusual Transaction",
     description: "Transaction amount significantly higher than historical average",
@@ -152,7 +33,7 @@ db.security_alerts.insertMany([
     type: "high_risk_counterparty",
     severity: "critical",
     status: "investigating",
-    wallet_address: "0x8ba1f109551bD432803012645Hac136c22C501e",
+    wallet_address: "0x8ba1f109551bd432803012645hac136c22c501e",
     network_id: "ethereum",
     title: "Transaction with Blacklisted Address",
     description: "Interaction detected with known malicious address",
@@ -189,7 +70,7 @@ db.security_alerts.insertMany([
 db.compliance_reports.insertMany([
   {
     id: "report_001",
-    wallet_address: "0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b1",
+    wallet_address: "0x742d35cc6634c0532925a3b8d4c9db96c4b4d8b1",
     network_id: "ethereum",
     report_type: "aml",
     status: "completed",
@@ -220,7 +101,7 @@ db.compliance_reports.insertMany([
   },
   {
     id: "report_002",
-    wallet_address: "0x8ba1f109551bD432803012645Hac136c22C501e",
+    wallet_address: "0x8ba1f109551bd432803012645hac136c22c501e",
     network_id: "ethereum",
     report_type: "risk_assessment",
     status: "completed",
@@ -261,8 +142,8 @@ db.compliance_reports.insertMany([
 db.transactions.insertMany([
   {
     hash: "0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef",
-    from_address: "0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b1",
-    to_address: "0x8ba1f109551bD432803012645Hac136c22C501e",
+    from_address: "0x742d35cc6634c0532925a3b8d4c9db96c4b4d8b1",
+    to_address: "0x8ba1f109551bd432803012645hac136c22c501e",
     network_id: "ethereum",
     value: "1500000000000000000000000",
     value_usd: 1500000,
@@ -282,7 +163,7 @@ db.transactions.insertMany([
   {
     hash: "0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890",
     from_address: "0x9876543210fedcba9876543210fedcba98765432",
-    to_address: "0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b1",
+    to_address: "0x742d35cc6634c0532925a3b8d4c9db96c4b4d8b1",
     network_id: "polygon",
     value: "50000000000000000000",
     value_usd: 50000,
@@ -302,6 +183,4 @@ db.transactions.insertMany([
 ]);
 
 print("MongoDB seed data inserted successfully!");
-print("Collections created: security_alerts, compliance_reports, transactions");
-print("Indexes created for optimal query performance");
 print("Sample data inserted for testing and development");
@@ -1,19 +1,8 @@
 // Neo4j seed data for Crypto Bubble Map Backend
 // Run with: cypher-shell -u neo4j -p password < neo4j_seed.cypher
 
-// Create constraints and indexes
-CREATE CONSTRAINT wallet_address_unique IF NOT EXISTS FOR (w:Wallet) REQUIRE w.address IS UNIQUE;
-CREATE CONSTRAINT network_id_unique IF NOT EXISTS FOR (n:Network) REQUIRE n.id IS UNIQUE;
-
-CREATE INDEX wallet_risk_score IF NOT EXISTS FOR (w:Wallet) ON (w.risk_score);
-CREATE INDEX wallet_balance IF NOT EXISTS FOR (w:Wallet) ON (w.balance);
-CREATE INDEX wallet_type IF NOT EXISTS FOR (w:Wallet) ON (w.type);
-CREATE INDEX wallet_network IF NOT EXISTS FOR (w:Wallet) ON (w.network_id);
-CREATE INDEX wallet_last_activity IF NOT EXISTS FOR (w:Wallet) ON (w.last_activity);
-
-CREATE INDEX transaction_timestamp IF NOT EXISTS FOR (t:Transaction) ON (t.timestamp);
-CREATE INDEX transaction_value IF NOT EXISTS FOR (t:Transaction) ON (t.value_usd);
-CREATE INDEX transaction_risk IF NOT EXISTS FOR (t:Transaction) ON (t.risk_score);
+// Constraints and indexes are created by the Neo4j migrations:
+// run `go run ./cmd/migrate up` first
 
 // Create sample networks
 CREATE (eth:Network {
@@ -54,7 +43,7 @@ CREATE (arb:Network {
 
 // Create sample wallets with different types and risk profiles
 CREATE (w1:Wallet {
-  address: '0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b1',
+  address: '0x742d35cc6634c0532925a3b8d4c9db96c4b4d8b1',
   network_id: 'ethereum',
   type: 'whale',
   balance: '15000.5',
@@ -66,6 +55,8 @@ CREATE (w1:Wallet {
   last_activity: datetime('2024-01-15T10:30:00Z'),
   is_flagged: true,
   tags: ['high-value', 'suspicious-activity'],
+  tags_text: 'high-value suspicious-activity',
+  ens_name: 'whale.eth',
   metadata: {
     ens_name: 'whale.eth',
     labels: ['DeFi Trader', 'High Volume'],
@@ -77,7 +68,7 @@ CREATE (w1:Wallet {
 });
 
 CREATE (w2:Wallet {
-  address: '0x8ba1f109551bD432803012645Hac136c22C501e',
+  address: '0x8ba1f109551bd432803012645hac136c22c501e',
   network_id: 'ethereum',
   type: 'exchange',
   balance: '250000.0',
@@ -166,8 +157,8 @@ CREATE (w)-[:ON_NETWORK]->(n);
 // Create sample transactions between wallets
 CREATE (t1:Transaction {
   hash: '0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef',
-  from_address: '0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b1',
-  to_address: '0x8ba1f109551bD432803012645Hac136c22C501e',
+  from_address: '0x742d35cc6634c0532925a3b8d4c9db96c4b4d8b1',
+  to_address: '0x8ba1f109551bd432803012645hac136c22c501e',
   network_id: 'ethereum',
   value: '1500.0',
   value_usd: 4500000,
@@ -184,7 +175,7 @@ CREATE (t1:Transaction {
 CREATE (t2:Transaction {
   hash: '0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890',
   from_address: '0x9876543210fedcba9876543210fedcba98765432',
-  to_address: '0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b1',
+  to_address: '0x742d35cc6634c0532925a3b8d4c9db96c4b4d8b1',
   network_id: 'polygon',
   value: '50.0',
   value_usd: 50000,
@@ -200,7 +191,7 @@ CREATE (t2:Transaction {
 
 CREATE (t3:Transaction {
   hash: '0xfedcba0987654321fedcba0987654321fedcba0987654321fedcba0987654321',
-  from_address: '0x8ba1f109551bD432803012645Hac136c22C501e',
+  from_address: '0x8ba1f109551bd432803012645hac136c22c501e',
   to_address: '0x1234567890abcdef1234567890abcdef12345678',
   network_id: 'ethereum',
   value: '0.1',
@@ -216,26 +207,26 @@ CREATE (t3:Transaction {
 });
 
 // Create transaction relationships
-MATCH (w1:Wallet {address: '0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b1'}),
-      (w2:Wallet {address: '0x8ba1f109551bD432803012645Hac136c22C501e'}),
+MATCH (w1:Wallet {address: '0x742d35cc6634c0532925a3b8d4c9db96c4b4d8b1'}),
+      (w2:Wallet {address: '0x8ba1f109551bd432803012645hac136c22c501e'}),
       (t1:Transaction {hash: '0x1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef'})
 CREATE (w1)-[:SENT {amount: '1500.0', timestamp: datetime('2024-01-15T10:30:00Z')}]->(t1)
 CREATE (t1)-[:RECEIVED {amount: '1500.0', timestamp: datetime('2024-01-15T10:30:00Z')}]->(w2);
 
 MATCH (w3:Wallet {address: '0x9876543210fedcba9876543210fedcba98765432'}),
-      (w1:Wallet {address: '0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b1'}),
+      (w1:Wallet {address: '0x742d35cc6634c0532925a3b8d4c9db96c4b4d8b1'}),
       (t2:Transaction {hash: '0xabcdef1234567890abcdef1234567890abcdef1234567890abcdef1234567890'})
 CREATE (w3)-[:SENT {amount: '50.0', timestamp: datetime('2024-01-14T16:20:00Z')}]->(t2)
 CREATE (t2)-[:RECEIVED {amount: '50.0', timestamp: datetime('2024-01-14T16:20:00Z')}]->(w1);
 
-MATCH (w2:Wallet {address: '0x8ba1f109551bD432803012645Hac136c22C501e'}),
+MATCH (w2:Wallet {address: '0x8ba1f109551bd432803012645hac136c22c501e'}),
       (w4:Wallet {address: '0x1234567890abcdef1234567890abcdef12345678'}),
       (t3:Transaction {hash: '0xfedcba0987654321fedcba0987654321fedcba0987654321fedcba0987654321'})
 CREATE (w2)-[:SENT {amount: '0.1', timestamp: datetime('2024-01-14T15:45:00Z')}]->(t3)
 CREATE (t3)-[:RECEIVED {amount: '0.1', timestamp: datetime('2024-01-14T15:45:00Z')}]->(w4);
 
 // Create wallet clustering relationships
-MATCH (w1:Wallet {address: '0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b1'}),
+MATCH (w1:Wallet {address: '0x742d35cc6634c0532925a3b8d4c9db96c4b4d8b1'}),
       (w3:Wallet {address: '0x9876543210fedcba9876543210fedcba98765432'})
 CREATE (w1)-[:CONNECTED_TO {
   strength: 0.75,
@@ -246,7 +237,7 @@ CREATE (w1)-[:CONNECTED_TO {
   relationship_type: 'frequent_counterparty'
 }]->(w3);
 
-MATCH (w2:Wallet {address: '0x8ba1f109551bD432803012645Hac136c22C501e'}),
+MATCH (w2:Wallet {address: '0x8ba1f109551bd432803012645hac136c22c501e'}),
       (w4:Wallet {address: '0x1234567890abcdef1234567890abcdef12345678'})
 CREATE (w2)-[:CONNECTED_TO {
   strength: 0.95,
@@ -281,15 +272,15 @@ CREATE (c2:Cluster {
 });
 
 // Connect wallets to clusters
-MATCH (w1:Wallet {address: '0x742d35Cc6634C0532925a3b8D4C9db96C4b4d8b1'}),
-      (w2:Wallet {address: '0x8ba1f109551bD432803012645Hac136c22C501e'}),
+MATCH (w1:Wallet {address: '0x742d35cc6634c0532925a3b8d4c9db96c4b4d8b1'}),
+      (w2:Wallet {address: '0x8ba1f109551bd432803012645hac136c22c501e'}),
       (w3:Wallet {address: '0x9876543210fedcba9876543210fedcba98765432'}),
       (c1:Cluster {id: 'cluster_001'})
 CREATE (w1)-[:BELONGS_TO {confidence: 0.85}]->(c1)
 CREATE (w2)-[:BELONGS_TO {confidence: 0.90}]->(c1)
 CREATE (w3)-[:BELONGS_TO {confidence: 0.75}]->(c1);
 
-MATCH (w2:Wallet {address: '0x8ba1f109551bD432803012645Hac136c22C501e'}),
+MATCH (w2:Wallet {address: '0x8ba1f109551bd432803012645hac136c22c501e'}),
       (w4:Wallet {address: '0x1234567890abcdef1234567890abcdef12345678'}),
       (c2:Cluster {id: 'cluster_002'})
 CREATE (w2)-[:BELONGS_TO {confidence: 0.95}]->(c2)
@@ -313,7 +304,3 @@ CREATE (stats2:NetworkStats {
   flagged_wallets: 500000,
   last_updated: datetime()
 });
-
-// Create indexes for better performance
-CREATE INDEX wallet_cluster_id IF NOT EXISTS FOR (c:Cluster) ON (c.id);
-CREATE INDEX network_stats_id IF NOT EXISTS FOR (s:NetworkStats) ON (s.network_id);
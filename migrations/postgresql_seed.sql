@@ -0,0 +1,11 @@
+-- PostgreSQL seed data for Crypto Bubble Map Backend
+-- Run with: psql -d crypto_bubble_map -f postgresql_seed.sql
+
+-- Tables are created by the PostgreSQL migrations: run `go run ./cmd/migrate up`
+-- first. The default watch list tags are created by PostgreSQL migration 2.
+
+-- Create admin user (password: admin123)
+-- Note: In production, this should be done securely
+INSERT INTO users (email, password_hash, first_name, last_name, role, is_active, email_verified, created_at, updated_at) VALUES
+    ('admin@cryptobubblemap.com', '$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi', 'Admin', 'User', 'ADMIN', true, true, NOW(), NOW())
+ON CONFLICT (email) DO NOTHING;
@@ -1,7 +1,7 @@
 #!/bin/bash
 
 # Migration runner script for Crypto Bubble Map Backend
-# This script runs all database migrations and seeds data
+# This script applies the schema migrations with cmd/migrate and seeds data
 
 set -e  # Exit on any error
 
@@ -35,6 +35,12 @@ NEO4J_PORT="${NEO4J_PORT:-7687}"
 NEO4J_USER="${NEO4J_USER:-neo4j}"
 NEO4J_PASSWORD="${NEO4J_PASSWORD:-password}"
 
+if [ -n "$MONGODB_USER" ] && [ -n "$MONGODB_PASSWORD" ]; then
+    MONGO_URI="mongodb://$MONGODB_USER:$MONGODB_PASSWORD@$MONGODB_HOST:$MONGODB_PORT/$MONGODB_DB"
+else
+    MONGO_URI="mongodb://$MONGODB_HOST:$MONGODB_PORT/$MONGODB_DB"
+fi
+
 # Functions
 print_header() {
     echo -e "${BLUE}================================${NC}"
@@ -62,6 +68,12 @@ print_info() {
 check_dependencies() {
     print_step "Checking dependencies..."
     
+    # Check if go is available
+    if ! command -v go &> /dev/null; then
+        print_error "go is not installed. It is needed to run the schema migrations."
+        exit 1
+    fi
+    
     # Check if psql is available
     if ! command -v psql &> /dev/null; then
         print_error "psql is not installed. Please install PostgreSQL client."
@@ -97,12 +109,6 @@ test_connections() {
     
     # Test MongoDB connection
     print_info "Testing MongoDB connection..."
-    if [ -n "$MONGODB_USER" ] && [ -n "$MONGODB_PASSWORD" ]; then
-        MONGO_URI="mongodb://$MONGODB_USER:$MONGODB_PASSWORD@$MONGODB_HOST:$MONGODB_PORT/$MONGODB_DB"
-    else
-        MONGO_URI="mongodb://$MONGODB_HOST:$MONGODB_PORT/$MONGODB_DB"
-    fi
-    
     if mongosh "$MONGO_URI" --eval "db.runCommand('ping')" &> /dev/null; then
         print_success "MongoDB connection successful"
     else
@@ -135,15 +141,27 @@ create_databases() {
     print_success "Neo4j database ready"
 }
 
+run_schema_migrations() {
+    # cmd/migrate reads the server's configuration variables
+    (cd "$PROJECT_ROOT" && \
+        POSTGRES_HOST="$POSTGRES_HOST" POSTGRES_PORT="$POSTGRES_PORT" POSTGRES_DB="$POSTGRES_DB" \
+        POSTGRES_USER="$POSTGRES_USER" POSTGRES_PASSWORD="$POSTGRES_PASSWORD" \
+        MONGO_URI="$MONGO_URI" MONGO_DATABASE="$MONGODB_DB" \
+        NEO4J_URI="bolt://$NEO4J_HOST:$NEO4J_PORT" NEO4J_USERNAME="$NEO4J_USER" NEO4J_PASSWORD="$NEO4J_PASSWORD" \
+        go run ./cmd/migrate -store "$1" up)
+}
+
 run_postgresql_migrations() {
     print_step "Running PostgreSQL migrations..."
     
-    if [ -f "$MIGRATIONS_DIR/001_initial_schema.sql" ]; then
-        print_info "Running initial schema migration..."
-        PGPASSWORD="$POSTGRES_PASSWORD" psql -h "$POSTGRES_HOST" -p "$POSTGRES_PORT" -U "$POSTGRES_USER" -d "$POSTGRES_DB" -f "$MIGRATIONS_DIR/001_initial_schema.sql"
+    run_schema_migrations postgresql
+    
+    if [ -f "$MIGRATIONS_DIR/postgresql_seed.sql" ]; then
+        print_info "Running PostgreSQL seed script..."
+        PGPASSWORD="$POSTGRES_PASSWORD" psql -h "$POSTGRES_HOST" -p "$POSTGRES_PORT" -U "$POSTGRES_USER" -d "$POSTGRES_DB" -f "$MIGRATIONS_DIR/postgresql_seed.sql"
         print_success "PostgreSQL migrations completed"
     else
-        print_error "PostgreSQL migration file not found"
+        print_error "PostgreSQL seed file not found"
         exit 1
     fi
 }
@@ -151,12 +169,14 @@ run_postgresql_migrations() {
 run_mongodb_migrations() {
     print_step "Running MongoDB migrations..."
     
+    run_schema_migrations mongodb
+    
     if [ -f "$MIGRATIONS_DIR/mongodb_seed.js" ]; then
         print_info "Running MongoDB seed script..."
         mongosh "$MONGO_URI" < "$MIGRATIONS_DIR/mongodb_seed.js"
         print_success "MongoDB migrations completed"
     else
-        print_error "MongoDB migration file not found"
+        print_error "MongoDB seed file not found"
         exit 1
     fi
 }
@@ -164,12 +184,14 @@ run_mongodb_migrations() {
 run_neo4j_migrations() {
     print_step "Running Neo4j migrations..."
     
+    run_schema_migrations neo4j
+    
     if [ -f "$MIGRATIONS_DIR/neo4j_seed.cypher" ]; then
         print_info "Running Neo4j seed script..."
         cypher-shell -a "bolt://$NEO4J_HOST:$NEO4J_PORT" -u "$NEO4J_USER" -p "$NEO4J_PASSWORD" -f "$MIGRATIONS_DIR/neo4j_seed.cypher"
         print_success "Neo4j migrations completed"
     else
-        print_error "Neo4j migration file not found"
+        print_error "Neo4j seed file not found"
         exit 1
     fi
 }